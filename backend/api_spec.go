@@ -0,0 +1,358 @@
+package main
+
+import (
+    "backend/config"
+    "backend/middleware"
+    "backend/models"
+    "backend/openapi"
+    "net/http"
+    "strings"
+)
+
+// Типы содержимого выгрузок
+const (
+    contentCSV  = "text/csv"
+    contentXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+    contentPDF  = "application/pdf"
+    contentHTML = "text/html"
+)
+
+var (
+    listFiles  = []string{contentCSV, contentXLSX, contentPDF}
+    gridFiles  = []string{contentHTML, contentPDF}
+    exportList = []openapi.Parameter{
+        openapi.QueryParam("format", "string", "json, csv, xlsx или pdf; по умолчанию по заголовку Accept"),
+        openapi.QueryParam("columns", "string", "состав и порядок колонок выгрузки: name,group_name"),
+        openapi.QueryParam("headers", "string", "fields - имена полей вместо русских заголовков"),
+    }
+    confirmParam = openapi.QueryParam("confirm", "boolean", "true - удалить вместе с зависимыми записями")
+    periodParams = []openapi.Parameter{
+        openapi.QueryParam("from", "string", "начало периода, YYYY-MM-DD"),
+        openapi.QueryParam("to", "string", "конец периода, YYYY-MM-DD"),
+    }
+)
+
+const legacyTag = "Без версии (устарело)"
+
+// legacyResponses ответы маршрутов /api без версии, которые отличаются от /api/v1
+var legacyResponses = map[string]interface{}{
+    "PATCH /teachers/:id":                  map[string]interface{}{}, // Только изменённые поля
+    "GET /teachers/:teacher_name/schedule": []models.Schedule{},
+    "POST /schedules":                      models.Schedule{},
+    "GET /schedules":                       models.Page[models.Schedule]{},
+    "GET /schedules/:id":                   models.Schedule{},
+    "PATCH /schedules/:id":                 models.Schedule{},
+    "GET /schedules/day/:day":              models.Page[models.Schedule]{},
+    "GET /schedules/group/:group_name":     models.Page[models.Schedule]{},
+    "GET /me/timetable":                    models.Timetable{},
+    "GET /guardian/students/:id/timetable": models.Timetable{},
+}
+
+// pageQuery параметры постраничного списка: limit, offset, sort, фильтры и выгрузка
+func pageQuery(filters ...string) []openapi.Parameter {
+    params := []openapi.Parameter{
+        openapi.QueryParam("limit", "integer", "по умолчанию 50, максимум 500"),
+        openapi.QueryParam("offset", "integer", ""),
+        openapi.QueryParam("sort", "string", "поля через запятую, '-' - по убыванию: name,-age"),
+    }
+    for _, filter := range filters {
+        params = append(params, openapi.QueryParam(filter, "string", "фильтр"))
+    }
+    return append(params, exportList...)
+}
+
+// listQuery параметры списка без постраничного вывода
+func listQuery(params ...openapi.Parameter) []openapi.Parameter {
+    return append(params, exportList...)
+}
+
+// importOp загрузка записей из CSV/XLSX
+func importOp(tag, summary string, permission string) openapi.Op {
+    return openapi.Op{
+        Tag: tag, Summary: summary, Permissions: []string{permission}, Upload: true,
+        Description: "Все строки сохраняются одной транзакцией; при ошибках - 422 с ошибками по строкам. С dry_run=true только проверка (200)",
+        Query: []openapi.Parameter{
+            openapi.QueryParam("dry_run", "boolean", "только проверка"),
+            openapi.QueryParam("report", "string", "csv или xlsx - отчёт об ошибках файлом"),
+        },
+        Status: http.StatusCreated, Response: models.ImportResult{}, Files: []string{contentCSV, contentXLSX},
+    }
+}
+
+func restoreOp(tag, summary string) openapi.Op {
+    return openapi.Op{Tag: tag, Summary: summary, Permissions: []string{models.PermTrashManage}, Response: openapi.CascadeMessage{}}
+}
+
+// apiSpec описание всех маршрутов setupRouter. Новый маршрут нужно добавить и сюда,
+// иначе не пройдёт TestSpecCoversRoutes
+func apiSpec() *openapi.Document {
+    d := openapi.New("College Management System API", "1.0", middleware.ErrorResponse{})
+    d.Info.Description = "API учебной части колледжа. Защищённые маршруты требуют JWT из POST /api/v1/login " +
+        "в заголовке Authorization: Bearer; x-permissions - права, из которых роли достаточно одного. " +
+        "Маршруты /api без версии устарели: ответы с заголовками Deprecation и Sunset, прежние форматы ответов"
+
+    // add описывает маршрут /api/v1 и его устаревший вариант /api с прежним форматом ответа
+    add := func(method, path string, op openapi.Op) {
+        route := strings.TrimPrefix(path, "/api")
+        d.Add(method, "/api/v1"+route, op)
+
+        legacy := op
+        legacy.Tag = legacyTag
+        legacy.Deprecated = true
+        legacy.Description = strings.TrimSpace("Устарело, используйте /api/v1" + route + ". " + op.Description)
+        if response, ok := legacyResponses[method+" "+route]; ok {
+            legacy.Response = response
+        }
+        d.Add(method, path, legacy)
+    }
+
+    // Авторизация и документация
+    add("POST", "/api/register", openapi.Op{Tag: "Авторизация", Summary: "Регистрация пользователя", Public: true,
+        Description: "Открыто доступна только роль pending без прав (по умолчанию); другие роли - 403, их назначает администратор, студенты активируют учётную запись по коду", Body: models.RegisterRequest{}, Status: http.StatusCreated, Response: openapi.Message{}})
+    add("POST", "/api/login", openapi.Op{Tag: "Авторизация", Summary: "Вход, выдаёт JWT", Public: true,
+        Body: models.Credentials{}, Response: openapi.Token{}})
+    add("POST", "/api/activate", openapi.Op{Tag: "Авторизация", Summary: "Активация учётной записи студента по коду", Public: true,
+        Body: models.ActivationRequest{}, Status: http.StatusCreated, Response: openapi.Message{}})
+    add("PUT", "/api/teacher/profile", openapi.Op{Tag: "Авторизация", Summary: "Смена своего логина или пароля",
+        Permissions: []string{models.PermProfileWrite}, Body: models.ProfileUpdate{}, Response: openapi.Message{}})
+    add("GET", "/api/openapi.json", openapi.Op{Tag: "Документация", Summary: "Это описание API", Public: true})
+    add("GET", "/api/docs", openapi.Op{Tag: "Документация", Summary: "Страница документации", Public: true, Files: []string{contentHTML}})
+
+    // Администрирование
+    admin := []string{models.PermUsersManage}
+    add("GET", "/api/admin", openapi.Op{Tag: "Администрирование", Summary: "Проверка доступа администратора", Permissions: admin, Response: openapi.Message{}})
+    add("GET", "/api/admin/trash", openapi.Op{Tag: "Администрирование", Summary: "Корзина удалённых записей", Permissions: []string{models.PermTrashManage},
+        Query: listQuery(openapi.QueryParam("type", "string", "teachers, students, courses, classrooms или schedules")),
+        Response: []models.TrashItem{}, Files: listFiles})
+    add("GET", "/api/admin/config", openapi.Op{Tag: "Администрирование", Summary: "Настройки без паролей и секретов", Permissions: admin, Response: config.Config{}})
+    add("GET", "/api/roles", openapi.Op{Tag: "Администрирование", Summary: "Роли и их права", Permissions: admin, Response: []models.Role{}})
+    add("POST", "/api/roles", openapi.Op{Tag: "Администрирование", Summary: "Создание роли", Permissions: admin,
+        Body: models.Role{}, Status: http.StatusCreated, Response: models.Role{}})
+    add("PUT", "/api/roles/:name/permissions", openapi.Op{Tag: "Администрирование", Summary: "Замена прав роли", Permissions: admin,
+        Body: models.RolePermissionsUpdate{}, Response: models.Role{}})
+    add("DELETE", "/api/roles/:name", openapi.Op{Tag: "Администрирование", Summary: "Удаление роли", Permissions: admin, Response: openapi.Message{}})
+    add("GET", "/api/permissions", openapi.Op{Tag: "Администрирование", Summary: "Все права", Permissions: admin, Response: []models.Permission{}})
+    add("PATCH", "/api/users/:id/role", openapi.Op{Tag: "Администрирование", Summary: "Назначение роли пользователю", Permissions: admin,
+        Body: models.UserRoleUpdate{}, Response: models.User{}})
+
+    // Журнал аудита
+    audit := []string{models.PermAuditRead}
+    add("GET", "/api/audit", openapi.Op{Tag: "Аудит", Summary: "Журнал изменений", Permissions: audit,
+        Query: listQuery(
+            openapi.QueryParam("user_id", "integer", ""),
+            openapi.QueryParam("entity_type", "string", ""),
+            openapi.QueryParam("entity_id", "integer", ""),
+            openapi.QueryParam("from", "string", "YYYY-MM-DD или RFC3339"),
+            openapi.QueryParam("to", "string", "YYYY-MM-DD или RFC3339"),
+            openapi.QueryParam("limit", "integer", ""),
+        ),
+        Response: []models.AuditEntry{}, Files: listFiles})
+    add("GET", "/api/audit/:entity_type/:entity_id", openapi.Op{Tag: "Аудит", Summary: "История записи", Permissions: audit,
+        Query: listQuery(), Response: []models.AuditEntry{}, Files: listFiles})
+
+    add("GET", "/api/search", openapi.Op{Tag: "Поиск", Summary: "Поиск студентов, преподавателей, курсов и аудиторий",
+        Description: "Ищутся только типы, на чтение которых у роли есть право",
+        Permissions: []string{models.PermStudentsRead, models.PermTeachersRead, models.PermCoursesRead, models.PermClassroomsRead},
+        Query: []openapi.Parameter{
+            {Name: "q", In: "query", Required: true, Description: "не короче 2 символов", Schema: &openapi.Schema{Type: "string"}},
+            openapi.QueryParam("types", "string", "student,teacher,course,classroom"),
+            openapi.QueryParam("limit", "integer", "до 100, по умолчанию 20"),
+        },
+        Response: models.SearchResponse{}})
+
+    // Преподаватели
+    teachersRead, teachersWrite := []string{models.PermTeachersRead}, []string{models.PermTeachersWrite}
+    add("GET", "/api/teachers", openapi.Op{Tag: "Преподаватели", Summary: "Список преподавателей", Permissions: teachersRead,
+        Query: pageQuery("name", "subject", "course", "hours_min", "hours_max"), Response: models.Page[models.Teacher]{}, Files: listFiles})
+    add("POST", "/api/teachers", openapi.Op{Tag: "Преподаватели", Summary: "Создание преподавателя", Permissions: teachersWrite,
+        Body: models.Teacher{}, Status: http.StatusCreated, Response: models.Teacher{}})
+    add("PATCH", "/api/teachers/:id", openapi.Op{Tag: "Преподаватели", Summary: "Изменение переданных полей", Permissions: teachersWrite,
+        Body: models.TeacherUpdate{}, Response: models.Teacher{}})
+    add("DELETE", "/api/teachers/:id", openapi.Op{Tag: "Преподаватели", Summary: "Удаление в корзину", Permissions: teachersWrite,
+        Description: "Если есть занятия, без confirm=true - 409 со списком в details.cascade",
+        Query: []openapi.Parameter{confirmParam}, Response: openapi.CascadeMessage{}})
+    add("POST", "/api/teachers/:id/restore", restoreOp("Преподаватели", "Восстановление из корзины"))
+    add("POST", "/api/teachers/import", importOp("Преподаватели", "Импорт из CSV/XLSX", models.PermTeachersWrite))
+    add("GET", "/api/teachers/:teacher_name/schedule", openapi.Op{Tag: "Преподаватели", Summary: "Расписание преподавателя",
+        Permissions: []string{models.PermScheduleRead}, Query: listQuery(), Response: []models.ScheduleResource{}, Files: listFiles})
+    add("POST", "/api/notify", openapi.Op{Tag: "Преподаватели", Summary: "Отправка письма", Permissions: []string{models.PermNotifySend},
+        Body: models.Notification{}, Response: openapi.Message{}})
+
+    // Студенты
+    studentsRead, studentsWrite := []string{models.PermStudentsRead}, []string{models.PermStudentsWrite}
+    add("GET", "/api/students", openapi.Op{Tag: "Студенты", Summary: "Список студентов", Permissions: studentsRead,
+        Query: pageQuery("group", "status", "name", "teacher_id", "age_min", "age_max"), Response: models.Page[models.Student]{}, Files: listFiles})
+    add("POST", "/api/students", openapi.Op{Tag: "Студенты", Summary: "Создание студента", Permissions: studentsWrite,
+        Body: models.Student{}, Status: http.StatusCreated, Response: models.Student{}})
+    add("GET", "/api/students/:id", openapi.Op{Tag: "Студенты", Summary: "Студент", Permissions: studentsRead, Response: models.Student{}})
+    add("PATCH", "/api/students/:id", openapi.Op{Tag: "Студенты", Summary: "Изменение переданных полей", Permissions: studentsWrite,
+        Body: models.StudentUpdate{}, Response: models.Student{}})
+    add("DELETE", "/api/students/:id", openapi.Op{Tag: "Студенты", Summary: "Удаление в корзину", Permissions: studentsWrite, Response: openapi.Message{}})
+    add("POST", "/api/students/:id/restore", restoreOp("Студенты", "Восстановление из корзины"))
+    add("POST", "/api/students/import", importOp("Студенты", "Импорт из CSV/XLSX", models.PermStudentsWrite))
+    add("POST", "/api/students/:id/status", openapi.Op{Tag: "Студенты", Summary: "Смена статуса по приказу", Permissions: studentsWrite,
+        Body: models.StudentStatusChange{}, Response: models.StudentOrder{}})
+    add("POST", "/api/students/:id/transfer", openapi.Op{Tag: "Студенты", Summary: "Перевод в другую группу по приказу", Permissions: studentsWrite,
+        Body: models.StudentTransfer{}, Response: models.StudentOrder{}})
+    add("GET", "/api/students/:id/orders", openapi.Op{Tag: "Студенты", Summary: "Приказы по студенту", Permissions: studentsRead,
+        Query: listQuery(), Response: []models.StudentOrder{}, Files: listFiles})
+    add("GET", "/api/students/:id/group-history", openapi.Op{Tag: "Студенты", Summary: "История переводов", Permissions: studentsRead,
+        Query: listQuery(), Response: []models.StudentGroupChange{}, Files: listFiles})
+    add("POST", "/api/students/accounts", openapi.Op{Tag: "Студенты", Summary: "Коды активации для студентов без учётной записи",
+        Permissions: admin, Body: models.ProvisionRequest{}, OptionalBody: true, Response: []models.ActivationCode{}})
+    add("GET", "/api/students/:id/grades", openapi.Op{Tag: "Студенты", Summary: "Зачётная книжка", Permissions: []string{models.PermGradesRead},
+        Query: listQuery(), Response: []models.Grade{}, Files: listFiles})
+
+    // Законные представители
+    guardiansWrite := []string{models.PermGuardiansWrite}
+    add("GET", "/api/students/:id/guardians", openapi.Op{Tag: "Представители", Summary: "Представители студента", Permissions: studentsRead,
+        Query: listQuery(), Response: []models.Guardian{}, Files: listFiles})
+    add("POST", "/api/students/:id/guardians", openapi.Op{Tag: "Представители", Summary: "Добавление представителя", Permissions: guardiansWrite,
+        Body: models.Guardian{}, Status: http.StatusCreated, Response: models.Guardian{}})
+    add("PATCH", "/api/guardians/:id", openapi.Op{Tag: "Представители", Summary: "Изменение переданных полей", Permissions: guardiansWrite,
+        Body: models.GuardianUpdate{}, Response: models.Guardian{}})
+    add("DELETE", "/api/guardians/:id", openapi.Op{Tag: "Представители", Summary: "Удаление представителя", Permissions: guardiansWrite, Response: openapi.Message{}})
+    add("POST", "/api/guardians/:id/account", openapi.Op{Tag: "Представители", Summary: "Учётная запись представителя", Permissions: admin,
+        Body: models.Credentials{}, Status: http.StatusCreated, Response: models.User{}})
+    add("PUT", "/api/guardians/:id/account", openapi.Op{Tag: "Представители", Summary: "Привязка к существующей учётной записи представителя", Permissions: admin,
+        Description: "Одна учётная запись представителя видит всех студентов, контакты которых к ней привязаны",
+        Body: models.GuardianAccountLink{}, Response: models.Guardian{}})
+
+    // Курсы
+    coursesRead, coursesWrite := []string{models.PermCoursesRead}, []string{models.PermCoursesWrite}
+    add("GET", "/api/courses", openapi.Op{Tag: "Курсы", Summary: "Список курсов", Permissions: coursesRead,
+        Query: pageQuery("name", "teacher_id"), Response: models.Page[models.Course]{}, Files: listFiles})
+    add("POST", "/api/courses", openapi.Op{Tag: "Курсы", Summary: "Создание курса", Permissions: coursesWrite,
+        Body: models.Course{}, Status: http.StatusCreated, Response: models.Course{}})
+    add("GET", "/api/courses/:id", openapi.Op{Tag: "Курсы", Summary: "Курс", Permissions: coursesRead, Response: models.Course{}})
+    add("PATCH", "/api/courses/:id", openapi.Op{Tag: "Курсы", Summary: "Изменение переданных полей", Permissions: coursesWrite,
+        Body: models.CourseUpdate{}, Response: models.Course{}})
+    add("DELETE", "/api/courses/:id", openapi.Op{Tag: "Курсы", Summary: "Удаление в корзину", Permissions: coursesWrite,
+        Description: "Если есть зависимые записи, без confirm=true - 409 со списком в details.cascade",
+        Query: []openapi.Parameter{confirmParam}, Response: openapi.CascadeMessage{}})
+    add("POST", "/api/courses/:id/restore", restoreOp("Курсы", "Восстановление из корзины"))
+    add("POST", "/api/courses/import", importOp("Курсы", "Импорт из CSV/XLSX", models.PermCoursesWrite))
+
+    // Аудитории
+    classroomsRead, classroomsWrite := []string{models.PermClassroomsRead}, []string{models.PermClassroomsWrite}
+    add("GET", "/api/classrooms", openapi.Op{Tag: "Аудитории", Summary: "Список аудиторий", Permissions: classroomsRead,
+        Query: pageQuery("name", "capacity_min", "capacity_max"), Response: models.Page[models.Classroom]{}, Files: listFiles})
+    add("POST", "/api/classrooms", openapi.Op{Tag: "Аудитории", Summary: "Создание аудитории", Permissions: classroomsWrite,
+        Body: models.Classroom{}, Status: http.StatusCreated, Response: models.Classroom{}})
+    add("GET", "/api/classrooms/:id", openapi.Op{Tag: "Аудитории", Summary: "Аудитория", Permissions: classroomsRead, Response: models.Classroom{}})
+    add("PATCH", "/api/classrooms/:id", openapi.Op{Tag: "Аудитории", Summary: "Изменение переданных полей", Permissions: classroomsWrite,
+        Body: models.ClassroomUpdate{}, Response: models.Classroom{}})
+    add("DELETE", "/api/classrooms/:id", openapi.Op{Tag: "Аудитории", Summary: "Удаление в корзину", Permissions: classroomsWrite,
+        Description: "Если есть занятия, без confirm=true - 409 со списком в details.cascade",
+        Query: []openapi.Parameter{confirmParam}, Response: openapi.CascadeMessage{}})
+    add("POST", "/api/classrooms/:id/restore", restoreOp("Аудитории", "Восстановление из корзины"))
+    add("POST", "/api/classrooms/import", importOp("Аудитории", "Импорт из CSV/XLSX", models.PermClassroomsWrite))
+
+    // Расписание
+    scheduleRead, scheduleWrite := []string{models.PermScheduleRead}, []string{models.PermScheduleWrite}
+    scheduleFilters := []string{"teacher_id", "classroom_id", "group", "day", "week_type", "date_from", "date_to"}
+    add("POST", "/api/schedules", openapi.Op{Tag: "Расписание", Summary: "Создание занятия", Permissions: scheduleWrite,
+        Description: "Занятие длится 90 минут; пересечение с занятием преподавателя, аудитории или группы - 409",
+        Body: models.ScheduleCreate{}, Status: http.StatusCreated, Response: models.ScheduleResource{}})
+    add("GET", "/api/schedules", openapi.Op{Tag: "Расписание", Summary: "Список занятий", Permissions: scheduleRead,
+        Query: pageQuery(scheduleFilters...), Response: models.Page[models.ScheduleResource]{}, Files: listFiles})
+    add("GET", "/api/schedules/:id", openapi.Op{Tag: "Расписание", Summary: "Занятие", Permissions: scheduleRead, Response: models.ScheduleResource{}})
+    add("PATCH", "/api/schedules/:id", openapi.Op{Tag: "Расписание", Summary: "Изменение переданных полей", Permissions: scheduleWrite,
+        Body: models.ScheduleUpdate{}, Response: models.ScheduleResource{}})
+    add("DELETE", "/api/schedules/:id", openapi.Op{Tag: "Расписание", Summary: "Удаление в корзину", Permissions: scheduleWrite, Response: openapi.Message{}})
+    add("POST", "/api/schedules/:id/restore", restoreOp("Расписание", "Восстановление из корзины"))
+    add("GET", "/api/schedules/day/:day", openapi.Op{Tag: "Расписание", Summary: "Занятия по дню недели", Permissions: scheduleRead,
+        Query: pageQuery(scheduleFilters...), Response: models.Page[models.ScheduleResource]{}, Files: listFiles})
+    add("GET", "/api/schedules/group/:group_name", openapi.Op{Tag: "Расписание", Summary: "Занятия группы", Permissions: scheduleRead,
+        Query: pageQuery(scheduleFilters...), Response: models.Page[models.ScheduleResource]{}, Files: listFiles})
+    add("PUT", "/api/schedules/:id/overrides/:date", openapi.Op{Tag: "Расписание", Summary: "Отмена или перенос занятия на дату",
+        Permissions: scheduleWrite, Body: models.ScheduleOverride{}, Response: models.ScheduleOverride{}})
+    add("DELETE", "/api/schedules/:id/overrides/:date", openapi.Op{Tag: "Расписание", Summary: "Отмена переноса", Permissions: scheduleWrite,
+        Response: openapi.Message{}})
+
+    // Сетки расписания
+    gridQuery := []openapi.Parameter{
+        openapi.QueryParam("format", "string", "json, html или pdf"),
+        openapi.QueryParam("date", "string", "YYYY-MM-DD - неделя с отменами и переносами"),
+    }
+    for _, kind := range []string{"groups", "teachers", "classrooms"} {
+        add("GET", "/api/timetables/"+kind+"/:name", openapi.Op{Tag: "Сетки расписания", Summary: "Сетка \"дни × пары\"",
+            Permissions: scheduleRead, Query: gridQuery, Response: models.TimetableGrid{}, Files: gridFiles})
+    }
+    add("GET", "/api/timetables/poster", openapi.Op{Tag: "Сетки расписания", Summary: "Все группы рядом", Permissions: scheduleRead,
+        Query: gridQuery, Response: models.TimetablePoster{}, Files: gridFiles})
+
+    // Посещаемость
+    attendance := []string{models.PermAttendanceWrite, models.PermAttendanceWriteOwn}
+    add("POST", "/api/schedules/:id/attendance", openapi.Op{Tag: "Посещаемость", Summary: "Отметки на занятии", Permissions: attendance,
+        Description: "С правом :own-courses - только на своих занятиях", Body: models.AttendanceMarks{}, Response: []models.Attendance{}})
+    add("GET", "/api/schedules/:id/attendance", openapi.Op{Tag: "Посещаемость", Summary: "Отметки на занятии", Permissions: attendance,
+        Query: []openapi.Parameter{openapi.QueryParam("date", "string", "YYYY-MM-DD")}, Response: []models.Attendance{}})
+
+    // Объявления
+    announcements := []string{models.PermAnnouncementsWrite}
+    add("GET", "/api/announcements", openapi.Op{Tag: "Объявления", Summary: "Объявления", Permissions: announcements,
+        Query: listQuery(openapi.QueryParam("group_name", "string", "")), Response: []models.Announcement{}, Files: listFiles})
+    add("POST", "/api/announcements", openapi.Op{Tag: "Объявления", Summary: "Создание объявления", Permissions: announcements,
+        Body: models.Announcement{}, Status: http.StatusCreated, Response: models.Announcement{}})
+    add("DELETE", "/api/announcements/:id", openapi.Op{Tag: "Объявления", Summary: "Удаление объявления", Permissions: announcements,
+        Response: openapi.Message{}})
+
+    // Личный кабинет студента
+    portal := []string{models.PermPortalRead}
+    add("GET", "/api/me", openapi.Op{Tag: "Кабинет студента", Summary: "Профиль", Permissions: portal, Response: models.Student{}})
+    add("GET", "/api/me/timetable", openapi.Op{Tag: "Кабинет студента", Summary: "Расписание", Permissions: portal, Query: periodParams, Response: models.TimetableResource{}})
+    add("GET", "/api/me/grades", openapi.Op{Tag: "Кабинет студента", Summary: "Оценки", Permissions: portal, Response: []models.Grade{}})
+    add("GET", "/api/me/attendance", openapi.Op{Tag: "Кабинет студента", Summary: "Посещаемость", Permissions: portal, Query: periodParams, Response: []models.Attendance{}})
+    add("GET", "/api/me/courses", openapi.Op{Tag: "Кабинет студента", Summary: "Курсы", Permissions: portal, Response: []models.Course{}})
+    add("GET", "/api/me/announcements", openapi.Op{Tag: "Кабинет студента", Summary: "Объявления группы", Permissions: portal, Response: []models.Announcement{}})
+
+    // Кабинет представителя
+    guardian := []string{models.PermGuardianRead}
+    add("GET", "/api/guardian/students", openapi.Op{Tag: "Кабинет представителя", Summary: "Связанные студенты", Permissions: guardian, Response: []models.Student{}})
+    add("GET", "/api/guardian/students/:id/timetable", openapi.Op{Tag: "Кабинет представителя", Summary: "Расписание студента", Permissions: guardian,
+        Query: periodParams, Response: models.TimetableResource{}})
+    add("GET", "/api/guardian/students/:id/attendance", openapi.Op{Tag: "Кабинет представителя", Summary: "Посещаемость студента", Permissions: guardian,
+        Query: periodParams, Response: []models.Attendance{}})
+    add("GET", "/api/guardian/students/:id/grades", openapi.Op{Tag: "Кабинет представителя", Summary: "Оценки студента", Permissions: guardian,
+        Response: []models.Grade{}})
+
+    // Ведомости
+    sheets := []string{models.PermGradeSheetsManage}
+    add("POST", "/api/grade-sheets", openapi.Op{Tag: "Ведомости", Summary: "Создание черновика ведомости", Permissions: sheets,
+        Body: models.GradeSheet{}, Status: http.StatusCreated, Response: models.GradeSheet{}})
+    add("GET", "/api/grade-sheets", openapi.Op{Tag: "Ведомости", Summary: "Список ведомостей", Permissions: []string{models.PermGradeSheetsManage, models.PermGradesRead},
+        Query: listQuery(openapi.QueryParam("course_id", "integer", ""), openapi.QueryParam("status", "string", "")),
+        Response: []models.GradeSheet{}, Files: listFiles})
+    add("POST", "/api/grade-sheets/:id/issue", openapi.Op{Tag: "Ведомости", Summary: "Выдача экзаменатору", Permissions: sheets, Response: models.GradeSheet{}})
+    add("POST", "/api/grade-sheets/:id/close", openapi.Op{Tag: "Ведомости", Summary: "Закрытие заполненной ведомости", Permissions: sheets, Response: models.GradeSheet{}})
+    add("POST", "/api/grade-sheets/:id/retake", openapi.Op{Tag: "Ведомости", Summary: "Ведомость пересдачи для не сдавших", Permissions: sheets,
+        Description: "Пока по ведомости есть выданная или заполненная пересдача, новая - 409",
+        Body: models.GradeSheet{}, OptionalBody: true, Status: http.StatusCreated, Response: models.GradeSheet{}})
+    add("DELETE", "/api/grade-sheets/:id", openapi.Op{Tag: "Ведомости", Summary: "Удаление черновика", Permissions: sheets, Response: openapi.Message{}})
+    add("GET", "/api/grade-sheets/:id", openapi.Op{Tag: "Ведомости", Summary: "Ведомость с оценками",
+        Permissions: []string{models.PermGradesRead, models.PermGradesReadOwn}, Response: models.GradeSheet{}})
+    add("PATCH", "/api/grade-sheets/:id/marks", openapi.Op{Tag: "Ведомости", Summary: "Заполнение выданной ведомости",
+        Description: "С правом :own-courses - только своя ведомость",
+        Permissions: []string{models.PermGradesWrite, models.PermGradesWriteOwn}, Body: models.GradeSheetMarks{}, Response: models.GradeSheet{}})
+    add("GET", "/api/grade-sheets/:id/export", openapi.Op{Tag: "Ведомости", Summary: "Ведомость для печати",
+        Permissions: []string{models.PermGradesRead, models.PermGradesReadOwn},
+        Query: []openapi.Parameter{openapi.QueryParam("format", "string", "pdf (по умолчанию) или xlsx")},
+        Files: []string{contentPDF, contentXLSX}})
+
+    // Мониторинг: без версии и без токена
+    d.Add("GET", "/healthz", openapi.Op{Tag: "Мониторинг", Summary: "Процесс жив", Public: true, Response: models.HealthStatus{}})
+    d.Add("GET", "/readyz", openapi.Op{Tag: "Мониторинг", Summary: "Готовность принимать запросы", Public: true,
+        Description: "База доступна и все миграции применены; иначе 503 с результатами проверок", Response: models.HealthStatus{}})
+    d.Add("GET", "/metrics", openapi.Op{Tag: "Мониторинг", Summary: "Метрики в текстовом формате Prometheus", Public: true,
+        Files: []string{"text/plain"}})
+
+    // Устаревшие маршруты - последней группой
+    for i, tag := range d.Tags {
+        if tag.Name == legacyTag {
+            d.Tags = append(append(d.Tags[:i:i], d.Tags[i+1:]...), tag)
+            break
+        }
+    }
+    return d
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+    "backend/config"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "regexp"
+    "strings"
+    "testing"
+)
+
+// Каждый маршрут роутера описан в OpenAPI, и в описании нет лишних маршрутов
+func TestSpecCoversRoutes(t *testing.T) {
+    router := setupRouter(config.Default(), nil, nil, nil)
+    spec := apiSpec()
+
+    registered := map[string]bool{}
+    for _, route := range router.Routes() {
+        if !spec.Has(route.Method, route.Path) {
+            t.Errorf("route %s %s is missing from the OpenAPI spec (api_spec.go)", route.Method, route.Path)
+        }
+        path := regexp.MustCompile(`:(\w+)`).ReplaceAllString(route.Path, "{$1}")
+        registered[route.Method+" "+path] = true
+    }
+    for _, route := range spec.Routes() {
+        if !registered[route] {
+            t.Errorf("spec describes %s, which the router does not register", route)
+        }
+    }
+}
+
+// Описание отдаётся по /api/openapi.json, и все ссылки $ref указывают на существующие схемы
+func TestSpecServedAndRefsResolve(t *testing.T) {
+    router := setupRouter(config.Default(), nil, nil, nil)
+
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+    if w.Code != http.StatusOK {
+        t.Fatalf("GET /api/openapi.json = %d, want 200", w.Code)
+    }
+    var doc struct {
+        OpenAPI    string                    `json:"openapi"`
+        Paths      map[string]map[string]any `json:"paths"`
+        Components struct {
+            Schemas map[string]any `json:"schemas"`
+        } `json:"components"`
+    }
+    if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+        t.Fatalf("spec is not JSON: %v", err)
+    }
+    if !strings.HasPrefix(doc.OpenAPI, "3.") || len(doc.Paths) == 0 {
+        t.Fatalf("unexpected spec header: openapi=%q, %d paths", doc.OpenAPI, len(doc.Paths))
+    }
+    for _, match := range regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`).FindAllStringSubmatch(w.Body.String(), -1) {
+        if _, ok := doc.Components.Schemas[match[1]]; !ok {
+            t.Errorf("$ref to undefined schema %s", match[1])
+        }
+    }
+
+    w = httptest.NewRecorder()
+    router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+    if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"/api/openapi.json"`) {
+        t.Fatalf("GET /api/docs = %d, page does not load the spec", w.Code)
+    }
+}
+
+// Маршруты /api без версии помечены устаревшими и ссылаются на /api/v1
+func TestLegacyRoutesDeprecated(t *testing.T) {
+    router := setupRouter(config.Default(), nil, nil, nil)
+
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+    if w.Header().Get("Deprecation") == "" || w.Header().Get("Sunset") != "Wed, 30 Jun 2027 00:00:00 GMT" {
+        t.Errorf("legacy headers: Deprecation=%q Sunset=%q", w.Header().Get("Deprecation"), w.Header().Get("Sunset"))
+    }
+    if links := strings.Join(w.Header().Values("Link"), ", "); !strings.Contains(links, `</api/v1/openapi.json>; rel="successor-version"`) {
+        t.Errorf("legacy Link = %q", links)
+    }
+
+    w = httptest.NewRecorder()
+    router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+    if w.Code != http.StatusOK || w.Header().Get("Deprecation") != "" {
+        t.Errorf("v1: status %d, Deprecation=%q", w.Code, w.Header().Get("Deprecation"))
+    }
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+    "backend/config"
+    "backend/export"
+    "backend/migrate"
+    "backend/models"
+    "backend/repository"
+    "backend/services"
+    "crypto/rand"
+    "database/sql"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "time"
+)
+
+// cliUser автор изменений из командной строки: записи журнала аудита без пользователя (user_id пуст)
+const cliUser = 0
+
+// runCommand выполняет подкоманду; сервер и служебные команды используют одни и те же репозитории и сервисы
+func runCommand(command string, args []string, cfg *config.Config, db *sql.DB, runner *migrate.Runner) error {
+    switch command {
+    case "serve":
+        return serve(cfg, db, runner)
+    case "migrate":
+        // migrate up|down|status|goto - работа со схемой без запуска сервера
+        return runMigrate(runner, args)
+    }
+
+    // Остальным командам нужна актуальная схема
+    if err := runner.Check(); err != nil {
+        return err
+    }
+    pending, err := runner.Pending()
+    if err != nil {
+        return err
+    }
+    if len(pending) > 0 {
+        return fmt.Errorf("%d migration(s) not applied, run migrate up first", len(pending))
+    }
+
+    switch command {
+    case "create-admin":
+        return runCreateAdmin(cfg, db, args)
+    case "reset-password":
+        return runResetPassword(cfg, db, args)
+    case "seed":
+        return runSeed(db, args)
+    case "export":
+        return runExport(db, args)
+    case "import":
+        return runImport(db, args)
+    case "recalc-hours":
+        return runRecalcHours(db, args)
+    case "check-integrity":
+        return runCheckIntegrity(db)
+    }
+    printUsage()
+    return fmt.Errorf("unknown command: %s", command)
+}
+
+func printUsage() {
+    fmt.Println(`Usage: backend [command] [flags]
+
+Commands:
+  serve                                       start the HTTP server (default)
+  migrate up | down [N] | status | goto VERSION
+  create-admin --username NAME [--password P] create an administrator account
+  reset-password --username NAME [--password P]
+  seed [--force]                              fill an empty database with demo data
+  export --entity E [--format json|csv] [--out FILE]
+  import --entity E --file FILE               import a JSON array of records
+  recalc-hours [--dry-run]                    recalculate teacher working hours from the schedule
+  check-integrity                             report inconsistent data, exit code 1 if any
+
+Entities: teachers, students, courses, classrooms, schedules.
+If --password is omitted, ADMIN_PASSWORD is used or a random password is printed.`)
+}
+
+func newAuthService(cfg *config.Config, db *sql.DB) *services.AuthService {
+    return services.NewAuthService(repositories.NewUserRepository(db), repositories.NewUnitOfWork(db), cfg.JWT.Secret, time.Duration(cfg.JWT.TokenTTL))
+}
+
+// passwordOrGenerate возвращает пароль из флага, ADMIN_PASSWORD или случайный; generated = true, если его нужно показать
+func passwordOrGenerate(password string) (string, bool, error) {
+    if password != "" {
+        return password, false, nil
+    }
+    if env := os.Getenv("ADMIN_PASSWORD"); env != "" {
+        return env, false, nil
+    }
+    buf := make([]byte, 12)
+    if _, err := rand.Read(buf); err != nil {
+        return "", false, err
+    }
+    return hex.EncodeToString(buf), true, nil
+}
+
+// runCreateAdmin создаёт учётную запись администратора
+func runCreateAdmin(cfg *config.Config, db *sql.DB, args []string) error {
+    fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+    username := fs.String("username", "admin", "login of the new administrator")
+    password := fs.String("password", "", "password (default: ADMIN_PASSWORD or random)")
+    if err := fs.Parse(args); err != nil {
+        return err
+    }
+
+    pass, generated, err := passwordOrGenerate(*password)
+    if err != nil {
+        return err
+    }
+    user, err := newAuthService(cfg, db).Register(*username, pass, "admin", cliUser)
+    if err != nil {
+        return err
+    }
+
+    fmt.Printf("Created admin '%s' (id %d)\n", user.Username, user.ID)
+    if generated {
+        fmt.Printf("Generated password: %s\n", pass)
+    }
+    return nil
+}
+
+// runResetPassword задаёт новый пароль существующему пользователю
+func runResetPassword(cfg *config.Config, db *sql.DB, args []string) error {
+    fs := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+    username := fs.String("username", "", "login of the user")
+    password := fs.String("password", "", "new password (default: ADMIN_PASSWORD or random)")
+    if err := fs.Parse(args); err != nil {
+        return err
+    }
+    if *username == "" {
+        return errors.New("--username is required")
+    }
+
+    pass, generated, err := passwordOrGenerate(*password)
+    if err != nil {
+        return err
+    }
+    if err := newAuthService(cfg, db).ResetPassword(*username, pass, cliUser); err != nil {
+        return err
+    }
+
+    fmt.Printf("Password for '%s' has been reset\n", *username)
+    if generated {
+        fmt.Printf("Generated password: %s\n", pass)
+    }
+    return nil
+}
+
+// runSeed заполняет базу демонстрационными данными через обычные сервисы, с записями в журнале аудита
+func runSeed(db *sql.DB, args []string) error {
+    fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+    force := fs.Bool("force", false, "seed even if the database already has data")
+    if err := fs.Parse(args); err != nil {
+        return err
+    }
+
+    teacherRepo := repositories.NewTeacherRepository(db)
+    uow := repositories.NewUnitOfWork(db)
+    classroomService := services.NewClassroomService(repositories.NewClassroomRepository(db), uow)
+    teacherService := services.NewTeacherService(teacherRepo, uow)
+    courseService := services.NewCourseService(repositories.NewCourseRepository(db), uow)
+    studentService := services.NewStudentService(repositories.NewStudentRepository(db), uow)
+    scheduleService := services.NewScheduleService(repositories.NewScheduleRepository(db), teacherRepo, uow)
+
+    existing, err := courseService.GetCourses()
+    if err != nil {
+        return err
+    }
+    if len(existing) > 0 && !*force {
+        return errors.New("database is not empty, use --force to seed anyway")
+    }
+
+    classrooms := []models.Classroom{
+        {Name: "Аудитория 101", Capacity: 30, Description: "Лекционная"},
+        {Name: "Аудитория 202", Capacity: 20, Description: "Компьютерный класс"},
+    }
+    for i := range classrooms {
+        if err := classroomService.CreateClassroom(&classrooms[i], cliUser); err != nil {
+            return fmt.Errorf("classroom '%s': %v", classrooms[i].Name, err)
+        }
+    }
+
+    // Курсы преподавателю назначаются при создании курса
+    teachers := []models.Teacher{
+        {Name: "Иванова Мария Петровна", Subject: "Математика", WorkingHours: 120},
+        {Name: "Смирнов Алексей Игоревич", Subject: "Программирование", WorkingHours: 120},
+    }
+    for i := range teachers {
+        if err := teacherService.CreateTeacher(&teachers[i], cliUser); err != nil {
+            return fmt.Errorf("teacher '%s': %v", teachers[i].Name, err)
+        }
+    }
+
+    courses := []models.Course{
+        {Name: "МАТ-101", Description: "Высшая математика", TeacherID: &teachers[0].ID},
+        {Name: "ПРГ-101", Description: "Основы программирования", TeacherID: &teachers[1].ID},
+    }
+    for i := range courses {
+        if err := courseService.CreateCourse(&courses[i], cliUser); err != nil {
+            return fmt.Errorf("course '%s': %v", courses[i].Name, err)
+        }
+    }
+
+    students := []models.Student{
+        {Name: "Кузнецов Дмитрий", DateOfBirth: "2005-03-14", GroupName: "МАТ-101"},
+        {Name: "Попова Анна", DateOfBirth: "2004-11-02", GroupName: "МАТ-101"},
+        {Name: "Соколов Илья", DateOfBirth: "2006-07-21", GroupName: "ПРГ-101"},
+        {Name: "Лебедева Ольга", DateOfBirth: "2005-01-30", GroupName: "ПРГ-101"},
+    }
+    for i := range students {
+        if err := studentService.CreateStudent(&students[i], cliUser); err != nil {
+            return fmt.Errorf("student '%s': %v", students[i].Name, err)
+        }
+    }
+
+    // Занятия по 90 минут; дата - опорный день нужной недели, важно только время
+    lessons := []struct {
+        teacher, classroom int
+        group, day         string
+        date               string
+    }{
+        {0, 0, "МАТ-101", "Monday", "2025-01-06"},
+        {0, 0, "МАТ-101", "Wednesday", "2025-01-08"},
+        {1, 1, "ПРГ-101", "Tuesday", "2025-01-07"},
+        {1, 1, "ПРГ-101", "Thursday", "2025-01-09"},
+    }
+    for _, lesson := range lessons {
+        start, err := time.Parse("2006-01-02 15:04", lesson.date+" 09:00")
+        if err != nil {
+            return err
+        }
+        schedule := &models.Schedule{
+            GroupName: lesson.group,
+            StartTime: start,
+            EndTime:   start.Add(90 * time.Minute),
+            DayOfWeek: lesson.day,
+        }
+        if err := scheduleService.CreateSchedule(teachers[lesson.teacher].ID, classrooms[lesson.classroom].ID, schedule, cliUser); err != nil {
+            return fmt.Errorf("schedule %s %s: %v", lesson.group, lesson.day, err)
+        }
+    }
+
+    fmt.Printf("Seeded %d classrooms, %d teachers, %d courses, %d students, %d lessons\n",
+        len(classrooms), len(teachers), len(courses), len(students), len(lessons))
+    return nil
+}
+
+// loadEntities возвращает все записи сущности для выгрузки
+func loadEntities(db *sql.DB, entity string) (interface{}, error) {
+    switch entity {
+    case "teachers":
+        return services.NewTeacherService(repositories.NewTeacherRepository(db), repositories.NewUnitOfWork(db)).GetAllTeachers()
+    case "students":
+        return services.NewStudentService(repositories.NewStudentRepository(db), repositories.NewUnitOfWork(db)).GetStudents("")
+    case "courses":
+        return services.NewCourseService(repositories.NewCourseRepository(db), repositories.NewUnitOfWork(db)).GetCourses()
+    case "classrooms":
+        return services.NewClassroomService(repositories.NewClassroomRepository(db), repositories.NewUnitOfWork(db)).GetClassrooms()
+    case "schedules":
+        return services.NewScheduleService(repositories.NewScheduleRepository(db), repositories.NewTeacherRepository(db), repositories.NewUnitOfWork(db)).GetSchedules()
+    }
+    return nil, fmt.Errorf("unknown entity: %s", entity)
+}
+
+// runExport выгружает сущность в JSON или CSV
+func runExport(db *sql.DB, args []string) error {
+    fs := flag.NewFlagSet("export", flag.ContinueOnError)
+    entity := fs.String("entity", "", "teachers, students, courses, classrooms or schedules")
+    format := fs.String("format", "json", "json or csv")
+    out := fs.String("out", "", "output file (default: stdout)")
+    if err := fs.Parse(args); err != nil {
+        return err
+    }
+    if *format != "json" && *format != "csv" {
+        return fmt.Errorf("unsupported format: %s", *format)
+    }
+
+    items, err := loadEntities(db, *entity)
+    if err != nil {
+        return err
+    }
+
+    var w io.Writer = os.Stdout
+    if *out != "" {
+        file, err := os.Create(*out)
+        if err != nil {
+            return err
+        }
+        defer file.Close()
+        w = file
+    }
+
+    if *format == "csv" {
+        table, err := export.FromStructs(items)
+        if err != nil {
+            return err
+        }
+        return export.WriteCSV(w, table)
+    }
+    encoder := json.NewEncoder(w)
+    encoder.SetIndent("", "  ")
+    return encoder.Encode(items)
+}
+
+// scheduleImport запись расписания в файле импорта: преподаватель и аудитория по ID
+type scheduleImport struct {
+    TeacherID   int       `json:"teacher_id"`
+    ClassroomID int       `json:"classroom_id"`
+    GroupName   string    `json:"group_name"`
+    StartTime   time.Time `json:"start_time"`
+    EndTime     time.Time `json:"end_time"`
+    DayOfWeek   string    `json:"day_of_week"`
+    WeekType    string    `json:"week_type"`
+}
+
+// importRecords разбирает JSON-массив и создаёт записи по одной; ошибки собираются, а не прерывают импорт
+func importRecords[T any](data []byte, create func(*T) error) (int, []string, error) {
+    var records []T
+    if err := json.Unmarshal(data, &records); err != nil {
+        return 0, nil, fmt.Errorf("invalid JSON: %v", err)
+    }
+
+    created := 0
+    var failures []string
+    for i := range records {
+        if err := create(&records[i]); err != nil {
+            failures = append(failures, fmt.Sprintf("record %d: %v", i+1, err))
+            continue
+        }
+        created++
+    }
+    return created, failures, nil
+}
+
+// runImport загружает JSON-массив записей через методы создания сервисов; каждая запись попадает в журнал аудита
+func runImport(db *sql.DB, args []string) error {
+    fs := flag.NewFlagSet("import", flag.ContinueOnError)
+    entity := fs.String("entity", "", "teachers, students, courses, classrooms or schedules")
+    file := fs.String("file", "", "JSON file with an array of records")
+    if err := fs.Parse(args); err != nil {
+        return err
+    }
+    if *file == "" {
+        return errors.New("--file is required")
+    }
+
+    data, err := os.ReadFile(*file)
+    if err != nil {
+        return err
+    }
+
+    var created int
+    var failures []string
+    switch *entity {
+    case "teachers":
+        service := services.NewTeacherService(repositories.NewTeacherRepository(db), repositories.NewUnitOfWork(db))
+        created, failures, err = importRecords(data, func(teacher *models.Teacher) error { return service.CreateTeacher(teacher, cliUser) })
+    case "students":
+        service := services.NewStudentService(repositories.NewStudentRepository(db), repositories.NewUnitOfWork(db))
+        created, failures, err = importRecords(data, func(student *models.Student) error { return service.CreateStudent(student, cliUser) })
+    case "courses":
+        service := services.NewCourseService(repositories.NewCourseRepository(db), repositories.NewUnitOfWork(db))
+        created, failures, err = importRecords(data, func(course *models.Course) error { return service.CreateCourse(course, cliUser) })
+    case "classrooms":
+        service := services.NewClassroomService(repositories.NewClassroomRepository(db), repositories.NewUnitOfWork(db))
+        created, failures, err = importRecords(data, func(classroom *models.Classroom) error { return service.CreateClassroom(classroom, cliUser) })
+    case "schedules":
+        service := services.NewScheduleService(repositories.NewScheduleRepository(db), repositories.NewTeacherRepository(db), repositories.NewUnitOfWork(db))
+        created, failures, err = importRecords(data, func(record *scheduleImport) error {
+            return service.CreateSchedule(record.TeacherID, record.ClassroomID, &models.Schedule{
+                GroupName: record.GroupName,
+                StartTime: record.StartTime,
+                EndTime:   record.EndTime,
+                DayOfWeek: record.DayOfWeek,
+                WeekType:  record.WeekType,
+            }, cliUser)
+        })
+    default:
+        return fmt.Errorf("unknown entity: %s", *entity)
+    }
+    if err != nil {
+        return err
+    }
+
+    fmt.Printf("Imported %d %s\n", created, *entity)
+    for _, failure := range failures {
+        fmt.Println("  " + failure)
+    }
+    if len(failures) > 0 {
+        return fmt.Errorf("%d record(s) failed", len(failures))
+    }
+    return nil
+}
+
+// runRecalcHours приводит остаток часов преподавателей в соответствие с расписанием; исправления пишутся в журнал аудита
+func runRecalcHours(db *sql.DB, args []string) error {
+    fs := flag.NewFlagSet("recalc-hours", flag.ContinueOnError)
+    dryRun := fs.Bool("dry-run", false, "only show the differences")
+    if err := fs.Parse(args); err != nil {
+        return err
+    }
+
+    service := services.NewTeacherService(repositories.NewTeacherRepository(db), repositories.NewUnitOfWork(db))
+    changes, err := service.RecalculateWorkingHours(!*dryRun, cliUser)
+    if err != nil {
+        return err
+    }
+
+    for _, change := range changes {
+        fmt.Printf("%d  %-40s %8.2f -> %8.2f\n", change.TeacherID, change.TeacherName, change.OldHours, change.NewHours)
+    }
+    switch {
+    case len(changes) == 0:
+        fmt.Println("Working hours match the schedule")
+    case *dryRun:
+        fmt.Printf("%d teacher(s) would be updated\n", len(changes))
+    default:
+        fmt.Printf("Updated %d teacher(s)\n", len(changes))
+    }
+    return nil
+}
+
+// runCheckIntegrity выводит найденные нарушения; при их наличии команда завершается с ошибкой
+func runCheckIntegrity(db *sql.DB) error {
+    service := services.NewIntegrityService(repositories.NewIntegrityRepository(db))
+    issues, err := service.CheckIntegrity()
+    if err != nil {
+        return err
+    }
+
+    for _, issue := range issues {
+        fmt.Printf("[%s] %s %d: %s\n", issue.Check, issue.EntityType, issue.EntityID, issue.Details)
+    }
+    if len(issues) > 0 {
+        return fmt.Errorf("found %d integrity issue(s)", len(issues))
+    }
+    fmt.Println("No integrity issues found")
+    return nil
+}
\ No newline at end of file
@@ -0,0 +1,373 @@
+package config
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/pelletier/go-toml/v2"
+    "gopkg.in/yaml.v3"
+)
+
+// Режимы работы приложения
+const (
+    EnvDevelopment = "development"
+    EnvProduction  = "production"
+)
+
+// DefaultJWTSecret секрет для локальной разработки; в production запуск с ним запрещён
+const DefaultJWTSecret = "your_secret_key"
+
+const redacted = "***"
+
+// Duration длительность, которая читается из конфигурации строкой ("30m", "24h")
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+    return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+    value, err := time.ParseDuration(string(text))
+    if err != nil {
+        return err
+    }
+    *d = Duration(value)
+    return nil
+}
+
+type ServerConfig struct {
+    Port int `json:"port" yaml:"port" toml:"port"`
+
+    // Таймауты соединения: медленный клиент не держит соединение бесконечно
+    ReadHeaderTimeout Duration `json:"read_header_timeout" yaml:"read_header_timeout" toml:"read_header_timeout"`
+    ReadTimeout       Duration `json:"read_timeout" yaml:"read_timeout" toml:"read_timeout"`
+    WriteTimeout      Duration `json:"write_timeout" yaml:"write_timeout" toml:"write_timeout"` // Включает выгрузку файлов
+    IdleTimeout       Duration `json:"idle_timeout" yaml:"idle_timeout" toml:"idle_timeout"`
+    // Сколько ждать завершения начатых запросов и фоновых задач после SIGTERM
+    ShutdownTimeout Duration `json:"shutdown_timeout" yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+
+    // Предел тела запроса в байтах; у загрузки файлов импорта свой предел (10 МБ)
+    MaxBodyBytes int `json:"max_body_bytes" yaml:"max_body_bytes" toml:"max_body_bytes"`
+
+    // HTTPS: оба файла или ни одного
+    TLSCertFile string `json:"tls_cert_file" yaml:"tls_cert_file" toml:"tls_cert_file"`
+    TLSKeyFile  string `json:"tls_key_file" yaml:"tls_key_file" toml:"tls_key_file"`
+
+    // Источники (https://college.example.com), с которых браузеру разрешены запросы к API; "*" - любые
+    CORSOrigins []string `json:"cors_origins" yaml:"cors_origins" toml:"cors_origins"`
+
+    // Дата (YYYY-MM-DD), после которой маршруты /api без версии могут быть удалены; отдаётся в заголовке Sunset
+    LegacyAPISunset string `json:"legacy_api_sunset" yaml:"legacy_api_sunset" toml:"legacy_api_sunset"`
+}
+
+// TLS сообщает, что сервер должен работать по HTTPS
+func (c ServerConfig) TLS() bool {
+    return c.TLSCertFile != ""
+}
+
+// LegacySunset дата отключения маршрутов /api без версии
+func (c ServerConfig) LegacySunset() time.Time {
+    sunset, _ := time.Parse("2006-01-02", c.LegacyAPISunset) // Формат проверяется в Validate
+    return sunset
+}
+
+type DatabaseConfig struct {
+    Host     string `json:"host" yaml:"host" toml:"host"`
+    Port     int    `json:"port" yaml:"port" toml:"port"`
+    User     string `json:"user" yaml:"user" toml:"user"`
+    Password string `json:"password" yaml:"password" toml:"password"`
+    Name     string `json:"name" yaml:"name" toml:"name"`
+    SSLMode  string `json:"sslmode" yaml:"sslmode" toml:"sslmode"`
+
+    // Пул соединений
+    MaxOpenConns    int      `json:"max_open_conns" yaml:"max_open_conns" toml:"max_open_conns"`
+    MaxIdleConns    int      `json:"max_idle_conns" yaml:"max_idle_conns" toml:"max_idle_conns"`
+    ConnMaxLifetime Duration `json:"conn_max_lifetime" yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+    ConnMaxIdleTime Duration `json:"conn_max_idle_time" yaml:"conn_max_idle_time" toml:"conn_max_idle_time"`
+
+    // Применять встроенные миграции при запуске сервера
+    AutoMigrate bool `json:"auto_migrate" yaml:"auto_migrate" toml:"auto_migrate"`
+}
+
+// DSN строка подключения для lib/pq
+func (c DatabaseConfig) DSN() string {
+    return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+        quoteDSN(c.Host), c.Port, quoteDSN(c.User), quoteDSN(c.Password), quoteDSN(c.Name), quoteDSN(c.SSLMode))
+}
+
+// quoteDSN экранирует значение для формата key=value
+func quoteDSN(value string) string {
+    if value != "" && !strings.ContainsAny(value, ` '\`) {
+        return value
+    }
+    value = strings.ReplaceAll(value, `\`, `\\`)
+    value = strings.ReplaceAll(value, `'`, `\'`)
+    return "'" + value + "'"
+}
+
+// LogConfig журнал приложения: уровень и формат записей
+type LogConfig struct {
+    Level  string `json:"level" yaml:"level" toml:"level"`    // debug, info, warn, error
+    Format string `json:"format" yaml:"format" toml:"format"` // text или json
+}
+
+type JWTConfig struct {
+    Secret   string   `json:"secret" yaml:"secret" toml:"secret"`
+    TokenTTL Duration `json:"token_ttl" yaml:"token_ttl" toml:"token_ttl"`
+}
+
+// Config настройки приложения. Порядок источников: значения по умолчанию,
+// файл YAML/TOML (CONFIG_FILE), переменные окружения
+type Config struct {
+    Env      string         `json:"env" yaml:"env" toml:"env"`
+    Server   ServerConfig   `json:"server" yaml:"server" toml:"server"`
+    Database DatabaseConfig `json:"database" yaml:"database" toml:"database"`
+    JWT      JWTConfig      `json:"jwt" yaml:"jwt" toml:"jwt"`
+    SMTP     EmailConfig    `json:"smtp" yaml:"smtp" toml:"smtp"`
+    Log      LogConfig      `json:"log" yaml:"log" toml:"log"`
+}
+
+// Default возвращает настройки для локальной разработки (совпадают с docker-compose)
+func Default() *Config {
+    return &Config{
+        Env:    EnvDevelopment,
+        Server: ServerConfig{
+            Port:              8080,
+            ReadHeaderTimeout: Duration(5 * time.Second),
+            ReadTimeout:       Duration(30 * time.Second),
+            WriteTimeout:      Duration(2 * time.Minute),
+            IdleTimeout:       Duration(2 * time.Minute),
+            ShutdownTimeout:   Duration(30 * time.Second),
+            MaxBodyBytes:      1 << 20,
+            LegacyAPISunset:   "2027-06-30",
+        },
+        Database: DatabaseConfig{
+            Host:            "db",
+            Port:            5432,
+            User:            "admin",
+            Password:        "password",
+            Name:            "college",
+            SSLMode:         "disable",
+            MaxOpenConns:    20,
+            MaxIdleConns:    10,
+            ConnMaxLifetime: Duration(30 * time.Minute),
+            ConnMaxIdleTime: Duration(5 * time.Minute),
+        },
+        JWT: JWTConfig{
+            Secret:   DefaultJWTSecret,
+            TokenTTL: Duration(24 * time.Hour),
+        },
+        SMTP: EmailConfig{
+            Host: "smtp.example.com",
+            Port: 587,
+        },
+        Log: LogConfig{
+            Level:  "info",
+            Format: "text",
+        },
+    }
+}
+
+// Load собирает конфигурацию; path - необязательный файл .yaml/.yml/.toml
+func Load(path string) (*Config, error) {
+    cfg := Default()
+
+    if path != "" {
+        if err := cfg.loadFile(path); err != nil {
+            return nil, err
+        }
+    }
+    if err := cfg.loadEnv(); err != nil {
+        return nil, err
+    }
+    if err := cfg.Validate(); err != nil {
+        return nil, err
+    }
+    return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return fmt.Errorf("failed to read config file: %v", err)
+    }
+
+    switch strings.ToLower(filepath.Ext(path)) {
+    case ".yaml", ".yml":
+        err = yaml.Unmarshal(data, c)
+    case ".toml":
+        err = toml.Unmarshal(data, c)
+    default:
+        return fmt.Errorf("unsupported config file format: %s (use .yaml, .yml or .toml)", path)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to parse config file %s: %v", path, err)
+    }
+    return nil
+}
+
+// loadEnv переопределяет настройки переменными окружения
+func (c *Config) loadEnv() error {
+    var errs []error
+    str := func(name string, target *string) {
+        if value, ok := os.LookupEnv(name); ok {
+            *target = value
+        }
+    }
+    num := func(name string, target *int) {
+        if value, ok := os.LookupEnv(name); ok {
+            n, err := strconv.Atoi(value)
+            if err != nil {
+                errs = append(errs, fmt.Errorf("%s: invalid number %q", name, value))
+                return
+            }
+            *target = n
+        }
+    }
+    flag := func(name string, target *bool) {
+        if value, ok := os.LookupEnv(name); ok {
+            b, err := strconv.ParseBool(value)
+            if err != nil {
+                errs = append(errs, fmt.Errorf("%s: invalid boolean %q", name, value))
+                return
+            }
+            *target = b
+        }
+    }
+    list := func(name string, target *[]string) {
+        if value, ok := os.LookupEnv(name); ok {
+            *target = nil
+            for _, item := range strings.Split(value, ",") {
+                if item = strings.TrimSpace(item); item != "" {
+                    *target = append(*target, item)
+                }
+            }
+        }
+    }
+    dur := func(name string, target *Duration) {
+        if value, ok := os.LookupEnv(name); ok {
+            if err := target.UnmarshalText([]byte(value)); err != nil {
+                errs = append(errs, fmt.Errorf("%s: invalid duration %q", name, value))
+            }
+        }
+    }
+
+    str("APP_ENV", &c.Env)
+    num("PORT", &c.Server.Port)
+    dur("SERVER_READ_HEADER_TIMEOUT", &c.Server.ReadHeaderTimeout)
+    dur("SERVER_READ_TIMEOUT", &c.Server.ReadTimeout)
+    dur("SERVER_WRITE_TIMEOUT", &c.Server.WriteTimeout)
+    dur("SERVER_IDLE_TIMEOUT", &c.Server.IdleTimeout)
+    dur("SERVER_SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+    num("SERVER_MAX_BODY_BYTES", &c.Server.MaxBodyBytes)
+    str("TLS_CERT_FILE", &c.Server.TLSCertFile)
+    str("TLS_KEY_FILE", &c.Server.TLSKeyFile)
+    list("CORS_ORIGINS", &c.Server.CORSOrigins)
+    str("API_LEGACY_SUNSET", &c.Server.LegacyAPISunset)
+
+    str("DB_HOST", &c.Database.Host)
+    num("DB_PORT", &c.Database.Port)
+    str("DB_USER", &c.Database.User)
+    str("DB_PASSWORD", &c.Database.Password)
+    str("DB_NAME", &c.Database.Name)
+    str("DB_SSLMODE", &c.Database.SSLMode)
+    num("DB_MAX_OPEN_CONNS", &c.Database.MaxOpenConns)
+    num("DB_MAX_IDLE_CONNS", &c.Database.MaxIdleConns)
+    dur("DB_CONN_MAX_LIFETIME", &c.Database.ConnMaxLifetime)
+    dur("DB_CONN_MAX_IDLE_TIME", &c.Database.ConnMaxIdleTime)
+    flag("DB_AUTO_MIGRATE", &c.Database.AutoMigrate)
+
+    str("JWT_SECRET", &c.JWT.Secret)
+    dur("JWT_TOKEN_TTL", &c.JWT.TokenTTL)
+
+    str("SMTP_HOST", &c.SMTP.Host)
+    num("SMTP_PORT", &c.SMTP.Port)
+    str("SMTP_USERNAME", &c.SMTP.Username)
+    str("SMTP_PASSWORD", &c.SMTP.Password)
+    str("SMTP_FROM", &c.SMTP.From)
+
+    str("LOG_LEVEL", &c.Log.Level)
+    str("LOG_FORMAT", &c.Log.Format)
+
+    return errors.Join(errs...)
+}
+
+// Validate проверяет настройки и возвращает все найденные ошибки сразу
+func (c *Config) Validate() error {
+    var errs []error
+    check := func(ok bool, format string, args ...interface{}) {
+        if !ok {
+            errs = append(errs, fmt.Errorf(format, args...))
+        }
+    }
+
+    check(c.Env == EnvDevelopment || c.Env == EnvProduction, "env must be %q or %q, got %q", EnvDevelopment, EnvProduction, c.Env)
+    check(c.Server.Port > 0 && c.Server.Port < 65536, "server.port must be between 1 and 65535")
+    check(c.Server.ReadHeaderTimeout > 0 && c.Server.ReadTimeout > 0 && c.Server.WriteTimeout > 0 && c.Server.IdleTimeout > 0,
+        "server timeouts must be positive")
+    check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+    check(c.Server.MaxBodyBytes > 0, "server.max_body_bytes must be positive")
+    check((c.Server.TLSCertFile == "") == (c.Server.TLSKeyFile == ""), "server.tls_cert_file and server.tls_key_file must be set together")
+    for _, origin := range c.Server.CORSOrigins {
+        check(origin == "*" || strings.HasPrefix(origin, "http://") || strings.HasPrefix(origin, "https://"),
+            "server.cors_origins: %q must be \"*\" or start with http:// or https://", origin)
+    }
+    _, err := time.Parse("2006-01-02", c.Server.LegacyAPISunset)
+    check(err == nil, "server.legacy_api_sunset must be a date YYYY-MM-DD, got %q", c.Server.LegacyAPISunset)
+
+    check(c.Database.Host != "", "database.host is required")
+    check(c.Database.Port > 0 && c.Database.Port < 65536, "database.port must be between 1 and 65535")
+    check(c.Database.User != "", "database.user is required")
+    check(c.Database.Name != "", "database.name is required")
+    check(c.Database.MaxOpenConns >= 0, "database.max_open_conns must not be negative")
+    check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns must not be negative")
+    check(c.Database.MaxOpenConns == 0 || c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+        "database.max_idle_conns must not exceed database.max_open_conns")
+    check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime must not be negative")
+    check(c.Database.ConnMaxIdleTime >= 0, "database.conn_max_idle_time must not be negative")
+
+    check(c.JWT.Secret != "", "jwt.secret is required")
+    check(c.JWT.TokenTTL > 0, "jwt.token_ttl must be positive")
+    if c.Env == EnvProduction {
+        check(c.JWT.Secret != DefaultJWTSecret, "jwt.secret must be changed from the default value in production (set JWT_SECRET)")
+        check(len(c.JWT.Secret) >= 32, "jwt.secret must be at least 32 characters in production")
+    }
+
+    check(c.SMTP.Port > 0 && c.SMTP.Port < 65536, "smtp.port must be between 1 and 65535")
+
+    switch c.Log.Level {
+    case "debug", "info", "warn", "error":
+    default:
+        check(false, "log.level must be debug, info, warn or error, got %q", c.Log.Level)
+    }
+    check(c.Log.Format == "text" || c.Log.Format == "json", "log.format must be \"text\" or \"json\", got %q", c.Log.Format)
+
+    if len(errs) > 0 {
+        return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+    }
+    return nil
+}
+
+// IsProduction сообщает, запущено ли приложение в production
+func (c *Config) IsProduction() bool {
+    return c.Env == EnvProduction
+}
+
+// Redacted возвращает копию настроек без паролей и секретов
+func (c *Config) Redacted() Config {
+    copy := *c
+    hide := func(value *string) {
+        if *value != "" {
+            *value = redacted
+        }
+    }
+    hide(&copy.Database.Password)
+    hide(&copy.JWT.Secret)
+    hide(&copy.SMTP.Password)
+    return copy
+}
\ No newline at end of file
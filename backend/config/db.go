@@ -3,23 +3,30 @@ package config
 import (
     "database/sql"
     "fmt"
-    "log"
+    "log/slog"
+    "time"
 
     _ "github.com/lib/pq"
 )
 
-func ConnectDB() *sql.DB {
-    connStr := "host=db port=5432 user=admin password=password dbname=college sslmode=disable"
-    db, err := sql.Open("postgres", connStr)
+// ConnectDB открывает пул соединений с настройками из конфигурации
+func ConnectDB(cfg DatabaseConfig) (*sql.DB, error) {
+    db, err := sql.Open("postgres", cfg.DSN())
     if err != nil {
-        log.Fatal(err)
+        return nil, err
     }
 
+    db.SetMaxOpenConns(cfg.MaxOpenConns)
+    db.SetMaxIdleConns(cfg.MaxIdleConns)
+    db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime))
+    db.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime))
+
     err = db.Ping()
     if err != nil {
-        log.Fatal(err)
+        db.Close()
+        return nil, fmt.Errorf("failed to connect to the database: %w", err)
     }
 
-    fmt.Println("Connected to the database")
-    return db
-}
\ No newline at end of file
+    slog.Info("connected to the database", "host", cfg.Host, "name", cfg.Name)
+    return db, nil
+}
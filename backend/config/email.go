@@ -1,19 +1,9 @@
 package config
 
 type EmailConfig struct {
-    Host     string
-    Port     int
-    Username string
-    Password string
-    From     string
-}
-
-func GetEmailConfig() *EmailConfig {
-    return &EmailConfig{
-        Host:     "smtp.example.com", // SMTP-сервер (например, Gmail: smtp.gmail.com)
-        Port:     587,               // Порт SMTP (обычно 587 для TLS)
-        Username: "your-email@example.com", // Ваш email
-        Password: "your-email-password",   // Пароль от email
-        From:     "your-email@example.com", // Отправитель
-    }
+    Host     string `json:"host" yaml:"host" toml:"host"`             // SMTP-сервер (например, Gmail: smtp.gmail.com)
+    Port     int    `json:"port" yaml:"port" toml:"port"`             // Порт SMTP (обычно 587 для TLS)
+    Username string `json:"username" yaml:"username" toml:"username"`
+    Password string `json:"password" yaml:"password" toml:"password"`
+    From     string `json:"from" yaml:"from" toml:"from"`             // Отправитель
 }
\ No newline at end of file
@@ -0,0 +1,99 @@
+package export
+
+import (
+    "encoding/csv"
+    "fmt"
+    "io"
+    "reflect"
+    "strings"
+    "time"
+)
+
+// WriteCSV записывает таблицу в CSV (только шапку и строки)
+func WriteCSV(w io.Writer, t Table) error {
+    writer := csv.NewWriter(w)
+    if err := writer.Write(t.Headers); err != nil {
+        return err
+    }
+    for _, row := range t.Rows {
+        if err := writer.Write(row); err != nil {
+            return err
+        }
+    }
+    writer.Flush()
+    return writer.Error()
+}
+
+// FromStructs строит таблицу из среза структур; колонки - поля с json-тегами
+func FromStructs(items interface{}) (Table, error) {
+    value := reflect.ValueOf(items)
+    if value.Kind() != reflect.Slice {
+        return Table{}, fmt.Errorf("expected slice, got %s", value.Kind())
+    }
+    columns, err := ListColumns(value.Type().Elem(), nil)
+    if err != nil {
+        return Table{}, err
+    }
+
+    var t Table
+    for _, column := range columns {
+        t.Headers = append(t.Headers, column.Name)
+    }
+    for i := 0; i < value.Len(); i++ {
+        item := reflect.Indirect(value.Index(i))
+        row := make([]string, len(columns))
+        for j, column := range columns {
+            row[j] = FormatValue(item.Field(column.index))
+        }
+        t.Rows = append(t.Rows, row)
+    }
+    return t, nil
+}
+
+// jsonName возвращает имя поля из json-тега; пустая строка - поле не выгружается
+func jsonName(field reflect.StructField) string {
+    if !field.IsExported() {
+        return ""
+    }
+    tag := field.Tag.Get("json")
+    if tag == "-" {
+        return ""
+    }
+    name := strings.Split(tag, ",")[0]
+    if name == "" {
+        name = field.Name
+    }
+    return name
+}
+
+// FormatValue приводит значение поля к строке для таблицы
+func FormatValue(value reflect.Value) string {
+    for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+        if value.IsNil() {
+            return ""
+        }
+        value = value.Elem()
+    }
+
+    if t, ok := value.Interface().(time.Time); ok {
+        if t.IsZero() {
+            return ""
+        }
+        return t.Format(time.RFC3339)
+    }
+
+    switch value.Kind() {
+    case reflect.Slice, reflect.Array:
+        if value.Type().Elem().Kind() == reflect.Uint8 {
+            return string(value.Bytes())
+        }
+        parts := make([]string, value.Len())
+        for i := range parts {
+            parts[i] = FormatValue(value.Index(i))
+        }
+        return strings.Join(parts, "; ")
+    case reflect.Float32, reflect.Float64:
+        return fmt.Sprintf("%g", value.Float())
+    }
+    return fmt.Sprint(value.Interface())
+}
\ No newline at end of file
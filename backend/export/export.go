@@ -0,0 +1,227 @@
+package export
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "unicode/utf8"
+
+    "github.com/go-pdf/fpdf"
+    "github.com/xuri/excelize/v2"
+)
+
+// Table описывает документ для выгрузки: заголовок, строки шапки, таблицу и подписи
+type Table struct {
+    Title   string
+    Meta    []string // Строки под заголовком (номер, дата, преподаватель и т.п.)
+    Headers []string
+    Rows    [][]string
+    Footer  []string // Строки под таблицей (подписи)
+}
+
+// Шрифты с кириллицей: Debian/Ubuntu и Alpine (пакет font-dejavu)
+var fontPaths = []string{
+    "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+    "/usr/share/fonts/dejavu/DejaVuSans.ttf",
+    "/usr/share/fonts/TTF/DejaVuSans.ttf",
+}
+
+// FontPath возвращает путь к TTF-шрифту для PDF; PDF_FONT_PATH имеет приоритет
+func FontPath() (string, error) {
+    if path := os.Getenv("PDF_FONT_PATH"); path != "" {
+        return path, nil
+    }
+    for _, path := range fontPaths {
+        if _, err := os.Stat(path); err == nil {
+            return path, nil
+        }
+    }
+    return "", errors.New("no TTF font with Cyrillic support found, set PDF_FONT_PATH")
+}
+
+// WriteXLSX записывает таблицу в формате XLSX
+func WriteXLSX(w io.Writer, t Table) error {
+    f := excelize.NewFile()
+    defer f.Close()
+
+    sheet := f.GetSheetName(0)
+    row := 1
+
+    if t.Title != "" {
+        if err := f.SetCellValue(sheet, cell(1, row), t.Title); err != nil {
+            return err
+        }
+        row++
+    }
+    for _, line := range t.Meta {
+        if err := f.SetCellValue(sheet, cell(1, row), line); err != nil {
+            return err
+        }
+        row++
+    }
+    if t.Title != "" || len(t.Meta) > 0 {
+        row++
+    }
+
+    if err := f.SetSheetRow(sheet, cell(1, row), &t.Headers); err != nil {
+        return err
+    }
+    row++
+
+    for _, values := range t.Rows {
+        if err := f.SetSheetRow(sheet, cell(1, row), &values); err != nil {
+            return err
+        }
+        row++
+    }
+
+    if len(t.Footer) > 0 {
+        row++
+        for _, line := range t.Footer {
+            if err := f.SetCellValue(sheet, cell(1, row), line); err != nil {
+                return err
+            }
+            row++
+        }
+    }
+
+    // Подбираем ширину колонок по самому длинному значению
+    for col, width := range columnWidths(t) {
+        name, err := excelize.ColumnNumberToName(col + 1)
+        if err != nil {
+            return err
+        }
+        if err := f.SetColWidth(sheet, name, name, float64(width)+2); err != nil {
+            return err
+        }
+    }
+
+    return f.Write(w)
+}
+
+// WritePDF записывает таблицу в формате PDF (A4)
+func WritePDF(w io.Writer, t Table, landscape bool) error {
+    fontPath, err := FontPath()
+    if err != nil {
+        return err
+    }
+
+    font, err := os.ReadFile(fontPath)
+    if err != nil {
+        return fmt.Errorf("failed to load pdf font: %v", err)
+    }
+
+    orientation := "P"
+    if landscape {
+        orientation = "L"
+    }
+
+    pdf := fpdf.New(orientation, "mm", "A4", "")
+    pdf.AddUTF8FontFromBytes("DejaVu", "", font)
+    pdf.SetMargins(10, 10, 10)
+    pdf.SetAutoPageBreak(true, 10)
+    pdf.AddPage()
+
+    if t.Title != "" {
+        pdf.SetFont("DejaVu", "", 14)
+        pdf.MultiCell(0, 8, t.Title, "", "C", false)
+        pdf.Ln(2)
+    }
+
+    pdf.SetFont("DejaVu", "", 10)
+    for _, line := range t.Meta {
+        pdf.MultiCell(0, 6, line, "", "L", false)
+    }
+    if len(t.Meta) > 0 {
+        pdf.Ln(2)
+    }
+
+    pageWidth, _ := pdf.GetPageSize()
+    left, _, right, _ := pdf.GetMargins()
+    widths := scaleWidths(columnWidths(t), pageWidth-left-right)
+
+    drawRow := func(values []string) {
+        for i, width := range widths {
+            value := ""
+            if i < len(values) {
+                value = values[i]
+            }
+            pdf.CellFormat(width, 7, value, "1", 0, "L", false, 0, "")
+        }
+        pdf.Ln(-1)
+    }
+
+    drawRow(t.Headers)
+    for _, values := range t.Rows {
+        // Повторяем шапку таблицы на новой странице
+        if pdf.GetY()+7 > pdfPageBottom(pdf) {
+            pdf.AddPage()
+            drawRow(t.Headers)
+        }
+        drawRow(values)
+    }
+
+    if len(t.Footer) > 0 {
+        pdf.Ln(6)
+        for _, line := range t.Footer {
+            pdf.MultiCell(0, 8, line, "", "L", false)
+        }
+    }
+
+    if err := pdf.Error(); err != nil {
+        return fmt.Errorf("failed to render pdf: %v", err)
+    }
+    return pdf.Output(w)
+}
+
+func pdfPageBottom(pdf *fpdf.Fpdf) float64 {
+    _, pageHeight := pdf.GetPageSize()
+    _, _, _, bottom := pdf.GetMargins()
+    return pageHeight - bottom
+}
+
+func cell(col, row int) string {
+    name, _ := excelize.CoordinatesToCellName(col, row)
+    return name
+}
+
+// columnWidths возвращает длину самого длинного значения в каждой колонке
+func columnWidths(t Table) []int {
+    widths := make([]int, len(t.Headers))
+    measure := func(values []string) {
+        for i, value := range values {
+            if i >= len(widths) {
+                break
+            }
+            if n := utf8.RuneCountInString(value); n > widths[i] {
+                widths[i] = n
+            }
+        }
+    }
+    measure(t.Headers)
+    for _, values := range t.Rows {
+        measure(values)
+    }
+    return widths
+}
+
+// scaleWidths распределяет ширину страницы пропорционально длине значений
+func scaleWidths(widths []int, total float64) []float64 {
+    sum := 0
+    for _, width := range widths {
+        if width < 3 {
+            width = 3
+        }
+        sum += width
+    }
+
+    result := make([]float64, len(widths))
+    for i, width := range widths {
+        if width < 3 {
+            width = 3
+        }
+        result[i] = total * float64(width) / float64(sum)
+    }
+    return result
+}
\ No newline at end of file
@@ -0,0 +1,239 @@
+package export
+
+import (
+    "fmt"
+    "html/template"
+    "io"
+    "os"
+    "strings"
+
+    "github.com/go-pdf/fpdf"
+)
+
+// Grid сетка для печати (например, расписание): колонки, подписи строк и многострочные ячейки
+type Grid struct {
+    Title    string
+    Subtitle string
+    Columns  []string
+    Rows     []GridRow
+    Legend   []string // Пояснения под сеткой
+}
+
+// GridRow строка сетки; Section - заголовок раздела перед строкой (день недели на общем плакате)
+type GridRow struct {
+    Section string
+    Label   []string
+    Cells   []GridCell
+}
+
+// GridCell ячейка сетки; в одной ячейке может быть несколько занятий (чётная и нечётная недели)
+type GridCell struct {
+    Items []GridItem
+}
+
+// GridItem запись в ячейке
+type GridItem struct {
+    Lines     []string
+    Cancelled bool // Выводится зачёркнутой
+}
+
+var gridTemplate = template.Must(template.New("grid").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>{{with index . 0}}{{.Title}}{{end}}</title>
+<style>
+    body { font-family: "DejaVu Sans", Arial, sans-serif; font-size: 12px; margin: 16px; }
+    h1 { font-size: 18px; margin: 0 0 4px; }
+    .subtitle { color: #555; margin-bottom: 8px; }
+    table { border-collapse: collapse; width: 100%; margin-bottom: 24px; page-break-after: always; }
+    th, td { border: 1px solid #333; padding: 4px; vertical-align: top; }
+    th { background: #eee; }
+    td.label { white-space: nowrap; font-weight: bold; width: 1%; }
+    tr.section td { background: #ddd; font-weight: bold; text-align: center; }
+    .item + .item { border-top: 1px dashed #999; margin-top: 4px; padding-top: 4px; }
+    .cancelled { text-decoration: line-through; color: #999; }
+    .legend { color: #555; margin-top: -16px; margin-bottom: 24px; }
+    @media print { @page { size: A4 landscape; margin: 10mm; } body { margin: 0; } }
+</style>
+</head>
+<body>
+{{range .}}
+<h1>{{.Title}}</h1>
+{{if .Subtitle}}<div class="subtitle">{{.Subtitle}}</div>{{end}}
+<table>
+    <tr><th></th>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+    {{$columns := len .Columns}}
+    {{range .Rows}}
+    {{if .Section}}<tr class="section"><td></td><td colspan="{{$columns}}">{{.Section}}</td></tr>{{end}}
+    <tr>
+        <td class="label">{{range $i, $line := .Label}}{{if $i}}<br>{{end}}{{$line}}{{end}}</td>
+        {{range .Cells}}<td>{{range .Items}}<div class="item{{if .Cancelled}} cancelled{{end}}">{{range $i, $line := .Lines}}{{if $i}}<br>{{end}}{{$line}}{{end}}</div>{{end}}</td>{{end}}
+    </tr>
+    {{end}}
+</table>
+{{if .Legend}}<div class="legend">{{range .Legend}}<div>{{.}}</div>{{end}}</div>{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// WriteGridHTML записывает сетки в HTML для печати или вывода на экран; каждая сетка - с новой страницы
+func WriteGridHTML(w io.Writer, grids []Grid) error {
+    if len(grids) == 0 {
+        return fmt.Errorf("nothing to render")
+    }
+    return gridTemplate.Execute(w, grids)
+}
+
+// WriteGridPDF записывает сетки в PDF (A4, альбомная ориентация); каждая сетка - с новой страницы
+func WriteGridPDF(w io.Writer, grids []Grid) error {
+    if len(grids) == 0 {
+        return fmt.Errorf("nothing to render")
+    }
+
+    fontPath, err := FontPath()
+    if err != nil {
+        return err
+    }
+    font, err := os.ReadFile(fontPath)
+    if err != nil {
+        return fmt.Errorf("failed to load pdf font: %v", err)
+    }
+
+    pdf := fpdf.New("L", "mm", "A4", "")
+    pdf.AddUTF8FontFromBytes("DejaVu", "", font)
+    pdf.SetMargins(10, 10, 10)
+    pdf.SetAutoPageBreak(false, 10)
+
+    for _, grid := range grids {
+        drawGridPDF(pdf, grid)
+    }
+
+    if err := pdf.Error(); err != nil {
+        return fmt.Errorf("failed to render pdf: %v", err)
+    }
+    return pdf.Output(w)
+}
+
+func drawGridPDF(pdf *fpdf.Fpdf, grid Grid) {
+    pdf.AddPage()
+    pageWidth, _ := pdf.GetPageSize()
+    left, _, right, _ := pdf.GetMargins()
+    width := pageWidth - left - right
+
+    pdf.SetFont("DejaVu", "", 14)
+    pdf.MultiCell(0, 7, grid.Title, "", "C", false)
+    if grid.Subtitle != "" {
+        pdf.SetFont("DejaVu", "", 9)
+        pdf.MultiCell(0, 5, grid.Subtitle, "", "C", false)
+    }
+    pdf.Ln(2)
+
+    // Чем больше колонок (групп на плакате), тем мельче шрифт
+    fontSize := 8.0
+    if n := len(grid.Columns); n > 6 {
+        fontSize = 8.0 * 6 / float64(n)
+        if fontSize < 4.5 {
+            fontSize = 4.5
+        }
+    }
+    lineHeight := fontSize * 0.45
+    labelWidth := 22.0
+    columnWidth := (width - labelWidth) / float64(len(grid.Columns))
+    pdf.SetFont("DejaVu", "", fontSize)
+
+    drawHeader := func() {
+        x, y := pdf.GetXY()
+        height := lineHeight*2 + 2
+        pdf.SetFillColor(230, 230, 230)
+        pdf.Rect(x, y, labelWidth, height, "FD")
+        for i, column := range grid.Columns {
+            cx := x + labelWidth + float64(i)*columnWidth
+            pdf.Rect(cx, y, columnWidth, height, "FD")
+            pdf.SetXY(cx, y+1)
+            pdf.MultiCell(columnWidth, lineHeight, column, "", "C", false)
+        }
+        pdf.SetXY(x, y+height)
+    }
+
+    // wrapped разбивает строки ячейки по ширине колонки
+    wrapped := func(lines []string, cellWidth float64) []string {
+        var result []string
+        for _, line := range lines {
+            result = append(result, pdf.SplitText(line, cellWidth-2)...)
+        }
+        return result
+    }
+
+    drawHeader()
+    for _, row := range grid.Rows {
+        // Высота строки - по самой высокой ячейке
+        lines := len(row.Label)
+        cells := make([][]GridItem, len(row.Cells))
+        for i, cell := range row.Cells {
+            count := 0
+            cells[i] = make([]GridItem, len(cell.Items))
+            for j, item := range cell.Items {
+                cells[i][j] = GridItem{Lines: wrapped(item.Lines, columnWidth), Cancelled: item.Cancelled}
+                count += len(cells[i][j].Lines)
+            }
+            if count > lines {
+                lines = count
+            }
+        }
+        height := float64(lines)*lineHeight + 2
+        sectionHeight := 0.0
+        if row.Section != "" {
+            sectionHeight = lineHeight + 2
+        }
+
+        if pdf.GetY()+height+sectionHeight > pdfPageBottom(pdf) {
+            pdf.AddPage()
+            drawHeader()
+        }
+
+        x, y := pdf.GetXY()
+        if row.Section != "" {
+            pdf.SetFillColor(215, 215, 215)
+            pdf.Rect(x, y, width, sectionHeight, "FD")
+            pdf.SetXY(x, y+1)
+            pdf.CellFormat(width, lineHeight, row.Section, "", 0, "C", false, 0, "")
+            y += sectionHeight
+        }
+
+        pdf.Rect(x, y, labelWidth, height, "D")
+        pdf.SetXY(x+1, y+1)
+        pdf.MultiCell(labelWidth-2, lineHeight, strings.Join(row.Label, "\n"), "", "L", false)
+
+        for i, items := range cells {
+            cx := x + labelWidth + float64(i)*columnWidth
+            pdf.Rect(cx, y, columnWidth, height, "D")
+            cy := y + 1
+            for _, item := range items {
+                if item.Cancelled {
+                    pdf.SetTextColor(150, 150, 150)
+                }
+                for _, line := range item.Lines {
+                    pdf.SetXY(cx+1, cy)
+                    pdf.CellFormat(columnWidth-2, lineHeight, line, "", 0, "L", false, 0, "")
+                    if item.Cancelled {
+                        textX := cx + 1 + pdf.GetCellMargin()
+                        pdf.Line(textX, cy+lineHeight/2, textX+pdf.GetStringWidth(line), cy+lineHeight/2)
+                    }
+                    cy += lineHeight
+                }
+                pdf.SetTextColor(0, 0, 0)
+            }
+        }
+        pdf.SetXY(x, y+height)
+    }
+
+    if len(grid.Legend) > 0 {
+        pdf.Ln(2)
+        pdf.SetFont("DejaVu", "", 8)
+        for _, line := range grid.Legend {
+            pdf.MultiCell(0, 4, line, "", "L", false)
+        }
+    }
+}
\ No newline at end of file
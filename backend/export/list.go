@@ -0,0 +1,234 @@
+package export
+
+import (
+    "encoding/csv"
+    "errors"
+    "fmt"
+    "io"
+    "reflect"
+    "strings"
+
+    "github.com/xuri/excelize/v2"
+)
+
+// Больше строк PDF не рендерит: документ собирается в памяти целиком
+const MaxPDFRows = 5000
+
+// ErrTooManyRows строк больше, чем MaxPDFRows
+var ErrTooManyRows = errors.New("too many rows for pdf, use csv or xlsx")
+
+// Column колонка выгрузки списка: имя поля (из json-тега) и русский заголовок (из тега label)
+type Column struct {
+    Name  string
+    Label string
+    index int
+}
+
+// ListColumns возвращает колонки для структуры; selected (имена полей) задаёт состав и порядок
+func ListColumns(itemType reflect.Type, selected []string) ([]Column, error) {
+    if itemType.Kind() == reflect.Ptr {
+        itemType = itemType.Elem()
+    }
+    if itemType.Kind() != reflect.Struct {
+        return nil, fmt.Errorf("expected struct, got %s", itemType.Kind())
+    }
+
+    var all []Column
+    byName := map[string]Column{}
+    for i := 0; i < itemType.NumField(); i++ {
+        field := itemType.Field(i)
+        name := jsonName(field)
+        label := field.Tag.Get("label")
+        if name == "" || label == "-" {
+            continue
+        }
+        if label == "" {
+            label = name
+        }
+        column := Column{Name: name, Label: label, index: i}
+        all = append(all, column)
+        byName[name] = column
+    }
+
+    if len(selected) == 0 {
+        return all, nil
+    }
+    columns := make([]Column, 0, len(selected))
+    for _, name := range selected {
+        column, ok := byName[strings.TrimSpace(name)]
+        if !ok {
+            return nil, fmt.Errorf("invalid column '%s'", name)
+        }
+        columns = append(columns, column)
+    }
+    return columns, nil
+}
+
+// RowWriter построчная запись таблицы; Close дописывает документ
+type RowWriter interface {
+    WriteRow(values []string) error
+    Close() error
+}
+
+// ListWriter пишет элементы списка построчно по мере поступления. Шапка уходит вместе с первым
+// элементом (или при Close), поэтому ошибку до первого элемента ещё можно вернуть обычным ответом
+type ListWriter struct {
+    rw      RowWriter
+    columns []Column
+    header  []string
+    row     []string
+}
+
+func NewListWriter(rw RowWriter, columns []Column, labels bool) *ListWriter {
+    header := make([]string, len(columns))
+    for i, column := range columns {
+        header[i] = column.Name
+        if labels {
+            header[i] = column.Label
+        }
+    }
+    return &ListWriter{rw: rw, columns: columns, header: header, row: make([]string, len(columns))}
+}
+
+func (lw *ListWriter) writeHeader() error {
+    if lw.header == nil {
+        return nil
+    }
+    header := lw.header
+    lw.header = nil
+    return lw.rw.WriteRow(header)
+}
+
+// Write записывает элемент: структуру или указатель на неё
+func (lw *ListWriter) Write(item interface{}) error {
+    if err := lw.writeHeader(); err != nil {
+        return err
+    }
+    value := reflect.Indirect(reflect.ValueOf(item))
+    for j, column := range lw.columns {
+        lw.row[j] = FormatValue(value.Field(column.index))
+    }
+    return lw.rw.WriteRow(lw.row)
+}
+
+// Close дописывает шапку пустого списка и закрывает документ
+func (lw *ListWriter) Close() error {
+    if err := lw.writeHeader(); err != nil {
+        return err
+    }
+    return lw.rw.Close()
+}
+
+// WriteList записывает шапку и элементы среза построчно, не собирая таблицу целиком
+func WriteList(rw RowWriter, items interface{}, columns []Column, labels bool) error {
+    value := reflect.ValueOf(items)
+    if value.Kind() != reflect.Slice {
+        return fmt.Errorf("expected slice, got %s", value.Kind())
+    }
+
+    lw := NewListWriter(rw, columns, labels)
+    for i := 0; i < value.Len(); i++ {
+        if err := lw.Write(value.Index(i).Interface()); err != nil {
+            return err
+        }
+    }
+    return lw.Close()
+}
+
+type csvRowWriter struct {
+    w      io.Writer
+    writer *csv.Writer
+    flush  func()
+    rows   int
+}
+
+// NewCSVRowWriter пишет CSV с BOM (чтобы Excel распознал UTF-8); flush вызывается каждые 500 строк.
+// До первой строки в w ничего не пишется
+func NewCSVRowWriter(w io.Writer, flush func()) RowWriter {
+    return &csvRowWriter{w: w, writer: csv.NewWriter(w), flush: flush}
+}
+
+func (cw *csvRowWriter) WriteRow(values []string) error {
+    if cw.rows == 0 {
+        if _, err := cw.w.Write([]byte("\xef\xbb\xbf")); err != nil {
+            return err
+        }
+    }
+    if err := cw.writer.Write(values); err != nil {
+        return err
+    }
+    cw.rows++
+    if cw.rows%500 == 0 {
+        cw.writer.Flush()
+        if cw.flush != nil {
+            cw.flush()
+        }
+    }
+    return nil
+}
+
+func (cw *csvRowWriter) Close() error {
+    cw.writer.Flush()
+    return cw.writer.Error()
+}
+
+type xlsxRowWriter struct {
+    w      io.Writer
+    file   *excelize.File
+    stream *excelize.StreamWriter
+    row    int
+}
+
+// NewXLSXRowWriter пишет XLSX потоково: строки уходят во временный файл excelize, а не в память
+func NewXLSXRowWriter(w io.Writer) (RowWriter, error) {
+    f := excelize.NewFile()
+    stream, err := f.NewStreamWriter(f.GetSheetName(0))
+    if err != nil {
+        f.Close()
+        return nil, err
+    }
+    return &xlsxRowWriter{w: w, file: f, stream: stream}, nil
+}
+
+func (xw *xlsxRowWriter) WriteRow(values []string) error {
+    xw.row++
+    cells := make([]interface{}, len(values))
+    for i, value := range values {
+        cells[i] = value
+    }
+    return xw.stream.SetRow(cell(1, xw.row), cells)
+}
+
+func (xw *xlsxRowWriter) Close() error {
+    defer xw.file.Close()
+    if err := xw.stream.Flush(); err != nil {
+        return err
+    }
+    return xw.file.Write(xw.w)
+}
+
+type pdfRowWriter struct {
+    w     io.Writer
+    table Table
+}
+
+// NewPDFRowWriter собирает строки и рендерит PDF при закрытии (не больше MaxPDFRows строк)
+func NewPDFRowWriter(w io.Writer, title string) RowWriter {
+    return &pdfRowWriter{w: w, table: Table{Title: title}}
+}
+
+func (pw *pdfRowWriter) WriteRow(values []string) error {
+    if pw.table.Headers == nil {
+        pw.table.Headers = append([]string{}, values...)
+        return nil
+    }
+    if len(pw.table.Rows) >= MaxPDFRows {
+        return ErrTooManyRows
+    }
+    pw.table.Rows = append(pw.table.Rows, append([]string{}, values...))
+    return nil
+}
+
+func (pw *pdfRowWriter) Close() error {
+    return WritePDF(pw.w, pw.table, len(pw.table.Headers) > 5)
+}
\ No newline at end of file
@@ -0,0 +1,106 @@
+package export
+
+import (
+    "bytes"
+    "encoding/csv"
+    "errors"
+    "fmt"
+    "io"
+    "path/filepath"
+    "strings"
+
+    "github.com/xuri/excelize/v2"
+)
+
+// ReadTable читает CSV или XLSX (по расширению файла); первая строка - шапка
+func ReadTable(r io.Reader, filename string) (Table, error) {
+    var rows [][]string
+    var err error
+    switch strings.ToLower(filepath.Ext(filename)) {
+    case ".csv", ".txt":
+        rows, err = ReadCSV(r)
+    case ".xlsx":
+        rows, err = ReadXLSX(r)
+    default:
+        return Table{}, fmt.Errorf("unsupported file type '%s', expected .csv or .xlsx", filepath.Ext(filename))
+    }
+    if err != nil {
+        return Table{}, err
+    }
+
+    if len(rows) == 0 || IsEmptyRow(rows[0]) {
+        return Table{}, errors.New("file has no header row")
+    }
+
+    t := Table{Headers: make([]string, len(rows[0]))}
+    for i, header := range rows[0] {
+        t.Headers[i] = strings.TrimSpace(header)
+    }
+    // Пустые строки сохраняем, чтобы номера строк в отчёте совпадали с файлом
+    t.Rows = rows[1:]
+    return t, nil
+}
+
+// ReadCSV читает CSV; разделитель (запятая или точка с запятой) определяется по первой строке
+func ReadCSV(r io.Reader) ([][]string, error) {
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return nil, err
+    }
+    data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // BOM, который добавляет Excel
+
+    firstLine := data
+    if i := bytes.IndexByte(data, '\n'); i >= 0 {
+        firstLine = data[:i]
+    }
+
+    reader := csv.NewReader(bytes.NewReader(data))
+    if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+        reader.Comma = ';'
+    }
+    reader.FieldsPerRecord = -1
+    reader.TrimLeadingSpace = true
+
+    var rows [][]string
+    for {
+        record, err := reader.Read()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, fmt.Errorf("invalid CSV: %v", err)
+        }
+        // csv пропускает пустые строки; добавляем их, чтобы номера строк совпадали с файлом
+        line, _ := reader.FieldPos(0)
+        for len(rows) < line-1 {
+            rows = append(rows, nil)
+        }
+        rows = append(rows, record)
+    }
+    return rows, nil
+}
+
+// ReadXLSX читает строки первого листа книги XLSX
+func ReadXLSX(r io.Reader) ([][]string, error) {
+    f, err := excelize.OpenReader(r)
+    if err != nil {
+        return nil, fmt.Errorf("invalid XLSX: %v", err)
+    }
+    defer f.Close()
+
+    sheets := f.GetSheetList()
+    if len(sheets) == 0 {
+        return nil, errors.New("invalid XLSX: workbook has no sheets")
+    }
+    return f.GetRows(sheets[0])
+}
+
+// IsEmptyRow возвращает true, если в строке нет значений
+func IsEmptyRow(row []string) bool {
+    for _, value := range row {
+        if strings.TrimSpace(value) != "" {
+            return false
+        }
+    }
+    return true
+}
\ No newline at end of file
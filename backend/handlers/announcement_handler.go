@@ -0,0 +1,62 @@
+package handlers
+
+import (
+    "backend/models"
+    "backend/services"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+)
+
+type AnnouncementHandler struct {
+    Service *services.AnnouncementService
+}
+
+func NewAnnouncementHandler(service *services.AnnouncementService) *AnnouncementHandler {
+    return &AnnouncementHandler{Service: service}
+}
+
+// CreateAnnouncement публикует объявление для группы (group_name) или для всех
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+    var announcement models.Announcement
+    if err := c.ShouldBindJSON(&announcement); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+    if userID := c.GetInt("user_id"); userID != 0 {
+        announcement.CreatedBy = &userID
+    }
+
+    if err := h.Service.CreateAnnouncement(&announcement, c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, announcement)
+}
+
+// GetAnnouncements возвращает объявления; ?group_name=... - объявления группы и общие
+func (h *AnnouncementHandler) GetAnnouncements(c *gin.Context) {
+    announcements, err := h.Service.GetAnnouncements(c.Query("group_name"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    respondList(c, "announcements", "Объявления", announcements)
+}
+
+func (h *AnnouncementHandler) DeleteAnnouncement(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    if err := h.Service.DeleteAnnouncement(id, c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Announcement deleted successfully"})
+}
\ No newline at end of file
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+    "backend/middleware"
+    "backend/models"
+    "backend/services"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+)
+
+type AttendanceHandler struct {
+    Service *services.AttendanceService
+}
+
+func NewAttendanceHandler(service *services.AttendanceService) *AttendanceHandler {
+    return &AttendanceHandler{Service: service}
+}
+
+// canMarkAttendance проверяет право отмечать посещаемость занятия:
+// общее право или право на свои занятия, если занятие ведёт текущий преподаватель
+func (h *AttendanceHandler) canMarkAttendance(c *gin.Context, scheduleID int) (bool, error) {
+    if middleware.HasPermission(c, models.PermAttendanceWrite) {
+        return true, nil
+    }
+    teacherID, ok := c.Get("teacher_id")
+    if !ok || !middleware.HasPermission(c, models.PermAttendanceWriteOwn) {
+        return false, nil
+    }
+    scheduleTeacherID, err := h.Service.ScheduleTeacherID(scheduleID)
+    if err != nil {
+        return false, err
+    }
+    return scheduleTeacherID == teacherID.(int), nil
+}
+
+// MarkAttendance отмечает посещаемость занятия на дату
+func (h *AttendanceHandler) MarkAttendance(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    var input models.AttendanceMarks
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+
+    allowed, err := h.canMarkAttendance(c, id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    if !allowed {
+        c.Error(models.Forbidden("access denied: lesson of another teacher"))
+        return
+    }
+
+    marks := make(map[int]string, len(input.Marks))
+    for _, item := range input.Marks {
+        marks[item.StudentID] = item.Status
+    }
+
+    attendance, err := h.Service.MarkAttendance(id, input.Date, marks, c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, attendance)
+}
+
+// GetScheduleAttendance возвращает отметки занятия; ?date=YYYY-MM-DD
+func (h *AttendanceHandler) GetScheduleAttendance(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    allowed, err := h.canMarkAttendance(c, id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    if !allowed {
+        c.Error(models.Forbidden("access denied: lesson of another teacher"))
+        return
+    }
+
+    attendance, err := h.Service.GetScheduleAttendance(id, c.Query("date"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, attendance)
+}
\ No newline at end of file
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+    "backend/models"
+    "backend/services"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+    Service *services.AuditService
+}
+
+func NewAuditHandler(service *services.AuditService) *AuditHandler {
+    return &AuditHandler{Service: service}
+}
+
+// parseAuditTime разбирает дату (YYYY-MM-DD) или время в формате RFC3339.
+// Для даты в качестве верхней границы берётся начало следующего дня
+func parseAuditTime(value string, upper bool) (time.Time, error) {
+    if t, err := time.Parse(time.RFC3339, value); err == nil {
+        return t, nil
+    }
+    t, err := time.Parse("2006-01-02", value)
+    if err != nil {
+        return time.Time{}, err
+    }
+    if upper {
+        t = t.AddDate(0, 0, 1)
+    }
+    return t, nil
+}
+
+// GetAuditLog возвращает журнал аудита; фильтры: user_id, entity_type, entity_id, from, to, limit
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+    var filter models.AuditFilter
+
+    ints := map[string]*int{
+        "user_id":   &filter.UserID,
+        "entity_id": &filter.EntityID,
+        "limit":     &filter.Limit,
+    }
+    for name, target := range ints {
+        if value := c.Query(name); value != "" {
+            parsed, err := strconv.Atoi(value)
+            if err != nil {
+                c.Error(models.Invalid("Invalid %s", name))
+                return
+            }
+            *target = parsed
+        }
+    }
+    filter.EntityType = c.Query("entity_type")
+
+    if value := c.Query("from"); value != "" {
+        from, err := parseAuditTime(value, false)
+        if err != nil {
+            c.Error(models.Invalid("Invalid from date. Use YYYY-MM-DD or RFC3339"))
+            return
+        }
+        filter.From = from
+    }
+    if value := c.Query("to"); value != "" {
+        to, err := parseAuditTime(value, true)
+        if err != nil {
+            c.Error(models.Invalid("Invalid to date. Use YYYY-MM-DD or RFC3339"))
+            return
+        }
+        filter.To = to
+    }
+
+    entries, err := h.Service.GetEntries(filter)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    respondList(c, "audit", "Журнал аудита", entries)
+}
+
+// GetEntityHistory возвращает историю изменений одной сущности
+func (h *AuditHandler) GetEntityHistory(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("entity_id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    entries, err := h.Service.GetEntityHistory(c.Param("entity_type"), id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    respondList(c, "audit", "Журнал аудита", entries)
+}
\ No newline at end of file
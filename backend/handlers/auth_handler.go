@@ -1,6 +1,7 @@
 package handlers
 
 import (
+    "backend/models"
     "backend/services"
     "net/http"
 
@@ -15,20 +16,16 @@ func NewAuthHandler(service *services.AuthService) *AuthHandler {
     return &AuthHandler{Service: service}
 }
 
-// Register регистрирует нового пользователя
+// Register открытая регистрация; роль только SelfRegisterRole
 func (h *AuthHandler) Register(c *gin.Context) {
-    var input struct {
-        Username string `json:"username"`
-        Password string `json:"password"`
-        Role     string `json:"role"`
-    }
+    var input models.RegisterRequest
     if err := c.ShouldBindJSON(&input); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+        c.Error(models.Invalid("invalid request body"))
         return
     }
 
-    if err := h.Service.Register(input.Username, input.Password, input.Role); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+    if _, err := h.Service.SignUp(input.Username, input.Password, input.Role); err != nil {
+        c.Error(err)
         return
     }
 
@@ -37,21 +34,39 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login авторизует пользователя
 func (h *AuthHandler) Login(c *gin.Context) {
-    var input struct {
-        Username string `json:"username"`
-        Password string `json:"password"`
-    }
+    var input models.Credentials
     if err := c.ShouldBindJSON(&input); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+        c.Error(models.Invalid("invalid request body"))
         return
     }
 
     token, err := h.Service.Login(input.Username, input.Password)
     if err != nil {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
     c.JSON(http.StatusOK, gin.H{"token": token})
 }
 
+// UpdateProfile меняет логин и/или пароль текущего пользователя
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+    userID := c.GetInt("user_id")
+    if userID == 0 {
+        c.Error(models.Unauthorized("Unauthorized"))
+        return
+    }
+
+    var update models.ProfileUpdate
+    if err := bindStrict(c, &update); err != nil {
+        c.Error(err)
+        return
+    }
+
+    if err := h.Service.UpdateProfile(userID, update); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Profile updated successfully"})
+}
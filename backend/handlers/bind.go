@@ -0,0 +1,19 @@
+package handlers
+
+import (
+    "backend/models"
+    "encoding/json"
+
+    "github.com/gin-gonic/gin"
+)
+
+// bindStrict разбирает JSON тела в DTO; неизвестные поля - ошибка валидации.
+// Правила из тегов validate проверяет сервис
+func bindStrict(c *gin.Context, dst interface{}) error {
+    decoder := json.NewDecoder(c.Request.Body)
+    decoder.DisallowUnknownFields()
+    if err := decoder.Decode(dst); err != nil {
+        return models.FromValidation(err)
+    }
+    return nil
+}
@@ -3,8 +3,6 @@ package handlers
 import (
 	"backend/models"
 	"backend/services"
-	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -22,12 +20,12 @@ func NewClassroomHandler(service *services.ClassroomService) *ClassroomHandler {
 func (h *ClassroomHandler) CreateClassroom(c *gin.Context) {
     var classroom models.Classroom
     if err := c.ShouldBindJSON(&classroom); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+        c.Error(models.FromValidation(err))
         return
     }
 
-    if err := h.Service.CreateClassroom(&classroom); err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+    if err := h.Service.CreateClassroom(&classroom, c.GetInt("user_id")); err != nil {
+        c.Error(err)
         return
     }
 
@@ -35,30 +33,36 @@ func (h *ClassroomHandler) CreateClassroom(c *gin.Context) {
 }
 
 func (h *ClassroomHandler) GetClassrooms(c *gin.Context) {
-    classrooms, err := h.Service.GetClassrooms()
+    query, err := parseListQuery(c)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
-    c.JSON(http.StatusOK, classrooms)
+    if exportList(c, "classrooms", "Аудитории", func(fn func(models.Classroom) error) error { return h.Service.ExportClassrooms(query, fn) }) {
+        return
+    }
+
+    page, err := h.Service.ListClassrooms(query)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, pageBody(c, page))
 }
 
 func (h *ClassroomHandler) GetClassroomByID(c *gin.Context) {
     id := c.Param("id")
     classroomID, err := strconv.Atoi(id)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
     classroom, err := h.Service.GetClassroomByID(classroomID)
     if err != nil {
-        if errors.Is(err, errors.New("classroom not found")) {
-            c.JSON(http.StatusNotFound, gin.H{"error": "Classroom not found"})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
@@ -69,23 +73,19 @@ func (h *ClassroomHandler) UpdateClassroom(c *gin.Context) {
     id := c.Param("id")
     classroomID, err := strconv.Atoi(id)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
-    var updates map[string]interface{}
-    if err := json.NewDecoder(c.Request.Body).Decode(&updates); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+    var update models.ClassroomUpdate
+    if err := bindStrict(c, &update); err != nil {
+        c.Error(err)
         return
     }
 
-    classroom, err := h.Service.UpdateClassroom(classroomID, updates)
+    classroom, err := h.Service.UpdateClassroom(classroomID, update, c.GetInt("user_id"))
     if err != nil {
-        if errors.Is(err, errors.New("classroom not found")) {
-            c.JSON(http.StatusNotFound, gin.H{"error": "Classroom not found"})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
@@ -96,18 +96,15 @@ func (h *ClassroomHandler) DeleteClassroom(c *gin.Context) {
     id := c.Param("id")
     classroomID, err := strconv.Atoi(id)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
-    if err := h.Service.DeleteClassroom(classroomID); err != nil {
-        if errors.Is(err, errors.New("classroom not found")) {
-            c.JSON(http.StatusNotFound, gin.H{"error": "Classroom not found"})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+    cascade, err := h.Service.DeleteClassroom(classroomID, c.Query("confirm") == "true", c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
         return
     }
 
-    c.JSON(http.StatusOK, gin.H{"message": "Classroom deleted successfully"})
+    c.JSON(http.StatusOK, gin.H{"message": "Classroom deleted successfully", "cascade": cascade})
 }
\ No newline at end of file
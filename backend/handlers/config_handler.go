@@ -0,0 +1,21 @@
+package handlers
+
+import (
+    "backend/config"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+)
+
+type ConfigHandler struct {
+    Config *config.Config
+}
+
+func NewConfigHandler(cfg *config.Config) *ConfigHandler {
+    return &ConfigHandler{Config: cfg}
+}
+
+// GetConfig возвращает действующие настройки; пароли и секреты скрыты
+func (h *ConfigHandler) GetConfig(c *gin.Context) {
+    c.JSON(http.StatusOK, h.Config.Redacted())
+}
\ No newline at end of file
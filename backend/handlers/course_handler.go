@@ -7,7 +7,6 @@ import (
     "github.com/gin-gonic/gin"
     "backend/models"
     "backend/services"
-	"fmt"
 )
 
 type CourseHandler struct {
@@ -21,12 +20,12 @@ func NewCourseHandler(service *services.CourseService) *CourseHandler {
 func (h *CourseHandler) CreateCourse(c *gin.Context) {
     var course models.Course
     if err := c.ShouldBindJSON(&course); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        c.Error(models.FromValidation(err))
         return
     }
 
-    if err := h.Service.CreateCourse(&course); err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+    if err := h.Service.CreateCourse(&course, c.GetInt("user_id")); err != nil {
+        c.Error(err)
         return
     }
 
@@ -34,30 +33,36 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 }
 
 func (h *CourseHandler) GetCourses(c *gin.Context) {
-    courses, err := h.Service.GetCourses()
+    query, err := parseListQuery(c)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
-    c.JSON(http.StatusOK, courses)
+    if exportList(c, "courses", "Курсы", func(fn func(models.Course) error) error { return h.Service.ExportCourses(query, fn) }) {
+        return
+    }
+
+    page, err := h.Service.ListCourses(query)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, pageBody(c, page))
 }
 
 func (h *CourseHandler) GetCourseByID(c *gin.Context) {
     idStr := c.Param("id")
     id, err := strconv.Atoi(idStr)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
     course, err := h.Service.GetCourseByID(id)
     if err != nil {
-        if err.Error() == fmt.Sprintf("course with id %d not found", id) {
-            c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
@@ -68,27 +73,19 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
     idStr := c.Param("id")
     id, err := strconv.Atoi(idStr)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
-    var updates map[string]interface{}
-    if err := c.ShouldBindJSON(&updates); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+    var update models.CourseUpdate
+    if err := bindStrict(c, &update); err != nil {
+        c.Error(err)
         return
     }
 
-    updatedCourse, err := h.Service.UpdateCourse(id, updates)
+    updatedCourse, err := h.Service.UpdateCourse(id, update, c.GetInt("user_id"))
     if err != nil {
-        if err.Error() == fmt.Sprintf("course with id %d not found", id) {
-            c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-            return
-        }
-        if err.Error() == "no fields to update" {
-            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
@@ -99,18 +96,15 @@ func (h *CourseHandler) DeleteCourse(c *gin.Context) {
     idStr := c.Param("id")
     id, err := strconv.Atoi(idStr)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
-    if err := h.Service.DeleteCourse(id); err != nil {
-        if err.Error() == fmt.Sprintf("course with id %d not found", id) {
-            c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+    cascade, err := h.Service.DeleteCourse(id, c.Query("confirm") == "true", c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
         return
     }
 
-    c.JSON(http.StatusOK, gin.H{"message": "Course deleted successfully"})
+    c.JSON(http.StatusOK, gin.H{"message": "Course deleted successfully", "cascade": cascade})
 }
\ No newline at end of file
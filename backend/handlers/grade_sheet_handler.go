@@ -0,0 +1,263 @@
+package handlers
+
+import (
+    "backend/middleware"
+    "backend/models"
+    "backend/services"
+    "bytes"
+    "fmt"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+)
+
+type GradeSheetHandler struct {
+    Service *services.GradeSheetService
+}
+
+func NewGradeSheetHandler(service *services.GradeSheetService) *GradeSheetHandler {
+    return &GradeSheetHandler{Service: service}
+}
+
+
+// canAccessSheet проверяет доступ к ведомости: общее право или право на свои курсы,
+// если пользователь - экзаменатор этой ведомости
+func canAccessSheet(c *gin.Context, sheet *models.GradeSheet, permission, ownPermission string) bool {
+    if middleware.HasPermission(c, permission) {
+        return true
+    }
+    if !middleware.HasPermission(c, ownPermission) || sheet.TeacherID == nil {
+        return false
+    }
+    teacherID, ok := c.Get("teacher_id")
+    return ok && teacherID.(int) == *sheet.TeacherID
+}
+
+// CreateGradeSheet создает черновик ведомости
+func (h *GradeSheetHandler) CreateGradeSheet(c *gin.Context) {
+    var sheet models.GradeSheet
+    if err := c.ShouldBindJSON(&sheet); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+
+    if err := h.Service.CreateGradeSheet(&sheet, c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, sheet)
+}
+
+func (h *GradeSheetHandler) GetGradeSheets(c *gin.Context) {
+    courseID := 0
+    if value := c.Query("course_id"); value != "" {
+        id, err := strconv.Atoi(value)
+        if err != nil {
+            c.Error(models.Invalid("Invalid course_id"))
+            return
+        }
+        courseID = id
+    }
+
+    sheets, err := h.Service.GetGradeSheets(courseID, c.Query("status"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    respondList(c, "grade_sheets", "Ведомости", sheets)
+}
+
+func (h *GradeSheetHandler) GetGradeSheetByID(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    sheet, err := h.Service.GetGradeSheetByID(id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    if !canAccessSheet(c, sheet, models.PermGradesRead, models.PermGradesReadOwn) {
+        c.Error(models.Forbidden("access denied: grade sheet of another teacher"))
+        return
+    }
+
+    c.JSON(http.StatusOK, sheet)
+}
+
+// IssueGradeSheet выдает ведомость экзаменатору
+func (h *GradeSheetHandler) IssueGradeSheet(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    sheet, err := h.Service.IssueGradeSheet(id, c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, sheet)
+}
+
+// FillGradeSheet выставляет оценки в ведомость
+func (h *GradeSheetHandler) FillGradeSheet(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    var input models.GradeSheetMarks
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+
+    marks := make(map[int]string, len(input.Marks))
+    for _, item := range input.Marks {
+        marks[item.StudentID] = item.Mark
+    }
+
+    before, err := h.Service.GetGradeSheetByID(id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    // Преподаватель с правом на свои курсы заполняет только свои ведомости
+    if !canAccessSheet(c, before, models.PermGradesWrite, models.PermGradesWriteOwn) {
+        c.Error(models.Forbidden("access denied: grade sheet of another teacher"))
+        return
+    }
+
+    sheet, err := h.Service.FillGradeSheet(id, marks, c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, sheet)
+}
+
+// CloseGradeSheet закрывает и подписывает ведомость
+func (h *GradeSheetHandler) CloseGradeSheet(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    sheet, err := h.Service.CloseGradeSheet(id, c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, sheet)
+}
+
+// CreateRetakeSheet создает ведомость пересдачи
+func (h *GradeSheetHandler) CreateRetakeSheet(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    // Тело необязательно: можно указать номер, дату и другого экзаменатора
+    var retake models.GradeSheet
+    if c.Request.ContentLength > 0 {
+        if err := c.ShouldBindJSON(&retake); err != nil {
+            c.Error(models.Invalid("Invalid request body"))
+            return
+        }
+    }
+
+    if err := h.Service.CreateRetakeSheet(id, &retake, c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, retake)
+}
+
+func (h *GradeSheetHandler) DeleteGradeSheet(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    if err := h.Service.DeleteGradeSheet(id, c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Grade sheet deleted successfully"})
+}
+
+// ExportGradeSheet отдает ведомость в PDF или XLSX (?format=pdf|xlsx)
+func (h *GradeSheetHandler) ExportGradeSheet(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    format := c.DefaultQuery("format", "pdf")
+    contentTypes := map[string]string{
+        "pdf":  "application/pdf",
+        "xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+    }
+    contentType, ok := contentTypes[format]
+    if !ok {
+        c.Error(models.Invalid("format must be 'pdf' or 'xlsx'"))
+        return
+    }
+
+    sheet, err := h.Service.GetGradeSheetByID(id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    if !canAccessSheet(c, sheet, models.PermGradesRead, models.PermGradesReadOwn) {
+        c.Error(models.Forbidden("access denied: grade sheet of another teacher"))
+        return
+    }
+
+    // Рендерим в буфер, чтобы при ошибке вернуть JSON, а не обрезанный файл
+    var buf bytes.Buffer
+    if err := h.Service.ExportGradeSheet(&buf, id, format); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="grade_sheet_%d.%s"`, id, format))
+    c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// GetStudentGrades возвращает зачётную книжку студента
+func (h *GradeSheetHandler) GetStudentGrades(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    grades, err := h.Service.GetStudentGrades(id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    respondList(c, "grades", "Зачётная книжка", grades)
+}
\ No newline at end of file
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+    "backend/models"
+    "backend/services"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+)
+
+type GuardianHandler struct {
+    Service *services.GuardianService
+    Portal  *services.PortalService
+}
+
+func NewGuardianHandler(service *services.GuardianService, portal *services.PortalService) *GuardianHandler {
+    return &GuardianHandler{Service: service, Portal: portal}
+}
+
+// GetStudentGuardians возвращает представителей студента
+func (h *GuardianHandler) GetStudentGuardians(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    guardians, err := h.Service.GetStudentGuardians(id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    respondList(c, "guardians", "Законные представители", guardians)
+}
+
+func (h *GuardianHandler) CreateGuardian(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    // По умолчанию представитель получает уведомления о пропусках
+    guardian := models.Guardian{NotifyAbsence: true}
+    if err := c.ShouldBindJSON(&guardian); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+    guardian.StudentID = id
+
+    if err := h.Service.CreateGuardian(&guardian, c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, guardian)
+}
+
+// UpdateGuardian частично обновляет контакт: переданные поля заменяют текущие
+func (h *GuardianHandler) UpdateGuardian(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    var update models.GuardianUpdate
+    if err := bindStrict(c, &update); err != nil {
+        c.Error(err)
+        return
+    }
+
+    guardian, err := h.Service.UpdateGuardian(id, update, c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, guardian)
+}
+
+func (h *GuardianHandler) DeleteGuardian(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    if err := h.Service.DeleteGuardian(id, c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Guardian deleted successfully"})
+}
+
+// CreateGuardianAccount создает учётную запись представителя.
+// Чтобы дать доступ к нескольким студентам, остальные контакты привязываются к ней через LinkGuardianAccount
+func (h *GuardianHandler) CreateGuardianAccount(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    var input models.Credentials
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+
+    user, err := h.Service.CreateAccount(id, input.Username, input.Password, c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, user)
+}
+
+// LinkGuardianAccount привязывает контакт к существующей учётной записи представителя
+func (h *GuardianHandler) LinkGuardianAccount(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    var link models.GuardianAccountLink
+    if err := bindStrict(c, &link); err != nil {
+        c.Error(err)
+        return
+    }
+
+    guardian, err := h.Service.LinkAccount(id, link, c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, guardian)
+}
+
+// GetLinkedStudents студенты, связанные с учётной записью представителя
+func (h *GuardianHandler) GetLinkedStudents(c *gin.Context) {
+    students, err := h.Service.GetLinkedStudents(c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, students)
+}
+
+// linkedStudentID возвращает ID студента из пути, если он связан с текущим представителем
+func (h *GuardianHandler) linkedStudentID(c *gin.Context) (int, bool) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return 0, false
+    }
+
+    linked, err := h.Service.IsLinked(c.GetInt("user_id"), id)
+    if err != nil {
+        c.Error(err)
+        return 0, false
+    }
+    if !linked {
+        c.Error(models.Forbidden("access denied: student is not linked to your account"))
+        return 0, false
+    }
+    return id, true
+}
+
+func (h *GuardianHandler) GetStudentTimetable(c *gin.Context) {
+    id, ok := h.linkedStudentID(c)
+    if !ok {
+        return
+    }
+
+    timetable, err := h.Portal.GetTimetable(id, c.Query("from"), c.Query("to"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, timetableBody(c, timetable))
+}
+
+func (h *GuardianHandler) GetStudentAttendance(c *gin.Context) {
+    id, ok := h.linkedStudentID(c)
+    if !ok {
+        return
+    }
+
+    attendance, err := h.Portal.GetAttendance(id, c.Query("from"), c.Query("to"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, attendance)
+}
+
+func (h *GuardianHandler) GetStudentGrades(c *gin.Context) {
+    id, ok := h.linkedStudentID(c)
+    if !ok {
+        return
+    }
+
+    grades, err := h.Portal.GetGrades(id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, grades)
+}
\ No newline at end of file
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+    "backend/models"
+    "context"
+    "database/sql"
+    "errors"
+    "log/slog"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+// readyTimeout время на проверку зависимостей, чтобы проба не висела вместе с БД
+const readyTimeout = 2 * time.Second
+
+type HealthHandler struct {
+    DB     *sql.DB
+    Schema func() error // Схема БД совпадает с миграциями в бинарном файле; nil - проверить нечем
+}
+
+func NewHealthHandler(db *sql.DB, schema func() error) *HealthHandler {
+    return &HealthHandler{DB: db, Schema: schema}
+}
+
+// Live отвечает, пока процесс жив и обрабатывает запросы
+func (h *HealthHandler) Live(c *gin.Context) {
+    c.JSON(http.StatusOK, models.HealthStatus{Status: models.HealthOK})
+}
+
+// Ready проверяет, что база доступна и миграции применены; иначе 503, и балансировщик не шлёт запросы.
+// Причины отказа пишутся в журнал, а не в ответ
+func (h *HealthHandler) Ready(c *gin.Context) {
+    checks := map[string]string{}
+    check := func(name string, fn func() error) {
+        if err := fn(); err != nil {
+            slog.WarnContext(c.Request.Context(), "readiness check failed", "check", name, "error", err)
+            checks[name] = models.HealthFailed
+            return
+        }
+        checks[name] = models.HealthOK
+    }
+
+    check("database", func() error {
+        if h.DB == nil {
+            return errors.New("no database connection")
+        }
+        ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+        defer cancel()
+        return h.DB.PingContext(ctx)
+    })
+    if checks["database"] == models.HealthOK {
+        check("migrations", func() error {
+            if h.Schema == nil {
+                return errors.New("no migration runner")
+            }
+            return h.Schema()
+        })
+    } else {
+        checks["migrations"] = models.HealthFailed
+    }
+
+    for _, result := range checks {
+        if result != models.HealthOK {
+            c.JSON(http.StatusServiceUnavailable, models.HealthStatus{Status: models.HealthFailed, Checks: checks})
+            return
+        }
+    }
+    c.JSON(http.StatusOK, models.HealthStatus{Status: models.HealthOK, Checks: checks})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+    "backend/middleware"
+    "backend/models"
+    "backend/export"
+    "backend/services"
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+)
+
+// Максимальный размер загружаемого файла
+const maxImportFileSize = 10 << 20
+
+var reportContentTypes = map[string]string{
+    "csv":  "text/csv; charset=utf-8",
+    "xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+type ImportHandler struct {
+    Service *services.ImportService
+}
+
+func NewImportHandler(service *services.ImportService) *ImportHandler {
+    return &ImportHandler{Service: service}
+}
+
+// Import загружает CSV/XLSX (multipart, поле file).
+// mapping - JSON {"колонка файла": "поле"}; ?dry_run=true - только проверка;
+// ?report=csv|xlsx - вместо JSON отдать отчёт об ошибках файлом
+func (h *ImportHandler) Import(entity string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        report := c.Query("report")
+        if _, ok := reportContentTypes[report]; report != "" && !ok {
+            c.Error(models.Invalid("report must be 'csv' or 'xlsx'"))
+            return
+        }
+
+        middleware.LimitBody(c, maxImportFileSize)
+        fileHeader, err := c.FormFile("file")
+        if err != nil {
+            c.Error(models.Invalid("file is required (multipart field 'file', up to 10 MB)"))
+            return
+        }
+
+        var mapping map[string]string
+        if raw := c.PostForm("mapping"); raw != "" {
+            if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+                c.Error(models.Invalid("mapping must be a JSON object {\"column\": \"field\"}"))
+                return
+            }
+        }
+        dryRun := c.Query("dry_run") == "true" || c.PostForm("dry_run") == "true"
+
+        file, err := fileHeader.Open()
+        if err != nil {
+            c.Error(models.FromValidation(err))
+            return
+        }
+        defer file.Close()
+
+        table, err := export.ReadTable(file, fileHeader.Filename)
+        if err != nil {
+            c.Error(models.FromValidation(err))
+            return
+        }
+
+        result, err := h.Service.Import(entity, table, mapping, dryRun, c.GetInt("user_id"))
+        if err != nil {
+            c.Error(err)
+            return
+        }
+
+        status := http.StatusOK
+        switch {
+        case len(result.Errors) > 0 && !dryRun:
+            status = http.StatusUnprocessableEntity // Ничего не сохранено
+        case result.Created > 0:
+            status = http.StatusCreated
+        }
+
+        if report != "" {
+            var buf bytes.Buffer
+            if err := h.Service.WriteErrorReport(&buf, result, report); err != nil {
+                c.Error(err)
+                return
+            }
+            c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import_%s_errors.%s"`, entity, report))
+            c.Data(status, reportContentTypes[report], buf.Bytes())
+            return
+        }
+        c.JSON(status, result)
+    }
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+    "backend/export"
+    "backend/middleware"
+    "backend/models"
+    "errors"
+    "fmt"
+    "log/slog"
+    "net/http"
+    "reflect"
+    "strconv"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+)
+
+var listContentTypes = map[string]string{
+    "csv":  "text/csv; charset=utf-8",
+    "xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+    "pdf":  "application/pdf",
+}
+
+// listFormat определяет формат ответа: ?format=json|csv|xlsx|pdf, иначе заголовок Accept; "" - JSON
+func listFormat(c *gin.Context) (string, error) {
+    if format := c.Query("format"); format != "" {
+        if format == "json" {
+            return "", nil
+        }
+        if _, ok := listContentTypes[format]; !ok {
+            return "", models.Invalid("format must be one of json, csv, xlsx, pdf")
+        }
+        return format, nil
+    }
+
+    accept := c.GetHeader("Accept")
+    for format, contentType := range listContentTypes {
+        if strings.Contains(accept, strings.Split(contentType, ";")[0]) {
+            return format, nil
+        }
+    }
+    return "", nil
+}
+
+// respondList отдаёт список в JSON или файлом CSV/XLSX/PDF.
+// ?columns=name,group_name - состав и порядок колонок; ?headers=fields - имена полей вместо русских заголовков
+func respondList(c *gin.Context, name, title string, items interface{}) {
+    format, err := listFormat(c)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    if format == "" {
+        c.JSON(http.StatusOK, items)
+        return
+    }
+
+    value := reflect.ValueOf(items)
+    writeListFile(c, format, name, title, value.Type().Elem(), func(write func(item interface{}) error) error {
+        for i := 0; i < value.Len(); i++ {
+            if err := write(value.Index(i).Interface()); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
+
+// exportList выгружает список файлом, если запрошен CSV/XLSX/PDF, и возвращает true; false - ответ в JSON
+// остаётся за вызывающим. each передаёт fn элементы по мере чтения из базы, не собирая список в памяти
+func exportList[T any](c *gin.Context, name, title string, each func(fn func(T) error) error) bool {
+    format, err := listFormat(c)
+    if err != nil {
+        c.Error(err)
+        return true
+    }
+    if format == "" {
+        return false
+    }
+
+    writeListFile(c, format, name, title, reflect.TypeOf((*T)(nil)).Elem(), func(write func(item interface{}) error) error {
+        return each(func(item T) error { return write(item) })
+    })
+    return true
+}
+
+// writeListFile пишет файл списка; each передаёт элементы в write
+func writeListFile(c *gin.Context, format, name, title string, itemType reflect.Type, each func(write func(item interface{}) error) error) {
+    var selected []string
+    if raw := c.Query("columns"); raw != "" {
+        selected = strings.Split(raw, ",")
+    }
+    columns, err := export.ListColumns(itemType, selected)
+    if err != nil {
+        c.Error(models.FromValidation(err))
+        return
+    }
+
+    c.Header("Content-Type", listContentTypes[format])
+    c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+    c.Status(http.StatusOK)
+
+    var rw export.RowWriter
+    switch format {
+    case "csv":
+        rw = export.NewCSVRowWriter(c.Writer, c.Writer.Flush)
+    case "xlsx":
+        rw, err = export.NewXLSXRowWriter(c.Writer)
+    case "pdf":
+        rw = export.NewPDFRowWriter(c.Writer, title)
+    }
+    if err == nil {
+        lw := export.NewListWriter(rw, columns, c.Query("headers") != "fields")
+        if err = each(lw.Write); err == nil {
+            err = lw.Close()
+        }
+    }
+    if err != nil {
+        // Пока не записана ни одна строка (ошибка фильтра, PDF, XLSX), можно вернуть обычную ошибку
+        if !c.Writer.Written() {
+            c.Writer.Header().Del("Content-Type")
+            c.Writer.Header().Del("Content-Disposition")
+            if errors.Is(err, export.ErrTooManyRows) {
+                err = models.Invalid("too many rows for pdf (max %d), use csv or xlsx", export.MaxPDFRows)
+            }
+            c.Error(err)
+            return
+        }
+        // Ответ уже пишется потоком: остаётся залогировать и оборвать его
+        slog.ErrorContext(c.Request.Context(), "failed to export list", "format", format, "error", err)
+        c.Abort()
+    }
+}
+
+// listReserved параметры запроса, которые не являются фильтрами
+var listReserved = map[string]bool{
+    "limit": true, "offset": true, "sort": true, "format": true, "columns": true, "headers": true,
+}
+
+// parseListQuery разбирает ?limit=&offset=&sort=name,-age и фильтры (остальные параметры запроса).
+// При выгрузке в файл страница не ограничивается: строки пишутся в ответ потоком (exportList);
+// маршруты без версии, как до постраничного вывода, отдают все строки
+func parseListQuery(c *gin.Context) (models.ListQuery, error) {
+    query := models.ListQuery{Limit: models.DefaultPageLimit, Filters: map[string]string{}}
+
+    if raw := c.Query("limit"); raw != "" {
+        limit, err := strconv.Atoi(raw)
+        if err != nil || limit < 1 || limit > models.MaxPageLimit {
+            return query, models.Invalid("limit must be between 1 and %d", models.MaxPageLimit)
+        }
+        query.Limit = limit
+    }
+    if raw := c.Query("offset"); raw != "" {
+        offset, err := strconv.Atoi(raw)
+        if err != nil || offset < 0 {
+            return query, models.Invalid("offset must be a non-negative number")
+        }
+        query.Offset = offset
+    }
+    if raw := c.Query("sort"); raw != "" {
+        for _, field := range strings.Split(raw, ",") {
+            field = strings.TrimSpace(field)
+            desc := strings.HasPrefix(field, "-")
+            field = strings.TrimPrefix(field, "-")
+            if field == "" {
+                return query, models.Invalid("invalid sort: %s", raw)
+            }
+            query.Sort = append(query.Sort, models.SortField{Field: field, Desc: desc})
+        }
+    }
+    for name, values := range c.Request.URL.Query() {
+        if !listReserved[name] && len(values) > 0 {
+            query.Filters[name] = values[0]
+        }
+    }
+
+    if format, _ := listFormat(c); format != "" || middleware.IsLegacyAPI(c) {
+        query.Limit = 0
+        query.Offset = 0
+    }
+    return query, nil
+}
+
+// pageBody страница списка в формате версии API: в /api/v1 - конверт {items,total,limit,offset},
+// на маршрутах без версии - прежний массив строк
+func pageBody[T any](c *gin.Context, page *models.Page[T]) interface{} {
+    if middleware.IsLegacyAPI(c) {
+        return page.Items
+    }
+    return page
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+    "backend/models"
+    "backend/services"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+)
+
+// PortalHandler личный кабинет студента (/api/me/...).
+// ID студента берётся только из токена, поэтому чужие данные запросить нельзя
+type PortalHandler struct {
+    Service *services.PortalService
+}
+
+func NewPortalHandler(service *services.PortalService) *PortalHandler {
+    return &PortalHandler{Service: service}
+}
+
+
+// currentStudentID возвращает ID студента, связанного с учётной записью
+func currentStudentID(c *gin.Context) (int, bool) {
+    studentID, ok := c.Get("student_id")
+    if !ok {
+        c.Error(models.Forbidden("account is not linked to a student"))
+        return 0, false
+    }
+    return studentID.(int), true
+}
+
+func (h *PortalHandler) GetProfile(c *gin.Context) {
+    studentID, ok := currentStudentID(c)
+    if !ok {
+        return
+    }
+
+    student, err := h.Service.GetProfile(studentID)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, student)
+}
+
+// GetTimetable расписание группы с отменами и переносами; ?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *PortalHandler) GetTimetable(c *gin.Context) {
+    studentID, ok := currentStudentID(c)
+    if !ok {
+        return
+    }
+
+    timetable, err := h.Service.GetTimetable(studentID, c.Query("from"), c.Query("to"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, timetableBody(c, timetable))
+}
+
+func (h *PortalHandler) GetGrades(c *gin.Context) {
+    studentID, ok := currentStudentID(c)
+    if !ok {
+        return
+    }
+
+    grades, err := h.Service.GetGrades(studentID)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, grades)
+}
+
+// GetAttendance посещаемость за период; ?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *PortalHandler) GetAttendance(c *gin.Context) {
+    studentID, ok := currentStudentID(c)
+    if !ok {
+        return
+    }
+
+    attendance, err := h.Service.GetAttendance(studentID, c.Query("from"), c.Query("to"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, attendance)
+}
+
+func (h *PortalHandler) GetCourses(c *gin.Context) {
+    studentID, ok := currentStudentID(c)
+    if !ok {
+        return
+    }
+
+    courses, err := h.Service.GetCourses(studentID)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, courses)
+}
+
+func (h *PortalHandler) GetAnnouncements(c *gin.Context) {
+    studentID, ok := currentStudentID(c)
+    if !ok {
+        return
+    }
+
+    announcements, err := h.Service.GetAnnouncements(studentID)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, announcements)
+}
\ No newline at end of file
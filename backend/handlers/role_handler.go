@@ -0,0 +1,107 @@
+package handlers
+
+import (
+    "backend/models"
+    "backend/services"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct {
+    Service *services.PermissionService
+}
+
+func NewRoleHandler(service *services.PermissionService) *RoleHandler {
+    return &RoleHandler{Service: service}
+}
+
+
+// GetRoles возвращает роли с их правами
+func (h *RoleHandler) GetRoles(c *gin.Context) {
+    roles, err := h.Service.GetRoles()
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, roles)
+}
+
+// GetPermissions возвращает справочник прав
+func (h *RoleHandler) GetPermissions(c *gin.Context) {
+    permissions, err := h.Service.GetPermissions()
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, permissions)
+}
+
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+    var role models.Role
+    if err := c.ShouldBindJSON(&role); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+
+    if err := h.Service.CreateRole(&role, c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, role)
+}
+
+// SetRolePermissions заменяет набор прав роли
+func (h *RoleHandler) SetRolePermissions(c *gin.Context) {
+    name := c.Param("name")
+
+    var input models.RolePermissionsUpdate
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+
+    role, err := h.Service.SetRolePermissions(name, input.Permissions, c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, role)
+}
+
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+    name := c.Param("name")
+
+    if err := h.Service.DeleteRole(name, c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Role deleted successfully"})
+}
+
+// AssignUserRole назначает пользователю роль; teacher_id связывает учётную запись с преподавателем
+func (h *RoleHandler) AssignUserRole(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    var input models.UserRoleUpdate
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+
+    user, err := h.Service.AssignUserRole(id, input.Role, input.TeacherID, c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, user)
+}
\ No newline at end of file
@@ -1,15 +1,12 @@
 package handlers
 
 import (
+	"backend/middleware"
 	"backend/models"
 	"backend/services"
-	"encoding/json"
-	"errors"
-	"fmt"
 
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,18 +21,9 @@ func NewScheduleHandler(service *services.ScheduleService) *ScheduleHandler {
 
 // CreateSchedule создает новую запись в расписании
 func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
-    type RequestBody struct {
-        TeacherID   int       `json:"teacher_id"`
-        ClassroomID int       `json:"classroom_id"`
-        GroupName   string    `json:"group_name"`
-        StartTime   time.Time `json:"start_time"`
-        EndTime     time.Time `json:"end_time"`
-        DayOfWeek   string    `json:"day_of_week"`
-    }
-
-    var req RequestBody
+    var req models.ScheduleCreate
     if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+        c.Error(models.FromValidation(err))
         return
     }
 
@@ -44,24 +32,19 @@ func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
         StartTime: req.StartTime,
         EndTime:   req.EndTime,
         DayOfWeek: req.DayOfWeek,
+        WeekType:  req.WeekType,
     }
 
-    if err := h.Service.CreateSchedule(req.TeacherID, req.ClassroomID, schedule); err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+    if err := h.Service.CreateSchedule(req.TeacherID, req.ClassroomID, schedule, c.GetInt("user_id")); err != nil {
+        c.Error(err)
         return
     }
 
-    c.JSON(http.StatusCreated, schedule)
+    c.JSON(http.StatusCreated, scheduleBody(c, schedule))
 }
 
 func (h *ScheduleHandler) GetSchedules(c *gin.Context) {
-    schedules, err := h.Service.GetSchedules()
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-        return
-    }
-
-    c.JSON(http.StatusOK, schedules)
+    h.listSchedules(c, nil)
 }
 
 // GetScheduleByID возвращает запись расписания по ID
@@ -69,21 +52,17 @@ func (h *ScheduleHandler) GetScheduleByID(c *gin.Context) {
     id := c.Param("id")
     scheduleID, err := strconv.Atoi(id)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
     schedule, err := h.Service.GetScheduleByID(scheduleID)
     if err != nil {
-        if errors.Is(err, errors.New("schedule not found")) {
-            c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
-    c.JSON(http.StatusOK, schedule)
+    c.JSON(http.StatusOK, scheduleBody(c, schedule))
 }
 
 // UpdateSchedule обновляет запись расписания
@@ -91,27 +70,23 @@ func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
     id := c.Param("id")
     scheduleID, err := strconv.Atoi(id)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
-    var updates map[string]interface{}
-    if err := json.NewDecoder(c.Request.Body).Decode(&updates); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+    var update models.ScheduleUpdate
+    if err := bindStrict(c, &update); err != nil {
+        c.Error(err)
         return
     }
 
-    schedule, err := h.Service.UpdateSchedule(scheduleID, updates)
+    schedule, err := h.Service.UpdateSchedule(scheduleID, update, c.GetInt("user_id"))
     if err != nil {
-        if errors.Is(err, errors.New("schedule not found")) {
-            c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
-    c.JSON(http.StatusOK, schedule)
+    c.JSON(http.StatusOK, scheduleBody(c, schedule))
 }
 
 // DeleteSchedule удаляет запись расписания по ID
@@ -119,16 +94,12 @@ func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
     id := c.Param("id")
     scheduleID, err := strconv.Atoi(id)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
-    if err := h.Service.DeleteSchedule(scheduleID); err != nil {
-        if errors.Is(err, errors.New("schedule not found")) {
-            c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+    if err := h.Service.DeleteSchedule(scheduleID, c.GetInt("user_id")); err != nil {
+        c.Error(err)
         return
     }
 
@@ -138,37 +109,108 @@ func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
 func (h *ScheduleHandler) GetSchedulesByDay(c *gin.Context) {
     dayOfWeek := c.Param("day")
     if dayOfWeek == "" {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "day_of_week is required"})
+        c.Error(models.Invalid("day_of_week is required"))
         return
     }
 
-    schedules, err := h.Service.GetSchedulesByDay(dayOfWeek)
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-        return
-    }
-    c.JSON(http.StatusOK, schedules)
+    h.listSchedules(c, map[string]string{"day": dayOfWeek})
 }
 
 // GetSchedulesByGroup возвращает расписание для конкретной группы
 func (h *ScheduleHandler) GetSchedulesByGroup(c *gin.Context) {
     groupName := c.Param("group_name")
-    
-    // Логируем полученное значение group_name
-    fmt.Printf("Received group_name: %q\n", groupName)
-
     if groupName == "" {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Group name is required"})
+        c.Error(models.Invalid("Group name is required"))
+        return
+    }
+
+    h.listSchedules(c, map[string]string{"group": groupName})
+}
+
+// listSchedules отдаёт страницу расписания; fixed - фильтры из пути, они важнее параметров запроса
+func (h *ScheduleHandler) listSchedules(c *gin.Context, fixed map[string]string) {
+    query, err := parseListQuery(c)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    for name, value := range fixed {
+        query.Filters[name] = value
+    }
+
+    // Файлы выгружаются с прежними колонками, в JSON /api/v1 - занятия со ссылками
+    if exportList(c, "schedules", "Расписание", func(fn func(models.Schedule) error) error { return h.Service.ExportSchedules(query, fn) }) {
+        return
+    }
+
+    page, err := h.Service.ListSchedules(query)
+    if err != nil {
+        c.Error(err)
         return
     }
 
-    schedules, err := h.Service.GetSchedulesByGroup(groupName)
+    if middleware.IsLegacyAPI(c) {
+        c.JSON(http.StatusOK, page.Items)
+        return
+    }
+    c.JSON(http.StatusOK, models.Page[models.ScheduleResource]{
+        Items: models.ScheduleResources(page.Items), Total: page.Total, Limit: page.Limit, Offset: page.Offset,
+    })
+}
+
+// scheduleBody занятие в формате версии API: в /api/v1 - со ссылками на преподавателя, аудиторию и группу
+func scheduleBody(c *gin.Context, schedule *models.Schedule) interface{} {
+    if middleware.IsLegacyAPI(c) {
+        return schedule
+    }
+    return schedule.Resource()
+}
+
+// timetableBody расписание студента в формате версии API
+func timetableBody(c *gin.Context, timetable *models.Timetable) interface{} {
+    if middleware.IsLegacyAPI(c) {
+        return timetable
+    }
+    return timetable.Resource()
+}
+
+
+
+// SaveScheduleOverride отменяет или переносит занятие на дату (/schedules/:id/overrides/:date)
+func (h *ScheduleHandler) SaveScheduleOverride(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
-    c.JSON(http.StatusOK, schedules)
+    var override models.ScheduleOverride
+    if err := c.ShouldBindJSON(&override); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+    override.ScheduleID = id
+    override.Date = c.Param("date")
+
+    if err := h.Service.SaveOverride(&override, c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, override)
 }
 
+func (h *ScheduleHandler) DeleteScheduleOverride(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    if err := h.Service.DeleteOverride(id, c.Param("date"), c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
 
+    c.JSON(http.StatusOK, gin.H{"message": "Schedule override deleted successfully"})
+}
\ No newline at end of file
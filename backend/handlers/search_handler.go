@@ -0,0 +1,53 @@
+package handlers
+
+import (
+    "backend/middleware"
+    "backend/models"
+    "backend/services"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+    Service *services.SearchService
+}
+
+func NewSearchHandler(service *services.SearchService) *SearchHandler {
+    return &SearchHandler{Service: service}
+}
+
+// Search ищет по ФИО, предметам и названиям: ?q=строка, ?types=student,teacher,course,classroom, ?limit=.
+// Ищутся только те типы, на чтение которых у роли есть право
+func (h *SearchHandler) Search(c *gin.Context) {
+    limit := models.DefaultSearchLimit
+    if raw := c.Query("limit"); raw != "" {
+        value, err := strconv.Atoi(raw)
+        if err != nil {
+            c.Error(models.Invalid("invalid limit"))
+            return
+        }
+        limit = value
+    }
+
+    var requested []string
+    if raw := c.Query("types"); raw != "" {
+        requested = strings.Split(raw, ",")
+    }
+
+    var allowed []string
+    for _, searchType := range models.SearchTypes {
+        if middleware.HasPermission(c, searchType.Permission) {
+            allowed = append(allowed, searchType.Type)
+        }
+    }
+
+    response, err := h.Service.Search(c.Query("q"), requested, allowed, limit)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+    "backend/models"
+    "backend/services"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+)
+
+type StudentAccountHandler struct {
+    Service *services.StudentAccountService
+}
+
+func NewStudentAccountHandler(service *services.StudentAccountService) *StudentAccountHandler {
+    return &StudentAccountHandler{Service: service}
+}
+
+// ProvisionAccounts выдаёт коды активации студентам без учётной записи.
+// Тело необязательно: {"group_name": "..."} ограничивает выдачу одной группой
+func (h *StudentAccountHandler) ProvisionAccounts(c *gin.Context) {
+    var input models.ProvisionRequest
+    if c.Request.ContentLength > 0 {
+        if err := c.ShouldBindJSON(&input); err != nil {
+            c.Error(models.Invalid("Invalid request body"))
+            return
+        }
+    }
+
+    codes, err := h.Service.ProvisionAccounts(input.GroupName, c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    c.JSON(http.StatusOK, codes)
+}
+
+// Activate создаёт учётную запись студента по одноразовому коду
+func (h *StudentAccountHandler) Activate(c *gin.Context) {
+    var input models.ActivationRequest
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+
+    if _, err := h.Service.Activate(input.Code, input.Username, input.Password); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{"message": "account activated successfully"})
+}
\ No newline at end of file
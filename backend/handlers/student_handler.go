@@ -7,9 +7,6 @@ import (
     "github.com/gin-gonic/gin"
     "backend/models"
     "backend/services"
-    "backend/utils"
-	"fmt"
-	"time"
 )
 
 type StudentHandler struct {
@@ -25,67 +22,55 @@ func (h *StudentHandler) CreateStudent(c *gin.Context) {
 
     // Получаем данные из запроса
     if err := c.ShouldBindJSON(&student); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        c.Error(models.FromValidation(err))
         return
     }
 
-    // Проверяем существование курса с указанным group_name
-    exists, err := h.Service.CourseExists(student.GroupName)
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-        return
-    }
-    if !exists {
-        c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("course with name '%s' does not exist", student.GroupName)})
+    // Создаём студента; сервис проверяет поля и существование группы
+    if err := h.Service.CreateStudent(&student, c.GetInt("user_id")); err != nil {
+        c.Error(err)
         return
     }
 
-    // Проверяем и парсим дату рождения
-    dateOfBirth, err := time.Parse("2006-01-02", student.DateOfBirth)
+    c.JSON(http.StatusCreated, student)
+}
+// GetStudents возвращает обучающихся студентов; ?status=... фильтрует по статусу, ?status=all - все
+func (h *StudentHandler) GetStudents(c *gin.Context) {
+    query, err := parseListQuery(c)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date_of_birth format. Use YYYY-MM-DD"})
+        c.Error(err)
         return
     }
+    status := c.DefaultQuery("status", models.StudentActive)
+    if status == "all" {
+        status = ""
+    }
+    query.Filters["status"] = status
 
-    // Вычисляем возраст
-    student.Age = utils.CalculateAge(dateOfBirth)
-
-    // Передаём дату рождения в сервис
-    student.DateOfBirth = dateOfBirth.Format("2006-01-02") // Сохраняем в формате YYYY-MM-DD
-
-    // Создаём студента
-    if err := h.Service.CreateStudent(&student); err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+    if exportList(c, "students", "Студенты", func(fn func(models.Student) error) error { return h.Service.ExportStudents(query, fn) }) {
         return
     }
 
-    c.JSON(http.StatusCreated, student)
-}
-func (h *StudentHandler) GetStudents(c *gin.Context) {
-    students, err := h.Service.GetStudents()
+    page, err := h.Service.ListStudents(query)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
-    c.JSON(http.StatusOK, students)
+    c.JSON(http.StatusOK, pageBody(c, page))
 }
 
 func (h *StudentHandler) GetStudentByID(c *gin.Context) {
     idStr := c.Param("id")
     id, err := strconv.Atoi(idStr)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
     student, err := h.Service.GetStudentByID(id)
     if err != nil {
-        if err.Error() == fmt.Sprintf("student with id %d not found", id) {
-            c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
@@ -96,57 +81,22 @@ func (h *StudentHandler) UpdateStudent(c *gin.Context) {
     idStr := c.Param("id")
     id, err := strconv.Atoi(idStr)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
-    var updates map[string]interface{}
-    if err := c.ShouldBindJSON(&updates); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+    var update models.StudentUpdate
+    if err := bindStrict(c, &update); err != nil {
+        c.Error(err)
         return
     }
 
-    // Если есть обновление group_name, проверяем его существование
-    if groupName, ok := updates["group_name"].(string); ok {
-        exists, err := h.Service.CourseExists(groupName)
-        if err != nil {
-            c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-            return
-        }
-        if !exists {
-            c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("course with name '%s' does not exist", groupName)})
-            return
-        }
-    }
-
-    // Если есть обновление даты рождения
-    if dateOfBirth, ok := updates["date_of_birth"].(string); ok {
-        parsedDate, err := time.Parse("2006-01-02", dateOfBirth)
-        if err != nil {
-            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date_of_birth format. Use YYYY-MM-DD"})
-            return
-        }
-        updates["date_of_birth"] = parsedDate.Format("2006-01-02") // Сохраняем в формате YYYY-MM-DD
-    }
-
-    updatedStudent, err := h.Service.UpdateStudent(id, updates)
+    updatedStudent, err := h.Service.UpdateStudent(id, update, c.GetInt("user_id"))
     if err != nil {
-        if err.Error() == fmt.Sprintf("student with id %d not found", id) {
-            c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-            return
-        }
-        if err.Error() == "no fields to update" {
-            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
-    // Вычисляем возраст
-    dateOfBirth, _ := time.Parse("2006-01-02", updatedStudent.DateOfBirth)
-    updatedStudent.Age = utils.CalculateAge(dateOfBirth)
-
     c.JSON(http.StatusOK, updatedStudent)
 }
 
@@ -154,18 +104,112 @@ func (h *StudentHandler) DeleteStudent(c *gin.Context) {
     idStr := c.Param("id")
     id, err := strconv.Atoi(idStr)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
-    if err := h.Service.DeleteStudent(id); err != nil {
-        if err.Error() == fmt.Sprintf("student with id %d not found", id) {
-            c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+    if err := h.Service.DeleteStudent(id, c.GetInt("user_id")); err != nil {
+        c.Error(err)
         return
     }
 
     c.JSON(http.StatusOK, gin.H{"message": "Student deleted successfully"})
+}
+
+
+// ChangeStudentStatus меняет статус студента по приказу (академ, отчисление, выпуск и т.д.)
+func (h *StudentHandler) ChangeStudentStatus(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    var input models.StudentStatusChange
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+
+    order := &models.StudentOrder{
+        OrderNumber: input.OrderNumber,
+        OrderDate:   input.OrderDate,
+        Reason:      input.Reason,
+        NewStatus:   input.Status,
+    }
+    if userID := c.GetInt("user_id"); userID != 0 {
+        order.CreatedBy = &userID
+    }
+
+    if err := h.Service.ChangeStudentStatus(id, order, c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, order)
+}
+
+// TransferStudent переводит студента в другую группу по приказу
+func (h *StudentHandler) TransferStudent(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    var input models.StudentTransfer
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.Error(models.Invalid("Invalid request body"))
+        return
+    }
+
+    order := &models.StudentOrder{
+        OrderNumber: input.OrderNumber,
+        OrderDate:   input.OrderDate,
+        Reason:      input.Reason,
+    }
+    if userID := c.GetInt("user_id"); userID != 0 {
+        order.CreatedBy = &userID
+    }
+
+    if err := h.Service.TransferStudent(id, input.GroupName, order, c.GetInt("user_id")); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, order)
+}
+
+// GetStudentOrders возвращает журнал приказов по студенту
+func (h *StudentHandler) GetStudentOrders(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    orders, err := h.Service.GetStudentOrders(id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    respondList(c, "student_orders", "Приказы", orders)
+}
+
+// GetStudentGroupHistory возвращает историю переводов между группами
+func (h *StudentHandler) GetStudentGroupHistory(c *gin.Context) {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.Error(models.Invalid("Invalid ID"))
+        return
+    }
+
+    history, err := h.Service.GetStudentGroupHistory(id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    respondList(c, "group_history", "История переводов", history)
 }
\ No newline at end of file
@@ -3,8 +3,8 @@ package handlers
 import (
 	"net/http"
 	"strconv"
-	"strings"
 
+	"backend/middleware"
 	"backend/models"
 	"backend/services"
 	"fmt"
@@ -26,19 +26,15 @@ func NewTeacherHandler(service *services.TeacherService, emailService *services.
 
 // Отправка уведомления преподавателю
 func (h *TeacherHandler) NotifyTeacher(c *gin.Context) {
-    var input struct {
-        Email   string `json:"email"`
-        Subject string `json:"subject"`
-        Body    string `json:"body"`
-    }
+    var input models.Notification
 
     if err := c.ShouldBindJSON(&input); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+        c.Error(models.Invalid("Invalid request body"))
         return
     }
 
     if err := h.EmailService.SendEmail(input.Email, input.Subject, input.Body); err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send email"})
+        c.Error(fmt.Errorf("Failed to send email"))
         return
     }
 
@@ -49,19 +45,13 @@ func (h *TeacherHandler) NotifyTeacher(c *gin.Context) {
 func (h *TeacherHandler) CreateTeacher(c *gin.Context) {
     var teacher models.Teacher
     if err := c.ShouldBindJSON(&teacher); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+        c.Error(models.FromValidation(err))
         return
     }
 
-    fmt.Printf("Received teacher data: %+v\n", teacher)
-
     // Пытаемся создать преподавателя
-    if err := h.Service.CreateTeacher(&teacher); err != nil {
-        if err.Error() == "teacher with this name and subject already exists" {
-            c.JSON(http.StatusConflict, gin.H{"error": "Teacher with this name and subject already exists"})
-            return
-        }
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+    if err := h.Service.CreateTeacher(&teacher, c.GetInt("user_id")); err != nil {
+        c.Error(err)
         return
     }
 
@@ -70,15 +60,23 @@ func (h *TeacherHandler) CreateTeacher(c *gin.Context) {
 
 // Получение всех преподавателей
 func (h *TeacherHandler) GetAllTeachers(c *gin.Context) {
-    teachers, err := h.Service.GetAllTeachers()
+    query, err := parseListQuery(c)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
+        return
+    }
+
+    if exportList(c, "teachers", "Преподаватели", func(fn func(models.Teacher) error) error { return h.Service.ExportTeachers(query, fn) }) {
         return
     }
 
-    fmt.Printf("Returning teachers: %+v\n", teachers)
+    page, err := h.Service.ListTeachers(query)
+    if err != nil {
+        c.Error(err)
+        return
+    }
 
-    c.JSON(http.StatusOK, teachers)
+    c.JSON(http.StatusOK, pageBody(c, page))
 }
 
 
@@ -89,107 +87,78 @@ func (h *TeacherHandler) UpdateTeacherPartial(c *gin.Context) {
     idStr := c.Param("id")
     id, err := strconv.Atoi(idStr)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
-    // Привязываем JSON-данные к словарю updates
-    var updates map[string]interface{}
-    if err := c.ShouldBindJSON(&updates); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+    var update models.TeacherUpdate
+    if err := bindStrict(c, &update); err != nil {
+        c.Error(err)
         return
     }
 
-    if len(updates) == 0 {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+    // Вызываем метод сервиса для обновления данных
+    teacher, err := h.Service.UpdateTeacherPartial(id, update, c.GetInt("user_id"))
+    if err != nil {
+        c.Error(err)
         return
     }
 
-    // Вызываем метод сервиса для обновления данных
-    updatedData, err := h.Service.UpdateTeacherPartial(id, updates)
-    if err != nil {
-        if strings.Contains(err.Error(), "not found") {
-            c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+    if middleware.IsLegacyAPI(c) {
+        c.JSON(http.StatusOK, legacyTeacherUpdate(update, teacher))
         return
     }
+    c.JSON(http.StatusOK, teacher)
+}
 
-    c.JSON(http.StatusOK, updatedData)
+// legacyTeacherUpdate прежний ответ PATCH /api/teachers/:id: ID и только изменённые поля
+func legacyTeacherUpdate(update models.TeacherUpdate, teacher *models.Teacher) gin.H {
+    updated := gin.H{"id": teacher.ID}
+    if update.Name != nil {
+        updated["name"] = teacher.Name
+    }
+    if update.Subject != nil {
+        updated["subject"] = teacher.Subject
+    }
+    if update.WorkingHours != nil {
+        updated["working_hours"] = teacher.WorkingHours
+    }
+    return updated
 }
 // Удаление преподавателя
 func (h *TeacherHandler) DeleteTeacher(c *gin.Context) {
     idStr := c.Param("id")
     id, err := strconv.Atoi(idStr)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+        c.Error(models.Invalid("Invalid ID"))
         return
     }
 
-    err = h.Service.DeleteTeacher(id)
+    cascade, err := h.Service.DeleteTeacher(id, c.Query("confirm") == "true", c.GetInt("user_id"))
     if err != nil {
-        if err.Error() == fmt.Sprintf("teacher with id %d not found", id) {
-            c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
-    c.JSON(http.StatusOK, gin.H{"message": "Teacher deleted successfully"})
+    c.JSON(http.StatusOK, gin.H{"message": "Teacher deleted successfully", "cascade": cascade})
 }
 
 func (h *TeacherHandler) GetTeacherSchedule(c *gin.Context) {
     teacherName := c.Param("teacher_name")
-    fmt.Println("Fetching schedule for teacher:", teacherName)
-
     if teacherName == "" {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "teacher_name is required"})
+        c.Error(models.Invalid("teacher_name is required"))
         return
     }
 
     schedules, err := h.Service.GetTeacherSchedule(teacherName)
     if err != nil {
-        fmt.Println("Error fetching teacher schedule:", err)
-        if err.Error() == "teacher not found" {
-            c.JSON(http.StatusNotFound, gin.H{"error": "Teacher not found"})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-        return
-    }
-
-    fmt.Println("Fetched schedule:", schedules)
-    c.JSON(http.StatusOK, schedules)
-}
-
-func (h *TeacherHandler) UpdateTeacherProfile(c *gin.Context) {
-    teacherID, exists := c.Get("user_id") // Получаем ID пользователя из контекста (JWT)
-    if !exists {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+        c.Error(err)
         return
     }
 
-    var updates map[string]interface{}
-    if err := c.ShouldBindJSON(&updates); err != nil {
-        fmt.Println("Error binding JSON:", err) // Логируем ошибку привязки JSON
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+    if format, err := listFormat(c); !middleware.IsLegacyAPI(c) && err == nil && format == "" {
+        c.JSON(http.StatusOK, models.ScheduleResources(schedules))
         return
     }
-
-    fmt.Println("Received updates:", updates) // Логируем входные данные
-
-    if len(updates) == 0 {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
-        return
-    }
-
-    if err := h.Service.UpdateTeacherProfile(teacherID.(int), updates); err != nil {
-        fmt.Println("Service error:", err) // Логируем ошибку сервиса
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-        return
-    }
-
-    c.JSON(http.StatusOK, gin.H{"message": "Profile updated successfully"})
-}
\ No newline at end of file
+    respondList(c, "teacher_schedule", "Расписание преподавателя", schedules)
+}
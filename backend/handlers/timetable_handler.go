@@ -0,0 +1,86 @@
+package handlers
+
+import (
+    "backend/models"
+    "backend/services"
+    "bytes"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+)
+
+var timetableContentTypes = map[string]string{
+    "html": "text/html; charset=utf-8",
+    "pdf":  "application/pdf",
+}
+
+type TimetableHandler struct {
+    Service *services.TimetableService
+}
+
+func NewTimetableHandler(service *services.TimetableService) *TimetableHandler {
+    return &TimetableHandler{Service: service}
+}
+
+// timetableFormat возвращает json, html или pdf (?format=, по умолчанию json)
+func timetableFormat(c *gin.Context) (string, bool) {
+    format := c.DefaultQuery("format", "json")
+    if _, ok := timetableContentTypes[format]; !ok && format != "json" {
+        c.Error(models.Invalid("format must be 'json', 'html' or 'pdf'"))
+        return "", false
+    }
+    return format, true
+}
+
+// GetGrid возвращает сетку расписания группы, преподавателя или аудитории.
+// ?date=YYYY-MM-DD - конкретная неделя с отменами и переносами; ?format=json|html|pdf
+func (h *TimetableHandler) GetGrid(kind string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        format, ok := timetableFormat(c)
+        if !ok {
+            return
+        }
+
+        grid, err := h.Service.GetGrid(kind, c.Param("name"), c.Query("date"))
+        if err != nil {
+            c.Error(err)
+            return
+        }
+        if format == "json" {
+            c.JSON(http.StatusOK, grid)
+            return
+        }
+
+        var buf bytes.Buffer
+        if err := h.Service.RenderGrid(&buf, grid, format); err != nil {
+            c.Error(err)
+            return
+        }
+        c.Data(http.StatusOK, timetableContentTypes[format], buf.Bytes())
+    }
+}
+
+// GetPoster возвращает общее расписание колледжа: все группы рядом
+func (h *TimetableHandler) GetPoster(c *gin.Context) {
+    format, ok := timetableFormat(c)
+    if !ok {
+        return
+    }
+
+    poster, err := h.Service.GetPoster(c.Query("date"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    if format == "json" {
+        c.JSON(http.StatusOK, poster)
+        return
+    }
+
+    var buf bytes.Buffer
+    if err := h.Service.RenderPoster(&buf, poster, format); err != nil {
+        c.Error(err)
+        return
+    }
+    c.Data(http.StatusOK, timetableContentTypes[format], buf.Bytes())
+}
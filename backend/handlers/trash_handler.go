@@ -0,0 +1,49 @@
+package handlers
+
+import (
+    "backend/models"
+    "backend/services"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+    Service *services.TrashService
+}
+
+func NewTrashHandler(service *services.TrashService) *TrashHandler {
+    return &TrashHandler{Service: service}
+}
+
+// GetTrash возвращает содержимое корзины; ?type=teachers|students|courses|classrooms|schedules
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+    items, err := h.Service.GetTrash(c.Query("type"))
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    respondList(c, "trash", "Корзина", items)
+}
+
+// Restore возвращает обработчик восстановления записи указанной таблицы из корзины
+func (h *TrashHandler) Restore(entityType string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        id, err := strconv.Atoi(c.Param("id"))
+        if err != nil {
+            c.Error(models.Invalid("Invalid ID"))
+            return
+        }
+
+        cascade, err := h.Service.Restore(entityType, id, c.GetInt("user_id"))
+        if err != nil {
+            c.Error(err)
+            return
+        }
+
+
+        c.JSON(http.StatusOK, gin.H{"message": "Record restored successfully", "cascade": cascade})
+    }
+}
\ No newline at end of file
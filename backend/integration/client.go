@@ -0,0 +1,91 @@
+package integration
+
+import (
+    "bytes"
+    "encoding/json"
+    "io"
+    "mime/multipart"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+// Client отправляет запросы в обработчик напрямую, без сети
+type Client struct {
+    Handler http.Handler
+    Token   string // JWT для заголовка Authorization; пустой - запрос без авторизации
+}
+
+func NewClient(handler http.Handler) *Client {
+    return &Client{Handler: handler}
+}
+
+// As копия клиента, которая ходит с токеном пользователя
+func (c *Client) As(user User) *Client {
+    return &Client{Handler: c.Handler, Token: user.Token}
+}
+
+// WithToken копия клиента с произвольным токеном, например просроченным
+func (c *Client) WithToken(token string) *Client {
+    return &Client{Handler: c.Handler, Token: token}
+}
+
+// File файл для загрузки в multipart-поле file
+type File struct {
+    Name    string
+    Content string
+}
+
+// Response ответ обработчика
+type Response struct {
+    Status int
+    Header http.Header
+    Body   []byte
+}
+
+// Decode разбирает тело ответа как JSON
+func (r *Response) Decode(t testing.TB, v any) {
+    t.Helper()
+    if err := json.Unmarshal(r.Body, v); err != nil {
+        t.Fatalf("failed to decode response %d %s: %v", r.Status, r.Body, err)
+    }
+}
+
+// Do выполняет запрос. body - nil, File (multipart) или значение для JSON
+func (c *Client) Do(t testing.TB, method, path string, body any) *Response {
+    t.Helper()
+
+    var reader io.Reader
+    contentType := ""
+    switch value := body.(type) {
+    case nil:
+    case File:
+        var buf bytes.Buffer
+        form := multipart.NewWriter(&buf)
+        part, err := form.CreateFormFile("file", value.Name)
+        if err != nil {
+            t.Fatalf("failed to build multipart body: %v", err)
+        }
+        io.WriteString(part, value.Content)
+        form.Close()
+        reader, contentType = &buf, form.FormDataContentType()
+    default:
+        data, err := json.Marshal(value)
+        if err != nil {
+            t.Fatalf("failed to encode request body: %v", err)
+        }
+        reader, contentType = bytes.NewReader(data), "application/json"
+    }
+
+    req := httptest.NewRequest(method, path, reader)
+    if contentType != "" {
+        req.Header.Set("Content-Type", contentType)
+    }
+    if c.Token != "" {
+        req.Header.Set("Authorization", "Bearer "+c.Token)
+    }
+
+    rec := httptest.NewRecorder()
+    c.Handler.ServeHTTP(rec, req)
+    return &Response{Status: rec.Code, Header: rec.Header(), Body: rec.Body.Bytes()}
+}
@@ -0,0 +1,491 @@
+package integration
+
+import (
+    "backend/models"
+    "backend/repository"
+    "backend/services"
+    "database/sql"
+    "fmt"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+// Пароль всех пользователей, созданных конструктором
+const Password = "password123"
+
+// sequence делает имена записей уникальными в пределах запуска
+var sequence atomic.Int64
+
+// Unique prefix с номером, который не повторяется в пределах запуска: имена, логины, номера приказов
+func Unique(prefix string) string {
+    return fmt.Sprintf("%s%d", prefix, sequence.Add(1))
+}
+
+// Fixtures создаёт тестовые записи через сервисы, то есть с теми же проверками, что и API:
+//
+//    teacher := f.Teacher().Hours(36).Build()
+//    course := f.Course().Teacher(teacher.ID).Build()
+//    student := f.Student().Group(course.Name).Build()
+//    lesson := f.Schedule().Teacher(teacher.ID).Group(course.Name).On("Monday").At("09:00").Build()
+//    token := f.User("teacher").Teacher(teacher.ID).Build().Token
+//
+// Незаданные поля заполняются уникальными значениями по умолчанию; любая ошибка останавливает тест.
+// Записи создаются без автора: в журнале аудита у них пустой user_id
+type Fixtures struct {
+    t  testing.TB
+    db *sql.DB
+
+    Teachers    *services.TeacherService
+    Courses     *services.CourseService
+    Classrooms  *services.ClassroomService
+    Students    *services.StudentService
+    Schedules   *services.ScheduleService
+    GradeSheets *services.GradeSheetService
+    Guardians   *services.GuardianService
+    Auth        *services.AuthService
+}
+
+// NewFixtures конструктор поверх базы db; secret - ключ подписи JWT, как у тестируемого роутера
+func NewFixtures(t testing.TB, db *sql.DB, secret string) *Fixtures {
+    uow := repositories.NewUnitOfWork(db)
+    teacherRepo := repositories.NewTeacherRepository(db)
+    userRepo := repositories.NewUserRepository(db)
+    return &Fixtures{
+        t:           t,
+        db:          db,
+        Teachers:    services.NewTeacherService(teacherRepo, uow),
+        Courses:     services.NewCourseService(repositories.NewCourseRepository(db), uow),
+        Classrooms:  services.NewClassroomService(repositories.NewClassroomRepository(db), uow),
+        Students:    services.NewStudentService(repositories.NewStudentRepository(db), uow),
+        Schedules:   services.NewScheduleService(repositories.NewScheduleRepository(db), teacherRepo, uow),
+        GradeSheets: services.NewGradeSheetService(repositories.NewGradeSheetRepository(db), uow),
+        Guardians:   services.NewGuardianService(repositories.NewGuardianRepository(db), uow),
+        Auth:        services.NewAuthService(userRepo, uow, secret, time.Hour),
+    }
+}
+
+func (f *Fixtures) must(what string, err error) {
+    f.t.Helper()
+    if err != nil {
+        f.t.Fatalf("fixture %s: %v", what, err)
+    }
+}
+
+// Преподаватель
+
+type TeacherBuilder struct {
+    f       *Fixtures
+    teacher models.Teacher
+    deleted bool
+}
+
+func (f *Fixtures) Teacher() *TeacherBuilder {
+    return &TeacherBuilder{f: f, teacher: models.Teacher{
+        Name:         Unique("Преподаватель "),
+        Subject:      "Математика",
+        WorkingHours: 72,
+    }}
+}
+
+func (b *TeacherBuilder) Named(name string) *TeacherBuilder {
+    b.teacher.Name = name
+    return b
+}
+
+func (b *TeacherBuilder) Subject(subject string) *TeacherBuilder {
+    b.teacher.Subject = subject
+    return b
+}
+
+func (b *TeacherBuilder) Hours(hours float64) *TeacherBuilder {
+    b.teacher.WorkingHours = hours
+    return b
+}
+
+// Deleted помещает преподавателя в корзину вместе с его занятиями
+func (b *TeacherBuilder) Deleted() *TeacherBuilder {
+    b.deleted = true
+    return b
+}
+
+func (b *TeacherBuilder) Build() models.Teacher {
+    b.f.t.Helper()
+    teacher := b.teacher
+    b.f.must("teacher", b.f.Teachers.CreateTeacher(&teacher, 0))
+    if b.deleted {
+        _, err := b.f.Teachers.DeleteTeacher(teacher.ID, true, 0)
+        b.f.must("delete teacher", err)
+    }
+    return teacher
+}
+
+// Курс (учебная группа)
+
+type CourseBuilder struct {
+    f       *Fixtures
+    course  models.Course
+    deleted bool
+}
+
+func (f *Fixtures) Course() *CourseBuilder {
+    return &CourseBuilder{f: f, course: models.Course{Name: Unique("ГР-")}}
+}
+
+func (b *CourseBuilder) Named(name string) *CourseBuilder {
+    b.course.Name = name
+    return b
+}
+
+func (b *CourseBuilder) Teacher(id int) *CourseBuilder {
+    b.course.TeacherID = &id
+    return b
+}
+
+func (b *CourseBuilder) Deleted() *CourseBuilder {
+    b.deleted = true
+    return b
+}
+
+func (b *CourseBuilder) Build() models.Course {
+    b.f.t.Helper()
+    course := b.course
+    b.f.must("course", b.f.Courses.CreateCourse(&course, 0))
+    if b.deleted {
+        _, err := b.f.Courses.DeleteCourse(course.ID, true, 0)
+        b.f.must("delete course", err)
+    }
+    return course
+}
+
+// Аудитория
+
+type RoomBuilder struct {
+    f         *Fixtures
+    classroom models.Classroom
+    deleted   bool
+}
+
+func (f *Fixtures) Room() *RoomBuilder {
+    return &RoomBuilder{f: f, classroom: models.Classroom{Name: Unique("Аудитория "), Capacity: 30}}
+}
+
+func (b *RoomBuilder) Named(name string) *RoomBuilder {
+    b.classroom.Name = name
+    return b
+}
+
+func (b *RoomBuilder) Capacity(capacity int) *RoomBuilder {
+    b.classroom.Capacity = capacity
+    return b
+}
+
+func (b *RoomBuilder) Deleted() *RoomBuilder {
+    b.deleted = true
+    return b
+}
+
+func (b *RoomBuilder) Build() models.Classroom {
+    b.f.t.Helper()
+    classroom := b.classroom
+    b.f.must("classroom", b.f.Classrooms.CreateClassroom(&classroom, 0))
+    if b.deleted {
+        _, err := b.f.Classrooms.DeleteClassroom(classroom.ID, true, 0)
+        b.f.must("delete classroom", err)
+    }
+    return classroom
+}
+
+// Студент; без группы создаётся новый курс
+
+type StudentBuilder struct {
+    f       *Fixtures
+    student models.Student
+    deleted bool
+}
+
+func (f *Fixtures) Student() *StudentBuilder {
+    return &StudentBuilder{f: f, student: models.Student{
+        Name:        Unique("Студент "),
+        DateOfBirth: "2005-03-14",
+    }}
+}
+
+func (b *StudentBuilder) Named(name string) *StudentBuilder {
+    b.student.Name = name
+    return b
+}
+
+func (b *StudentBuilder) Group(name string) *StudentBuilder {
+    b.student.GroupName = name
+    return b
+}
+
+func (b *StudentBuilder) Born(date string) *StudentBuilder {
+    b.student.DateOfBirth = date
+    return b
+}
+
+func (b *StudentBuilder) Deleted() *StudentBuilder {
+    b.deleted = true
+    return b
+}
+
+func (b *StudentBuilder) Build() models.Student {
+    b.f.t.Helper()
+    student := b.student
+    if student.GroupName == "" {
+        student.GroupName = b.f.Course().Build().Name
+    }
+    b.f.must("student", b.f.Students.CreateStudent(&student, 0))
+    if b.deleted {
+        b.f.must("delete student", b.f.Students.DeleteStudent(student.ID, 0))
+    }
+    return student
+}
+
+// Занятие; по умолчанию понедельник 09:00-10:30 каждую неделю,
+// преподаватель, аудитория и группа создаются новые
+
+type ScheduleBuilder struct {
+    f           *Fixtures
+    teacherID   int
+    classroomID int
+    schedule    models.Schedule
+    start       string
+    deleted     bool
+}
+
+func (f *Fixtures) Schedule() *ScheduleBuilder {
+    return &ScheduleBuilder{f: f, start: "09:00", schedule: models.Schedule{DayOfWeek: "Monday", WeekType: models.WeekAll}}
+}
+
+func (b *ScheduleBuilder) Teacher(id int) *ScheduleBuilder {
+    b.teacherID = id
+    return b
+}
+
+func (b *ScheduleBuilder) Room(id int) *ScheduleBuilder {
+    b.classroomID = id
+    return b
+}
+
+func (b *ScheduleBuilder) Group(name string) *ScheduleBuilder {
+    b.schedule.GroupName = name
+    return b
+}
+
+// On день недели по-английски, как в API: "Monday"
+func (b *ScheduleBuilder) On(day string) *ScheduleBuilder {
+    b.schedule.DayOfWeek = day
+    return b
+}
+
+// At время начала "ЧЧ:ММ"; занятие длится 90 минут
+func (b *ScheduleBuilder) At(start string) *ScheduleBuilder {
+    b.start = start
+    return b
+}
+
+// Week all, odd или even
+func (b *ScheduleBuilder) Week(weekType string) *ScheduleBuilder {
+    b.schedule.WeekType = weekType
+    return b
+}
+
+func (b *ScheduleBuilder) Deleted() *ScheduleBuilder {
+    b.deleted = true
+    return b
+}
+
+func (b *ScheduleBuilder) Build() models.Schedule {
+    b.f.t.Helper()
+    schedule := b.schedule
+    if b.teacherID == 0 {
+        b.teacherID = b.f.Teacher().Build().ID
+    }
+    if b.classroomID == 0 {
+        b.classroomID = b.f.Room().Build().ID
+    }
+    if schedule.GroupName == "" {
+        schedule.GroupName = b.f.Course().Build().Name
+    }
+
+    start, err := time.Parse("2006-01-02 15:04", "2025-09-01 "+b.start)
+    b.f.must("schedule start", err)
+    schedule.StartTime = start
+    schedule.EndTime = start.Add(90 * time.Minute)
+
+    b.f.must("schedule", b.f.Schedules.CreateSchedule(b.teacherID, b.classroomID, &schedule, 0))
+    if b.deleted {
+        b.f.must("delete schedule", b.f.Schedules.DeleteSchedule(schedule.ID, 0))
+        return schedule
+    }
+    saved, err := b.f.Schedules.GetScheduleByID(schedule.ID) // С именами преподавателя и аудитории
+    b.f.must("schedule", err)
+    return *saved
+}
+
+// Ведомость; в неё попадают активные студенты группы курса на момент создания
+
+type GradeSheetBuilder struct {
+    f      *Fixtures
+    sheet  models.GradeSheet
+    status string
+    mark   string
+}
+
+func (f *Fixtures) GradeSheet(courseID int) *GradeSheetBuilder {
+    return &GradeSheetBuilder{f: f, status: models.GradeSheetDraft, sheet: models.GradeSheet{
+        CourseID:    courseID,
+        ControlType: models.ControlExam,
+        Number:      Unique("В-"),
+        ExamDate:    "2025-06-20",
+    }}
+}
+
+func (b *GradeSheetBuilder) Teacher(id int) *GradeSheetBuilder {
+    b.sheet.TeacherID = &id
+    return b
+}
+
+func (b *GradeSheetBuilder) Credit() *GradeSheetBuilder {
+    b.sheet.ControlType = models.ControlCredit
+    return b
+}
+
+// Issued выдаёт ведомость экзаменатору
+func (b *GradeSheetBuilder) Issued() *GradeSheetBuilder {
+    b.status = models.GradeSheetIssued
+    return b
+}
+
+// Marked выдаёт ведомость и ставит всем студентам отметку mark
+func (b *GradeSheetBuilder) Marked(mark string) *GradeSheetBuilder {
+    b.status, b.mark = models.GradeSheetFilled, mark
+    return b
+}
+
+// Closed выдаёт, заполняет отметкой mark и закрывает ведомость
+func (b *GradeSheetBuilder) Closed(mark string) *GradeSheetBuilder {
+    b.status, b.mark = models.GradeSheetClosed, mark
+    return b
+}
+
+func (b *GradeSheetBuilder) Build() models.GradeSheet {
+    b.f.t.Helper()
+    sheet := b.sheet
+    b.f.must("grade sheet", b.f.GradeSheets.CreateGradeSheet(&sheet, 0))
+    if b.status == models.GradeSheetDraft {
+        return sheet
+    }
+
+    saved, err := b.f.GradeSheets.IssueGradeSheet(sheet.ID, 0)
+    b.f.must("issue grade sheet", err)
+    if b.mark != "" {
+        marks := map[int]string{}
+        for _, entry := range saved.Entries {
+            marks[entry.StudentID] = b.mark
+        }
+        saved, err = b.f.GradeSheets.FillGradeSheet(sheet.ID, marks, 0)
+        b.f.must("fill grade sheet", err)
+    }
+    if b.status == models.GradeSheetClosed {
+        saved, err = b.f.GradeSheets.CloseGradeSheet(sheet.ID, b.f.User("registrar").Build().ID)
+        b.f.must("close grade sheet", err)
+    }
+    return *saved
+}
+
+// Законный представитель студента
+
+type GuardianBuilder struct {
+    f        *Fixtures
+    guardian models.Guardian
+}
+
+func (f *Fixtures) Guardian(studentID int) *GuardianBuilder {
+    return &GuardianBuilder{f: f, guardian: models.Guardian{
+        StudentID: studentID,
+        Name:      Unique("Представитель "),
+        Phone:     "+7 900 000-00-00",
+        Relation:  "mother",
+    }}
+}
+
+func (b *GuardianBuilder) Email(email string) *GuardianBuilder {
+    b.guardian.Email = email
+    return b
+}
+
+func (b *GuardianBuilder) Build() models.Guardian {
+    b.f.t.Helper()
+    guardian := b.guardian
+    b.f.must("guardian", b.f.Guardians.CreateGuardian(&guardian, 0))
+    return guardian
+}
+
+// Пользователь с токеном
+
+// User учётная запись и её JWT
+type User struct {
+    ID       int
+    Username string
+    Role     string
+    Token    string
+}
+
+type UserBuilder struct {
+    f          *Fixtures
+    role       string
+    teacherID  *int
+    studentID  *int
+    guardianID int
+}
+
+// User пользователь с ролью role (admin, teacher, registrar, student, guardian, ...)
+func (f *Fixtures) User(role string) *UserBuilder {
+    return &UserBuilder{f: f, role: role}
+}
+
+// Teacher связывает учётную запись с преподавателем (права :own-courses)
+func (b *UserBuilder) Teacher(id int) *UserBuilder {
+    b.teacherID = &id
+    return b
+}
+
+// Student связывает учётную запись со студентом (личный кабинет)
+func (b *UserBuilder) Student(id int) *UserBuilder {
+    b.studentID = &id
+    return b
+}
+
+// Guardian учётная запись представителя; роль заменяется на guardian
+func (b *UserBuilder) Guardian(id int) *UserBuilder {
+    b.guardianID = id
+    b.role = "guardian"
+    return b
+}
+
+func (b *UserBuilder) Build() User {
+    b.f.t.Helper()
+    username := Unique(b.role)
+
+    var user *models.User
+    var err error
+    if b.guardianID != 0 {
+        user, err = b.f.Guardians.CreateAccount(b.guardianID, username, Password, 0)
+    } else {
+        user, err = b.f.Auth.Register(username, Password, b.role, 0)
+    }
+    b.f.must("user", err)
+
+    if b.teacherID != nil || b.studentID != nil {
+        _, err = b.f.db.Exec(`UPDATE users SET teacher_id = $1, student_id = $2 WHERE id = $3`, b.teacherID, b.studentID, user.ID)
+        b.f.must("link user", err)
+    }
+
+    token, err := b.f.Auth.Login(username, Password)
+    b.f.must("login", err)
+    return User{ID: user.ID, Username: username, Role: b.role, Token: token}
+}
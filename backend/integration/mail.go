@@ -0,0 +1,100 @@
+package integration
+
+import (
+    "backend/config"
+    "bufio"
+    "net"
+    "strings"
+    "sync"
+)
+
+// MailSink SMTP-сервер на локальном порту, который принимает письма без авторизации
+// и TLS и запоминает получателей. Нужен маршрутам, отправляющим email
+type MailSink struct {
+    listener net.Listener
+    mu       sync.Mutex
+    to       []string
+}
+
+func StartMailSink() (*MailSink, error) {
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        return nil, err
+    }
+    s := &MailSink{listener: listener}
+    go s.serve()
+    return s, nil
+}
+
+// Config настройки SMTP, указывающие на заглушку
+func (s *MailSink) Config() config.EmailConfig {
+    addr := s.listener.Addr().(*net.TCPAddr)
+    return config.EmailConfig{Host: addr.IP.String(), Port: addr.Port, From: "college@example.com"}
+}
+
+// Recipients адреса всех принятых писем по порядку
+func (s *MailSink) Recipients() []string {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return append([]string(nil), s.to...)
+}
+
+func (s *MailSink) Close() error {
+    return s.listener.Close()
+}
+
+func (s *MailSink) serve() {
+    for {
+        conn, err := s.listener.Accept()
+        if err != nil {
+            return
+        }
+        go s.handle(conn)
+    }
+}
+
+// handle минимальный диалог SMTP: EHLO, MAIL, RCPT, DATA, QUIT
+func (s *MailSink) handle(conn net.Conn) {
+    defer conn.Close()
+    reader := bufio.NewReader(conn)
+    reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+    reply("220 localhost ESMTP")
+    var recipients []string
+    for {
+        line, err := reader.ReadString('\n')
+        if err != nil {
+            return
+        }
+        command := strings.ToUpper(strings.TrimSpace(line))
+        switch {
+        case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+            reply("250 localhost")
+        case strings.HasPrefix(command, "RCPT TO:"):
+            address := strings.TrimSpace(strings.TrimSpace(line)[len("RCPT TO:"):])
+            recipients = append(recipients, strings.Trim(address, "<>"))
+            reply("250 OK")
+        case command == "DATA":
+            reply("354 End data with <CR><LF>.<CR><LF>")
+            for {
+                data, err := reader.ReadString('\n')
+                if err != nil {
+                    return
+                }
+                if data == ".\r\n" || data == ".\n" {
+                    break
+                }
+            }
+            s.mu.Lock()
+            s.to = append(s.to, recipients...)
+            s.mu.Unlock()
+            recipients = nil
+            reply("250 OK")
+        case command == "QUIT":
+            reply("221 Bye")
+            return
+        default: // MAIL FROM, RSET, NOOP
+            reply("250 OK")
+        }
+    }
+}
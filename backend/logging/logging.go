@@ -0,0 +1,91 @@
+package logging
+
+import (
+    "context"
+    "io"
+    "log/slog"
+    "strings"
+)
+
+// Форматы вывода журнала
+const (
+    FormatText = "text"
+    FormatJSON = "json"
+)
+
+const redacted = "***"
+
+// sensitiveKeys части имён полей, значения которых не попадают в журнал
+var sensitiveKeys = []string{"password", "secret", "token", "authorization", "hash", "cookie"}
+
+// New создаёт логгер с уровнем (debug, info, warn, error) и форматом (text, json).
+// Значения полей с паролями, токенами и хешами заменяются на "***", а поля из контекста
+// запроса (WithAttrs) добавляются к каждой записи, сделанной с этим контекстом
+func New(w io.Writer, level, format string) *slog.Logger {
+    options := &slog.HandlerOptions{Level: ParseLevel(level), ReplaceAttr: redact}
+    var handler slog.Handler
+    if format == FormatJSON {
+        handler = slog.NewJSONHandler(w, options)
+    } else {
+        handler = slog.NewTextHandler(w, options)
+    }
+    return slog.New(contextHandler{handler})
+}
+
+// ParseLevel уровень по названию; неизвестное название - info
+func ParseLevel(name string) slog.Level {
+    var level slog.Level
+    if err := level.UnmarshalText([]byte(name)); err != nil {
+        return slog.LevelInfo
+    }
+    return level
+}
+
+// IsSensitive сообщает, что поле с таким именем содержит секрет
+func IsSensitive(key string) bool {
+    key = strings.ToLower(key)
+    for _, part := range sensitiveKeys {
+        if strings.Contains(key, part) {
+            return true
+        }
+    }
+    return false
+}
+
+func redact(groups []string, attr slog.Attr) slog.Attr {
+    if attr.Value.Kind() != slog.KindGroup && IsSensitive(attr.Key) {
+        return slog.String(attr.Key, redacted)
+    }
+    return attr
+}
+
+type attrsKey struct{}
+
+// WithAttrs возвращает контекст, записи с которым получают дополнительные поля
+// (номер запроса, пользователь, маршрут)
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+    existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+    combined := make([]slog.Attr, 0, len(existing)+len(attrs))
+    combined = append(append(combined, existing...), attrs...)
+    return context.WithValue(ctx, attrsKey{}, combined)
+}
+
+// contextHandler добавляет к записи поля из контекста
+type contextHandler struct {
+    slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+    if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+        record.AddAttrs(attrs...)
+    }
+    return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+    return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+    return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "log/slog"
+    "testing"
+)
+
+func TestSecretsRedacted(t *testing.T) {
+    var buf bytes.Buffer
+    logger := New(&buf, "info", FormatJSON)
+
+    logger.Info("login", "username", "admin", "password", "qwerty", "Authorization", "Bearer abc",
+        slog.Group("user", "password_hash", "$2a$10$hash"), "jwt_token", "abc")
+
+    var entry map[string]any
+    if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+        t.Fatalf("log is not JSON: %v", err)
+    }
+    for _, key := range []string{"password", "Authorization", "jwt_token"} {
+        if entry[key] != redacted {
+            t.Errorf("%s = %v, want redacted", key, entry[key])
+        }
+    }
+    if user, _ := entry["user"].(map[string]any); user["password_hash"] != redacted {
+        t.Errorf("user.password_hash = %v, want redacted", user["password_hash"])
+    }
+    if entry["username"] != "admin" {
+        t.Errorf("username = %v, must not be redacted", entry["username"])
+    }
+}
+
+func TestContextAttrsAndLevel(t *testing.T) {
+    var buf bytes.Buffer
+    logger := New(&buf, "warn", FormatJSON)
+
+    ctx := WithAttrs(context.Background(), slog.String("request_id", "abc"))
+    ctx = WithAttrs(ctx, slog.Int("user_id", 7))
+    logger.InfoContext(ctx, "skipped")
+    if buf.Len() != 0 {
+        t.Fatalf("info record written at warn level: %s", buf.String())
+    }
+
+    logger.WarnContext(ctx, "slow request")
+    var entry map[string]any
+    if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+        t.Fatalf("log is not JSON: %v", err)
+    }
+    if entry["request_id"] != "abc" || entry["user_id"] != float64(7) {
+        t.Errorf("context attrs missing: %v", entry)
+    }
+}
@@ -2,113 +2,136 @@ package main
 
 import (
     "backend/config"
-    "backend/handlers"
-    "backend/repository"
+    "backend/migrate"
+    "backend/logging"
     "backend/services"
-    "backend/middleware"
+    "context"
+    "crypto/tls"
+    "database/sql"
+    "fmt"
+    "log"
+    "log/slog"
+    "net/http"
+    "os"
+    "os/signal"
+    "syscall"
+    "time"
+
     "github.com/gin-gonic/gin"
 )
 
 func main() {
-    // Подключение к бдхе
-    db := config.ConnectDB()
-    defer db.Close()
-
-    // Инициализация репозитория
-    teacherRepo := repositories.NewTeacherRepository(db)
-    studentRepo := repositories.NewStudentRepository(db)
-    courseRepo := repositories.NewCourseRepository(db)
-    classroomRepo := repositories.NewClassroomRepository(db)
-    scheduleRepo := repositories.NewScheduleRepository(db)
-    userRepo := repositories.NewUserRepository(db) // Добавляем репозиторий для пользователей
-
-    // Инициализация сервиса
-    teacherService := services.NewTeacherService(teacherRepo)
-    studentService := services.NewStudentService(studentRepo)
-    courseService := services.NewCourseService(courseRepo)
-    classroomService := services.NewClassroomService(classroomRepo)
-    scheduleService := services.NewScheduleService(scheduleRepo, teacherRepo) // Передаем teacherRepo
-    authService := services.NewAuthService(userRepo, "your_secret_key")       // Добавляем сервис для авторизации
-    emailService := services.NewEmailService()
-    // Инициализация обработчика
-    teacherHandler := handlers.NewTeacherHandler(teacherService, emailService)
-
-    studentHandler := handlers.NewStudentHandler(studentService)
-    courseHandler := handlers.NewCourseHandler(courseService)
-    classroomHandler := handlers.NewClassroomHandler(classroomService)
-    scheduleHandler := handlers.NewScheduleHandler(scheduleService)
-    authHandler := handlers.NewAuthHandler(authService) // Добавляем обработчик для авторизации
-
-    // Роутер
-    r := gin.Default()
-
-    
-
-    api := r.Group("/api")
-
-    // Маршруты для авторизации
-    api.POST("/register", authHandler.Register) // Регистрация нового пользователя
-    api.POST("/login", authHandler.Login)       // Авторизация пользователя
-
- // Защищенные маршруты
-authorized := api.Group("/")
-authorized.Use(middleware.AuthMiddleware("your_secret_key")) // Middleware для проверки JWT-токена
-{
-
-    
-    // Только администраторы
-    admin := authorized.Group("/")
-    admin.Use(middleware.RoleMiddleware("admin"))
-    {
-        admin.GET("/admin", func(c *gin.Context) {
-            c.JSON(200, gin.H{"message": "welcome, admin!"})
-        })
+    // Настройки: значения по умолчанию, файл CONFIG_FILE (YAML/TOML), переменные окружения
+    cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+    if err != nil {
+        log.Fatal(err)
+    }
+    // Журнал в stdout; стандартный log тоже пишет через него
+    slog.SetDefault(logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format))
+    if !cfg.IsProduction() && cfg.JWT.Secret == config.DefaultJWTSecret {
+        slog.Warn("using the default JWT secret, set JWT_SECRET")
+    }
 
-        // Маршруты только для администраторов
-        admin.GET("/teachers", teacherHandler.GetAllTeachers)
-        admin.POST("/teachers", teacherHandler.CreateTeacher)
-        admin.PATCH("/teachers/:id", teacherHandler.UpdateTeacherPartial)
-        admin.DELETE("/teachers/:id", teacherHandler.DeleteTeacher)
+    // Без аргументов запускается сервер
+    command, args := "serve", []string{}
+    if len(os.Args) > 1 {
+        command, args = os.Args[1], os.Args[2:]
+    }
+    if command == "help" || command == "-h" || command == "--help" {
+        printUsage()
+        return
+    }
 
-        admin.GET("/students", studentHandler.GetStudents)
-        admin.POST("/students", studentHandler.CreateStudent)
-        admin.GET("/students/:id", studentHandler.GetStudentByID)
-        admin.PATCH("/students/:id", studentHandler.UpdateStudent)
-        admin.DELETE("/students/:id", studentHandler.DeleteStudent)
+    // Подключение к бдхе
+    db, err := config.ConnectDB(cfg.Database)
+    if err != nil {
+        fatal(err)
+    }
+    defer db.Close()
 
-        admin.GET("/courses", courseHandler.GetCourses)
-        admin.POST("/courses", courseHandler.CreateCourse)
-        admin.GET("/courses/:id", courseHandler.GetCourseByID)
-        admin.PATCH("/courses/:id", courseHandler.UpdateCourse)
-        admin.DELETE("/courses/:id", courseHandler.DeleteCourse)
+    runner, err := newMigrationRunner(db)
+    if err != nil {
+        db.Close()
+        fatal(err)
+    }
 
-        admin.POST("/classrooms", classroomHandler.CreateClassroom)
-        admin.GET("/classrooms", classroomHandler.GetClassrooms)
-        admin.GET("/classrooms/:id", classroomHandler.GetClassroomByID)
-        admin.PATCH("/classrooms/:id", classroomHandler.UpdateClassroom)
-        admin.DELETE("/classrooms/:id", classroomHandler.DeleteClassroom)
+    if err := runCommand(command, args, cfg, db, runner); err != nil {
+        db.Close()
+        fatal(err)
+    }
+}
 
-        admin.POST("/schedules", scheduleHandler.CreateSchedule)
-        admin.GET("/schedules", scheduleHandler.GetSchedules)
-        admin.GET("/schedules/:id", scheduleHandler.GetScheduleByID)
-        admin.PATCH("/schedules/:id", scheduleHandler.UpdateSchedule)
-        admin.DELETE("/schedules/:id", scheduleHandler.DeleteSchedule)
-        admin.GET("/schedules/day/:day", scheduleHandler.GetSchedulesByDay)       // Просмотр расписания по дню недели
-        admin.GET("/schedules/group/:group_name", scheduleHandler.GetSchedulesByGroup)
+// fatal пишет ошибку в журнал и завершает процесс
+func fatal(err error) {
+    slog.Error(err.Error())
+    os.Exit(1)
+}
 
-        // Новый маршрут для отправки email-уведомлений
-        admin.POST("/notify", teacherHandler.NotifyTeacher)
+// serve применяет миграции (если включено), проверяет схему и запускает HTTP-сервер
+func serve(cfg *config.Config, db *sql.DB, runner *migrate.Runner) error {
+    if cfg.Database.AutoMigrate {
+        runner.Log = func(format string, args ...interface{}) {
+            slog.Info(fmt.Sprintf(format, args...))
+        }
+        if _, err := runner.Up(); err != nil {
+            return err
+        }
+    }
+    // Не запускаемся на схеме, которая новее приложения
+    if err := runner.Check(); err != nil {
+        return err
     }
 
-    // Учителя и администраторы
-    teacher := authorized.Group("/")
-    teacher.Use(middleware.RoleMiddleware("teacher", "admin"))
-    {
-        // Маршрут для просмотра расписания учителя
-        teacher.GET("/teachers/:teacher_name/schedule", teacherHandler.GetTeacherSchedule)
-        teacher.PUT("/teacher/profile", teacherHandler.UpdateTeacherProfile)
+    if cfg.IsProduction() {
+        gin.SetMode(gin.ReleaseMode) // Без отладочного вывода gin
+    }
+    tasks := services.NewBackground()
+    server := newHTTPServer(cfg.Server, setupRouter(cfg, db, runner, tasks))
+
+    // SIGINT/SIGTERM: сервер перестаёт принимать соединения и дожидается начатых запросов и фоновых задач
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+
+    errs := make(chan error, 1)
+    go func() {
+        slog.Info("server started", "addr", server.Addr, "tls", cfg.Server.TLS())
+        if cfg.Server.TLS() {
+            errs <- server.ListenAndServeTLS(cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile)
+        } else {
+            errs <- server.ListenAndServe()
+        }
+    }()
+    select {
+    case err := <-errs:
+        return err // Не удалось занять порт или прочитать сертификат
+    case <-ctx.Done():
+    }
+    stop() // Повторный сигнал завершает процесс сразу
 
+    slog.Info("shutting down", "timeout", time.Duration(cfg.Server.ShutdownTimeout))
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout))
+    defer cancel()
+    if err := server.Shutdown(shutdownCtx); err != nil {
+        return fmt.Errorf("failed to finish requests in time: %w", err)
     }
+    if err := tasks.Wait(shutdownCtx); err != nil {
+        return fmt.Errorf("failed to finish background tasks in time: %w", err)
+    }
+    slog.Info("server stopped")
+    return nil
 }
-    r.Run(":8080")
+
+// newHTTPServer сервер с таймаутами из настроек; ошибки соединений пишутся в журнал
+func newHTTPServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+    return &http.Server{
+        Addr:              fmt.Sprintf(":%d", cfg.Port),
+        Handler:           handler,
+        ReadHeaderTimeout: time.Duration(cfg.ReadHeaderTimeout),
+        ReadTimeout:       time.Duration(cfg.ReadTimeout),
+        WriteTimeout:      time.Duration(cfg.WriteTimeout),
+        IdleTimeout:       time.Duration(cfg.IdleTimeout),
+        MaxHeaderBytes:    64 << 10,
+        TLSConfig:         &tls.Config{MinVersion: tls.VersionTLS12},
+        ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+    }
 }
\ No newline at end of file
@@ -0,0 +1,17 @@
+package metrics
+
+// Метрики запросов HTTP; route - шаблон маршрута gin (/api/v1/students/:id), чтобы число серий не росло
+var (
+    HTTPRequests = Default.NewCounter("http_requests_total", "Запросы HTTP по маршрутам и статусам",
+        "method", "route", "status")
+    HTTPDuration = Default.NewHistogram("http_request_duration_seconds", "Длительность обработки запросов HTTP",
+        DefaultBuckets, "method", "route", "status")
+)
+
+// События предметной области
+var (
+    ScheduleConflicts = Default.NewCounter("college_schedule_conflicts_total",
+        "Отклонённые занятия, пересекающиеся с другими занятиями преподавателя")
+    LoginFailures = Default.NewCounter("college_login_failures_total", "Неудачные попытки входа")
+    Emails        = Default.NewCounter("college_emails_total", "Отправленные письма; result - sent или failed", "result")
+)
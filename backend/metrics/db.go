@@ -0,0 +1,45 @@
+package metrics
+
+import (
+    "database/sql"
+    "io"
+)
+
+// Collectors несколько сборщиков как один
+type Collectors []Collector
+
+func (cs Collectors) Collect(w io.Writer) {
+    for _, c := range cs {
+        c.Collect(w)
+    }
+}
+
+// DBStats метрики пула соединений из sql.DB.Stats(); без подключения к БД - пусто
+func DBStats(db *sql.DB) Collector {
+    if db == nil {
+        return Collectors{}
+    }
+    stat := func(value func(s sql.DBStats) float64) func() float64 {
+        return func() float64 { return value(db.Stats()) }
+    }
+    return Collectors{
+        NewGaugeFunc("db_max_open_connections", "Максимум открытых соединений с БД",
+            stat(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })),
+        NewGaugeFunc("db_open_connections", "Открытые соединения с БД",
+            stat(func(s sql.DBStats) float64 { return float64(s.OpenConnections) })),
+        NewGaugeFunc("db_in_use_connections", "Занятые соединения с БД",
+            stat(func(s sql.DBStats) float64 { return float64(s.InUse) })),
+        NewGaugeFunc("db_idle_connections", "Свободные соединения с БД",
+            stat(func(s sql.DBStats) float64 { return float64(s.Idle) })),
+        NewCounterFunc("db_wait_count_total", "Сколько раз запрос ждал свободного соединения",
+            stat(func(s sql.DBStats) float64 { return float64(s.WaitCount) })),
+        NewCounterFunc("db_wait_duration_seconds_total", "Суммарное ожидание свободного соединения",
+            stat(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() })),
+        NewCounterFunc("db_max_idle_closed_total", "Соединения, закрытые из-за max_idle_conns",
+            stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) })),
+        NewCounterFunc("db_max_idle_time_closed_total", "Соединения, закрытые из-за conn_max_idle_time",
+            stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) })),
+        NewCounterFunc("db_max_lifetime_closed_total", "Соединения, закрытые из-за conn_max_lifetime",
+            stat(func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) })),
+    }
+}
@@ -0,0 +1,239 @@
+// Package metrics счётчики и гистограммы, которые отдаются в текстовом формате Prometheus
+package metrics
+
+import (
+    "fmt"
+    "io"
+    "math"
+    "net/http"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+)
+
+// ContentType формат ответа /metrics
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets границы гистограммы длительности в секундах
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Collector пишет свои метрики в текстовом формате
+type Collector interface {
+    Collect(w io.Writer)
+}
+
+// Registry набор метрик приложения
+type Registry struct {
+    mu       sync.Mutex
+    families []Collector
+}
+
+func NewRegistry() *Registry {
+    return &Registry{}
+}
+
+// Default метрики, которые обновляют обработчики и сервисы
+var Default = NewRegistry()
+
+func (r *Registry) register(c Collector) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.families = append(r.families, c)
+}
+
+func (r *Registry) Collect(w io.Writer) {
+    r.mu.Lock()
+    families := append([]Collector(nil), r.families...)
+    r.mu.Unlock()
+    for _, family := range families {
+        family.Collect(w)
+    }
+}
+
+// Handler отдаёт метрики всех сборщиков
+func Handler(collectors ...Collector) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", ContentType)
+        for _, c := range collectors {
+            c.Collect(w)
+        }
+    })
+}
+
+// desc имя, описание и метки семейства метрик
+type desc struct {
+    name   string
+    help   string
+    kind   string
+    labels []string
+}
+
+func (d desc) header(w io.Writer) {
+    fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(d.help), d.name, d.kind)
+}
+
+// labelKey значения меток одной серии; пустая строка у метрики без меток
+func (d desc) labelKey(values []string) string {
+    if len(values) != len(d.labels) {
+        panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", d.name, len(d.labels), len(values)))
+    }
+    return strings.Join(values, "\xff")
+}
+
+// series имя серии с метками: name{method="GET",status="200"}
+func (d desc) series(suffix, key string, extra ...string) string {
+    var pairs []string
+    if key != "" || len(d.labels) > 0 {
+        for i, value := range strings.Split(key, "\xff") {
+            pairs = append(pairs, d.labels[i]+`="`+escapeLabel(value)+`"`)
+        }
+    }
+    for i := 0; i+1 < len(extra); i += 2 {
+        pairs = append(pairs, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+    }
+    if len(pairs) == 0 {
+        return d.name + suffix
+    }
+    return d.name + suffix + "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabel(value string) string {
+    return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(v float64) string {
+    switch {
+    case math.IsInf(v, 1):
+        return "+Inf"
+    case math.IsInf(v, -1):
+        return "-Inf"
+    }
+    return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// CounterVec счётчик с метками; только растёт
+type CounterVec struct {
+    desc
+    mu     sync.Mutex
+    values map[string]float64
+}
+
+// NewCounter регистрирует счётчик; labels - имена меток
+func (r *Registry) NewCounter(name, help string, labels ...string) *CounterVec {
+    c := &CounterVec{desc: desc{name: name, help: help, kind: "counter", labels: labels}, values: map[string]float64{}}
+    r.register(c)
+    return c
+}
+
+// Inc увеличивает серию с указанными значениями меток на 1
+func (c *CounterVec) Inc(labelValues ...string) {
+    c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+    key := c.labelKey(labelValues)
+    c.mu.Lock()
+    c.values[key] += delta
+    c.mu.Unlock()
+}
+
+func (c *CounterVec) Collect(w io.Writer) {
+    c.header(w)
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if len(c.labels) == 0 {
+        fmt.Fprintf(w, "%s %s\n", c.name, formatFloat(c.values[""])) // Счётчик без меток виден и до первого события
+        return
+    }
+    for _, key := range sortedKeys(c.values) {
+        fmt.Fprintf(w, "%s %s\n", c.series("", key), formatFloat(c.values[key]))
+    }
+}
+
+// HistogramVec распределение значений (длительностей) с метками
+type HistogramVec struct {
+    desc
+    buckets []float64
+    mu      sync.Mutex
+    values  map[string]*histogram
+}
+
+type histogram struct {
+    counts []uint64 // По границам buckets, без накопления
+    count  uint64
+    sum    float64
+}
+
+// NewHistogram регистрирует гистограмму с границами buckets по возрастанию
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+    h := &HistogramVec{desc: desc{name: name, help: help, kind: "histogram", labels: labels}, buckets: buckets, values: map[string]*histogram{}}
+    r.register(h)
+    return h
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+    key := h.labelKey(labelValues)
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    series, ok := h.values[key]
+    if !ok {
+        series = &histogram{counts: make([]uint64, len(h.buckets))}
+        h.values[key] = series
+    }
+    for i, bound := range h.buckets {
+        if value <= bound {
+            series.counts[i]++
+            break
+        }
+    }
+    series.count++
+    series.sum += value
+}
+
+func (h *HistogramVec) Collect(w io.Writer) {
+    h.header(w)
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    for _, key := range sortedKeys(h.values) {
+        series := h.values[key]
+        var cumulative uint64
+        for i, bound := range h.buckets {
+            cumulative += series.counts[i]
+            fmt.Fprintf(w, "%s %d\n", h.series("_bucket", key, "le", formatFloat(bound)), cumulative)
+        }
+        fmt.Fprintf(w, "%s %d\n", h.series("_bucket", key, "le", "+Inf"), series.count)
+        fmt.Fprintf(w, "%s %s\n", h.series("_sum", key), formatFloat(series.sum))
+        fmt.Fprintf(w, "%s %d\n", h.series("_count", key), series.count)
+    }
+}
+
+// Func метрика без меток, значение которой читается при каждом сборе
+type Func struct {
+    desc
+    value func() float64
+}
+
+// NewGaugeFunc значение, которое может как расти, так и уменьшаться (открытые соединения)
+func NewGaugeFunc(name, help string, value func() float64) *Func {
+    return &Func{desc: desc{name: name, help: help, kind: "gauge"}, value: value}
+}
+
+// NewCounterFunc накопленное значение из внешнего источника (счётчики sql.DBStats)
+func NewCounterFunc(name, help string, value func() float64) *Func {
+    return &Func{desc: desc{name: name, help: help, kind: "counter"}, value: value}
+}
+
+func (f *Func) Collect(w io.Writer) {
+    f.header(w)
+    fmt.Fprintf(w, "%s %s\n", f.name, formatFloat(f.value()))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+    keys := make([]string, 0, len(m))
+    for key := range m {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    return keys
+}
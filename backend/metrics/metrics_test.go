@@ -0,0 +1,52 @@
+package metrics
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestTextFormat(t *testing.T) {
+    r := NewRegistry()
+    requests := r.NewCounter("requests_total", "Запросы", "route", "status")
+    duration := r.NewHistogram("duration_seconds", "Длительность", []float64{0.1, 1}, "route")
+    r.NewCounter("logins_total", "Входы")
+
+    requests.Inc("/students/:id", "200")
+    requests.Add(2, "/students/:id", "200")
+    requests.Inc(`/a"b`, "500")
+    duration.Observe(0.05, "/students")
+    duration.Observe(0.5, "/students")
+    duration.Observe(3, "/students")
+
+    w := httptest.NewRecorder()
+    Handler(r, NewGaugeFunc("open_connections", "Соединения", func() float64 { return 4 })).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+    if w.Header().Get("Content-Type") != ContentType {
+        t.Errorf("Content-Type = %q", w.Header().Get("Content-Type"))
+    }
+    want := `# HELP requests_total Запросы
+# TYPE requests_total counter
+requests_total{route="/a\"b",status="500"} 1
+requests_total{route="/students/:id",status="200"} 3
+# HELP duration_seconds Длительность
+# TYPE duration_seconds histogram
+duration_seconds_bucket{route="/students",le="0.1"} 1
+duration_seconds_bucket{route="/students",le="1"} 2
+duration_seconds_bucket{route="/students",le="+Inf"} 3
+duration_seconds_sum{route="/students"} 3.55
+duration_seconds_count{route="/students"} 3
+# HELP logins_total Входы
+# TYPE logins_total counter
+logins_total 0
+# HELP open_connections Соединения
+# TYPE open_connections gauge
+open_connections 4
+`
+    if got := w.Body.String(); got != want {
+        t.Errorf("metrics:\n%s\nwant:\n%s", got, want)
+    }
+    if strings.Contains(w.Body.String(), "\xff") {
+        t.Error("label separator leaked into the output")
+    }
+}
@@ -1,22 +1,29 @@
 package middleware
 
 import (
-
-    "net/http"
+    "backend/logging"
+    "backend/models"
+    "fmt"
+    "log/slog"
 
     "github.com/dgrijalva/jwt-go"
     "github.com/gin-gonic/gin"
-	"fmt"
 	"strings"
 )
 
-func AuthMiddleware(secretKey string) gin.HandlerFunc {
+// UserLoader возвращает пользователя по ID; nil - пользователя нет
+type UserLoader interface {
+    GetUserByID(id int) (*models.User, error)
+}
+
+// AuthMiddleware проверяет JWT. Роль и привязки к преподавателю и студенту берутся из users на каждый
+// запрос, а не из токена: иначе пользователь с отозванной ролью сохранял бы права до истечения токена
+func AuthMiddleware(secretKey string, users UserLoader) gin.HandlerFunc {
     return func(c *gin.Context) {
         // Получаем заголовок Authorization
         tokenString := c.GetHeader("Authorization")
         if tokenString == "" {
-            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
-            fmt.Println("Error: Missing token in Authorization header")
+            abortWithError(c, models.Unauthorized("missing token"))
             return
         }
 
@@ -24,42 +31,57 @@ func AuthMiddleware(secretKey string) gin.HandlerFunc {
         if len(tokenString) > 7 && strings.ToUpper(tokenString[:7]) == "BEARER " {
             tokenString = tokenString[7:]
         } else {
-            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token format"})
-            fmt.Println("Error: Token format is invalid")
+            abortWithError(c, models.Unauthorized("invalid token format"))
             return
         }
 
-        fmt.Println("Received token:", tokenString) // Отладочное сообщение
-
         // Парсим токен
         token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
             return []byte(secretKey), nil
         })
         if err != nil {
-            fmt.Println("Error parsing token:", err) // Отладочное сообщение
-            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token", "details": err.Error()})
+            abortWithError(c, models.Unauthorized("invalid token: %v", err))
             return
         }
 
         if !token.Valid {
-            fmt.Println("Token is not valid") // Отладочное сообщение
-            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+            abortWithError(c, models.Unauthorized("invalid token"))
             return
         }
 
         // Проверяем claims
         claims, ok := token.Claims.(jwt.MapClaims)
         if !ok {
-            fmt.Println("Invalid token claims") // Отладочное сообщение
-            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
+            abortWithError(c, models.Unauthorized("invalid token claims"))
             return
         }
 
-        fmt.Println("Token is valid. Claims:", claims) // Отладочное сообщение
+        userID, ok := claims["user_id"].(float64)
+        if !ok {
+            abortWithError(c, models.Unauthorized("invalid token claims"))
+            return
+        }
+        user, err := users.GetUserByID(int(userID))
+        if err != nil {
+            abortWithError(c, fmt.Errorf("failed to load user: %w", err))
+            return
+        }
+        if user == nil {
+            abortWithError(c, models.Unauthorized("user not found"))
+            return
+        }
 
-        // Устанавливаем user_id и role в контексте запроса
-        c.Set("user_id", int(claims["user_id"].(float64)))
-        c.Set("role", claims["role"].(string))
+        // Устанавливаем user_id и текущую роль в контексте запроса
+        c.Set("user_id", user.ID)
+        c.Set("role", user.Role)
+        if user.TeacherID != nil {
+            c.Set("teacher_id", *user.TeacherID)
+        }
+        if user.StudentID != nil {
+            c.Set("student_id", *user.StudentID)
+        }
+        // Пользователь попадает во все записи журнала по этому запросу
+        c.Request = c.Request.WithContext(logging.WithAttrs(c.Request.Context(), slog.Int("user_id", c.GetInt("user_id"))))
         c.Next()
     }
 }
\ No newline at end of file
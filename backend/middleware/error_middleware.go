@@ -0,0 +1,113 @@
+package middleware
+
+import (
+    "backend/models"
+    "database/sql"
+    "errors"
+    "log/slog"
+    "net/http"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "github.com/lib/pq"
+)
+
+// ErrorResponse тело ответа с ошибкой; одинаково для всех обработчиков
+type ErrorResponse struct {
+    Code    string            `json:"code"`
+    Error   string            `json:"error"`
+    Fields  map[string]string `json:"fields,omitempty"` // Сообщения по полям на языке запроса
+    Rules   map[string]string `json:"rules,omitempty"`  // Нарушенные правила по полям ("gt=0")
+    Details interface{}       `json:"details,omitempty"`
+}
+
+var errorStatuses = map[string]int{
+    models.CodeValidation:   http.StatusBadRequest,
+    models.CodeUnauthorized: http.StatusUnauthorized,
+    models.CodeForbidden:    http.StatusForbidden,
+    models.CodeNotFound:     http.StatusNotFound,
+    models.CodeConflict:     http.StatusConflict,
+    models.CodeTooLarge:     http.StatusRequestEntityTooLarge,
+    models.CodeInternal:     http.StatusInternalServerError,
+}
+
+// ErrorMiddleware отвечает на ошибку, переданную обработчиком через c.Error.
+// Код ошибки определяет HTTP-статус; текст неизвестных ошибок клиенту не отдаётся, а пишется в лог.
+// Ошибки валидации переводятся на язык из Accept-Language (ru по умолчанию или en)
+func ErrorMiddleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        c.Next()
+
+        if len(c.Errors) == 0 || c.Writer.Written() {
+            return
+        }
+        appErr := AsError(c.Errors.Last().Err)
+        // Обработчик мог сообщить о "неверном JSON", а на деле тело обрезано пределом
+        if limit, ok := c.Get(bodyTooLargeKey); ok {
+            appErr = models.TooLarge("request body is too large (max %d bytes)", limit)
+        }
+        if appErr.Code == models.CodeInternal {
+            slog.ErrorContext(c.Request.Context(), "internal error", "error", c.Errors.Last().Err)
+        }
+        response := ErrorResponse{
+            Code:    appErr.Code,
+            Error:   appErr.Message,
+            Details: appErr.Details,
+        }
+        if appErr.Code == models.CodeValidation {
+            response.Error, response.Fields = models.LocalizeValidation(appErr, requestLanguage(c))
+            response.Rules = appErr.Fields
+        }
+        c.JSON(errorStatuses[appErr.Code], response)
+    }
+}
+
+// requestLanguage первый поддерживаемый язык из Accept-Language ("en-US,en;q=0.9" -> en)
+func requestLanguage(c *gin.Context) string {
+    for _, tag := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+        tag = strings.TrimSpace(strings.Split(tag, ";")[0])
+        switch strings.ToLower(strings.Split(tag, "-")[0]) {
+        case models.LangRu:
+            return models.LangRu
+        case models.LangEn:
+            return models.LangEn
+        }
+    }
+    return models.LangRu
+}
+
+// AsError приводит любую ошибку к models.Error. Нарушения ограничений БД, не перехваченные
+// репозиторием, тоже получают осмысленный код
+func AsError(err error) *models.Error {
+    var appErr *models.Error
+    if errors.As(err, &appErr) {
+        if _, ok := errorStatuses[appErr.Code]; ok {
+            return appErr
+        }
+    }
+
+    if errors.Is(err, sql.ErrNoRows) {
+        return models.NotFound("record not found")
+    }
+
+    var pqErr *pq.Error
+    if errors.As(err, &pqErr) {
+        switch pqErr.Code {
+        case "23505": // unique_violation
+            return models.Conflict("record already exists")
+        case "23503": // foreign_key_violation
+            return models.Conflict("record is referenced by or references a missing record")
+        case "23514", "22P02", "22007", "22008": // check_violation, неверный формат значения или даты
+            return models.Invalid("invalid value: %s", pqErr.Message)
+        case "40001", "40P01": // serialization_failure, deadlock_detected
+            return models.Conflict("concurrent update, retry the request")
+        }
+    }
+    return &models.Error{Code: models.CodeInternal, Message: "internal server error"}
+}
+
+// abortWithError прерывает цепочку; ответ пишет ErrorMiddleware
+func abortWithError(c *gin.Context, err error) {
+    c.Error(err)
+    c.Abort()
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+    "backend/logging"
+    "crypto/rand"
+    "encoding/hex"
+    "fmt"
+    "log/slog"
+    "net/http"
+    "regexp"
+    "runtime/debug"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader заголовок с номером запроса; приходит от прокси или создаётся заново и возвращается в ответе
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID номер запроса от клиента принимается, только если он не испортит журнал
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID присваивает запросу номер для сквозного поиска в журналах и отдаёт его в X-Request-ID.
+// Номер, метод и маршрут попадают во все записи, сделанные с контекстом запроса
+func RequestID() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        id := c.GetHeader(RequestIDHeader)
+        if !validRequestID.MatchString(id) {
+            id = newRequestID()
+        }
+        c.Set("request_id", id)
+        c.Header(RequestIDHeader, id)
+
+        ctx := logging.WithAttrs(c.Request.Context(),
+            slog.String("request_id", id),
+            slog.String("method", c.Request.Method),
+            slog.String("route", c.FullPath()),
+        )
+        c.Request = c.Request.WithContext(ctx)
+        c.Next()
+    }
+}
+
+func newRequestID() string {
+    b := make([]byte, 16)
+    rand.Read(b)
+    return hex.EncodeToString(b)
+}
+
+// AccessLog пишет в журнал каждый запрос: статус, длительность и размер ответа.
+// Ответы 5xx пишутся с уровнем error, 4xx - warn
+func AccessLog(logger *slog.Logger) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        start := time.Now()
+        c.Next()
+
+        status := c.Writer.Status()
+        level := slog.LevelInfo
+        switch {
+        case status >= http.StatusInternalServerError:
+            level = slog.LevelError
+        case status >= http.StatusBadRequest:
+            level = slog.LevelWarn
+        }
+        attrs := []slog.Attr{
+            slog.String("path", c.Request.URL.Path),
+            slog.Int("status", status),
+            slog.Duration("latency", time.Since(start)),
+            slog.Int("bytes", c.Writer.Size()),
+            slog.String("client_ip", c.ClientIP()),
+        }
+        logger.LogAttrs(c.Request.Context(), level, "request", attrs...)
+    }
+}
+
+// Recovery превращает панику обработчика во внутреннюю ошибку; ErrorMiddleware отвечает 500 и пишет её в журнал со стеком
+func Recovery() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        defer func() {
+            if recovered := recover(); recovered != nil {
+                abortWithError(c, fmt.Errorf("panic: %v\n%s", recovered, debug.Stack()))
+            }
+        }()
+        c.Next()
+    }
+}
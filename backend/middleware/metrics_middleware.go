@@ -0,0 +1,26 @@
+package middleware
+
+import (
+    "backend/metrics"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+// Metrics считает запросы и их длительность по маршрутам и статусам.
+// Запросы к несуществующим путям идут под одним маршрутом, чтобы не плодить серии
+func Metrics() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        start := time.Now()
+        c.Next()
+
+        route := c.FullPath()
+        if route == "" {
+            route = "unmatched"
+        }
+        status := strconv.Itoa(c.Writer.Status())
+        metrics.HTTPRequests.Inc(c.Request.Method, route, status)
+        metrics.HTTPDuration.Observe(time.Since(start).Seconds(), c.Request.Method, route, status)
+    }
+}
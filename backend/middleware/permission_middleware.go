@@ -0,0 +1,51 @@
+package middleware
+
+import (
+    "backend/models"
+    "fmt"
+
+    "github.com/gin-gonic/gin"
+)
+
+// PermissionChecker возвращает набор прав роли
+type PermissionChecker interface {
+    RolePermissions(role string) (map[string]bool, error)
+}
+
+// PermissionMiddleware пропускает запрос, если у роли пользователя есть хотя бы одно из прав.
+// Права роли сохраняются в контексте для проверок внутри обработчиков
+func PermissionMiddleware(checker PermissionChecker, required ...string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        role := c.GetString("role")
+        if role == "" {
+            abortWithError(c, models.Unauthorized("role not found"))
+            return
+        }
+
+        permissions, err := checker.RolePermissions(role)
+        if err != nil {
+            abortWithError(c, fmt.Errorf("failed to load permissions: %v", err))
+            return
+        }
+        c.Set("permissions", permissions)
+
+        for _, permission := range required {
+            if permissions[permission] {
+                c.Next()
+                return
+            }
+        }
+
+        abortWithError(c, models.Forbidden("access denied").WithDetails(gin.H{"required": required}))
+    }
+}
+
+// HasPermission проверяет право, загруженное PermissionMiddleware
+func HasPermission(c *gin.Context, permission string) bool {
+    permissions, ok := c.Get("permissions")
+    if !ok {
+        return false
+    }
+    set, ok := permissions.(map[string]bool)
+    return ok && set[permission]
+}
\ No newline at end of file
@@ -1,7 +1,7 @@
 package middleware
 
 import (
-    "net/http"
+    "backend/models"
 
     "github.com/gin-gonic/gin"
 )
@@ -12,7 +12,7 @@ func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
         // Получаем роль из контекста
         role := c.GetString("role")
         if role == "" {
-            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "role not found"})
+            abortWithError(c, models.Unauthorized("role not found"))
             return
         }
 
@@ -25,6 +25,6 @@ func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
         }
 
         // Если роль не разрешена, возвращаем ошибку
-        c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "access denied"})
+        abortWithError(c, models.Forbidden("access denied"))
     }
 }
\ No newline at end of file
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+    "errors"
+    "io"
+    "net/http"
+    "slices"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+)
+
+const (
+    originalBodyKey = "original_body"
+    bodyTooLargeKey = "body_too_large"
+)
+
+// LimitBody ограничивает тело запроса limit байтами; повторный вызов (в обработчике загрузки)
+// заменяет общий предел своим. При превышении чтение тела возвращает ошибку, а ответ - 413
+func LimitBody(c *gin.Context, limit int64) {
+    body, ok := c.Get(originalBodyKey)
+    if !ok {
+        body = c.Request.Body
+        c.Set(originalBodyKey, body)
+    }
+    c.Request.Body = limitedBody{ReadCloser: http.MaxBytesReader(c.Writer, body.(io.ReadCloser), limit), c: c}
+}
+
+// limitedBody запоминает в контексте, что тело превысило предел, чтобы ErrorMiddleware ответил 413
+type limitedBody struct {
+    io.ReadCloser
+    c *gin.Context
+}
+
+func (b limitedBody) Read(p []byte) (int, error) {
+    n, err := b.ReadCloser.Read(p)
+    var tooLarge *http.MaxBytesError
+    if errors.As(err, &tooLarge) {
+        b.c.Set(bodyTooLargeKey, tooLarge.Limit)
+    }
+    return n, err
+}
+
+// BodyLimit общий предел тела запроса
+func BodyLimit(limit int64) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        LimitBody(c, limit)
+        c.Next()
+    }
+}
+
+// SecurityHeaders запрещает браузеру угадывать тип содержимого, встраивать ответы во фреймы
+// и передавать адрес страницы. С HTTPS браузер запоминает, что сайт открывается только по нему
+func SecurityHeaders(tls bool) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        header := c.Writer.Header()
+        header.Set("X-Content-Type-Options", "nosniff")
+        header.Set("X-Frame-Options", "DENY")
+        header.Set("Content-Security-Policy", "frame-ancestors 'none'")
+        header.Set("Referrer-Policy", "no-referrer")
+        if tls {
+            header.Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+        }
+        c.Next()
+    }
+}
+
+// Заголовки, которые фронтенд отправляет и читает
+var (
+    corsAllowHeaders  = "Authorization, Content-Type, Accept, Accept-Language, X-Request-ID"
+    corsExposeHeaders = "X-Request-ID, Content-Disposition, Deprecation, Sunset, Link"
+    corsAllowMethods  = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+)
+
+// CORS разрешает браузеру запросы к API со страниц с источников origins ("*" - с любых).
+// Предварительные запросы OPTIONS получают ответ 204 без обращения к маршрутам.
+// Токен передаётся в заголовке, а не в cookie, поэтому credentials не разрешаются
+func CORS(origins []string) gin.HandlerFunc {
+    allowAll := slices.Contains(origins, "*")
+    allowed := map[string]bool{}
+    for _, origin := range origins {
+        allowed[strings.TrimSuffix(origin, "/")] = true // Браузер присылает источник без "/"
+    }
+    return func(c *gin.Context) {
+        origin := c.GetHeader("Origin")
+        if origin == "" || (!allowAll && !allowed[origin]) {
+            c.Next()
+            return
+        }
+
+        header := c.Writer.Header()
+        header.Add("Vary", "Origin")
+        if allowAll {
+            header.Set("Access-Control-Allow-Origin", "*")
+        } else {
+            header.Set("Access-Control-Allow-Origin", origin)
+        }
+        header.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+
+        if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+            header.Set("Access-Control-Allow-Methods", corsAllowMethods)
+            header.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+            header.Set("Access-Control-Max-Age", "600")
+            c.AbortWithStatus(http.StatusNoContent)
+            return
+        }
+        c.Next()
+    }
+}
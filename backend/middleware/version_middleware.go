@@ -0,0 +1,50 @@
+package middleware
+
+import (
+    "fmt"
+    "net/http"
+    "strings"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+// Версии API
+const (
+    APIv1     = "v1"
+    APILegacy = "legacy" // Маршруты /api без версии, оставлены на время перехода на /api/v1
+)
+
+const apiVersionKey = "api_version"
+
+// APIVersion запоминает версию API, по которой пришёл запрос
+func APIVersion(version string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        c.Set(apiVersionKey, version)
+        c.Next()
+    }
+}
+
+// IsLegacyAPI сообщает, что запрос пришёл на маршрут без версии и ответ нужен в старом формате
+func IsLegacyAPI(c *gin.Context) bool {
+    return c.GetString(apiVersionKey) == APILegacy
+}
+
+// Deprecated помечает ответы устаревших маршрутов заголовками Deprecation (RFC 9745), Sunset (RFC 8594)
+// и ссылками на тот же маршрут новой версии и на документацию. from и to - префиксы старого и нового пути
+func Deprecated(since, sunset time.Time, from, to, docs string) gin.HandlerFunc {
+    deprecation := fmt.Sprintf("@%d", since.Unix())
+    sunsetDate := sunset.UTC().Format(http.TimeFormat)
+    return func(c *gin.Context) {
+        successor := to + strings.TrimPrefix(c.Request.URL.Path, from)
+        if c.Request.URL.RawQuery != "" {
+            successor += "?" + c.Request.URL.RawQuery
+        }
+        header := c.Writer.Header()
+        header.Set("Deprecation", deprecation)
+        header.Set("Sunset", sunsetDate)
+        header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+        header.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"; type="text/html"`, docs))
+        c.Next()
+    }
+}
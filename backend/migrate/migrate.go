@@ -0,0 +1,453 @@
+// Package migrate применяет встроенные миграции и ведёт учёт версий схемы
+package migrate
+
+import (
+    "context"
+    "crypto/sha256"
+    "database/sql"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "io/fs"
+    "regexp"
+    "sort"
+    "strconv"
+    "time"
+)
+
+// Таблица учёта применённых миграций
+const versionTable = "schema_versions"
+
+// Ключ advisory-блокировки, чтобы два экземпляра не применяли миграции одновременно
+const lockKey = 7243150021
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// ErrDatabaseAhead база содержит миграции, которых нет в бинарном файле
+var ErrDatabaseAhead = errors.New("database schema is ahead of the application")
+
+// ErrNotInitialised таблицы версий ещё нет: её создаёт migrate up (или serve с автоматическими миграциями)
+var ErrNotInitialised = errors.New("migrations are not initialised, run migrate up")
+
+type Migration struct {
+    Version  int64
+    Name     string
+    Up       string
+    Down     string
+    Checksum string // SHA-256 файла up
+}
+
+// Status состояние миграции в базе
+type Status struct {
+    Version          int64      `json:"version"`
+    Name             string     `json:"name"`
+    Applied          bool       `json:"applied"`
+    AppliedAt        *time.Time `json:"applied_at"`
+    ChecksumMismatch bool       `json:"checksum_mismatch"` // Файл изменён после применения
+    Unknown          bool       `json:"unknown"`           // Есть в базе, но нет в бинарном файле
+}
+
+// Load читает миграции из файловой системы и сортирует их по версии
+func Load(fsys fs.FS) ([]Migration, error) {
+    entries, err := fs.ReadDir(fsys, ".")
+    if err != nil {
+        return nil, err
+    }
+
+    byVersion := map[int64]*Migration{}
+    for _, entry := range entries {
+        match := fileName.FindStringSubmatch(entry.Name())
+        if match == nil {
+            continue
+        }
+        version, err := strconv.ParseInt(match[1], 10, 64)
+        if err != nil {
+            return nil, fmt.Errorf("invalid migration version in %s: %v", entry.Name(), err)
+        }
+        data, err := fs.ReadFile(fsys, entry.Name())
+        if err != nil {
+            return nil, err
+        }
+
+        migration, ok := byVersion[version]
+        if !ok {
+            migration = &Migration{Version: version, Name: match[2]}
+            byVersion[version] = migration
+        } else if migration.Name != match[2] {
+            return nil, fmt.Errorf("migration %d has different names: %s and %s", version, migration.Name, match[2])
+        }
+
+        if match[3] == "up" {
+            migration.Up = string(data)
+            sum := sha256.Sum256(data)
+            migration.Checksum = hex.EncodeToString(sum[:])
+        } else {
+            migration.Down = string(data)
+        }
+    }
+
+    migrations := make([]Migration, 0, len(byVersion))
+    for _, migration := range byVersion {
+        if migration.Checksum == "" {
+            return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+        }
+        migrations = append(migrations, *migration)
+    }
+    sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+    return migrations, nil
+}
+
+type Runner struct {
+    DB         *sql.DB
+    Migrations []Migration
+    Log        func(format string, args ...interface{})
+}
+
+func NewRunner(db *sql.DB, migrations []Migration) *Runner {
+    return &Runner{DB: db, Migrations: migrations, Log: func(format string, args ...interface{}) {
+        fmt.Printf(format+"\n", args...)
+    }}
+}
+
+type appliedVersion struct {
+    Name      string
+    Checksum  string
+    AppliedAt time.Time
+}
+
+// ensureTable создает таблицу версий. Если схема раньше накатывалась утилитой golang-migrate,
+// её версия переносится: все миграции до неё считаются применёнными
+func (r *Runner) ensureTable() error {
+    _, err := r.DB.Exec(`
+        CREATE TABLE IF NOT EXISTS ` + versionTable + ` (
+            version BIGINT PRIMARY KEY,
+            name VARCHAR(255) NOT NULL,
+            checksum VARCHAR(64) NOT NULL,
+            applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+        )
+    `)
+    if err != nil {
+        return fmt.Errorf("failed to create %s: %v", versionTable, err)
+    }
+
+    var count int
+    if err := r.DB.QueryRow(`SELECT COUNT(*) FROM ` + versionTable).Scan(&count); err != nil {
+        return err
+    }
+    if count > 0 {
+        return nil
+    }
+
+    var legacy sql.NullString
+    if err := r.DB.QueryRow(`SELECT to_regclass('schema_migrations')::text`).Scan(&legacy); err != nil {
+        return err
+    }
+    if !legacy.Valid {
+        return nil
+    }
+
+    var version int64
+    var dirty bool
+    err = r.DB.QueryRow(`SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil
+    }
+    if err != nil {
+        return fmt.Errorf("failed to read golang-migrate version: %v", err)
+    }
+    if dirty {
+        return fmt.Errorf("golang-migrate version %d is dirty, fix the schema manually first", version)
+    }
+
+    for _, migration := range r.Migrations {
+        if migration.Version > version {
+            break
+        }
+        _, err := r.DB.Exec(`INSERT INTO `+versionTable+` (version, name, checksum) VALUES ($1, $2, $3)`,
+            migration.Version, migration.Name, migration.Checksum)
+        if err != nil {
+            return err
+        }
+    }
+    r.Log("Imported golang-migrate version %d", version)
+    return nil
+}
+
+// applied читает таблицу версий, ничего не создавая; если таблицы нет - ErrNotInitialised
+func (r *Runner) applied() (map[int64]appliedVersion, error) {
+    var table sql.NullString
+    if err := r.DB.QueryRow(`SELECT to_regclass($1)::text`, versionTable).Scan(&table); err != nil {
+        return nil, err
+    }
+    if !table.Valid {
+        return nil, ErrNotInitialised
+    }
+
+    rows, err := r.DB.Query(`SELECT version, name, checksum, applied_at FROM ` + versionTable)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    result := map[int64]appliedVersion{}
+    for rows.Next() {
+        var version int64
+        var item appliedVersion
+        if err := rows.Scan(&version, &item.Name, &item.Checksum, &item.AppliedAt); err != nil {
+            return nil, err
+        }
+        result[version] = item
+    }
+    return result, rows.Err()
+}
+
+// withLock выполняет fn под advisory-блокировкой. Блокировка сессионная,
+// поэтому берётся и снимается на одном выделенном соединении
+func (r *Runner) withLock(fn func() error) error {
+    ctx := context.Background()
+    conn, err := r.DB.Conn(ctx)
+    if err != nil {
+        return err
+    }
+    defer conn.Close()
+
+    if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+        return fmt.Errorf("failed to acquire migration lock: %v", err)
+    }
+    defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, lockKey)
+
+    if err := r.ensureTable(); err != nil {
+        return err
+    }
+    return fn()
+}
+
+// Status состояние встроенных и применённых миграций. Только читает таблицу версий:
+// создание таблицы и перенос версии golang-migrate выполняются под блокировкой в Up, Down и Goto
+func (r *Runner) Status() ([]Status, error) {
+    applied, err := r.applied()
+    if err != nil {
+        return nil, err
+    }
+
+    statuses := []Status{}
+    known := map[int64]bool{}
+    for _, migration := range r.Migrations {
+        known[migration.Version] = true
+        status := Status{Version: migration.Version, Name: migration.Name}
+        if item, ok := applied[migration.Version]; ok {
+            appliedAt := item.AppliedAt
+            status.Applied = true
+            status.AppliedAt = &appliedAt
+            status.ChecksumMismatch = item.Checksum != migration.Checksum
+        }
+        statuses = append(statuses, status)
+    }
+    for version, item := range applied {
+        if known[version] {
+            continue
+        }
+        appliedAt := item.AppliedAt
+        statuses = append(statuses, Status{Version: version, Name: item.Name, Applied: true, AppliedAt: &appliedAt, Unknown: true})
+    }
+    sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+    return statuses, nil
+}
+
+// Check проверяет, что бинарный файл знает все применённые миграции.
+// Изменённые после применения файлы только выводятся предупреждением
+func (r *Runner) Check() error {
+    statuses, err := r.Status()
+    if err != nil {
+        return err
+    }
+    for _, status := range statuses {
+        if status.Unknown {
+            return fmt.Errorf("%w: version %d (%s) is applied but not embedded", ErrDatabaseAhead, status.Version, status.Name)
+        }
+        if status.ChecksumMismatch {
+            r.Log("Warning: migration %d_%s was changed after it had been applied", status.Version, status.Name)
+        }
+    }
+    return nil
+}
+
+// Current проверяет, что схема совпадает с бинарным файлом: все миграции применены и неизвестных нет
+func (r *Runner) Current() error {
+    statuses, err := r.Status()
+    if err != nil {
+        return err
+    }
+    pending := 0
+    for _, status := range statuses {
+        if status.Unknown {
+            return fmt.Errorf("%w: version %d (%s) is applied but not embedded", ErrDatabaseAhead, status.Version, status.Name)
+        }
+        if !status.Applied {
+            pending++
+        }
+    }
+    if pending > 0 {
+        return fmt.Errorf("%d migration(s) are not applied", pending)
+    }
+    return nil
+}
+
+// Pending возвращает неприменённые миграции
+func (r *Runner) Pending() ([]Migration, error) {
+    statuses, err := r.Status()
+    if err != nil {
+        return nil, err
+    }
+    applied := map[int64]bool{}
+    for _, status := range statuses {
+        if status.Applied {
+            applied[status.Version] = true
+        }
+    }
+
+    pending := []Migration{}
+    for _, migration := range r.Migrations {
+        if !applied[migration.Version] {
+            pending = append(pending, migration)
+        }
+    }
+    return pending, nil
+}
+
+// Up применяет все неприменённые миграции
+func (r *Runner) Up() (int, error) {
+    return r.Goto(r.latest())
+}
+
+// Down откатывает последние steps применённых миграций
+func (r *Runner) Down(steps int) (int, error) {
+    if steps <= 0 {
+        return 0, errors.New("steps must be positive")
+    }
+
+    count := 0
+    err := r.withLock(func() error {
+        applied, err := r.applied()
+        if err != nil {
+            return err
+        }
+        for i := len(r.Migrations) - 1; i >= 0 && count < steps; i-- {
+            migration := r.Migrations[i]
+            if _, ok := applied[migration.Version]; !ok {
+                continue
+            }
+            if err := r.revert(migration); err != nil {
+                return err
+            }
+            count++
+        }
+        return nil
+    })
+    return count, err
+}
+
+// Goto приводит схему к версии: применяет миграции до неё и откатывает более новые.
+// Версия 0 означает откат всех миграций
+func (r *Runner) Goto(version int64) (int, error) {
+    if version != 0 && r.find(version) == nil {
+        return 0, fmt.Errorf("unknown migration version %d", version)
+    }
+
+    count := 0
+    err := r.withLock(func() error {
+        applied, err := r.applied()
+        if err != nil {
+            return err
+        }
+        for known := range applied {
+            if r.find(known) == nil {
+                return fmt.Errorf("%w: version %d is applied but not embedded", ErrDatabaseAhead, known)
+            }
+        }
+
+        // Сначала откатываем новее целевой версии, начиная с последней
+        for i := len(r.Migrations) - 1; i >= 0; i-- {
+            migration := r.Migrations[i]
+            if _, ok := applied[migration.Version]; ok && migration.Version > version {
+                if err := r.revert(migration); err != nil {
+                    return err
+                }
+                count++
+            }
+        }
+        for _, migration := range r.Migrations {
+            if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+                if err := r.apply(migration); err != nil {
+                    return err
+                }
+                count++
+            }
+        }
+        return nil
+    })
+    return count, err
+}
+
+func (r *Runner) latest() int64 {
+    if len(r.Migrations) == 0 {
+        return 0
+    }
+    return r.Migrations[len(r.Migrations)-1].Version
+}
+
+func (r *Runner) find(version int64) *Migration {
+    for i := range r.Migrations {
+        if r.Migrations[i].Version == version {
+            return &r.Migrations[i]
+        }
+    }
+    return nil
+}
+
+// apply выполняет миграцию и запись о ней в одной транзакции
+func (r *Runner) apply(migration Migration) error {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.Exec(migration.Up); err != nil {
+        return fmt.Errorf("migration %d_%s failed: %v", migration.Version, migration.Name, err)
+    }
+    _, err = tx.Exec(`INSERT INTO `+versionTable+` (version, name, checksum) VALUES ($1, $2, $3)`,
+        migration.Version, migration.Name, migration.Checksum)
+    if err != nil {
+        return err
+    }
+    if err := tx.Commit(); err != nil {
+        return err
+    }
+    r.Log("Applied %d_%s", migration.Version, migration.Name)
+    return nil
+}
+
+func (r *Runner) revert(migration Migration) error {
+    if migration.Down == "" {
+        return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.Exec(migration.Down); err != nil {
+        return fmt.Errorf("rollback of %d_%s failed: %v", migration.Version, migration.Name, err)
+    }
+    if _, err := tx.Exec(`DELETE FROM `+versionTable+` WHERE version = $1`, migration.Version); err != nil {
+        return err
+    }
+    if err := tx.Commit(); err != nil {
+        return err
+    }
+    r.Log("Reverted %d_%s", migration.Version, migration.Name)
+    return nil
+}
\ No newline at end of file
@@ -0,0 +1,86 @@
+package main
+
+import (
+    "backend/migrate"
+    "backend/migrations"
+    "database/sql"
+    "errors"
+    "fmt"
+    "strconv"
+)
+
+func newMigrationRunner(db *sql.DB) (*migrate.Runner, error) {
+    list, err := migrate.Load(migrations.FS)
+    if err != nil {
+        return nil, err
+    }
+    return migrate.NewRunner(db, list), nil
+}
+
+// runMigrate выполняет подкоманду migrate: up, down [N], status, goto VERSION
+func runMigrate(runner *migrate.Runner, args []string) error {
+    if len(args) == 0 {
+        return errors.New("usage: migrate up | down [N] | status | goto VERSION")
+    }
+
+    switch args[0] {
+    case "up":
+        count, err := runner.Up()
+        if err != nil {
+            return err
+        }
+        fmt.Printf("Applied %d migration(s)\n", count)
+    case "down":
+        steps := 1
+        if len(args) > 1 {
+            n, err := strconv.Atoi(args[1])
+            if err != nil {
+                return fmt.Errorf("invalid number of steps: %s", args[1])
+            }
+            steps = n
+        }
+        count, err := runner.Down(steps)
+        if err != nil {
+            return err
+        }
+        fmt.Printf("Reverted %d migration(s)\n", count)
+    case "goto":
+        if len(args) < 2 {
+            return errors.New("usage: migrate goto VERSION")
+        }
+        version, err := strconv.ParseInt(args[1], 10, 64)
+        if err != nil {
+            return fmt.Errorf("invalid version: %s", args[1])
+        }
+        count, err := runner.Goto(version)
+        if err != nil {
+            return err
+        }
+        fmt.Printf("Schema is at version %d (%d migration(s) changed)\n", version, count)
+    case "status":
+        statuses, err := runner.Status()
+        if errors.Is(err, migrate.ErrNotInitialised) {
+            fmt.Println("Migrations are not initialised, run migrate up")
+            return nil
+        }
+        if err != nil {
+            return err
+        }
+        for _, status := range statuses {
+            state := "pending"
+            if status.Applied {
+                state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+            }
+            if status.ChecksumMismatch {
+                state += " (changed after apply)"
+            }
+            if status.Unknown {
+                state += " (not embedded in this build)"
+            }
+            fmt.Printf("%d  %-45s %s\n", status.Version, status.Name, state)
+        }
+    default:
+        return fmt.Errorf("unknown migrate command: %s", args[0])
+    }
+    return nil
+}
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS grades;
+DROP TABLE IF EXISTS grade_sheet_entries;
+DROP TABLE IF EXISTS grade_sheets;
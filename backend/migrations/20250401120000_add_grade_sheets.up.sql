@@ -0,0 +1,32 @@
+CREATE TABLE grade_sheets (
+    id SERIAL PRIMARY KEY,
+    number VARCHAR(50) NOT NULL DEFAULT '',
+    course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    teacher_id INT REFERENCES teachers(id) ON DELETE SET NULL,
+    control_type VARCHAR(10) NOT NULL CHECK (control_type IN ('exam', 'credit')),
+    status VARCHAR(10) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'issued', 'filled', 'closed')),
+    parent_id INT REFERENCES grade_sheets(id) ON DELETE SET NULL,
+    exam_date DATE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    issued_at TIMESTAMP,
+    closed_at TIMESTAMP,
+    closed_by INT REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE grade_sheet_entries (
+    id SERIAL PRIMARY KEY,
+    grade_sheet_id INT NOT NULL REFERENCES grade_sheets(id) ON DELETE CASCADE,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    mark VARCHAR(10) CHECK (mark IN ('5', '4', '3', '2', 'pass', 'fail', 'absent')),
+    UNIQUE (grade_sheet_id, student_id)
+);
+
+CREATE TABLE grades (
+    id SERIAL PRIMARY KEY,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    grade_sheet_id INT REFERENCES grade_sheets(id) ON DELETE SET NULL,
+    mark VARCHAR(10) NOT NULL,
+    graded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (student_id, course_id)
+);
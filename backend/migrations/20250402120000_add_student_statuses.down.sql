@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS student_group_history;
+DROP TABLE IF EXISTS student_orders;
+ALTER TABLE students DROP COLUMN status;
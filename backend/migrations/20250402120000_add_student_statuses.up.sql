@@ -0,0 +1,25 @@
+ALTER TABLE students ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'academic_leave', 'expelled', 'graduated', 'transferred'));
+
+CREATE TABLE student_orders (
+    id SERIAL PRIMARY KEY,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    order_number VARCHAR(50) NOT NULL,
+    order_date DATE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    old_status VARCHAR(20) NOT NULL,
+    new_status VARCHAR(20) NOT NULL,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE student_group_history (
+    id SERIAL PRIMARY KEY,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    old_group VARCHAR(50) NOT NULL,
+    new_group VARCHAR(50) NOT NULL,
+    order_id INT REFERENCES student_orders(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_students_status ON students(status);
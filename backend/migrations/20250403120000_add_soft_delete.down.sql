@@ -0,0 +1,5 @@
+ALTER TABLE schedules DROP COLUMN deleted_at;
+ALTER TABLE classrooms DROP COLUMN deleted_at;
+ALTER TABLE courses DROP COLUMN deleted_at;
+ALTER TABLE students DROP COLUMN deleted_at;
+ALTER TABLE teachers DROP COLUMN deleted_at;
@@ -0,0 +1,11 @@
+ALTER TABLE teachers ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE students ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE courses ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE classrooms ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE schedules ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_teachers_deleted_at ON teachers(deleted_at);
+CREATE INDEX idx_students_deleted_at ON students(deleted_at);
+CREATE INDEX idx_courses_deleted_at ON courses(deleted_at);
+CREATE INDEX idx_classrooms_deleted_at ON classrooms(deleted_at);
+CREATE INDEX idx_schedules_deleted_at ON schedules(deleted_at);
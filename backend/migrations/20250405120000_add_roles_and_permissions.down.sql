@@ -0,0 +1,8 @@
+ALTER TABLE users DROP COLUMN teacher_id;
+ALTER TABLE users DROP CONSTRAINT users_role_fkey;
+UPDATE users SET role = 'teacher' WHERE role NOT IN ('admin', 'teacher');
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'teacher'));
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,112 @@
+CREATE TABLE roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Администратор'),
+    ('teacher', 'Преподаватель'),
+    ('registrar', 'Учебная часть'),
+    ('department_head', 'Заведующий отделением'),
+    ('dispatcher', 'Диспетчер расписания'),
+    ('curator', 'Куратор группы'),
+    ('student', 'Студент'),
+    ('auditor', 'Аудитор (только чтение)');
+
+INSERT INTO permissions (name, description) VALUES
+    ('teachers:read', 'Просмотр преподавателей'),
+    ('teachers:write', 'Изменение преподавателей'),
+    ('students:read', 'Просмотр студентов'),
+    ('students:write', 'Изменение студентов и приказы'),
+    ('courses:read', 'Просмотр курсов'),
+    ('courses:write', 'Изменение курсов'),
+    ('classrooms:read', 'Просмотр аудиторий'),
+    ('classrooms:write', 'Изменение аудиторий'),
+    ('schedule:read', 'Просмотр расписания'),
+    ('schedule:write', 'Изменение расписания'),
+    ('grades:read', 'Просмотр всех ведомостей и оценок'),
+    ('grades:read:own-courses', 'Просмотр ведомостей своих курсов'),
+    ('grades:write', 'Выставление оценок в любые ведомости'),
+    ('grades:write:own-courses', 'Выставление оценок в ведомости своих курсов'),
+    ('grade_sheets:manage', 'Создание, выдача, закрытие ведомостей'),
+    ('trash:manage', 'Корзина и восстановление записей'),
+    ('audit:read', 'Просмотр журнала аудита'),
+    ('notify:send', 'Отправка уведомлений'),
+    ('users:manage', 'Управление пользователями и ролями'),
+    ('profile:write', 'Изменение своего профиля');
+
+-- Администратор получает все права
+INSERT INTO role_permissions (role, permission)
+SELECT 'admin', name FROM permissions;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('teacher', 'courses:read'),
+    ('teacher', 'schedule:read'),
+    ('teacher', 'grades:read:own-courses'),
+    ('teacher', 'grades:write:own-courses'),
+    ('teacher', 'profile:write'),
+
+    ('registrar', 'teachers:read'),
+    ('registrar', 'students:read'),
+    ('registrar', 'students:write'),
+    ('registrar', 'courses:read'),
+    ('registrar', 'classrooms:read'),
+    ('registrar', 'schedule:read'),
+    ('registrar', 'grades:read'),
+    ('registrar', 'grades:write'),
+    ('registrar', 'grade_sheets:manage'),
+    ('registrar', 'profile:write'),
+
+    ('department_head', 'teachers:read'),
+    ('department_head', 'teachers:write'),
+    ('department_head', 'students:read'),
+    ('department_head', 'courses:read'),
+    ('department_head', 'courses:write'),
+    ('department_head', 'classrooms:read'),
+    ('department_head', 'schedule:read'),
+    ('department_head', 'grades:read'),
+    ('department_head', 'grade_sheets:manage'),
+    ('department_head', 'audit:read'),
+    ('department_head', 'notify:send'),
+    ('department_head', 'profile:write'),
+
+    ('dispatcher', 'teachers:read'),
+    ('dispatcher', 'courses:read'),
+    ('dispatcher', 'classrooms:read'),
+    ('dispatcher', 'classrooms:write'),
+    ('dispatcher', 'schedule:read'),
+    ('dispatcher', 'schedule:write'),
+    ('dispatcher', 'profile:write'),
+
+    ('curator', 'students:read'),
+    ('curator', 'courses:read'),
+    ('curator', 'schedule:read'),
+    ('curator', 'grades:read'),
+    ('curator', 'notify:send'),
+    ('curator', 'profile:write'),
+
+    ('auditor', 'teachers:read'),
+    ('auditor', 'students:read'),
+    ('auditor', 'courses:read'),
+    ('auditor', 'classrooms:read'),
+    ('auditor', 'schedule:read'),
+    ('auditor', 'grades:read'),
+    ('auditor', 'audit:read');
+
+-- Роль пользователя теперь ссылается на таблицу ролей вместо жёсткого списка
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
+
+-- Связь учётной записи с преподавателем для правил "только свои курсы"
+ALTER TABLE users ADD COLUMN teacher_id INT REFERENCES teachers(id) ON DELETE SET NULL;
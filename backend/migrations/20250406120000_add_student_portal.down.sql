@@ -0,0 +1,9 @@
+DELETE FROM role_permissions WHERE permission IN ('portal:read', 'attendance:write', 'attendance:write:own-courses', 'announcements:write');
+DELETE FROM permissions WHERE name IN ('portal:read', 'attendance:write', 'attendance:write:own-courses', 'announcements:write');
+
+DROP TABLE IF EXISTS announcements;
+DROP TABLE IF EXISTS attendance;
+DROP TABLE IF EXISTS schedule_overrides;
+DROP TABLE IF EXISTS activation_codes;
+
+ALTER TABLE users DROP COLUMN student_id;
@@ -0,0 +1,71 @@
+-- Учётная запись студента связана со строкой students
+ALTER TABLE users ADD COLUMN student_id INT UNIQUE REFERENCES students(id) ON DELETE SET NULL;
+
+-- Одноразовые коды активации учётных записей студентов (хранится только хэш)
+CREATE TABLE activation_codes (
+    id SERIAL PRIMARY KEY,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_activation_codes_student ON activation_codes(student_id);
+
+-- Изменения расписания на конкретную дату: отмена, перенос, замена аудитории
+CREATE TABLE schedule_overrides (
+    id SERIAL PRIMARY KEY,
+    schedule_id INT NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    cancelled BOOLEAN NOT NULL DEFAULT FALSE,
+    classroom_id INT REFERENCES classrooms(id) ON DELETE SET NULL,
+    start_time TIMESTAMP,
+    end_time TIMESTAMP,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (schedule_id, date)
+);
+
+-- Посещаемость занятий
+CREATE TABLE attendance (
+    id SERIAL PRIMARY KEY,
+    schedule_id INT NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('present', 'absent', 'late', 'excused')),
+    marked_by INT REFERENCES users(id) ON DELETE SET NULL,
+    marked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (schedule_id, student_id, date)
+);
+
+CREATE INDEX idx_attendance_student ON attendance(student_id, date);
+
+-- Объявления; group_name = NULL - для всех групп
+CREATE TABLE announcements (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    group_name VARCHAR(255) REFERENCES courses(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('portal:read', 'Личный кабинет студента'),
+    ('attendance:write', 'Отметка посещаемости'),
+    ('attendance:write:own-courses', 'Отметка посещаемости на своих занятиях'),
+    ('announcements:write', 'Публикация объявлений');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'portal:read'),
+    ('admin', 'attendance:write'),
+    ('admin', 'attendance:write:own-courses'),
+    ('admin', 'announcements:write'),
+    ('teacher', 'attendance:write:own-courses'),
+    ('registrar', 'attendance:write'),
+    ('registrar', 'announcements:write'),
+    ('department_head', 'announcements:write'),
+    ('curator', 'attendance:write'),
+    ('curator', 'announcements:write'),
+    ('student', 'portal:read');
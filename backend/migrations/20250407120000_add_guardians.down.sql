@@ -0,0 +1,8 @@
+DELETE FROM role_permissions WHERE permission IN ('guardians:write', 'guardian:read');
+DELETE FROM permissions WHERE name IN ('guardians:write', 'guardian:read');
+-- Учётные записи представителей удаляются: без роли guardian им нечего открывать,
+-- а любая другая роль дала бы чужие права. Ссылки на них (журнал, объявления) обнуляются
+DELETE FROM users WHERE role = 'guardian';
+DELETE FROM roles WHERE name = 'guardian';
+
+DROP TABLE IF EXISTS guardians;
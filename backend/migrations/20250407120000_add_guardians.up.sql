@@ -0,0 +1,30 @@
+-- Законные представители студентов. Одна учётная запись представителя
+-- может быть связана с несколькими студентами (например, братьями и сёстрами)
+CREATE TABLE guardians (
+    id SERIAL PRIMARY KEY,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    relation VARCHAR(20) NOT NULL CHECK (relation IN ('mother', 'father', 'grandparent', 'guardian', 'other')),
+    user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    notify_absence BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_guardians_student ON guardians(student_id);
+CREATE INDEX idx_guardians_user ON guardians(user_id);
+
+INSERT INTO roles (name, description) VALUES
+    ('guardian', 'Законный представитель студента');
+
+INSERT INTO permissions (name, description) VALUES
+    ('guardians:write', 'Управление контактами законных представителей'),
+    ('guardian:read', 'Просмотр данных связанных студентов');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'guardians:write'),
+    ('admin', 'guardian:read'),
+    ('registrar', 'guardians:write'),
+    ('curator', 'guardians:write'),
+    ('guardian', 'guardian:read');
@@ -0,0 +1,10 @@
+-- working_hours - остаток часов; hours_budget - выделенные часы, из которых вычитаются занятия.
+-- Бюджет восстанавливается по текущему остатку и действующему расписанию
+ALTER TABLE teachers ADD COLUMN hours_budget FLOAT NOT NULL DEFAULT 0;
+
+UPDATE teachers t
+SET hours_budget = t.working_hours + COALESCE((
+    SELECT SUM(EXTRACT(EPOCH FROM (s.end_time - s.start_time)) / 3600)
+    FROM schedules s
+    WHERE s.teacher_id = t.id AND s.deleted_at IS NULL
+), 0);
@@ -0,0 +1,3 @@
+-- Чередование по неделям: all - каждую неделю, odd/even - по нечётным/чётным неделям учебного года
+ALTER TABLE schedules ADD COLUMN week_type VARCHAR(4) NOT NULL DEFAULT 'all'
+    CHECK (week_type IN ('all', 'odd', 'even'));
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_classrooms_description_trgm;
+DROP INDEX IF EXISTS idx_classrooms_name_trgm;
+DROP INDEX IF EXISTS idx_courses_description_trgm;
+DROP INDEX IF EXISTS idx_courses_name_trgm;
+DROP INDEX IF EXISTS idx_teachers_subject_trgm;
+DROP INDEX IF EXISTS idx_teachers_name_trgm;
+DROP INDEX IF EXISTS idx_students_name_trgm;
+DROP EXTENSION IF EXISTS pg_trgm;
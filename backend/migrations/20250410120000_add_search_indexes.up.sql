@@ -0,0 +1,10 @@
+-- Поиск по ФИО, предметам и названиям: триграммы дают поиск по части слова и с опечатками
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_students_name_trgm ON students USING GIN (lower(name) gin_trgm_ops);
+CREATE INDEX idx_teachers_name_trgm ON teachers USING GIN (lower(name) gin_trgm_ops);
+CREATE INDEX idx_teachers_subject_trgm ON teachers USING GIN (lower(subject) gin_trgm_ops);
+CREATE INDEX idx_courses_name_trgm ON courses USING GIN (lower(name) gin_trgm_ops);
+CREATE INDEX idx_courses_description_trgm ON courses USING GIN (lower(description) gin_trgm_ops);
+CREATE INDEX idx_classrooms_name_trgm ON classrooms USING GIN (lower(name) gin_trgm_ops);
+CREATE INDEX idx_classrooms_description_trgm ON classrooms USING GIN (lower(description) gin_trgm_ops);
@@ -0,0 +1,11 @@
+-- Откат не пройдёт, если имя удалённой записи уже занято: сначала очистите корзину от таких записей
+DROP INDEX IF EXISTS classrooms_name_active_key;
+DROP INDEX IF EXISTS courses_name_active_key;
+
+ALTER TABLE classrooms ADD CONSTRAINT classrooms_name_key UNIQUE (name);
+ALTER TABLE courses ADD CONSTRAINT courses_name_key UNIQUE (name);
+
+ALTER TABLE announcements ADD CONSTRAINT announcements_group_name_fkey
+    FOREIGN KEY (group_name) REFERENCES courses(name) ON UPDATE CASCADE ON DELETE CASCADE;
+ALTER TABLE schedules ADD CONSTRAINT schedules_group_name_fkey
+    FOREIGN KEY (group_name) REFERENCES courses(name) ON DELETE CASCADE;
@@ -0,0 +1,12 @@
+-- Названия курсов и аудиторий уникальны только среди записей не в корзине: имя удалённой записи
+-- можно занять снова. Внешний ключ не может ссылаться на частичный индекс, поэтому ссылки на курс
+-- по названию (schedules.group_name, announcements.group_name) проверяют репозитории,
+-- а висячие ссылки находит проверка целостности
+ALTER TABLE schedules DROP CONSTRAINT schedules_group_name_fkey;
+ALTER TABLE announcements DROP CONSTRAINT announcements_group_name_fkey;
+
+ALTER TABLE courses DROP CONSTRAINT courses_name_key;
+ALTER TABLE classrooms DROP CONSTRAINT classrooms_name_key;
+
+CREATE UNIQUE INDEX courses_name_active_key ON courses(name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX classrooms_name_active_key ON classrooms(name) WHERE deleted_at IS NULL;
@@ -0,0 +1,20 @@
+-- Ширина колонок не возвращается: после отката в них могут остаться длинные названия
+ALTER TABLE schedules DROP CONSTRAINT schedules_group_name_fkey;
+ALTER TABLE announcements DROP CONSTRAINT announcements_group_name_fkey;
+
+ALTER TABLE courses DROP CONSTRAINT courses_name_key;
+ALTER TABLE classrooms DROP CONSTRAINT classrooms_name_key;
+
+UPDATE schedules s SET group_name = left(c.name, -length(' [deleted #' || c.id || ']'))
+FROM courses c
+WHERE c.deleted_at IS NOT NULL AND s.group_name = c.name;
+
+UPDATE announcements a SET group_name = left(c.name, -length(' [deleted #' || c.id || ']'))
+FROM courses c
+WHERE c.deleted_at IS NOT NULL AND a.group_name = c.name;
+
+UPDATE courses SET name = left(name, -length(' [deleted #' || id || ']')) WHERE deleted_at IS NOT NULL;
+UPDATE classrooms SET name = left(name, -length(' [deleted #' || id || ']')) WHERE deleted_at IS NOT NULL;
+
+CREATE UNIQUE INDEX courses_name_active_key ON courses(name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX classrooms_name_active_key ON classrooms(name) WHERE deleted_at IS NULL;
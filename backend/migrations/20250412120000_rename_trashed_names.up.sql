@@ -0,0 +1,40 @@
+-- Названия курсов и аудиторий снова уникальны среди всех записей, ссылки на курс по названию -
+-- снова внешние ключи. Чтобы название удалённой записи можно было занять, запись в корзине получает
+-- суффикс " [deleted #id]" (снимается при восстановлении), а ON UPDATE CASCADE переводит занятия
+-- и объявления группы вслед за курсом. Колонки расширены под суффикс
+ALTER TABLE courses ALTER COLUMN name TYPE VARCHAR(300);
+ALTER TABLE classrooms ALTER COLUMN name TYPE VARCHAR(300);
+ALTER TABLE schedules ALTER COLUMN group_name TYPE VARCHAR(300);
+ALTER TABLE announcements ALTER COLUMN group_name TYPE VARCHAR(300);
+ALTER TABLE students ALTER COLUMN group_name TYPE VARCHAR(300);
+
+UPDATE courses SET name = name || ' [deleted #' || id || ']' WHERE deleted_at IS NOT NULL;
+UPDATE classrooms SET name = name || ' [deleted #' || id || ']' WHERE deleted_at IS NOT NULL;
+
+-- Занятия, удалённые вместе с курсом, следуют за ним, даже если название уже занято новым курсом
+UPDATE schedules s SET group_name = c.name
+FROM courses c
+WHERE c.deleted_at IS NOT NULL AND s.deleted_at = c.deleted_at
+  AND c.name = s.group_name || ' [deleted #' || c.id || ']';
+
+-- Остальные ссылки на название, которого больше нет, - на удалённый курс с этим названием
+UPDATE schedules s SET group_name = c.name
+FROM courses c
+WHERE c.deleted_at IS NOT NULL AND c.name = s.group_name || ' [deleted #' || c.id || ']'
+  AND NOT EXISTS (SELECT 1 FROM courses a WHERE a.name = s.group_name);
+
+UPDATE announcements a SET group_name = c.name
+FROM courses c
+WHERE c.deleted_at IS NOT NULL AND c.name = a.group_name || ' [deleted #' || c.id || ']'
+  AND NOT EXISTS (SELECT 1 FROM courses n WHERE n.name = a.group_name);
+
+DROP INDEX courses_name_active_key;
+DROP INDEX classrooms_name_active_key;
+
+ALTER TABLE courses ADD CONSTRAINT courses_name_key UNIQUE (name);
+ALTER TABLE classrooms ADD CONSTRAINT classrooms_name_key UNIQUE (name);
+
+ALTER TABLE schedules ADD CONSTRAINT schedules_group_name_fkey
+    FOREIGN KEY (group_name) REFERENCES courses(name) ON UPDATE CASCADE ON DELETE CASCADE;
+ALTER TABLE announcements ADD CONSTRAINT announcements_group_name_fkey
+    FOREIGN KEY (group_name) REFERENCES courses(name) ON UPDATE CASCADE ON DELETE CASCADE;
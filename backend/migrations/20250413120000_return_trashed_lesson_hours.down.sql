@@ -0,0 +1 @@
+-- Возвращённые часы не списываются повторно: остаток после отката тот же
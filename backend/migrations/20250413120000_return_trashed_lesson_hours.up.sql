@@ -0,0 +1,8 @@
+-- Занятия в корзине возвращают часы преподавателю. Остаток приводится к бюджету минус действующие
+-- занятия: часы занятий, удалённых раньше без возврата, возвращаются
+UPDATE teachers t
+SET working_hours = t.hours_budget - COALESCE((
+    SELECT SUM(EXTRACT(EPOCH FROM (s.end_time - s.start_time)) / 3600)
+    FROM schedules s
+    WHERE s.teacher_id = t.id AND s.deleted_at IS NULL
+), 0);
@@ -0,0 +1,3 @@
+-- Роли идентифицируются названием, а журнал аудита ссылается на записи по числовому ID.
+-- Суррогатный ID даёт изменениям ролей настоящий entity_id вместо 0
+ALTER TABLE roles ADD COLUMN id SERIAL UNIQUE;
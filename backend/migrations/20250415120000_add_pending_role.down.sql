@@ -0,0 +1,3 @@
+-- Учётные записи без назначенной роли ничего не могут и без роли 'pending' не имеют смысла
+DELETE FROM users WHERE role = 'pending';
+DELETE FROM roles WHERE name = 'pending';
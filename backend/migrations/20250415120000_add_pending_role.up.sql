@@ -0,0 +1,2 @@
+-- Открытая регистрация выдаёт роль без прав: доступ появляется, когда администратор назначит роль
+INSERT INTO roles (name, description) VALUES ('pending', 'Ожидает назначения роли');
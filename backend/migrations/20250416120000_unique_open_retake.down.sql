@@ -0,0 +1 @@
+DROP INDEX IF EXISTS grade_sheets_open_retake_key;
@@ -0,0 +1,12 @@
+-- У студента не больше одной выданной или заполненной пересдачи по одной ведомости. В пересдачу
+-- попадают все студенты исходной ведомости с неудовлетворительными отметками, поэтому достаточно
+-- одной открытой пересдачи на исходную ведомость (parent_id). Черновиков может быть несколько.
+-- Лишние открытые пересдачи, выданные раньше появления проверки, возвращаются в черновики с отметками
+UPDATE grade_sheets g SET status = 'draft', issued_at = NULL
+WHERE g.parent_id IS NOT NULL AND g.status IN ('issued', 'filled')
+  AND EXISTS (
+    SELECT 1 FROM grade_sheets o
+    WHERE o.parent_id = g.parent_id AND o.status IN ('issued', 'filled') AND o.id < g.id
+  );
+
+CREATE UNIQUE INDEX grade_sheets_open_retake_key ON grade_sheets(parent_id) WHERE status IN ('issued', 'filled');
@@ -0,0 +1,28 @@
+package models
+
+import (
+    "encoding/json"
+    "time"
+)
+
+// AuditEntry запись журнала аудита об изменении данных
+type AuditEntry struct {
+    ID         int             `json:"id" label:"ID"`
+    UserID     *int            `json:"user_id" label:"Пользователь"`     // Кто изменил (из JWT); пусто - командная строка или открытая регистрация
+    Action     string          `json:"action" label:"Действие"`      // create, update, delete, restore и т.д.
+    EntityType string          `json:"entity_type" label:"Тип"` // teachers, students, schedules и т.д.
+    EntityID   int             `json:"entity_id" label:"ID записи"`
+    Before     json.RawMessage `json:"before" label:"До"`      // Состояние до изменения
+    After      json.RawMessage `json:"after" label:"После"`       // Состояние после изменения
+    CreatedAt  time.Time       `json:"created_at" label:"Время"`
+}
+
+// AuditFilter фильтры журнала аудита; нулевые значения не ограничивают выборку
+type AuditFilter struct {
+    UserID     int
+    EntityType string
+    EntityID   int
+    From       time.Time
+    To         time.Time
+    Limit      int
+}
\ No newline at end of file
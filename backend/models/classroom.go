@@ -1,8 +1,15 @@
 package models
 
 type Classroom struct {
-    ID          int     `json:"id"`
-    Name        string  `json:"name"`        // Название аудитории (например, "Аудитория 101")
-    Capacity    int     `json:"capacity"`    // Вместимость аудитории (количество мест)
-    Description string  `json:"description"` // Описание аудитории (необязательное поле)
+    ID          int     `json:"id" label:"ID"`
+    Name        string  `json:"name" validate:"required,max=255" label:"Название"`        // Название аудитории (например, "Аудитория 101")
+    Capacity    int     `json:"capacity" validate:"gt=0" label:"Вместимость"`    // Вместимость аудитории (количество мест)
+    Description string  `json:"description" label:"Описание"` // Описание аудитории (необязательное поле)
+}
+
+// ClassroomUpdate частичное обновление аудитории: nil - поле не меняется
+type ClassroomUpdate struct {
+    Name        *string `json:"name" validate:"omitnil,min=1,max=255"`
+    Capacity    *int    `json:"capacity" validate:"omitnil,gt=0"`
+    Description *string `json:"description"`
 }
\ No newline at end of file
@@ -1,8 +1,15 @@
 package models
 
 type Course struct {
-    ID          int    `json:"id"`
-    Name        string `json:"name"`
-    Description string `json:"description"`
-    TeacherID   *int   `json:"teacher_id"`
+    ID          int    `json:"id" label:"ID"`
+    Name        string `json:"name" validate:"required,max=255" label:"Название"`
+    Description string `json:"description" label:"Описание"`
+    TeacherID   *int   `json:"teacher_id" validate:"omitnil,gt=0" label:"ID преподавателя"`
+}
+
+// CourseUpdate частичное обновление курса: nil - поле не меняется
+type CourseUpdate struct {
+    Name        *string `json:"name" validate:"omitnil,min=1,max=255"`
+    Description *string `json:"description"`
+    TeacherID   *int    `json:"teacher_id" validate:"omitnil,gt=0"`
 }
\ No newline at end of file
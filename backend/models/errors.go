@@ -0,0 +1,135 @@
+package models
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "reflect"
+    "strings"
+
+    "github.com/go-playground/validator/v10"
+)
+
+// Машинно-читаемые коды ошибок; по коду ErrorMiddleware выбирает HTTP-статус
+const (
+    CodeValidation   = "validation"   // 400: неверные данные запроса
+    CodeUnauthorized = "unauthorized" // 401: нет или неверный токен
+    CodeForbidden    = "forbidden"    // 403: не хватает прав
+    CodeNotFound     = "not_found"    // 404: записи нет
+    CodeConflict     = "conflict"     // 409: состояние записи не допускает операцию
+    CodeTooLarge     = "too_large"    // 413: тело запроса больше допустимого
+    CodeInternal     = "internal"     // 500: всё остальное
+)
+
+// Error ошибка предметной области. Репозитории и сервисы возвращают её вместо строк,
+// обработчики передают её в c.Error, а ErrorMiddleware превращает в ответ
+type Error struct {
+    Code    string
+    Message string
+    Fields  map[string]string // Ошибки по полям запроса (для validation)
+    Details interface{}       // Дополнительные данные ответа (например, затронутые удалением записи)
+}
+
+func (e *Error) Error() string {
+    return e.Message
+}
+
+// Is сравнивает по коду, чтобы работало errors.Is(err, models.ErrNotFound);
+// у образца с текстом должен совпадать и текст
+func (e *Error) Is(target error) bool {
+    t, ok := target.(*Error)
+    return ok && t.Code == e.Code && (t.Message == "" || t.Message == e.Message)
+}
+
+// Образцы для errors.Is
+var (
+    ErrValidation   = &Error{Code: CodeValidation}
+    ErrUnauthorized = &Error{Code: CodeUnauthorized}
+    ErrForbidden    = &Error{Code: CodeForbidden}
+    ErrNotFound     = &Error{Code: CodeNotFound}
+    ErrConflict     = &Error{Code: CodeConflict}
+)
+
+func newError(code, format string, args ...interface{}) *Error {
+    return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Invalid ошибка в данных запроса
+func Invalid(format string, args ...interface{}) *Error {
+    return newError(CodeValidation, format, args...)
+}
+
+// Unauthorized пользователь не аутентифицирован
+func Unauthorized(format string, args ...interface{}) *Error {
+    return newError(CodeUnauthorized, format, args...)
+}
+
+// Forbidden у пользователя нет прав на операцию
+func Forbidden(format string, args ...interface{}) *Error {
+    return newError(CodeForbidden, format, args...)
+}
+
+// NotFound запись не найдена
+func NotFound(format string, args ...interface{}) *Error {
+    return newError(CodeNotFound, format, args...)
+}
+
+// Conflict операция противоречит текущему состоянию записи
+func Conflict(format string, args ...interface{}) *Error {
+    return newError(CodeConflict, format, args...)
+}
+
+// TooLarge тело запроса превышает предел
+func TooLarge(format string, args ...interface{}) *Error {
+    return newError(CodeTooLarge, format, args...)
+}
+
+// InvalidFields ошибка валидации с пояснениями по полям
+func InvalidFields(message string, fields map[string]string) *Error {
+    return &Error{Code: CodeValidation, Message: message, Fields: fields}
+}
+
+// WithDetails добавляет к ошибке данные для ответа
+func (e *Error) WithDetails(details interface{}) *Error {
+    e.Details = details
+    return e
+}
+
+// FromValidation превращает ошибку validator или разбора JSON в ошибку валидации.
+// Ошибки validator, неверные типы и неизвестные поля раскладываются по полям (имена полей - как в JSON)
+func FromValidation(err error) *Error {
+    var validationErrors validator.ValidationErrors
+    if errors.As(err, &validationErrors) {
+        fields := make(map[string]string, len(validationErrors))
+        for _, fieldError := range validationErrors {
+            rule := fieldError.Tag()
+            if fieldError.Param() != "" {
+                rule += "=" + fieldError.Param()
+            }
+            fields[fieldError.Field()] = rule
+        }
+        return InvalidFields(ValidationFailed, fields)
+    }
+    var typeError *json.UnmarshalTypeError
+    if errors.As(err, &typeError) && typeError.Field != "" {
+        return InvalidFields(ValidationFailed, map[string]string{typeError.Field: "type=" + typeError.Type.String()})
+    }
+    // encoding/json не экспортирует тип ошибки для DisallowUnknownFields
+    if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+        return InvalidFields(ValidationFailed, map[string]string{strings.Trim(field, `"`): "unknown"})
+    }
+    var typed *Error
+    if errors.As(err, &typed) {
+        return typed
+    }
+    return Invalid("invalid request body: %v", err)
+}
+
+// jsonFieldName имя поля для ошибок валидации - из тега json
+func jsonFieldName(field reflect.StructField) string {
+    name := strings.Split(field.Tag.Get("json"), ",")[0]
+    if name == "" || name == "-" {
+        return field.Name
+    }
+    return name
+}
@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// Статусы ведомости
+const (
+    GradeSheetDraft  = "draft"  // Черновик, список студентов можно менять
+    GradeSheetIssued = "issued" // Выдана экзаменатору
+    GradeSheetFilled = "filled" // Все оценки проставлены
+    GradeSheetClosed = "closed" // Закрыта и подписана, изменения запрещены
+)
+
+// Форма контроля
+const (
+    ControlExam   = "exam"   // Экзамен
+    ControlCredit = "credit" // Зачёт
+)
+
+// Допустимые отметки
+const (
+    MarkExcellent = "5"
+    MarkGood      = "4"
+    MarkSatisfy   = "3"
+    MarkFail      = "2"
+    MarkPass      = "pass"   // Зачтено
+    MarkNoPass    = "fail"   // Не зачтено
+    MarkAbsent    = "absent" // Не явился
+)
+
+type GradeSheet struct {
+    ID          int               `json:"id" label:"ID"`
+    Number      string            `json:"number" label:"Номер"`       // Номер ведомости
+    CourseID    int               `json:"course_id" label:"ID курса"`
+    CourseName  string            `json:"course_name" label:"Курс"`  // (подтягивается через JOIN)
+    GroupName   string            `json:"group_name" label:"Группа"`   // Группа = название курса
+    TeacherID   *int              `json:"teacher_id" label:"ID экзаменатора"`   // Экзаменатор
+    TeacherName string            `json:"teacher_name" label:"Экзаменатор"` // (подтягивается через JOIN)
+    ControlType string            `json:"control_type" label:"Вид контроля"` // exam или credit
+    Status      string            `json:"status" label:"Статус"`
+    ParentID    *int              `json:"parent_id" label:"Исходная ведомость"`    // Исходная ведомость, если это пересдача
+    ExamDate    string            `json:"exam_date" label:"Дата"`    // Дата в формате YYYY-MM-DD
+    CreatedAt   time.Time         `json:"created_at" label:"Создана"`
+    IssuedAt    *time.Time        `json:"issued_at" label:"Выдана"`
+    ClosedAt    *time.Time        `json:"closed_at" label:"Закрыта"`
+    ClosedBy    *int              `json:"closed_by" label:"Подписал"`    // ID пользователя, подписавшего ведомость
+    Entries     []GradeSheetEntry `json:"entries,omitempty" label:"-"`
+}
+
+type GradeSheetEntry struct {
+    ID          int     `json:"id"`
+    StudentID   int     `json:"student_id"`
+    StudentName string  `json:"student_name"` // (подтягивается через JOIN)
+    Mark        *string `json:"mark"`         // nil, пока оценка не выставлена
+}
+
+// Grade итоговая оценка в зачётной книжке
+type Grade struct {
+    ID           int       `json:"id" label:"ID"`
+    StudentID    int       `json:"student_id" label:"ID студента"`
+    CourseID     int       `json:"course_id" label:"ID курса"`
+    CourseName   string    `json:"course_name" label:"Курс"`
+    GradeSheetID *int      `json:"grade_sheet_id" label:"Ведомость"`
+    Mark         string    `json:"mark" label:"Отметка"`
+    GradedAt     time.Time `json:"graded_at" label:"Дата"`
+}
+
+// IsValidMark проверяет, допустима ли отметка для формы контроля
+func IsValidMark(controlType, mark string) bool {
+    if mark == MarkAbsent {
+        return true
+    }
+    switch controlType {
+    case ControlExam:
+        return mark == MarkExcellent || mark == MarkGood || mark == MarkSatisfy || mark == MarkFail
+    case ControlCredit:
+        return mark == MarkPass || mark == MarkNoPass
+    }
+    return false
+}
+
+// IsFailingMark возвращает true, если студенту нужна пересдача
+func IsFailingMark(mark string) bool {
+    return mark == MarkFail || mark == MarkNoPass || mark == MarkAbsent
+}
+
+// GradeSheetMarks отметки для заполнения ведомости
+type GradeSheetMarks struct {
+    Marks []GradeMark `json:"marks"`
+}
+
+type GradeMark struct {
+    StudentID int    `json:"student_id"`
+    Mark      string `json:"mark"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Guardian законный представитель студента
+type Guardian struct {
+    ID            int       `json:"id" label:"ID"`
+    StudentID     int       `json:"student_id" label:"ID студента"`
+    Name          string    `json:"name" validate:"required,max=255" label:"ФИО"`
+    Phone         string    `json:"phone" validate:"max=50" label:"Телефон"`
+    Email         string    `json:"email" validate:"omitempty,email,max=255" label:"Email"`
+    Relation      string    `json:"relation" validate:"required,oneof=mother father grandparent guardian other" label:"Кем приходится"`
+    UserID        *int      `json:"user_id" label:"Учётная запись"`        // Только чтение: задаётся созданием или привязкой учётной записи
+    NotifyAbsence bool      `json:"notify_absence" label:"Уведомлять о пропусках"` // Сообщать о пропусках несовершеннолетнего
+    CreatedAt     time.Time `json:"created_at" label:"Создан"`
+}
+
+// GuardianUpdate частичное обновление контакта: nil - поле не меняется.
+// Учётной записи (user_id) здесь нет: её создаёт POST /guardians/:id/account и привязывает PUT /guardians/:id/account.
+// Формат email проверяется на итоговом контакте: пустая строка убирает email
+type GuardianUpdate struct {
+    Name          *string `json:"name" validate:"omitnil,min=1,max=255" label:"ФИО"`
+    Phone         *string `json:"phone" validate:"omitnil,max=50" label:"Телефон"`
+    Email         *string `json:"email" validate:"omitnil,max=255" label:"Email"`
+    Relation      *string `json:"relation" validate:"omitnil,oneof=mother father grandparent guardian other" label:"Кем приходится"`
+    NotifyAbsence *bool   `json:"notify_absence" label:"Уведомлять о пропусках"`
+}
+
+// GuardianAccountLink привязка контакта к существующей учётной записи представителя,
+// например второго ребёнка к учётной записи, созданной для первого
+type GuardianAccountLink struct {
+    UserID int `json:"user_id" validate:"required,min=1" label:"Учётная запись"`
+}
+
+// AbsenceContact адресат уведомления о пропуске занятия
+type AbsenceContact struct {
+    StudentID    int
+    StudentName  string
+    DateOfBirth  time.Time
+    GuardianName string
+    Email        string
+}
\ No newline at end of file
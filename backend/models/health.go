@@ -0,0 +1,13 @@
+package models
+
+// Состояния проверок /healthz и /readyz
+const (
+    HealthOK     = "ok"
+    HealthFailed = "failed"
+)
+
+// HealthStatus ответ проверки состояния; checks - результат по каждой зависимости
+type HealthStatus struct {
+    Status string            `json:"status"`
+    Checks map[string]string `json:"checks,omitempty"`
+}
@@ -0,0 +1,51 @@
+package models
+
+// ImportError ошибка в строке импортируемого файла; Row - номер строки в файле (шапка - строка 1)
+type ImportError struct {
+    Row     int    `json:"row"`
+    Column  string `json:"column,omitempty"`
+    Value   string `json:"value,omitempty"`
+    Message string `json:"message"`
+}
+
+// ImportResult итог импорта: при ошибках в файле ничего не сохраняется
+type ImportResult struct {
+    Entity  string        `json:"entity"`
+    DryRun  bool          `json:"dry_run"`
+    Total   int           `json:"total"`   // Непустых строк в файле
+    Valid   int           `json:"valid"`   // Строк без ошибок
+    Created int           `json:"created"` // Сохранено записей (0 при dry-run или ошибках)
+    Errors  []ImportError `json:"errors"`
+}
+
+// ImportField колонка импорта: поле модели и допустимые названия колонок в файле
+type ImportField struct {
+    Name     string   `json:"name"`
+    Required bool     `json:"required"`
+    Aliases  []string `json:"aliases"`
+}
+
+// ImportFields поля, которые можно загрузить для каждой сущности
+var ImportFields = map[string][]ImportField{
+    "students": {
+        {Name: "name", Required: true, Aliases: []string{"ФИО", "Студент", "Имя"}},
+        {Name: "date_of_birth", Required: true, Aliases: []string{"Дата рождения"}},
+        {Name: "group_name", Required: true, Aliases: []string{"Группа"}},
+    },
+    "teachers": {
+        {Name: "name", Required: true, Aliases: []string{"ФИО", "Преподаватель", "Имя"}},
+        {Name: "subject", Required: true, Aliases: []string{"Предмет"}},
+        {Name: "working_hours", Aliases: []string{"Часы", "Рабочие часы"}},
+        {Name: "courses", Aliases: []string{"Курсы"}},
+    },
+    "courses": {
+        {Name: "name", Required: true, Aliases: []string{"Название", "Курс", "Группа"}},
+        {Name: "description", Aliases: []string{"Описание"}},
+        {Name: "teacher_id", Aliases: []string{"ID преподавателя"}},
+    },
+    "classrooms": {
+        {Name: "name", Required: true, Aliases: []string{"Название", "Аудитория"}},
+        {Name: "capacity", Required: true, Aliases: []string{"Вместимость", "Мест"}},
+        {Name: "description", Aliases: []string{"Описание"}},
+    },
+}
\ No newline at end of file
@@ -0,0 +1,9 @@
+package models
+
+// IntegrityIssue нарушение согласованности данных, найденное проверкой целостности
+type IntegrityIssue struct {
+    Check      string `json:"check"`       // Название проверки
+    EntityType string `json:"entity_type"`
+    EntityID   int    `json:"entity_id"`
+    Details    string `json:"details"`
+}
\ No newline at end of file
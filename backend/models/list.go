@@ -0,0 +1,38 @@
+package models
+
+// Размер страницы списков по умолчанию и максимальный
+const (
+    DefaultPageLimit = 50
+    MaxPageLimit     = 500
+)
+
+// ListQuery параметры списка: страница, сортировка и фильтры (имя параметра -> значение).
+// Limit = 0 - без ограничения (используется при выгрузке в файл)
+type ListQuery struct {
+    Limit   int
+    Offset  int
+    Sort    []SortField
+    Filters map[string]string
+}
+
+// SortField поле сортировки; в запросе "-field" означает сортировку по убыванию
+type SortField struct {
+    Field string
+    Desc  bool
+}
+
+// Page конверт ответа списка
+type Page[T any] struct {
+    Items  []T `json:"items"`
+    Total  int `json:"total"`  // Всего записей с учётом фильтров
+    Limit  int `json:"limit"`  // 0 - без ограничения
+    Offset int `json:"offset"`
+}
+
+// NewPage собирает страницу; пустой список сериализуется как [], а не null
+func NewPage[T any](items []T, total int, query ListQuery) *Page[T] {
+    if items == nil {
+        items = []T{}
+    }
+    return &Page[T]{Items: items, Total: total, Limit: query.Limit, Offset: query.Offset}
+}
\ No newline at end of file
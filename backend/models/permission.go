@@ -0,0 +1,55 @@
+package models
+
+// Права доступа. Суффикс :own-courses ограничивает право курсами преподавателя
+const (
+    PermTeachersRead        = "teachers:read"
+    PermTeachersWrite       = "teachers:write"
+    PermStudentsRead        = "students:read"
+    PermStudentsWrite       = "students:write"
+    PermCoursesRead         = "courses:read"
+    PermCoursesWrite        = "courses:write"
+    PermClassroomsRead      = "classrooms:read"
+    PermClassroomsWrite     = "classrooms:write"
+    PermScheduleRead        = "schedule:read"
+    PermScheduleWrite       = "schedule:write"
+    PermGradesRead          = "grades:read"
+    PermGradesReadOwn       = "grades:read:own-courses"
+    PermGradesWrite         = "grades:write"
+    PermGradesWriteOwn      = "grades:write:own-courses"
+    PermGradeSheetsManage   = "grade_sheets:manage"
+    PermTrashManage         = "trash:manage"
+    PermAuditRead           = "audit:read"
+    PermNotifySend          = "notify:send"
+    PermUsersManage         = "users:manage"
+    PermProfileWrite        = "profile:write"
+    PermPortalRead          = "portal:read"
+    PermAttendanceWrite     = "attendance:write"
+    PermAttendanceWriteOwn  = "attendance:write:own-courses"
+    PermAnnouncementsWrite  = "announcements:write"
+    PermGuardiansWrite      = "guardians:write"
+    PermGuardianRead        = "guardian:read"
+)
+
+// Role именованный набор прав
+type Role struct {
+    ID          int      `json:"id"` // Суррогатный ключ для журнала аудита; роль задаётся названием
+    Name        string   `json:"name" validate:"required"`
+    Description string   `json:"description"`
+    Permissions []string `json:"permissions"`
+}
+
+type Permission struct {
+    Name        string `json:"name"`
+    Description string `json:"description"`
+}
+
+// RolePermissionsUpdate новый набор прав роли целиком
+type RolePermissionsUpdate struct {
+    Permissions []string `json:"permissions"`
+}
+
+// UserRoleUpdate назначение роли; teacher_id связывает учётную запись с преподавателем
+type UserRoleUpdate struct {
+    Role      string `json:"role"`
+    TeacherID *int   `json:"teacher_id"`
+}
@@ -0,0 +1,113 @@
+package models
+
+import "time"
+
+// Статусы посещаемости
+const (
+    AttendancePresent = "present" // Присутствовал
+    AttendanceAbsent  = "absent"  // Отсутствовал
+    AttendanceLate    = "late"    // Опоздал
+    AttendanceExcused = "excused" // Отсутствовал по уважительной причине
+)
+
+// IsValidAttendanceStatus проверяет, что статус посещаемости известен
+func IsValidAttendanceStatus(status string) bool {
+    switch status {
+    case AttendancePresent, AttendanceAbsent, AttendanceLate, AttendanceExcused:
+        return true
+    }
+    return false
+}
+
+// ScheduleOverride изменение занятия на конкретную дату
+type ScheduleOverride struct {
+    ID            int        `json:"id"`
+    ScheduleID    int        `json:"schedule_id"`
+    Date          string     `json:"date"`           // Дата занятия в формате YYYY-MM-DD
+    Cancelled     bool       `json:"cancelled"`      // Занятие отменено
+    ClassroomID   *int       `json:"classroom_id"`   // Другая аудитория
+    ClassroomName *string    `json:"classroom_name"` // (подтягивается через JOIN)
+    StartTime     *time.Time `json:"start_time"`     // Перенос по времени
+    EndTime       *time.Time `json:"end_time"`
+    Reason        string     `json:"reason"`
+    CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Timetable расписание группы и изменения в нём за период
+type Timetable struct {
+    GroupName string             `json:"group_name"`
+    From      string             `json:"from"`
+    To        string             `json:"to"`
+    Schedule  []Schedule         `json:"schedule"`
+    Overrides []ScheduleOverride `json:"overrides"`
+}
+
+// TimetableResource представление расписания студента в /api/v1
+type TimetableResource struct {
+    GroupName string             `json:"group_name"`
+    From      string             `json:"from"`
+    To        string             `json:"to"`
+    Schedule  []ScheduleResource `json:"schedule"`
+    Overrides []ScheduleOverride `json:"overrides"`
+}
+
+// Resource представление расписания для /api/v1
+func (t Timetable) Resource() TimetableResource {
+    return TimetableResource{GroupName: t.GroupName, From: t.From, To: t.To, Schedule: ScheduleResources(t.Schedule), Overrides: t.Overrides}
+}
+
+// Attendance отметка посещаемости студента на занятии
+type Attendance struct {
+    ID         int       `json:"id"`
+    ScheduleID int       `json:"schedule_id"`
+    StudentID  int       `json:"student_id"`
+    Date       string    `json:"date"`
+    Status     string    `json:"status"`
+    GroupName  string    `json:"group_name"`  // (подтягивается через JOIN)
+    DayOfWeek  string    `json:"day_of_week"` // (подтягивается через JOIN)
+    MarkedBy   *int      `json:"marked_by"`
+    MarkedAt   time.Time `json:"marked_at"`
+}
+
+// Announcement объявление; пустой GroupName означает объявление для всех групп
+type Announcement struct {
+    ID        int       `json:"id" label:"ID"`
+    Title     string    `json:"title" validate:"required,max=255" label:"Заголовок"`
+    Body      string    `json:"body" validate:"required" label:"Текст"`
+    GroupName *string   `json:"group_name" label:"Группа"`
+    CreatedBy *int      `json:"created_by" label:"Автор"`
+    CreatedAt time.Time `json:"created_at" label:"Создано"`
+}
+
+// ActivationCode одноразовый код для активации учётной записи студента.
+// Код показывается один раз при выдаче, в базе хранится только его хэш
+type ActivationCode struct {
+    StudentID   int       `json:"student_id"`
+    StudentName string    `json:"student_name"`
+    GroupName   string    `json:"group_name"`
+    Code        string    `json:"code"`
+    ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// ProvisionRequest выдача кодов активации; пустая группа - всем студентам без учётной записи
+type ProvisionRequest struct {
+    GroupName string `json:"group_name"`
+}
+
+// ActivationRequest активация учётной записи студента по коду
+type ActivationRequest struct {
+    Code     string `json:"code"`
+    Username string `json:"username"`
+    Password string `json:"password"`
+}
+
+// AttendanceMarks отметки посещаемости занятия за дату (YYYY-MM-DD)
+type AttendanceMarks struct {
+    Date  string           `json:"date"`
+    Marks []AttendanceMark `json:"marks"`
+}
+
+type AttendanceMark struct {
+    StudentID int    `json:"student_id"`
+    Status    string `json:"status"` // present, absent, late или excused
+}
@@ -3,11 +3,126 @@ package models
 import "time"
 
 type Schedule struct {
-    ID            int       `json:"id"`
-    TeacherName   string    `json:"teacher_name"`   //  (подтягивается через JOIN)
-    ClassroomName string    `json:"classroom_name"` //  (подтягивается через JOIN)
-    GroupName     string    `json:"group_name"`    
-    StartTime     time.Time `json:"start_time"`    
-    EndTime       time.Time `json:"end_time"`      
-    DayOfWeek     string    `json:"day_of_week"`   //(например, "Monday")
+    ID            int       `json:"id" label:"ID"`
+    TeacherID     int       `json:"teacher_id" label:"ID преподавателя"`
+    TeacherName   string    `json:"teacher_name" label:"Преподаватель"`   //  (подтягивается через JOIN)
+    ClassroomID   int       `json:"classroom_id" label:"ID аудитории"`
+    ClassroomName string    `json:"classroom_name" label:"Аудитория"` //  (подтягивается через JOIN)
+    GroupName     string    `json:"group_name" validate:"required" label:"Группа"`    
+    StartTime     time.Time `json:"start_time" validate:"required" label:"Начало"`    
+    EndTime       time.Time `json:"end_time" validate:"required" label:"Окончание"`      
+    DayOfWeek     string    `json:"day_of_week" validate:"required,weekday" label:"День недели"`   //(например, "Monday")
+    WeekType      string    `json:"week_type" validate:"omitempty,oneof=all odd even" label:"Неделя"`       // all, odd или even
 }
+
+// Ref ссылка на связанную запись: ID и название для отображения
+type Ref struct {
+    ID   int    `json:"id"`
+    Name string `json:"name"`
+}
+
+// GroupRef учебная группа занятия; группа задаётся названием курса
+type GroupRef struct {
+    Name string `json:"name"`
+}
+
+// ScheduleResource представление занятия в /api/v1: связанные записи - ссылками, а не только именами
+type ScheduleResource struct {
+    ID        int       `json:"id"`
+    Teacher   Ref       `json:"teacher"`
+    Classroom Ref       `json:"classroom"`
+    Group     GroupRef  `json:"group"`
+    StartTime time.Time `json:"start_time"`
+    EndTime   time.Time `json:"end_time"`
+    DayOfWeek string    `json:"day_of_week"`
+    WeekType  string    `json:"week_type"`
+}
+
+// Resource представление занятия для /api/v1
+func (s Schedule) Resource() ScheduleResource {
+    return ScheduleResource{
+        ID:        s.ID,
+        Teacher:   Ref{ID: s.TeacherID, Name: s.TeacherName},
+        Classroom: Ref{ID: s.ClassroomID, Name: s.ClassroomName},
+        Group:     GroupRef{Name: s.GroupName},
+        StartTime: s.StartTime,
+        EndTime:   s.EndTime,
+        DayOfWeek: s.DayOfWeek,
+        WeekType:  s.WeekType,
+    }
+}
+
+// ScheduleResources представление списка занятий для /api/v1
+func ScheduleResources(schedules []Schedule) []ScheduleResource {
+    resources := make([]ScheduleResource, len(schedules))
+    for i, schedule := range schedules {
+        resources[i] = schedule.Resource()
+    }
+    return resources
+}
+
+// ScheduleCreate тело запроса на создание занятия: преподаватель и аудитория по ID
+type ScheduleCreate struct {
+    TeacherID   int       `json:"teacher_id"`
+    ClassroomID int       `json:"classroom_id"`
+    GroupName   string    `json:"group_name"`
+    StartTime   time.Time `json:"start_time"`
+    EndTime     time.Time `json:"end_time"`
+    DayOfWeek   string    `json:"day_of_week"`
+    WeekType    string    `json:"week_type"` // all (по умолчанию), odd или even
+}
+
+// ScheduleUpdate частичное обновление занятия: nil - поле не меняется.
+// Окончание позже начала проверяется и для пары полей, и для одного поля против сохранённого
+type ScheduleUpdate struct {
+    TeacherID   *int       `json:"teacher_id" validate:"omitnil,gt=0"`
+    ClassroomID *int       `json:"classroom_id" validate:"omitnil,gt=0"`
+    GroupName   *string    `json:"group_name" validate:"omitnil,min=1"`
+    StartTime   *time.Time `json:"start_time"`
+    EndTime     *time.Time `json:"end_time"`
+    DayOfWeek   *string    `json:"day_of_week" validate:"omitnil,weekday"`
+    WeekType    *string    `json:"week_type" validate:"omitnil,oneof=all odd even"`
+}
+
+// Чередование занятий по неделям учебного года
+const (
+    WeekAll  = "all"  // Каждую неделю
+    WeekOdd  = "odd"  // По нечётным неделям
+    WeekEven = "even" // По чётным неделям
+)
+
+// IsValidWeekType проверяет значение week_type
+func IsValidWeekType(weekType string) bool {
+    return weekType == WeekAll || weekType == WeekOdd || weekType == WeekEven
+}
+
+
+// AcademicWeekType возвращает odd или even для недели, в которую попадает дата.
+// Первая (нечётная) неделя учебного года - неделя, содержащая 1 сентября
+func AcademicWeekType(date time.Time) string {
+    year := date.Year()
+    if date.Month() < time.September {
+        year--
+    }
+    first := WeekStart(time.Date(year, time.September, 1, 0, 0, 0, 0, time.UTC))
+    day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+    week := int(WeekStart(day).Sub(first).Hours()/24/7) + 1
+    if week%2 == 1 {
+        return WeekOdd
+    }
+    return WeekEven
+}
+
+// WeekStart возвращает понедельник недели, в которую попадает дата
+func WeekStart(date time.Time) time.Time {
+    offset := (int(date.Weekday()) + 6) % 7
+    return time.Date(date.Year(), date.Month(), date.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// OccursOn проверяет, что занятие проходит в указанную дату (день недели и чередование недель)
+func (s *Schedule) OccursOn(date time.Time) bool {
+    if date.Weekday().String() != s.DayOfWeek {
+        return false
+    }
+    return s.WeekType == WeekAll || s.WeekType == "" || s.WeekType == AcademicWeekType(date)
+}
\ No newline at end of file
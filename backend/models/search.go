@@ -0,0 +1,42 @@
+package models
+
+// Типы результатов поиска
+const (
+    SearchStudent   = "student"
+    SearchTeacher   = "teacher"
+    SearchCourse    = "course"
+    SearchClassroom = "classroom"
+)
+
+// SearchTypes типы в порядке вывода и право, без которого тип не ищется
+var SearchTypes = []struct {
+    Type       string
+    Permission string
+}{
+    {SearchStudent, PermStudentsRead},
+    {SearchTeacher, PermTeachersRead},
+    {SearchCourse, PermCoursesRead},
+    {SearchClassroom, PermClassroomsRead},
+}
+
+// Размер выдачи поиска по умолчанию и максимальный
+const (
+    DefaultSearchLimit = 20
+    MaxSearchLimit     = 100
+)
+
+// SearchResult найденная запись
+type SearchResult struct {
+    Type     string  `json:"type"`     // student, teacher, course или classroom
+    ID       int     `json:"id"`
+    Title    string  `json:"title"`    // ФИО или название
+    Subtitle string  `json:"subtitle"` // Группа, предмет, описание или вместимость
+    Rank     float64 `json:"rank"`     // Релевантность от 0 до 1
+}
+
+// SearchResponse ответ поиска; Types - типы, доступные роли пользователя
+type SearchResponse struct {
+    Query string         `json:"query"`
+    Types []string       `json:"types"`
+    Items []SearchResult `json:"items"`
+}
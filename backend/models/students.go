@@ -1,11 +1,78 @@
 package models
 
+import "time"
+
+// Статусы студента
+const (
+    StudentActive        = "active"         // Обучается
+    StudentAcademicLeave = "academic_leave" // Академический отпуск
+    StudentExpelled      = "expelled"       // Отчислен
+    StudentGraduated     = "graduated"      // Выпущен
+    StudentTransferred   = "transferred"    // Переведён в другое учебное заведение
+)
 
 type Student struct {
-    ID        int    `json:"id"`
-    Name      string `json:"name"`
-	DateOfBirth string    `json:"date_of_birth"`
-    Age       int    `json:"age"`
-    GroupName string `json:"group_name"` 
-    TeacherID   *int    `json:"teacher_id"`
-}
\ No newline at end of file
+    ID        int    `json:"id" label:"ID"`
+    Name      string `json:"name" validate:"required,max=255" label:"ФИО"`
+	DateOfBirth string    `json:"date_of_birth" validate:"required,datetime=2006-01-02,notfuture" label:"Дата рождения"`
+    Age       int    `json:"age" label:"Возраст"`
+    GroupName string `json:"group_name" validate:"required" label:"Группа"` 
+    TeacherID   *int    `json:"teacher_id" label:"ID преподавателя"`
+    Status    string `json:"status" label:"Статус"`
+}
+
+// StudentUpdate частичное обновление студента: nil - поле не меняется.
+// Статус меняется только приказом
+type StudentUpdate struct {
+    Name        *string `json:"name" validate:"omitnil,min=1,max=255"`
+    DateOfBirth *string `json:"date_of_birth" validate:"omitnil,datetime=2006-01-02,notfuture"`
+    GroupName   *string `json:"group_name" validate:"omitnil,min=1"`
+}
+
+// IsValidStudentStatus проверяет, что статус студента известен
+func IsValidStudentStatus(status string) bool {
+    switch status {
+    case StudentActive, StudentAcademicLeave, StudentExpelled, StudentGraduated, StudentTransferred:
+        return true
+    }
+    return false
+}
+
+// StudentOrder приказ о движении студента (смена статуса или перевод в другую группу)
+type StudentOrder struct {
+    ID          int       `json:"id" label:"ID"`
+    StudentID   int       `json:"student_id" label:"ID студента"`
+    OrderNumber string    `json:"order_number" label:"Номер приказа"` // Номер приказа
+    OrderDate   string    `json:"order_date" label:"Дата приказа"`   // Дата приказа в формате YYYY-MM-DD
+    Reason      string    `json:"reason" label:"Основание"`
+    OldStatus   string    `json:"old_status" label:"Прежний статус"`
+    NewStatus   string    `json:"new_status" label:"Новый статус"`
+    CreatedBy   *int      `json:"created_by" label:"Внёс"`   // ID пользователя, внесшего приказ
+    CreatedAt   time.Time `json:"created_at" label:"Создан"`
+}
+
+// StudentGroupChange запись истории переводов между группами
+type StudentGroupChange struct {
+    ID        int       `json:"id" label:"ID"`
+    StudentID int       `json:"student_id" label:"ID студента"`
+    OldGroup  string    `json:"old_group" label:"Прежняя группа"`
+    NewGroup  string    `json:"new_group" label:"Новая группа"`
+    OrderID   *int      `json:"order_id" label:"ID приказа"` // Приказ, если перевод оформлен приказом
+    ChangedAt time.Time `json:"changed_at" label:"Дата перевода"`
+}
+
+// StudentStatusChange смена статуса студента по приказу
+type StudentStatusChange struct {
+    Status      string `json:"status"`
+    OrderNumber string `json:"order_number"`
+    OrderDate   string `json:"order_date"` // YYYY-MM-DD
+    Reason      string `json:"reason"`
+}
+
+// StudentTransfer перевод студента в другую группу по приказу
+type StudentTransfer struct {
+    GroupName   string `json:"group_name"`
+    OrderNumber string `json:"order_number"`
+    OrderDate   string `json:"order_date"` // YYYY-MM-DD
+    Reason      string `json:"reason"`
+}
@@ -1,19 +1,31 @@
 package models
 
-import (
-    "github.com/go-playground/validator/v10"
-)
-
 type Teacher struct {
-    ID           int       `json:"id"`
-    Name         string    `json:"name" validate:"required"`       // Имя преподавателя
-    Subject      string    `json:"subject" validate:"required"`    // Предмет, который преподает
-    Courses      []string  `json:"courses" validate:"omitempty,dive,gt=0"` // Список имен курсов
-    WorkingHours float64   `json:"working_hours" validate:"gte=0"` // Количество рабочих часов
+    ID           int       `json:"id" label:"ID"`
+    Name         string    `json:"name" validate:"required" label:"ФИО"`       // Имя преподавателя
+    Subject      string    `json:"subject" validate:"required" label:"Предмет"`    // Предмет, который преподает
+    Courses      []string  `json:"courses" validate:"omitempty,dive,gt=0" label:"Курсы"` // Список имен курсов
+    WorkingHours float64   `json:"working_hours" validate:"gte=0" label:"Остаток часов"` // Количество рабочих часов
 }
 
-var Validate *validator.Validate
+// TeacherUpdate частичное обновление преподавателя: nil - поле не меняется
+type TeacherUpdate struct {
+    Name         *string  `json:"name" validate:"omitnil,min=1,max=255"`
+    Subject      *string  `json:"subject" validate:"omitnil,min=1,max=255"`
+    WorkingHours *float64 `json:"working_hours" validate:"omitnil,gte=0"`
+}
 
-func init() {
-    Validate = validator.New()
-}
\ No newline at end of file
+// HoursChange расхождение остатка часов преподавателя с расписанием
+type HoursChange struct {
+    TeacherID   int     `json:"teacher_id"`
+    TeacherName string  `json:"teacher_name"`
+    OldHours    float64 `json:"old_hours"`
+    NewHours    float64 `json:"new_hours"`
+}
+
+// Notification письмо преподавателю
+type Notification struct {
+    Email   string `json:"email"`
+    Subject string `json:"subject"`
+    Body    string `json:"body"`
+}
@@ -0,0 +1,44 @@
+package models
+
+// TimetableGrid сетка расписания "дни × пары" для группы, преподавателя или аудитории
+type TimetableGrid struct {
+    Kind      string       `json:"kind"`                 // group, teacher, classroom
+    Name      string       `json:"name"`                 // Название группы, ФИО преподавателя или аудитория
+    WeekStart string       `json:"week_start,omitempty"` // Понедельник выбранной недели; пусто - шаблон на обе недели
+    WeekType  string       `json:"week_type,omitempty"`  // odd или even для выбранной недели
+    Days      []string     `json:"days"`
+    Periods   []Period     `json:"periods"`
+    Lessons   []GridLesson `json:"lessons"`
+}
+
+// Period пара: номер и время (HH:MM)
+type Period struct {
+    Number int    `json:"number"`
+    Start  string `json:"start"`
+    End    string `json:"end"`
+}
+
+// GridLesson занятие в ячейке сетки
+type GridLesson struct {
+    Day           string  `json:"day"`
+    Period        int     `json:"period"`
+    ScheduleID    int     `json:"schedule_id"`
+    GroupName     string  `json:"group_name"`
+    TeacherName   string  `json:"teacher_name"`
+    ClassroomName string  `json:"classroom_name"`
+    WeekType      string  `json:"week_type"`
+    Date          string  `json:"date,omitempty"`         // Дата занятия на выбранной неделе
+    Cancelled     bool    `json:"cancelled"`
+    MovedTo       *string `json:"moved_to,omitempty"`     // Новое время "HH:MM-HH:MM", если занятие перенесено
+    ClassroomNote *string `json:"classroom_note,omitempty"` // Другая аудитория на эту дату
+    Reason        string  `json:"reason,omitempty"`
+}
+
+// TimetablePoster общее расписание колледжа: сетки всех групп на общих парах
+type TimetablePoster struct {
+    WeekStart string          `json:"week_start,omitempty"`
+    WeekType  string          `json:"week_type,omitempty"`
+    Days      []string        `json:"days"`
+    Periods   []Period        `json:"periods"`
+    Groups    []TimetableGrid `json:"groups"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// TrashItem запись, помещённая в корзину (мягкое удаление)
+type TrashItem struct {
+    EntityType string    `json:"entity_type" label:"Тип"` // teachers, students, courses, classrooms, schedules
+    ID         int       `json:"id" label:"ID"`
+    Name       string    `json:"name" label:"Название"`
+    DeletedAt  time.Time `json:"deleted_at" label:"Удалено"`
+}
+
+// Cascade зависимые записи, затронутые удалением или восстановлением: таблица -> список ID
+type Cascade map[string][]int
+
+// Empty возвращает true, если зависимых записей нет
+func (c Cascade) Empty() bool {
+    for _, ids := range c {
+        if len(ids) > 0 {
+            return false
+        }
+    }
+    return true
+}
\ No newline at end of file
@@ -6,7 +6,9 @@ type User struct {
     ID           int    `json:"id"`
     Username     string `json:"username" validate:"required"`
     PasswordHash string `json:"-"`
-    Role         string `json:"role" validate:"oneof=admin teacher"`
+    Role         string `json:"role" validate:"required"` // Имя роли из таблицы roles
+    TeacherID    *int   `json:"teacher_id"`                // Преподаватель, связанный с учётной записью
+    StudentID    *int   `json:"student_id"`                // Студент, связанный с учётной записью
 }
 
 // HashPassword хэширует пароль
@@ -23,4 +25,22 @@ func (u *User) HashPassword(password string) error {
 func (u *User) CheckPassword(password string) bool {
     err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password))
     return err == nil
-}
\ No newline at end of file
+}
+// ProfileUpdate изменение своей учётной записи: nil - поле не меняется
+type ProfileUpdate struct {
+    Username *string `json:"username" validate:"omitnil,min=1,max=255"`
+    Password *string `json:"password" validate:"omitnil,min=1"`
+}
+
+// RegisterRequest тело POST /register
+type RegisterRequest struct {
+    Username string `json:"username"`
+    Password string `json:"password"`
+    Role     string `json:"role"` // Необязательно; открыто доступна только роль teacher
+}
+
+// Credentials логин и пароль: вход и создание учётной записи представителя
+type Credentials struct {
+    Username string `json:"username"`
+    Password string `json:"password"`
+}
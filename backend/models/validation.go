@@ -0,0 +1,142 @@
+package models
+
+import (
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/go-playground/validator/v10"
+)
+
+// Validate общий валидатор моделей; сервисы проверяют им входные данные
+var Validate *validator.Validate
+
+func init() {
+    Validate = validator.New()
+    Validate.RegisterTagNameFunc(jsonFieldName) // В ошибках валидации - имена полей из JSON
+    Validate.RegisterValidation("weekday", isWeekday)
+    Validate.RegisterValidation("notfuture", isNotFuture)
+    Validate.RegisterStructValidation(scheduleTimes, Schedule{})
+    Validate.RegisterStructValidation(scheduleUpdateTimes, ScheduleUpdate{})
+}
+
+// isWeekday день недели в формате time.Weekday: Monday ... Sunday
+func isWeekday(fl validator.FieldLevel) bool {
+    day := fl.Field().String()
+    for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+        if day == weekday.String() {
+            return true
+        }
+    }
+    return false
+}
+
+// isNotFuture дата YYYY-MM-DD не позже сегодняшнего дня; формат проверяет datetime
+func isNotFuture(fl validator.FieldLevel) bool {
+    date, err := time.Parse("2006-01-02", fl.Field().String())
+    if err != nil {
+        return true
+    }
+    return !date.After(time.Now())
+}
+
+// scheduleTimes окончание занятия позже начала
+func scheduleTimes(sl validator.StructLevel) {
+    schedule := sl.Current().Interface().(Schedule)
+    if !schedule.EndTime.After(schedule.StartTime) {
+        sl.ReportError(schedule.EndTime, "end_time", "EndTime", "after", "start_time")
+    }
+}
+
+// scheduleUpdateTimes то же для частичного обновления, если переданы оба времени.
+// Если передано одно, сервис сравнивает его с сохранённым
+func scheduleUpdateTimes(sl validator.StructLevel) {
+    update := sl.Current().Interface().(ScheduleUpdate)
+    if update.StartTime != nil && update.EndTime != nil && !update.EndTime.After(*update.StartTime) {
+        sl.ReportError(update.EndTime, "end_time", "EndTime", "after", "start_time")
+    }
+}
+
+// Языки сообщений об ошибках валидации
+const (
+    LangRu = "ru" // По умолчанию
+    LangEn = "en"
+)
+
+// ValidationFailed текст ошибки валидации с пояснениями по полям
+const ValidationFailed = "validation failed"
+
+var validationTitles = map[string]string{
+    LangRu: "ошибка в данных запроса",
+    LangEn: "validation failed",
+}
+
+// validationMessages шаблоны сообщений по правилам; %s - параметр правила
+var validationMessages = map[string]map[string]string{
+    LangRu: {
+        "required":  "обязательное поле",
+        "gt":        "должно быть больше %s",
+        "gte":       "должно быть не меньше %s",
+        "lt":        "должно быть меньше %s",
+        "lte":       "должно быть не больше %s",
+        "min":       "слишком короткое значение (минимум %s)",
+        "max":       "слишком длинное значение (максимум %s)",
+        "oneof":     "допустимые значения: %s",
+        "email":     "неверный email",
+        "datetime":  "неверная дата, ожидается формат YYYY-MM-DD",
+        "notfuture": "дата не может быть в будущем",
+        "weekday":   "день недели: Monday, Tuesday, Wednesday, Thursday, Friday, Saturday или Sunday",
+        "after":     "должно быть позже %s",
+        "type":      "неверный тип значения, ожидается %s",
+        "unknown":   "неизвестное поле",
+    },
+    LangEn: {
+        "required":  "is required",
+        "gt":        "must be greater than %s",
+        "gte":       "must be at least %s",
+        "lt":        "must be less than %s",
+        "lte":       "must be at most %s",
+        "min":       "is too short (minimum %s)",
+        "max":       "is too long (maximum %s)",
+        "oneof":     "must be one of: %s",
+        "email":     "must be a valid email",
+        "datetime":  "must be a date in YYYY-MM-DD format",
+        "notfuture": "must not be in the future",
+        "weekday":   "must be a weekday: Monday, Tuesday, Wednesday, Thursday, Friday, Saturday or Sunday",
+        "after":     "must be after %s",
+        "type":      "has a wrong type, expected %s",
+        "unknown":   "unknown field",
+    },
+}
+
+// LocalizeValidation переводит ошибку валидации на язык lang: текст и правила по полям
+// ("gt=0") превращаются в сообщения. Неизвестные правила остаются как есть
+func LocalizeValidation(err *Error, lang string) (string, map[string]string) {
+    messages, ok := validationMessages[lang]
+    if !ok {
+        lang, messages = LangRu, validationMessages[LangRu]
+    }
+
+    message := err.Message
+    if message == ValidationFailed {
+        message = validationTitles[lang]
+    }
+    if len(err.Fields) == 0 {
+        return message, nil
+    }
+
+    fields := make(map[string]string, len(err.Fields))
+    for field, rule := range err.Fields {
+        tag, param, _ := strings.Cut(rule, "=")
+        template, ok := messages[tag]
+        switch {
+        case !ok:
+            fields[field] = rule
+        case strings.Contains(template, "%s"):
+            fields[field] = fmt.Sprintf(template, strings.ReplaceAll(param, " ", ", "))
+        default:
+            fields[field] = template
+        }
+    }
+    return message, fields
+}
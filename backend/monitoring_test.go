@@ -0,0 +1,46 @@
+package main
+
+import (
+    "backend/config"
+    "backend/models"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+// Без базы процесс жив, но не готов; запросы к API считаются в /metrics по шаблону маршрута
+func TestHealthAndMetrics(t *testing.T) {
+    router := setupRouter(config.Default(), nil, nil, nil)
+    get := func(path string) *httptest.ResponseRecorder {
+        w := httptest.NewRecorder()
+        router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+        return w
+    }
+
+    if w := get("/healthz"); w.Code != http.StatusOK {
+        t.Errorf("GET /healthz = %d, want 200", w.Code)
+    }
+
+    w := get("/readyz")
+    var ready models.HealthStatus
+    if err := json.Unmarshal(w.Body.Bytes(), &ready); err != nil || w.Code != http.StatusServiceUnavailable || ready.Checks["database"] != models.HealthFailed {
+        t.Errorf("GET /readyz = %d %s, want 503 with a failed database check", w.Code, w.Body)
+    }
+
+    get("/api/v1/students/42")
+    body := get("/metrics").Body.String()
+    for _, want := range []string{
+        `http_requests_total{method="GET",route="/api/v1/students/:id",status="401"}`,
+        "# TYPE http_request_duration_seconds histogram",
+        "college_schedule_conflicts_total 0",
+    } {
+        if !strings.Contains(body, want) {
+            t.Errorf("metrics do not contain %s", want)
+        }
+    }
+    if strings.Contains(body, `route="/metrics"`) || strings.Contains(body, `route="/healthz"`) {
+        t.Error("probes must not be counted as API requests")
+    }
+}
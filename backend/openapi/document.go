@@ -0,0 +1,287 @@
+// Package openapi собирает описание API в формате OpenAPI 3 из маршрутов и моделей.
+// Схемы тел запросов и ответов строятся по Go-типам: теги json - имена полей,
+// validate - обязательность и ограничения, label - заголовок поля
+package openapi
+
+import (
+    "net/http"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+)
+
+const Version = "3.0.3"
+
+// Document корень описания OpenAPI
+type Document struct {
+    OpenAPI    string               `json:"openapi"`
+    Info       Info                 `json:"info"`
+    Servers    []Server             `json:"servers,omitempty"`
+    Tags       []Tag                `json:"tags,omitempty"`
+    Paths      map[string]*PathItem `json:"paths"`
+    Components Components           `json:"components"`
+
+    types       map[string]reflect.Type // Имена схем -> типы, для разрешения совпадений имён
+    tags        map[string]bool
+    errorSchema *Schema
+}
+
+type Info struct {
+    Title       string `json:"title"`
+    Description string `json:"description,omitempty"`
+    Version     string `json:"version"`
+}
+
+type Server struct {
+    URL string `json:"url"`
+}
+
+type Tag struct {
+    Name string `json:"name"`
+}
+
+// PathItem операции одного пути по HTTP-методам (get, post, ...)
+type PathItem map[string]*Operation
+
+type Operation struct {
+    Tags        []string              `json:"tags,omitempty"`
+    Summary     string                `json:"summary,omitempty"`
+    Description string                `json:"description,omitempty"`
+    OperationID string                `json:"operationId"`
+    Parameters  []Parameter           `json:"parameters,omitempty"`
+    RequestBody *RequestBody          `json:"requestBody,omitempty"`
+    Responses   map[string]*Response  `json:"responses"`
+    Security    []map[string][]string `json:"security"`
+    Deprecated  bool                  `json:"deprecated,omitempty"`
+    Permissions []string              `json:"x-permissions,omitempty"` // Достаточно одного из прав
+}
+
+type Parameter struct {
+    Name        string  `json:"name"`
+    In          string  `json:"in"`
+    Description string  `json:"description,omitempty"`
+    Required    bool    `json:"required,omitempty"`
+    Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+    Required bool                 `json:"required,omitempty"`
+    Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+    Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+    Description string               `json:"description"`
+    Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+    Schemas         map[string]*Schema        `json:"schemas"`
+    SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+    Type         string `json:"type"`
+    Scheme       string `json:"scheme"`
+    BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+const bearerAuth = "bearerAuth"
+
+// Message ответ с одним сообщением
+type Message struct {
+    Message string `json:"message"`
+}
+
+// Token ответ на вход: JWT для заголовка Authorization: Bearer
+type Token struct {
+    Token string `json:"token"`
+}
+
+// CascadeMessage ответ на удаление и восстановление: сообщение и затронутые записи по таблицам
+type CascadeMessage struct {
+    Message string           `json:"message"`
+    Cascade map[string][]int `json:"cascade"`
+}
+
+// Op описание одного маршрута для Add
+type Op struct {
+    Tag         string
+    Summary     string
+    Description string
+    Public      bool        // Без токена
+    Deprecated  bool        // Устаревший маршрут, оставлен для совместимости
+    Permissions []string    // Права, из которых достаточно одного
+    Query       []Parameter // Параметры строки запроса
+    Body        interface{} // Пример значения тела JSON; nil - без тела
+    OptionalBody bool        // Тело можно не передавать
+    Upload      bool        // multipart/form-data с полем file
+    Status      int         // Код успешного ответа, по умолчанию 200
+    Response    interface{} // Пример значения ответа; nil - без тела
+    Files       []string    // Дополнительные типы содержимого ответа (text/csv, application/pdf, ...)
+}
+
+// New пустое описание с JWT-авторизацией и общей схемой ошибки
+func New(title, version string, errorBody interface{}) *Document {
+    d := &Document{
+        OpenAPI: Version,
+        Info:    Info{Title: title, Version: version},
+        Paths:   map[string]*PathItem{},
+        Components: Components{
+            Schemas: map[string]*Schema{},
+            SecuritySchemes: map[string]SecurityScheme{
+                bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+            },
+        },
+        types: map[string]reflect.Type{},
+        tags:  map[string]bool{},
+    }
+    d.errorSchema = d.schemaOf(reflect.TypeOf(errorBody))
+    return d
+}
+
+// Add описывает маршрут в форме gin: метод и путь с параметрами ":id"
+func (d *Document) Add(method, ginPath string, op Op) {
+    path, params := convertPath(ginPath)
+    item, ok := d.Paths[path]
+    if !ok {
+        item = &PathItem{}
+        d.Paths[path] = item
+    }
+
+    operation := &Operation{
+        Summary:     op.Summary,
+        Description: op.Description,
+        OperationID: operationID(method, ginPath),
+        Parameters:  append(params, op.Query...),
+        Responses:   map[string]*Response{},
+        Security:    []map[string][]string{{bearerAuth: {}}},
+        Deprecated:  op.Deprecated,
+        Permissions: op.Permissions,
+    }
+    if op.Public {
+        operation.Security = []map[string][]string{} // Пустой список отменяет авторизацию
+    }
+    if op.Tag != "" {
+        operation.Tags = []string{op.Tag}
+        if !d.tags[op.Tag] {
+            d.tags[op.Tag] = true
+            d.Tags = append(d.Tags, Tag{Name: op.Tag})
+        }
+    }
+
+    switch {
+    case op.Upload:
+        operation.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+            "multipart/form-data": {Schema: &Schema{
+                Type: "object",
+                Properties: map[string]*Schema{
+                    "file":    {Type: "string", Format: "binary", Description: "CSV или XLSX, до 10 МБ"},
+                    "mapping": {Type: "string", Description: `JSON {"Колонка": "поле"}`},
+                    "dry_run": {Type: "boolean"},
+                },
+                Required: []string{"file"},
+            }},
+        }}
+    case op.Body != nil:
+        operation.RequestBody = &RequestBody{Required: !op.OptionalBody, Content: map[string]MediaType{
+            "application/json": {Schema: d.schemaOf(reflect.TypeOf(op.Body))},
+        }}
+    }
+
+    status := op.Status
+    if status == 0 {
+        status = http.StatusOK
+    }
+    success := &Response{Description: http.StatusText(status)}
+    if op.Response != nil {
+        success.Content = map[string]MediaType{"application/json": {Schema: d.schemaOf(reflect.TypeOf(op.Response))}}
+    }
+    for _, contentType := range op.Files {
+        if success.Content == nil {
+            success.Content = map[string]MediaType{}
+        }
+        success.Content[contentType] = MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+    }
+    operation.Responses[strconv.Itoa(status)] = success
+
+    errorContent := map[string]MediaType{"application/json": {Schema: d.errorSchema}}
+    if !op.Public {
+        operation.Responses["401"] = &Response{Description: "Нет или неверный токен", Content: errorContent}
+        if len(op.Permissions) > 0 {
+            operation.Responses["403"] = &Response{Description: "У роли нет нужного права", Content: errorContent}
+        }
+    }
+    operation.Responses["default"] = &Response{Description: "Ошибка", Content: errorContent}
+
+    (*item)[strings.ToLower(method)] = operation
+}
+
+// convertPath "/students/:id" -> "/students/{id}" и описания параметров пути
+func convertPath(ginPath string) (string, []Parameter) {
+    var params []Parameter
+    segments := strings.Split(ginPath, "/")
+    for i, segment := range segments {
+        if !strings.HasPrefix(segment, ":") && !strings.HasPrefix(segment, "*") {
+            continue
+        }
+        name := segment[1:]
+        schema := &Schema{Type: "string"}
+        if name == "id" || strings.HasSuffix(name, "_id") {
+            schema = &Schema{Type: "integer"}
+        }
+        params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+        segments[i] = "{" + name + "}"
+    }
+    return strings.Join(segments, "/"), params
+}
+
+// operationID "GET /students/:id/orders" -> "getStudentsIdOrders"
+func operationID(method, ginPath string) string {
+    id := strings.ToLower(method)
+    for _, part := range strings.FieldsFunc(ginPath, func(r rune) bool { return r == '/' || r == ':' || r == '-' || r == '_' }) {
+        id += strings.ToUpper(part[:1]) + part[1:]
+    }
+    return id
+}
+
+// Has описан ли маршрут в форме gin
+func (d *Document) Has(method, ginPath string) bool {
+    path, _ := convertPath(ginPath)
+    item, ok := d.Paths[path]
+    if !ok {
+        return false
+    }
+    _, ok = (*item)[strings.ToLower(method)]
+    return ok
+}
+
+// Routes все описанные операции как "METHOD /path" в форме OpenAPI, по порядку
+func (d *Document) Routes() []string {
+    var routes []string
+    for path, item := range d.Paths {
+        for method := range *item {
+            routes = append(routes, strings.ToUpper(method)+" "+path)
+        }
+    }
+    sort.Strings(routes)
+    return routes
+}
+
+// Handler отдаёт описание в JSON
+func (d *Document) Handler() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        c.JSON(http.StatusOK, d)
+    }
+}
+
+// QueryParam необязательный параметр строки запроса
+func QueryParam(name, typ, description string) Parameter {
+    return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: typ}}
+}
@@ -0,0 +1,221 @@
+package openapi
+
+import (
+    "encoding"
+    "reflect"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Schema схема JSON-значения (подмножество OpenAPI 3.0)
+type Schema struct {
+    Ref                  string             `json:"$ref,omitempty"`
+    Type                 string             `json:"type,omitempty"`
+    Format               string             `json:"format,omitempty"`
+    Title                string             `json:"title,omitempty"`
+    Description          string             `json:"description,omitempty"`
+    Enum                 []string           `json:"enum,omitempty"`
+    Nullable             bool               `json:"nullable,omitempty"`
+    Minimum              *float64           `json:"minimum,omitempty"`
+    ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+    Maximum              *float64           `json:"maximum,omitempty"`
+    MinLength            *int               `json:"minLength,omitempty"`
+    MaxLength            *int               `json:"maxLength,omitempty"`
+    MinItems             *int               `json:"minItems,omitempty"`
+    Items                *Schema            `json:"items,omitempty"`
+    Properties           map[string]*Schema `json:"properties,omitempty"`
+    Required             []string           `json:"required,omitempty"`
+    AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+    timeType          = reflect.TypeOf(time.Time{})
+    textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Дни недели для пользовательского правила validate:"weekday"
+var weekdays = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+// schemaOf схема Go-типа. Именованные структуры попадают в components.schemas и подставляются ссылкой
+func (d *Document) schemaOf(t reflect.Type) *Schema {
+    switch {
+    case t == timeType:
+        return &Schema{Type: "string", Format: "date-time"}
+    case t.Kind() != reflect.Pointer && t.Implements(textMarshalerType):
+        return &Schema{Type: "string"} // В JSON - строкой, например config.Duration "24h0m0s"
+    case t.Kind() == reflect.Pointer:
+        schema := d.schemaOf(t.Elem())
+        if schema.Ref == "" {
+            schema.Nullable = true
+        }
+        return schema
+    }
+
+    switch t.Kind() {
+    case reflect.Bool:
+        return &Schema{Type: "boolean"}
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+        return &Schema{Type: "integer"}
+    case reflect.Int64, reflect.Uint64:
+        return &Schema{Type: "integer", Format: "int64"}
+    case reflect.Float32, reflect.Float64:
+        return &Schema{Type: "number"}
+    case reflect.String:
+        return &Schema{Type: "string"}
+    case reflect.Slice, reflect.Array:
+        if t.Elem().Kind() == reflect.Uint8 {
+            return &Schema{Type: "string", Format: "byte"}
+        }
+        return &Schema{Type: "array", Items: d.schemaOf(t.Elem())}
+    case reflect.Map:
+        return &Schema{Type: "object", AdditionalProperties: d.schemaOf(t.Elem())}
+    case reflect.Struct:
+        if t.Name() == "" {
+            return d.structSchema(t)
+        }
+        return &Schema{Ref: "#/components/schemas/" + d.register(t)}
+    }
+    return &Schema{} // interface{}: любое значение
+}
+
+// register добавляет структуру в components.schemas и возвращает её имя
+func (d *Document) register(t reflect.Type) string {
+    name := schemaName(t)
+    if existing, ok := d.types[name]; ok && existing != t {
+        pkg := pkgName(t)
+        name = strings.ToUpper(pkg[:1]) + pkg[1:] + name // Одинаковые имена из разных пакетов: ConfigConfig
+    }
+    if _, ok := d.types[name]; ok {
+        return name
+    }
+    d.types[name] = t
+    d.Components.Schemas[name] = &Schema{} // Заглушка на случай ссылки структуры на саму себя
+    d.Components.Schemas[name] = d.structSchema(t)
+    return name
+}
+
+// schemaName имя схемы: Teacher, для обобщённых типов Page[models.Teacher] - TeacherPage
+func schemaName(t reflect.Type) string {
+    name := t.Name()
+    open := strings.IndexByte(name, '[')
+    if open < 0 {
+        return name
+    }
+    args := strings.Split(strings.TrimSuffix(name[open+1:], "]"), ",")
+    prefix := ""
+    for _, arg := range args {
+        prefix += arg[strings.LastIndexByte(arg, '.')+1:]
+    }
+    return prefix + name[:open]
+}
+
+func pkgName(t reflect.Type) string {
+    return t.PkgPath()[strings.LastIndexByte(t.PkgPath(), '/')+1:]
+}
+
+func (d *Document) structSchema(t reflect.Type) *Schema {
+    schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        if !field.IsExported() {
+            continue
+        }
+        name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+        if name == "-" {
+            continue
+        }
+        // Встроенная структура без имени в JSON: её поля на верхнем уровне
+        if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+            embedded := d.structSchema(field.Type)
+            for key, value := range embedded.Properties {
+                schema.Properties[key] = value
+            }
+            schema.Required = append(schema.Required, embedded.Required...)
+            continue
+        }
+        if name == "" {
+            name = field.Name
+        }
+
+        property := d.schemaOf(field.Type)
+        if property.Ref == "" {
+            if label := field.Tag.Get("label"); label != "" && label != "-" {
+                property.Title = label
+            }
+            if applyRules(property, field.Tag.Get("validate")) && field.Type.Kind() != reflect.Pointer {
+                schema.Required = append(schema.Required, name)
+            }
+        } else if hasRule(field.Tag.Get("validate"), "required") {
+            schema.Required = append(schema.Required, name)
+        }
+        schema.Properties[name] = property
+    }
+    return schema
+}
+
+func hasRule(tag, rule string) bool {
+    for _, item := range strings.Split(tag, ",") {
+        if item == rule {
+            return true
+        }
+    }
+    return false
+}
+
+// applyRules переносит правила validate в схему и сообщает, обязательно ли поле
+func applyRules(schema *Schema, tag string) (required bool) {
+    for _, rule := range strings.Split(tag, ",") {
+        name, param, _ := strings.Cut(rule, "=")
+        switch name {
+        case "dive":
+            return required // Дальше правила для элементов
+        case "required":
+            required = true
+        case "oneof":
+            schema.Enum = strings.Fields(param)
+        case "weekday":
+            schema.Enum = weekdays
+        case "email":
+            schema.Format = "email"
+        case "datetime":
+            if param == "2006-01-02" {
+                schema.Format = "date"
+            }
+        case "min", "max", "gt", "gte", "lte", "lt":
+            limit(schema, name, param)
+        }
+    }
+    return required
+}
+
+// limit ограничение длины строки, числа элементов или значения числа
+func limit(schema *Schema, rule, param string) {
+    value, err := strconv.ParseFloat(param, 64)
+    if err != nil {
+        return
+    }
+    n := int(value)
+    switch schema.Type {
+    case "string":
+        switch rule {
+        case "min", "gte":
+            schema.MinLength = &n
+        case "max", "lte":
+            schema.MaxLength = &n
+        }
+    case "array":
+        if rule == "min" || rule == "gte" {
+            schema.MinItems = &n
+        }
+    case "integer", "number":
+        switch rule {
+        case "min", "gte":
+            schema.Minimum = &value
+        case "gt":
+            schema.Minimum, schema.ExclusiveMinimum = &value, true
+        case "max", "lte", "lt":
+            schema.Maximum = &value
+        }
+    }
+}
@@ -0,0 +1,21 @@
+package openapi
+
+import (
+    _ "embed"
+    "net/http"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+)
+
+// Страница документации без внешних зависимостей: загружает описание и позволяет отправить запрос
+//go:embed docs.html
+var docsPage string
+
+// UI отдаёт страницу документации, которая читает описание по адресу specURL
+func UI(specURL string) gin.HandlerFunc {
+    page := []byte(strings.Replace(docsPage, "{{SPEC_URL}}", specURL, 1))
+    return func(c *gin.Context) {
+        c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+    }
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+    "backend/models"
+    "database/sql"
+    "errors"
+    "fmt"
+    "time"
+)
+
+type ActivationRepository struct {
+    DB DBTX
+}
+
+func NewActivationRepository(db DBTX) *ActivationRepository {
+    return &ActivationRepository{DB: db}
+}
+
+// GetStudentsWithoutAccount возвращает обучающихся студентов без учётной записи;
+// пустой groupName - все группы
+func (r *ActivationRepository) GetStudentsWithoutAccount(groupName string) ([]models.Student, error) {
+    query := `
+        SELECT s.id, s.name, s.group_name
+        FROM students s
+        WHERE s.deleted_at IS NULL AND s.status = 'active'
+          AND NOT EXISTS (SELECT 1 FROM users u WHERE u.student_id = s.id)
+    `
+    args := []interface{}{}
+    if groupName != "" {
+        query += ` AND s.group_name = $1`
+        args = append(args, groupName)
+    }
+    query += ` ORDER BY s.group_name, s.name`
+
+    rows, err := r.DB.Query(query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    students := []models.Student{}
+    for rows.Next() {
+        var student models.Student
+        if err := rows.Scan(&student.ID, &student.Name, &student.GroupName); err != nil {
+            return nil, err
+        }
+        students = append(students, student)
+    }
+    return students, rows.Err()
+}
+
+// ReplaceCodes сохраняет новые коды активации; неиспользованные старые коды этих студентов аннулируются.
+// hashes - хэши кодов по ID студента
+func (r *ActivationRepository) ReplaceCodes(hashes map[int]string, expiresAt time.Time) error {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    for studentID, hash := range hashes {
+        if _, err := tx.Exec(`DELETE FROM activation_codes WHERE student_id = $1 AND used_at IS NULL`, studentID); err != nil {
+            return err
+        }
+        _, err := tx.Exec(`
+            INSERT INTO activation_codes (student_id, code_hash, expires_at)
+            VALUES ($1, $2, $3)
+        `, studentID, hash, expiresAt)
+        if err != nil {
+            return fmt.Errorf("failed to save activation code: %w", err)
+        }
+    }
+    return tx.Commit()
+}
+
+// Activate гасит код активации и создает учётную запись студента
+func (r *ActivationRepository) Activate(codeHash string, user *models.User) error {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var codeID, studentID int
+    var expiresAt time.Time
+    var usedAt sql.NullTime
+    err = tx.QueryRow(`
+        SELECT id, student_id, expires_at, used_at
+        FROM activation_codes
+        WHERE code_hash = $1
+        FOR UPDATE
+    `, codeHash).Scan(&codeID, &studentID, &expiresAt, &usedAt)
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return models.Invalid("invalid activation code")
+        }
+        return err
+    }
+    if usedAt.Valid {
+        return models.Conflict("activation code already used")
+    }
+    if time.Now().After(expiresAt) {
+        return models.Conflict("activation code expired")
+    }
+
+    var hasAccount bool
+    if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM users WHERE student_id = $1)`, studentID).Scan(&hasAccount); err != nil {
+        return err
+    }
+    if hasAccount {
+        return models.Conflict("student account already activated")
+    }
+
+    var usernameTaken bool
+    if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`, user.Username).Scan(&usernameTaken); err != nil {
+        return err
+    }
+    if usernameTaken {
+        return models.Conflict("username '%s' already taken", user.Username)
+    }
+
+    user.StudentID = &studentID
+    err = tx.QueryRow(`
+        INSERT INTO users (username, password_hash, role, student_id)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `, user.Username, user.PasswordHash, user.Role, studentID).Scan(&user.ID)
+    if err != nil {
+        return fmt.Errorf("failed to create user: %w", err)
+    }
+
+    if _, err := tx.Exec(`UPDATE activation_codes SET used_at = CURRENT_TIMESTAMP WHERE id = $1`, codeID); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
\ No newline at end of file
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+    "backend/models"
+    "database/sql"
+    "fmt"
+)
+
+type AnnouncementRepository struct {
+    DB DBTX
+}
+
+func NewAnnouncementRepository(db DBTX) *AnnouncementRepository {
+    return &AnnouncementRepository{DB: db}
+}
+
+func (r *AnnouncementRepository) CreateAnnouncement(announcement *models.Announcement) error {
+    query := `
+        INSERT INTO announcements (title, body, group_name, created_by)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at
+    `
+    err := r.DB.QueryRow(query, announcement.Title, announcement.Body, announcement.GroupName, announcement.CreatedBy).
+        Scan(&announcement.ID, &announcement.CreatedAt)
+    if err != nil {
+        return fmt.Errorf("failed to create announcement: %w", err)
+    }
+    return nil
+}
+
+// GetAnnouncements возвращает объявления группы и общие объявления;
+// пустой groupName - все объявления
+func (r *AnnouncementRepository) GetAnnouncements(groupName string) ([]models.Announcement, error) {
+    query := `SELECT id, title, body, group_name, created_by, created_at FROM announcements`
+    args := []interface{}{}
+    if groupName != "" {
+        query += ` WHERE group_name IS NULL OR group_name = $1`
+        args = append(args, groupName)
+    }
+    query += ` ORDER BY created_at DESC`
+
+    rows, err := r.DB.Query(query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    announcements := []models.Announcement{}
+    for rows.Next() {
+        var announcement models.Announcement
+        var group sql.NullString
+        var createdBy sql.NullInt64
+        if err := rows.Scan(&announcement.ID, &announcement.Title, &announcement.Body, &group, &createdBy, &announcement.CreatedAt); err != nil {
+            return nil, err
+        }
+        if group.Valid {
+            announcement.GroupName = &group.String
+        }
+        if createdBy.Valid {
+            value := int(createdBy.Int64)
+            announcement.CreatedBy = &value
+        }
+        announcements = append(announcements, announcement)
+    }
+    return announcements, rows.Err()
+}
+
+func (r *AnnouncementRepository) DeleteAnnouncement(id int) error {
+    result, err := r.DB.Exec(`DELETE FROM announcements WHERE id = $1`, id)
+    if err != nil {
+        return err
+    }
+    rowsAffected, _ := result.RowsAffected()
+    if rowsAffected == 0 {
+        return models.NotFound("announcement with id %d not found", id)
+    }
+    return nil
+}
\ No newline at end of file
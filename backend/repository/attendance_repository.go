@@ -0,0 +1,141 @@
+package repositories
+
+import (
+    "backend/models"
+    "database/sql"
+    "errors"
+    "fmt"
+
+    "github.com/lib/pq"
+)
+
+type AttendanceRepository struct {
+    DB DBTX
+}
+
+func NewAttendanceRepository(db DBTX) *AttendanceRepository {
+    return &AttendanceRepository{DB: db}
+}
+
+const attendanceSelect = `
+    SELECT a.id, a.schedule_id, a.student_id, to_char(a.date, 'YYYY-MM-DD'), a.status, s.group_name, s.day_of_week,
+           a.marked_by, a.marked_at
+    FROM attendance a
+    JOIN schedules s ON a.schedule_id = s.id
+`
+
+func scanAttendance(row rowScanner) (*models.Attendance, error) {
+    var attendance models.Attendance
+    var markedBy sql.NullInt64
+    err := row.Scan(&attendance.ID, &attendance.ScheduleID, &attendance.StudentID, &attendance.Date, &attendance.Status,
+        &attendance.GroupName, &attendance.DayOfWeek, &markedBy, &attendance.MarkedAt)
+    if err != nil {
+        return nil, err
+    }
+    if markedBy.Valid {
+        value := int(markedBy.Int64)
+        attendance.MarkedBy = &value
+    }
+    return &attendance, nil
+}
+
+// MarkAttendance сохраняет отметки посещаемости занятия на дату.
+// Отмечать можно только студентов группы, к которой относится занятие
+func (r *AttendanceRepository) MarkAttendance(scheduleID int, date string, marks map[int]string, markedBy int) ([]models.Attendance, error) {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var groupName string
+    err = tx.QueryRow(`SELECT group_name FROM schedules WHERE id = $1 AND deleted_at IS NULL`, scheduleID).Scan(&groupName)
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, models.NotFound("schedule not found")
+        }
+        return nil, err
+    }
+
+    var cancelled bool
+    err = tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM schedule_overrides WHERE schedule_id = $1 AND date = $2 AND cancelled)`, scheduleID, date).Scan(&cancelled)
+    if err != nil {
+        return nil, err
+    }
+    if cancelled {
+        return nil, models.Conflict("lesson on %s is cancelled", date)
+    }
+
+    studentIDs := make([]int64, 0, len(marks))
+    for studentID := range marks {
+        studentIDs = append(studentIDs, int64(studentID))
+    }
+    ids, err := collectIDs(tx, `
+        SELECT id FROM students
+        WHERE id = ANY($1) AND group_name = $2 AND deleted_at IS NULL
+    `, pq.Array(studentIDs), groupName)
+    if err != nil {
+        return nil, err
+    }
+    inGroup := make(map[int]bool, len(ids))
+    for _, id := range ids {
+        inGroup[id] = true
+    }
+
+    var byUser *int
+    if markedBy != 0 {
+        byUser = &markedBy
+    }
+
+    saved := []int{}
+    for studentID, status := range marks {
+        if !inGroup[studentID] {
+            return nil, models.Invalid("student with id %d is not in group '%s'", studentID, groupName)
+        }
+        var id int
+        err := tx.QueryRow(`
+            INSERT INTO attendance (schedule_id, student_id, date, status, marked_by)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (schedule_id, student_id, date) DO UPDATE
+            SET status = EXCLUDED.status, marked_by = EXCLUDED.marked_by, marked_at = CURRENT_TIMESTAMP
+            RETURNING id
+        `, scheduleID, studentID, date, status, byUser).Scan(&id)
+        if err != nil {
+            return nil, fmt.Errorf("failed to save attendance: %w", err)
+        }
+        saved = append(saved, id)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return r.getAttendance(`WHERE a.id = ANY($1) ORDER BY a.student_id`, pq.Array(saved))
+}
+
+// GetScheduleAttendance возвращает отметки занятия на дату
+func (r *AttendanceRepository) GetScheduleAttendance(scheduleID int, date string) ([]models.Attendance, error) {
+    return r.getAttendance(`WHERE a.schedule_id = $1 AND a.date = $2 ORDER BY a.student_id`, scheduleID, date)
+}
+
+// GetStudentAttendance возвращает посещаемость студента за период (даты включительно)
+func (r *AttendanceRepository) GetStudentAttendance(studentID int, from, to string) ([]models.Attendance, error) {
+    return r.getAttendance(`WHERE a.student_id = $1 AND a.date BETWEEN $2 AND $3 ORDER BY a.date, s.start_time`, studentID, from, to)
+}
+
+func (r *AttendanceRepository) getAttendance(where string, args ...interface{}) ([]models.Attendance, error) {
+    rows, err := r.DB.Query(attendanceSelect+where, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    result := []models.Attendance{}
+    for rows.Next() {
+        attendance, err := scanAttendance(rows)
+        if err != nil {
+            return nil, err
+        }
+        result = append(result, *attendance)
+    }
+    return result, rows.Err()
+}
\ No newline at end of file
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+    "backend/models"
+    "database/sql"
+    "fmt"
+)
+
+type AuditRepository struct {
+    DB DBTX
+}
+
+func NewAuditRepository(db DBTX) *AuditRepository {
+    return &AuditRepository{DB: db}
+}
+
+// CreateEntry добавляет запись в журнал аудита
+func (r *AuditRepository) CreateEntry(entry *models.AuditEntry) error {
+    query := `
+        INSERT INTO audit_log (user_id, action, entity_type, entity_id, before, after)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at
+    `
+    return r.DB.QueryRow(query, entry.UserID, entry.Action, entry.EntityType, entry.EntityID, nullJSON(entry.Before), nullJSON(entry.After)).
+        Scan(&entry.ID, &entry.CreatedAt)
+}
+
+func nullJSON(data []byte) interface{} {
+    if len(data) == 0 {
+        return nil
+    }
+    return string(data)
+}
+
+// GetEntries возвращает записи журнала по фильтрам, новые сначала
+func (r *AuditRepository) GetEntries(filter models.AuditFilter) ([]models.AuditEntry, error) {
+    query := `
+        SELECT id, user_id, action, entity_type, entity_id, before, after, created_at
+        FROM audit_log
+        WHERE 1=1
+    `
+    args := []interface{}{}
+    paramIndex := 1
+
+    if filter.UserID != 0 {
+        query += fmt.Sprintf(" AND user_id = $%d", paramIndex)
+        args = append(args, filter.UserID)
+        paramIndex++
+    }
+    if filter.EntityType != "" {
+        query += fmt.Sprintf(" AND entity_type = $%d", paramIndex)
+        args = append(args, filter.EntityType)
+        paramIndex++
+    }
+    if filter.EntityID != 0 {
+        query += fmt.Sprintf(" AND entity_id = $%d", paramIndex)
+        args = append(args, filter.EntityID)
+        paramIndex++
+    }
+    if !filter.From.IsZero() {
+        query += fmt.Sprintf(" AND created_at >= $%d", paramIndex)
+        args = append(args, filter.From)
+        paramIndex++
+    }
+    if !filter.To.IsZero() {
+        query += fmt.Sprintf(" AND created_at < $%d", paramIndex)
+        args = append(args, filter.To)
+        paramIndex++
+    }
+
+    query += " ORDER BY created_at DESC, id DESC"
+    if filter.Limit > 0 {
+        query += fmt.Sprintf(" LIMIT $%d", paramIndex)
+        args = append(args, filter.Limit)
+    }
+
+    rows, err := r.DB.Query(query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    entries := []models.AuditEntry{}
+    for rows.Next() {
+        var entry models.AuditEntry
+        var userID sql.NullInt64
+        var before, after []byte
+        if err := rows.Scan(&entry.ID, &userID, &entry.Action, &entry.EntityType, &entry.EntityID, &before, &after, &entry.CreatedAt); err != nil {
+            return nil, err
+        }
+        if userID.Valid {
+            value := int(userID.Int64)
+            entry.UserID = &value
+        }
+        entry.Before = before
+        entry.After = after
+        entries = append(entries, entry)
+    }
+    return entries, rows.Err()
+}
\ No newline at end of file
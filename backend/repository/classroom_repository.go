@@ -4,15 +4,13 @@ import (
     "backend/models"
     "database/sql"
     "errors"
-	"fmt"
-	"strings"
 )
 
 type ClassroomRepository struct {
-    DB *sql.DB
+    DB DBTX
 }
 
-func NewClassroomRepository(db *sql.DB) *ClassroomRepository {
+func NewClassroomRepository(db DBTX) *ClassroomRepository {
     return &ClassroomRepository{DB: db}
 }
 
@@ -27,35 +25,74 @@ func (r *ClassroomRepository) CreateClassroom(classroom *models.Classroom) error
     return err
 }
 
-// GetClassrooms возвращает все аудитории
-func (r *ClassroomRepository) GetClassrooms() ([]models.Classroom, error) {
-    query := `SELECT id, name, capacity, description FROM classrooms`
-    rows, err := r.DB.Query(query)
-    if err != nil {
+// classroomList поля сортировки и фильтры списка аудиторий
+var classroomList = listSpec{
+    sorts: map[string]string{
+        "id":       "id",
+        "name":     "name",
+        "capacity": "capacity",
+    },
+    filters: map[string]listFilter{
+        "name":         prefixFilter("name"),
+        "capacity_min": intFilter("capacity >= ?"),
+        "capacity_max": intFilter("capacity <= ?"),
+    },
+    id: "id",
+}
+
+const classroomListQuery = `SELECT id, name, capacity, description FROM classrooms WHERE deleted_at IS NULL`
+
+func scanClassroom(row rowScanner) (*models.Classroom, error) {
+    var classroom models.Classroom
+    if err := row.Scan(&classroom.ID, &classroom.Name, &classroom.Capacity, &classroom.Description); err != nil {
         return nil, err
     }
-    defer rows.Close()
+    return &classroom, nil
+}
 
-    var classrooms []models.Classroom
-    for rows.Next() {
-        var classroom models.Classroom
-        if err := rows.Scan(&classroom.ID, &classroom.Name, &classroom.Capacity, &classroom.Description); err != nil {
-            return nil, err
+// ListClassrooms возвращает страницу аудиторий и их общее количество с учётом фильтров
+func (r *ClassroomRepository) ListClassrooms(query models.ListQuery) ([]models.Classroom, int, error) {
+    classrooms := []models.Classroom{}
+    total, err := queryList(r.DB, classroomList, classroomListQuery, query, func(rows *sql.Rows) error {
+        classroom, err := scanClassroom(rows)
+        if err != nil {
+            return err
         }
-        classrooms = append(classrooms, classroom)
+        classrooms = append(classrooms, *classroom)
+        return nil
+    })
+    if err != nil {
+        return nil, 0, err
     }
-    return classrooms, nil
+    return classrooms, total, nil
+}
+
+// EachClassroom передаёт fn аудитории с учётом фильтров и сортировки по мере чтения из базы
+func (r *ClassroomRepository) EachClassroom(query models.ListQuery, fn func(models.Classroom) error) error {
+    return eachList(r.DB, classroomList, classroomListQuery, query, func(rows *sql.Rows) error {
+        classroom, err := scanClassroom(rows)
+        if err != nil {
+            return err
+        }
+        return fn(*classroom)
+    })
+}
+
+// GetClassrooms возвращает все аудитории
+func (r *ClassroomRepository) GetClassrooms() ([]models.Classroom, error) {
+    classrooms, _, err := r.ListClassrooms(models.ListQuery{})
+    return classrooms, err
 }
 
 // GetClassroomByID возвращает аудиторию по ID
 func (r *ClassroomRepository) GetClassroomByID(id int) (*models.Classroom, error) {
-    query := `SELECT id, name, capacity, description FROM classrooms WHERE id = $1`
+    query := `SELECT id, name, capacity, description FROM classrooms WHERE id = $1 AND deleted_at IS NULL`
     row := r.DB.QueryRow(query, id)
 
     var classroom models.Classroom
     if err := row.Scan(&classroom.ID, &classroom.Name, &classroom.Capacity, &classroom.Description); err != nil {
         if errors.Is(err, sql.ErrNoRows) {
-            return nil, errors.New("classroom not found")
+            return nil, models.NotFound("classroom not found")
         }
         return nil, err
     }
@@ -63,67 +100,48 @@ func (r *ClassroomRepository) GetClassroomByID(id int) (*models.Classroom, error
 }
 
 // UpdateClassroom обновляет данные аудитории
-func (r *ClassroomRepository) UpdateClassroom(id int, updates map[string]interface{}) (*models.Classroom, error) {
-    setClauses := []string{}
-    args := []interface{}{}
-    paramIndex := 1
-
-    for key, value := range updates {
-        switch key {
-        case "name":
-            setClauses = append(setClauses, fmt.Sprintf("name = $%d", paramIndex))
-            args = append(args, value)
-            paramIndex++
-        case "capacity":
-            capacity, ok := value.(float64) // JSON передает числа как float64
-            if !ok {
-                return nil, errors.New("invalid type for capacity")
-            }
-            setClauses = append(setClauses, fmt.Sprintf("capacity = $%d", paramIndex))
-            args = append(args, int(capacity)) // Преобразуем float64 в int
-            paramIndex++
-        case "description":
-            description, ok := value.(string)
-            if !ok {
-                return nil, errors.New("invalid type for description")
-            }
-            setClauses = append(setClauses, fmt.Sprintf("description = $%d", paramIndex))
-            args = append(args, description)
-            paramIndex++
-        default:
-            return nil, errors.New("invalid field: " + key)
-        }
+func (r *ClassroomRepository) UpdateClassroom(id int, update models.ClassroomUpdate) (*models.Classroom, error) {
+    var set updateSet
+    if update.Name != nil {
+        set.set("name = ?", *update.Name)
     }
-
-    if len(setClauses) == 0 {
-        return nil, errors.New("no fields to update")
+    if update.Capacity != nil {
+        set.set("capacity = ?", *update.Capacity)
+    }
+    if update.Description != nil {
+        set.set("description = ?", *update.Description)
     }
 
-    query := fmt.Sprintf(`UPDATE classrooms SET %s WHERE id = $%d RETURNING id, name, capacity, description`, strings.Join(setClauses, ", "), paramIndex)
-    args = append(args, id)
+    query, args, err := set.query(`UPDATE classrooms SET %s WHERE id = $%d AND deleted_at IS NULL RETURNING id, name, capacity, description`, id)
+    if err != nil {
+        return nil, err
+    }
 
     var classroom models.Classroom
-    err := r.DB.QueryRow(query, args...).Scan(&classroom.ID, &classroom.Name, &classroom.Capacity, &classroom.Description)
+    err = r.DB.QueryRow(query, args...).Scan(&classroom.ID, &classroom.Name, &classroom.Capacity, &classroom.Description)
     if err != nil {
         if errors.Is(err, sql.ErrNoRows) {
-            return nil, errors.New("classroom not found")
+            return nil, models.NotFound("classroom not found")
         }
         return nil, err
     }
     return &classroom, nil
 }
 
-// DeleteClassroom удаляет аудиторию по ID
-func (r *ClassroomRepository) DeleteClassroom(id int) error {
-    query := `DELETE FROM classrooms WHERE id = $1`
-    result, err := r.DB.Exec(query, id)
-    if err != nil {
-        return err
+// GetClassroomDeletionImpact возвращает записи, которые будут удалены вместе с аудиторией
+func (r *ClassroomRepository) GetClassroomDeletionImpact(id int) (models.Cascade, error) {
+    impact, err := deletionImpact(r.DB, "classrooms", id)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, models.NotFound("classroom not found")
     }
+    return impact, err
+}
 
-    rowsAffected, _ := result.RowsAffected()
-    if rowsAffected == 0 {
-        return errors.New("classroom not found")
+// DeleteClassroom помещает аудиторию и занятия в ней в корзину
+func (r *ClassroomRepository) DeleteClassroom(id int) (models.Cascade, error) {
+    cascade, err := softDelete(r.DB, "classrooms", id)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, models.NotFound("classroom not found")
     }
-    return nil
+    return cascade, err
 }
\ No newline at end of file
@@ -5,51 +5,57 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/lib/pq"
 )
 
 type CourseRepository struct {
-    DB *sql.DB
+    DB DBTX
 }
 
 
 
-func NewCourseRepository(db *sql.DB) *CourseRepository {
+func NewCourseRepository(db DBTX) *CourseRepository {
     return &CourseRepository{DB: db}
 }
 
 
 
-// CreateCourse создаёт новый курс
+// CreateCourse создаёт новый курс и добавляет его в список курсов преподавателя одной транзакцией
 func (r *CourseRepository) CreateCourse(course *models.Course) error {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
     // Создаем новый курс
     query := `
         INSERT INTO courses (name, description, teacher_id)
         VALUES ($1, $2, $3)
         RETURNING id
     `
-    err := r.DB.QueryRow(query, course.Name, course.Description, course.TeacherID).Scan(&course.ID)
+    err = tx.QueryRow(query, course.Name, course.Description, course.TeacherID).Scan(&course.ID)
     if err != nil {
-        return fmt.Errorf("failed to create course: %v", err)
+        return fmt.Errorf("failed to create course: %w", err)
     }
 
     // Если teacher_id указан, обновляем поле courses у преподавателя
     if course.TeacherID != nil {
         teacherID := *course.TeacherID
 
-        // Получаем текущие курсы преподавателя
+        // Получаем текущие курсы преподавателя; строка блокируется, чтобы параллельное
+        // создание курса не затёрло массив
         var currentCourses []string
-        err := r.DB.QueryRow(
-            "SELECT courses FROM teachers WHERE id = $1",
+        err := tx.QueryRow(
+            "SELECT courses FROM teachers WHERE id = $1 AND deleted_at IS NULL FOR UPDATE",
             teacherID,
         ).Scan(pq.Array(&currentCourses))
         if err != nil {
             if errors.Is(err, sql.ErrNoRows) {
-                return fmt.Errorf("teacher with id %d not found", teacherID)
+                return models.NotFound("teacher with id %d not found", teacherID)
             }
-            return fmt.Errorf("failed to fetch teacher data: %v", err)
+            return fmt.Errorf("failed to fetch teacher data: %w", err)
         }
 
         // Добавляем название курса в массив courses (если его еще нет)
@@ -63,14 +69,14 @@ func (r *CourseRepository) CreateCourse(course *models.Course) error {
                 SET courses = $1
                 WHERE id = $2
             `
-            _, err := r.DB.Exec(updateQuery, pq.Array(currentCourses), teacherID)
+            _, err := tx.Exec(updateQuery, pq.Array(currentCourses), teacherID)
             if err != nil {
-                return fmt.Errorf("failed to update teacher's courses: %v", err)
+                return fmt.Errorf("failed to update teacher's courses: %w", err)
             }
         }
     }
 
-    return nil
+    return tx.Commit()
 }
 
 // Вспомогательная функция для проверки наличия элемента в массиве
@@ -85,43 +91,79 @@ func contains(slice []string, item string) bool {
 
 
 
-// GetCourses возвращает все курсы
-func (r *CourseRepository) GetCourses() ([]models.Course, error) {
-    query := `SELECT id, name, description, teacher_id FROM courses`
-    rows, err := r.DB.Query(query)
-    if err != nil {
+// courseList поля сортировки и фильтры списка курсов
+var courseList = listSpec{
+    sorts: map[string]string{
+        "id":         "id",
+        "name":       "name",
+        "teacher_id": "teacher_id",
+    },
+    filters: map[string]listFilter{
+        "name":       prefixFilter("name"),
+        "teacher_id": intFilter("teacher_id = ?"),
+    },
+    id: "id",
+}
+
+const courseListQuery = `SELECT id, name, description, teacher_id FROM courses WHERE deleted_at IS NULL`
+
+func scanCourse(row rowScanner) (*models.Course, error) {
+    var course models.Course
+    var teacherID sql.NullInt64
+    if err := row.Scan(&course.ID, &course.Name, &course.Description, &teacherID); err != nil {
         return nil, err
     }
-    defer rows.Close()
+    if teacherID.Valid {
+        teacherIDValue := int(teacherID.Int64)
+        course.TeacherID = &teacherIDValue
+    }
+    return &course, nil
+}
 
-    var courses []models.Course
-    for rows.Next() {
-        var course models.Course
-        var teacherID sql.NullInt64
-        if err := rows.Scan(&course.ID, &course.Name, &course.Description, &teacherID); err != nil {
-            return nil, err
-        }
-        if teacherID.Valid {
-            teacherIDValue := int(teacherID.Int64)
-            course.TeacherID = &teacherIDValue
-        } else {
-            course.TeacherID = nil
+// ListCourses возвращает страницу курсов и их общее количество с учётом фильтров
+func (r *CourseRepository) ListCourses(query models.ListQuery) ([]models.Course, int, error) {
+    courses := []models.Course{}
+    total, err := queryList(r.DB, courseList, courseListQuery, query, func(rows *sql.Rows) error {
+        course, err := scanCourse(rows)
+        if err != nil {
+            return err
         }
-        courses = append(courses, course)
+        courses = append(courses, *course)
+        return nil
+    })
+    if err != nil {
+        return nil, 0, err
     }
-    return courses, nil
+    return courses, total, nil
+}
+
+// EachCourse передаёт fn курсы с учётом фильтров и сортировки по мере чтения из базы
+func (r *CourseRepository) EachCourse(query models.ListQuery, fn func(models.Course) error) error {
+    return eachList(r.DB, courseList, courseListQuery, query, func(rows *sql.Rows) error {
+        course, err := scanCourse(rows)
+        if err != nil {
+            return err
+        }
+        return fn(*course)
+    })
+}
+
+// GetCourses возвращает все курсы
+func (r *CourseRepository) GetCourses() ([]models.Course, error) {
+    courses, _, err := r.ListCourses(models.ListQuery{})
+    return courses, err
 }
 
 // GetCourseByID возвращает курс по ID
 func (r *CourseRepository) GetCourseByID(id int) (*models.Course, error) {
-    query := `SELECT id, name, description, teacher_id FROM courses WHERE id = $1`
+    query := `SELECT id, name, description, teacher_id FROM courses WHERE id = $1 AND deleted_at IS NULL`
     row := r.DB.QueryRow(query, id)
 
     var course models.Course
     var teacherID sql.NullInt64
     if err := row.Scan(&course.ID, &course.Name, &course.Description, &teacherID); err != nil {
         if errors.Is(err, sql.ErrNoRows) {
-            return nil, fmt.Errorf("course with id %d not found", id)
+            return nil, models.NotFound("course with id %d not found", id)
         }
         return nil, err
     }
@@ -134,44 +176,49 @@ func (r *CourseRepository) GetCourseByID(id int) (*models.Course, error) {
     return &course, nil
 }
 
-// UpdateCourse обновляет данные курса
-func (r *CourseRepository) UpdateCourse(id int, updates map[string]interface{}) (*models.Course, error) {
-    setClauses := []string{}
-    args := []interface{}{}
-    paramIndex := 1
-
-    for key, value := range updates {
-        switch key {
-        case "name":
-            setClauses = append(setClauses, fmt.Sprintf("name = $%d", paramIndex))
-            args = append(args, value)
-            paramIndex++
-        case "description":
-            setClauses = append(setClauses, fmt.Sprintf("description = $%d", paramIndex))
-            args = append(args, value)
-            paramIndex++
-        case "teacher_id":
-            setClauses = append(setClauses, fmt.Sprintf("teacher_id = $%d", paramIndex))
-            args = append(args, value)
-            paramIndex++
-        default:
-            return nil, fmt.Errorf("invalid field: %s", key)
+// UpdateCourse обновляет данные курса. При переименовании занятия и объявления группы переходят
+// на новое название по внешним ключам (ON UPDATE CASCADE), студенты группы и список курсов
+// преподавателя - в той же транзакции
+func (r *CourseRepository) UpdateCourse(id int, update models.CourseUpdate) (*models.Course, error) {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var oldName string
+    if update.Name != nil {
+        err := tx.QueryRow(`SELECT name FROM courses WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&oldName)
+        if err != nil {
+            if errors.Is(err, sql.ErrNoRows) {
+                return nil, models.NotFound("course with id %d not found", id)
+            }
+            return nil, err
         }
     }
 
-    if len(setClauses) == 0 {
-        return nil, fmt.Errorf("no fields to update")
+    var set updateSet
+    if update.Name != nil {
+        set.set("name = ?", *update.Name)
+    }
+    if update.Description != nil {
+        set.set("description = ?", *update.Description)
+    }
+    if update.TeacherID != nil {
+        set.set("teacher_id = ?", *update.TeacherID)
     }
 
-    query := fmt.Sprintf(`UPDATE courses SET %s WHERE id = $%d RETURNING id, name, description, teacher_id`, strings.Join(setClauses, ", "), paramIndex)
-    args = append(args, id)
+    query, args, err := set.query(`UPDATE courses SET %s WHERE id = $%d AND deleted_at IS NULL RETURNING id, name, description, teacher_id`, id)
+    if err != nil {
+        return nil, err
+    }
 
     var course models.Course
     var teacherID sql.NullInt64
-    err := r.DB.QueryRow(query, args...).Scan(&course.ID, &course.Name, &course.Description, &teacherID)
+    err = tx.QueryRow(query, args...).Scan(&course.ID, &course.Name, &course.Description, &teacherID)
     if err != nil {
         if errors.Is(err, sql.ErrNoRows) {
-            return nil, fmt.Errorf("course with id %d not found", id)
+            return nil, models.NotFound("course with id %d not found", id)
         }
         return nil, err
     }
@@ -181,86 +228,43 @@ func (r *CourseRepository) UpdateCourse(id int, updates map[string]interface{})
     } else {
         course.TeacherID = nil
     }
-    return &course, nil
-}
 
-// DeleteCourse удаляет курс по ID
-func (r *CourseRepository) DeleteCourse(id int) error {
-    // Получаем teacher_id курса перед удалением
-    var teacherID sql.NullInt64
-    var courseName string
-    query := `
-        SELECT teacher_id, name
-        FROM courses
-        WHERE id = $1
-    `
-    err := r.DB.QueryRow(query, id).Scan(&teacherID, &courseName)
-    if err != nil {
-        if errors.Is(err, sql.ErrNoRows) {
-            return fmt.Errorf("course with id %d not found", id)
+    if update.Name != nil && course.Name != oldName {
+        if _, err := tx.Exec(`UPDATE students SET group_name = $1 WHERE group_name = $2`, course.Name, oldName); err != nil {
+            return nil, fmt.Errorf("failed to update students: %w", err)
+        }
+        if _, err := tx.Exec(`UPDATE teachers SET courses = array_replace(courses, $2, $1) WHERE $2 = ANY(courses)`, course.Name, oldName); err != nil {
+            return nil, fmt.Errorf("failed to update teacher's courses: %w", err)
         }
-        return fmt.Errorf("failed to fetch course data: %v", err)
-    }
-
-    // Удаляем курс
-    deleteQuery := `
-        DELETE FROM courses
-        WHERE id = $1
-    `
-    result, err := r.DB.Exec(deleteQuery, id)
-    if err != nil {
-        return fmt.Errorf("failed to delete course: %v", err)
     }
 
-    rowsAffected, _ := result.RowsAffected()
-    if rowsAffected == 0 {
-        return fmt.Errorf("course with id %d not found", id)
+    if err := tx.Commit(); err != nil {
+        return nil, err
     }
+    return &course, nil
+}
 
-    // Если teacher_id указан, удаляем название курса из массива courses у преподавателя
-    if teacherID.Valid {
-        var currentCourses []string
-        err := r.DB.QueryRow(
-            "SELECT courses FROM teachers WHERE id = $1",
-            teacherID.Int64,
-        ).Scan(pq.Array(&currentCourses))
-        if err != nil {
-            if errors.Is(err, sql.ErrNoRows) {
-                return fmt.Errorf("teacher with id %d not found", teacherID.Int64)
-            }
-            return fmt.Errorf("failed to fetch teacher data: %v", err)
-        }
-
-        // Удаляем название курса из массива courses
-        newCourses := remove(currentCourses, courseName)
-        updateQuery := `
-            UPDATE teachers
-            SET courses = $1
-            WHERE id = $2
-        `
-        _, err = r.DB.Exec(updateQuery, pq.Array(newCourses), teacherID.Int64)
-        if err != nil {
-            return fmt.Errorf("failed to update teacher's courses: %v", err)
-        }
+// GetCourseDeletionImpact возвращает записи, которые будут удалены вместе с курсом
+func (r *CourseRepository) GetCourseDeletionImpact(id int) (models.Cascade, error) {
+    impact, err := deletionImpact(r.DB, "courses", id)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, models.NotFound("course with id %d not found", id)
     }
-
-    return nil
+    return impact, err
 }
 
-// Вспомогательная функция для удаления элемента из массива
-func remove(slice []string, item string) []string {
-    result := []string{}
-    for _, v := range slice {
-        if v != item {
-            result = append(result, v)
-        }
+// DeleteCourse помещает курс и занятия группы в корзину и убирает курс из списка курсов преподавателя
+func (r *CourseRepository) DeleteCourse(id int) (models.Cascade, error) {
+    cascade, err := softDelete(r.DB, "courses", id)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, models.NotFound("course with id %d not found", id)
     }
-    return result
+    return cascade, err
 }
 
 // GetCoursesByTeacherID возвращает курсы, связанные с преподавателем
 func (r *CourseRepository) GetCoursesByTeacherID(teacherID int) ([]models.Course, error) {
-    query := `SELECT id, name, description, teacher_id FROM courses WHERE teacher_id = $1`
+    query := `SELECT id, name, description, teacher_id FROM courses WHERE teacher_id = $1 AND deleted_at IS NULL`
     rows, err := r.DB.Query(query, teacherID)
     if err != nil {
         return nil, err
@@ -285,3 +289,40 @@ func (r *CourseRepository) GetCoursesByTeacherID(teacherID int) ([]models.Course
     return courses, nil
 }
 
+
+// GetStudentCourses возвращает курсы студента: курс его группы и курсы, по которым он аттестовывался
+func (r *CourseRepository) GetStudentCourses(studentID int) ([]models.Course, error) {
+    query := `
+        SELECT c.id, c.name, c.description, c.teacher_id
+        FROM courses c
+        WHERE c.deleted_at IS NULL AND (
+            c.name = (SELECT group_name FROM students WHERE id = $1)
+            OR c.id IN (
+                SELECT gs.course_id FROM grade_sheets gs
+                JOIN grade_sheet_entries e ON e.grade_sheet_id = gs.id
+                WHERE e.student_id = $1
+            )
+        )
+        ORDER BY c.name
+    `
+    rows, err := r.DB.Query(query, studentID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    courses := []models.Course{}
+    for rows.Next() {
+        var course models.Course
+        var teacherID sql.NullInt64
+        if err := rows.Scan(&course.ID, &course.Name, &course.Description, &teacherID); err != nil {
+            return nil, err
+        }
+        if teacherID.Valid {
+            teacherIDValue := int(teacherID.Int64)
+            course.TeacherID = &teacherIDValue
+        }
+        courses = append(courses, course)
+    }
+    return courses, rows.Err()
+}
\ No newline at end of file
@@ -0,0 +1,374 @@
+package repositories
+
+import (
+    "backend/models"
+    "database/sql"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/lib/pq"
+)
+
+type GradeSheetRepository struct {
+    DB DBTX
+}
+
+func NewGradeSheetRepository(db DBTX) *GradeSheetRepository {
+    return &GradeSheetRepository{DB: db}
+}
+
+const gradeSheetSelect = `
+    SELECT g.id, g.number, g.course_id, c.name, g.teacher_id, COALESCE(t.name, ''), g.control_type, g.status,
+           g.parent_id, g.exam_date, g.created_at, g.issued_at, g.closed_at, g.closed_by
+    FROM grade_sheets g
+    JOIN courses c ON g.course_id = c.id
+    LEFT JOIN teachers t ON g.teacher_id = t.id
+`
+
+type rowScanner interface {
+    Scan(dest ...interface{}) error
+}
+
+func scanGradeSheet(row rowScanner) (*models.GradeSheet, error) {
+    var sheet models.GradeSheet
+    var teacherID, parentID, closedBy sql.NullInt64
+    var examDate sql.NullTime
+    var issuedAt, closedAt sql.NullTime
+    if err := row.Scan(&sheet.ID, &sheet.Number, &sheet.CourseID, &sheet.CourseName, &teacherID, &sheet.TeacherName,
+        &sheet.ControlType, &sheet.Status, &parentID, &examDate, &sheet.CreatedAt, &issuedAt, &closedAt, &closedBy); err != nil {
+        return nil, err
+    }
+
+    sheet.GroupName = sheet.CourseName
+    if teacherID.Valid {
+        value := int(teacherID.Int64)
+        sheet.TeacherID = &value
+    }
+    if parentID.Valid {
+        value := int(parentID.Int64)
+        sheet.ParentID = &value
+    }
+    if closedBy.Valid {
+        value := int(closedBy.Int64)
+        sheet.ClosedBy = &value
+    }
+    if examDate.Valid {
+        sheet.ExamDate = examDate.Time.Format("2006-01-02")
+    }
+    if issuedAt.Valid {
+        sheet.IssuedAt = &issuedAt.Time
+    }
+    if closedAt.Valid {
+        sheet.ClosedAt = &closedAt.Time
+    }
+    return &sheet, nil
+}
+
+// CreateGradeSheet создает ведомость и заполняет её студентами группы курса
+func (r *GradeSheetRepository) CreateGradeSheet(sheet *models.GradeSheet) error {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var examDate interface{}
+    if sheet.ExamDate != "" {
+        parsedDate, err := time.Parse("2006-01-02", sheet.ExamDate)
+        if err != nil {
+            return models.Invalid("invalid exam_date format: %v", err)
+        }
+        examDate = parsedDate
+    }
+
+    query := `
+        INSERT INTO grade_sheets (number, course_id, teacher_id, control_type, status, parent_id, exam_date)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id
+    `
+    err = tx.QueryRow(query, sheet.Number, sheet.CourseID, sheet.TeacherID, sheet.ControlType, models.GradeSheetDraft, sheet.ParentID, examDate).Scan(&sheet.ID)
+    if err != nil {
+        return fmt.Errorf("failed to create grade sheet: %w", err)
+    }
+
+    if sheet.ParentID != nil {
+        // Пересдача: переносим только студентов с неудовлетворительными отметками
+        _, err = tx.Exec(`
+            INSERT INTO grade_sheet_entries (grade_sheet_id, student_id)
+            SELECT $1, e.student_id
+            FROM grade_sheet_entries e
+            WHERE e.grade_sheet_id = $2 AND e.mark IN ($3, $4, $5)
+        `, sheet.ID, *sheet.ParentID, models.MarkFail, models.MarkNoPass, models.MarkAbsent)
+    } else {
+        // Группа студента совпадает с названием курса, в ведомость попадают только обучающиеся
+        _, err = tx.Exec(`
+            INSERT INTO grade_sheet_entries (grade_sheet_id, student_id)
+            SELECT $1, s.id
+            FROM students s
+            JOIN courses c ON s.group_name = c.name
+            WHERE c.id = $2 AND s.status = $3 AND s.deleted_at IS NULL
+        `, sheet.ID, sheet.CourseID, models.StudentActive)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to fill grade sheet: %w", err)
+    }
+
+    return tx.Commit()
+}
+
+// GetGradeSheets возвращает ведомости с необязательными фильтрами по курсу и статусу
+func (r *GradeSheetRepository) GetGradeSheets(courseID int, status string) ([]models.GradeSheet, error) {
+    query := gradeSheetSelect + ` WHERE 1=1`
+    args := []interface{}{}
+    paramIndex := 1
+
+    if courseID != 0 {
+        query += fmt.Sprintf(" AND g.course_id = $%d", paramIndex)
+        args = append(args, courseID)
+        paramIndex++
+    }
+
+    if status != "" {
+        query += fmt.Sprintf(" AND g.status = $%d", paramIndex)
+        args = append(args, status)
+        paramIndex++
+    }
+
+    query += " ORDER BY g.id"
+
+    rows, err := r.DB.Query(query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    sheets := []models.GradeSheet{}
+    for rows.Next() {
+        sheet, err := scanGradeSheet(rows)
+        if err != nil {
+            return nil, err
+        }
+        sheets = append(sheets, *sheet)
+    }
+    return sheets, rows.Err()
+}
+
+// GetGradeSheetByID возвращает ведомость вместе со строками
+func (r *GradeSheetRepository) GetGradeSheetByID(id int) (*models.GradeSheet, error) {
+    sheet, err := scanGradeSheet(r.DB.QueryRow(gradeSheetSelect+` WHERE g.id = $1`, id))
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, models.NotFound("grade sheet with id %d not found", id)
+        }
+        return nil, err
+    }
+
+    rows, err := r.DB.Query(`
+        SELECT e.id, e.student_id, s.name, e.mark
+        FROM grade_sheet_entries e
+        JOIN students s ON e.student_id = s.id
+        WHERE e.grade_sheet_id = $1
+        ORDER BY s.name
+    `, id)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    sheet.Entries = []models.GradeSheetEntry{}
+    for rows.Next() {
+        var entry models.GradeSheetEntry
+        var mark sql.NullString
+        if err := rows.Scan(&entry.ID, &entry.StudentID, &entry.StudentName, &mark); err != nil {
+            return nil, err
+        }
+        if mark.Valid {
+            entry.Mark = &mark.String
+        }
+        sheet.Entries = append(sheet.Entries, entry)
+    }
+    return sheet, rows.Err()
+}
+
+// GetOpenRetakeID возвращает выданную или заполненную пересдачу ведомости parentID, в которой есть
+// студенты этой ведомости; 0 - такой нет
+func (r *GradeSheetRepository) GetOpenRetakeID(parentID int) (int, error) {
+    var id int
+    err := r.DB.QueryRow(`
+        SELECT g.id
+        FROM grade_sheets g
+        JOIN grade_sheet_entries e ON e.grade_sheet_id = g.id
+        JOIN grade_sheet_entries p ON p.student_id = e.student_id AND p.grade_sheet_id = g.parent_id
+        WHERE g.parent_id = $1 AND g.status IN ($2, $3)
+        ORDER BY g.id
+        LIMIT 1
+    `, parentID, models.GradeSheetIssued, models.GradeSheetFilled).Scan(&id)
+    if errors.Is(err, sql.ErrNoRows) {
+        return 0, nil
+    }
+    return id, err
+}
+
+// UpdateStatus переводит ведомость из одного статуса в другой
+func (r *GradeSheetRepository) UpdateStatus(id int, from, to string) error {
+    query := `UPDATE grade_sheets SET status = $1 WHERE id = $2 AND status = $3`
+    if to == models.GradeSheetIssued {
+        query = `UPDATE grade_sheets SET status = $1, issued_at = CURRENT_TIMESTAMP WHERE id = $2 AND status = $3`
+    }
+
+    result, err := r.DB.Exec(query, to, id, from)
+    if err != nil {
+        // Другую пересдачу той же ведомости выдали после проверки (grade_sheets_open_retake_key)
+        var pqErr *pq.Error
+        if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+            return models.Conflict("another retake of the same grade sheet is already issued")
+        }
+        return err
+    }
+
+    rowsAffected, _ := result.RowsAffected()
+    if rowsAffected == 0 {
+        return models.Conflict("grade sheet with id %d is not in status '%s'", id, from)
+    }
+    return nil
+}
+
+// SetMarks выставляет оценки и переводит ведомость в статус filled, когда заполнены все строки
+func (r *GradeSheetRepository) SetMarks(id int, marks map[int]string) error {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    // Блокируем ведомость, чтобы её не закрыли во время заполнения
+    var status string
+    err = tx.QueryRow(`SELECT status FROM grade_sheets WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return models.NotFound("grade sheet with id %d not found", id)
+        }
+        return err
+    }
+    if status != models.GradeSheetIssued && status != models.GradeSheetFilled {
+        return models.Conflict("grade sheet in status '%s' cannot be filled", status)
+    }
+
+    for studentID, mark := range marks {
+        result, err := tx.Exec(`UPDATE grade_sheet_entries SET mark = $1 WHERE grade_sheet_id = $2 AND student_id = $3`, mark, id, studentID)
+        if err != nil {
+            return err
+        }
+        rowsAffected, _ := result.RowsAffected()
+        if rowsAffected == 0 {
+            return models.Invalid("student with id %d is not listed in grade sheet %d", studentID, id)
+        }
+    }
+
+    var missing int
+    if err := tx.QueryRow(`SELECT COUNT(*) FROM grade_sheet_entries WHERE grade_sheet_id = $1 AND mark IS NULL`, id).Scan(&missing); err != nil {
+        return err
+    }
+
+    newStatus := models.GradeSheetIssued
+    if missing == 0 {
+        newStatus = models.GradeSheetFilled
+    }
+    if _, err := tx.Exec(`UPDATE grade_sheets SET status = $1 WHERE id = $2`, newStatus, id); err != nil {
+        return err
+    }
+
+    return tx.Commit()
+}
+
+// CloseGradeSheet закрывает заполненную ведомость и переносит оценки в зачётную книжку
+func (r *GradeSheetRepository) CloseGradeSheet(id, userID int) error {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var status string
+    var courseID int
+    err = tx.QueryRow(`SELECT status, course_id FROM grade_sheets WHERE id = $1 FOR UPDATE`, id).Scan(&status, &courseID)
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return models.NotFound("grade sheet with id %d not found", id)
+        }
+        return err
+    }
+    if status != models.GradeSheetFilled {
+        return models.Conflict("grade sheet in status '%s' cannot be closed", status)
+    }
+
+    // Пересдача перезаписывает предыдущую итоговую оценку
+    _, err = tx.Exec(`
+        INSERT INTO grades (student_id, course_id, grade_sheet_id, mark)
+        SELECT e.student_id, $2, $1, e.mark
+        FROM grade_sheet_entries e
+        WHERE e.grade_sheet_id = $1
+        ON CONFLICT (student_id, course_id)
+        DO UPDATE SET mark = EXCLUDED.mark, grade_sheet_id = EXCLUDED.grade_sheet_id, graded_at = CURRENT_TIMESTAMP
+    `, id, courseID)
+    if err != nil {
+        return fmt.Errorf("failed to write grades: %w", err)
+    }
+
+    _, err = tx.Exec(`
+        UPDATE grade_sheets
+        SET status = $1, closed_at = CURRENT_TIMESTAMP, closed_by = $2
+        WHERE id = $3
+    `, models.GradeSheetClosed, userID, id)
+    if err != nil {
+        return err
+    }
+
+    return tx.Commit()
+}
+
+// DeleteGradeSheet удаляет ведомость, пока она в черновике
+func (r *GradeSheetRepository) DeleteGradeSheet(id int) error {
+    result, err := r.DB.Exec(`DELETE FROM grade_sheets WHERE id = $1 AND status = $2`, id, models.GradeSheetDraft)
+    if err != nil {
+        return err
+    }
+
+    rowsAffected, _ := result.RowsAffected()
+    if rowsAffected == 0 {
+        return models.NotFound("grade sheet with id %d not found or is not a draft", id)
+    }
+    return nil
+}
+
+// GetStudentGrades возвращает итоговые оценки студента
+func (r *GradeSheetRepository) GetStudentGrades(studentID int) ([]models.Grade, error) {
+    query := `
+        SELECT g.id, g.student_id, g.course_id, c.name, g.grade_sheet_id, g.mark, g.graded_at
+        FROM grades g
+        JOIN courses c ON g.course_id = c.id
+        WHERE g.student_id = $1
+        ORDER BY c.name
+    `
+    rows, err := r.DB.Query(query, studentID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    grades := []models.Grade{}
+    for rows.Next() {
+        var grade models.Grade
+        var sheetID sql.NullInt64
+        if err := rows.Scan(&grade.ID, &grade.StudentID, &grade.CourseID, &grade.CourseName, &sheetID, &grade.Mark, &grade.GradedAt); err != nil {
+            return nil, err
+        }
+        if sheetID.Valid {
+            value := int(sheetID.Int64)
+            grade.GradeSheetID = &value
+        }
+        grades = append(grades, grade)
+    }
+    return grades, rows.Err()
+}
\ No newline at end of file
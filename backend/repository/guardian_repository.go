@@ -0,0 +1,273 @@
+package repositories
+
+import (
+    "backend/models"
+    "database/sql"
+    "errors"
+    "fmt"
+
+    "github.com/lib/pq"
+)
+
+type GuardianRepository struct {
+    DB DBTX
+}
+
+func NewGuardianRepository(db DBTX) *GuardianRepository {
+    return &GuardianRepository{DB: db}
+}
+
+const guardianSelect = `
+    SELECT id, student_id, name, phone, email, relation, user_id, notify_absence, created_at
+    FROM guardians
+`
+
+func scanGuardian(row rowScanner) (*models.Guardian, error) {
+    var guardian models.Guardian
+    var userID sql.NullInt64
+    err := row.Scan(&guardian.ID, &guardian.StudentID, &guardian.Name, &guardian.Phone, &guardian.Email,
+        &guardian.Relation, &userID, &guardian.NotifyAbsence, &guardian.CreatedAt)
+    if err != nil {
+        return nil, err
+    }
+    if userID.Valid {
+        value := int(userID.Int64)
+        guardian.UserID = &value
+    }
+    return &guardian, nil
+}
+
+func (r *GuardianRepository) CreateGuardian(guardian *models.Guardian) error {
+    var exists bool
+    err := r.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM students WHERE id = $1 AND deleted_at IS NULL)`, guardian.StudentID).Scan(&exists)
+    if err != nil {
+        return err
+    }
+    if !exists {
+        return models.NotFound("student with id %d not found", guardian.StudentID)
+    }
+
+    query := `
+        INSERT INTO guardians (student_id, name, phone, email, relation, notify_absence)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at
+    `
+    err = r.DB.QueryRow(query, guardian.StudentID, guardian.Name, guardian.Phone, guardian.Email, guardian.Relation,
+        guardian.NotifyAbsence).Scan(&guardian.ID, &guardian.CreatedAt)
+    if err != nil {
+        return fmt.Errorf("failed to create guardian: %w", err)
+    }
+    return nil
+}
+
+func (r *GuardianRepository) GetGuardianByID(id int) (*models.Guardian, error) {
+    guardian, err := scanGuardian(r.DB.QueryRow(guardianSelect+` WHERE id = $1`, id))
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, models.NotFound("guardian with id %d not found", id)
+        }
+        return nil, err
+    }
+    return guardian, nil
+}
+
+// GetStudentGuardians возвращает представителей студента
+func (r *GuardianRepository) GetStudentGuardians(studentID int) ([]models.Guardian, error) {
+    rows, err := r.DB.Query(guardianSelect+` WHERE student_id = $1 ORDER BY id`, studentID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    guardians := []models.Guardian{}
+    for rows.Next() {
+        guardian, err := scanGuardian(rows)
+        if err != nil {
+            return nil, err
+        }
+        guardians = append(guardians, *guardian)
+    }
+    return guardians, rows.Err()
+}
+
+func (r *GuardianRepository) UpdateGuardian(guardian *models.Guardian) error {
+    query := `
+        UPDATE guardians
+        SET name = $1, phone = $2, email = $3, relation = $4, notify_absence = $5
+        WHERE id = $6
+    `
+    result, err := r.DB.Exec(query, guardian.Name, guardian.Phone, guardian.Email, guardian.Relation,
+        guardian.NotifyAbsence, guardian.ID)
+    if err != nil {
+        return fmt.Errorf("failed to update guardian: %w", err)
+    }
+    rowsAffected, _ := result.RowsAffected()
+    if rowsAffected == 0 {
+        return models.NotFound("guardian with id %d not found", guardian.ID)
+    }
+    return nil
+}
+
+func (r *GuardianRepository) DeleteGuardian(id int) error {
+    result, err := r.DB.Exec(`DELETE FROM guardians WHERE id = $1`, id)
+    if err != nil {
+        return err
+    }
+    rowsAffected, _ := result.RowsAffected()
+    if rowsAffected == 0 {
+        return models.NotFound("guardian with id %d not found", id)
+    }
+    return nil
+}
+
+// CreateAccount создает учётную запись представителя и связывает её с контактом
+func (r *GuardianRepository) CreateAccount(guardianID int, user *models.User) error {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var userID sql.NullInt64
+    err = tx.QueryRow(`SELECT user_id FROM guardians WHERE id = $1 FOR UPDATE`, guardianID).Scan(&userID)
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return models.NotFound("guardian with id %d not found", guardianID)
+        }
+        return err
+    }
+    if userID.Valid {
+        return models.Conflict("guardian already has an account")
+    }
+
+    var usernameTaken bool
+    if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`, user.Username).Scan(&usernameTaken); err != nil {
+        return err
+    }
+    if usernameTaken {
+        return models.Conflict("username '%s' already taken", user.Username)
+    }
+
+    err = tx.QueryRow(`
+        INSERT INTO users (username, password_hash, role)
+        VALUES ($1, $2, $3)
+        RETURNING id
+    `, user.Username, user.PasswordHash, user.Role).Scan(&user.ID)
+    if err != nil {
+        return fmt.Errorf("failed to create user: %w", err)
+    }
+
+    if _, err := tx.Exec(`UPDATE guardians SET user_id = $1 WHERE id = $2`, user.ID, guardianID); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+// LinkAccount связывает контакт с существующей учётной записью с ролью guardian
+func (r *GuardianRepository) LinkAccount(guardianID, userID int) (*models.Guardian, error) {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var linked sql.NullInt64
+    err = tx.QueryRow(`SELECT user_id FROM guardians WHERE id = $1 FOR UPDATE`, guardianID).Scan(&linked)
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, models.NotFound("guardian with id %d not found", guardianID)
+        }
+        return nil, err
+    }
+    if linked.Valid && int(linked.Int64) != userID {
+        return nil, models.Conflict("guardian already has an account")
+    }
+
+    var role string
+    err = tx.QueryRow(`SELECT role FROM users WHERE id = $1 FOR SHARE`, userID).Scan(&role)
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, models.NotFound("user with id %d not found", userID)
+        }
+        return nil, err
+    }
+    if role != "guardian" {
+        return nil, models.Invalid("user with id %d is not a guardian account", userID)
+    }
+
+    if _, err := tx.Exec(`UPDATE guardians SET user_id = $1 WHERE id = $2`, userID, guardianID); err != nil {
+        return nil, err
+    }
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return r.GetGuardianByID(guardianID)
+}
+
+// GetLinkedStudents возвращает студентов, связанных с учётной записью представителя
+func (r *GuardianRepository) GetLinkedStudents(userID int) ([]models.Student, error) {
+    query := `
+        SELECT DISTINCT s.id, s.name, s.date_of_birth, s.group_name, s.status
+        FROM guardians g
+        JOIN students s ON g.student_id = s.id
+        WHERE g.user_id = $1 AND s.deleted_at IS NULL
+        ORDER BY s.name
+    `
+    rows, err := r.DB.Query(query, userID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    students := []models.Student{}
+    for rows.Next() {
+        var student models.Student
+        var dateOfBirth sql.NullTime
+        if err := rows.Scan(&student.ID, &student.Name, &dateOfBirth, &student.GroupName, &student.Status); err != nil {
+            return nil, err
+        }
+        if dateOfBirth.Valid {
+            student.DateOfBirth = dateOfBirth.Time.Format("2006-01-02")
+        }
+        students = append(students, student)
+    }
+    return students, rows.Err()
+}
+
+// IsLinked проверяет, что студент связан с учётной записью представителя
+func (r *GuardianRepository) IsLinked(userID, studentID int) (bool, error) {
+    var linked bool
+    err := r.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM guardians WHERE user_id = $1 AND student_id = $2)`, userID, studentID).Scan(&linked)
+    return linked, err
+}
+
+// GetAbsenceContacts возвращает адресатов уведомлений о пропусках для студентов
+func (r *GuardianRepository) GetAbsenceContacts(studentIDs []int) ([]models.AbsenceContact, error) {
+    ids := make([]int64, len(studentIDs))
+    for i, id := range studentIDs {
+        ids[i] = int64(id)
+    }
+
+    query := `
+        SELECT s.id, s.name, s.date_of_birth, g.name, g.email
+        FROM guardians g
+        JOIN students s ON g.student_id = s.id
+        WHERE g.student_id = ANY($1) AND g.notify_absence AND g.email <> '' AND s.deleted_at IS NULL
+        ORDER BY s.id, g.id
+    `
+    rows, err := r.DB.Query(query, pq.Array(ids))
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    contacts := []models.AbsenceContact{}
+    for rows.Next() {
+        var contact models.AbsenceContact
+        if err := rows.Scan(&contact.StudentID, &contact.StudentName, &contact.DateOfBirth, &contact.GuardianName, &contact.Email); err != nil {
+            return nil, err
+        }
+        contacts = append(contacts, contact)
+    }
+    return contacts, rows.Err()
+}
\ No newline at end of file
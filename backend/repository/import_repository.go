@@ -0,0 +1,238 @@
+package repositories
+
+import (
+    "backend/models"
+    "database/sql"
+    "errors"
+    "fmt"
+
+    "github.com/lib/pq"
+)
+
+type ImportRepository struct {
+    DB DBTX
+}
+
+func NewImportRepository(db DBTX) *ImportRepository {
+    return &ImportRepository{DB: db}
+}
+
+// ImportLookup справочники для проверки строк импорта без запроса на каждую строку
+type ImportLookup struct {
+    CourseNames    map[string]bool
+    TeacherIDs     map[int]bool
+    StudentKeys    map[string]bool // ФИО + дата рождения
+    TeacherKeys    map[string]bool // ФИО + предмет
+    ClassroomNames map[string]bool
+}
+
+// StudentKey ключ для поиска дубликатов студентов
+func StudentKey(name, dateOfBirth string) string {
+    return name + "|" + dateOfBirth
+}
+
+// TeacherKey ключ для поиска дубликатов преподавателей
+func TeacherKey(name, subject string) string {
+    return name + "|" + subject
+}
+
+// LoadImportLookup загружает справочники без записей из корзины: название курса или аудитории в корзине
+// хранится с суффиксом корзины и не мешает занять его снова, поэтому dry-run и сохранение
+// дают одинаковый результат
+func (r *ImportRepository) LoadImportLookup() (*ImportLookup, error) {
+    lookup := &ImportLookup{
+        CourseNames:    map[string]bool{},
+        TeacherIDs:     map[int]bool{},
+        StudentKeys:    map[string]bool{},
+        TeacherKeys:    map[string]bool{},
+        ClassroomNames: map[string]bool{},
+    }
+
+    err := r.scan(`SELECT name FROM courses WHERE deleted_at IS NULL`, func(rows *sql.Rows) error {
+        var name string
+        if err := rows.Scan(&name); err != nil {
+            return err
+        }
+        lookup.CourseNames[name] = true
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    err = r.scan(`SELECT id, name, subject FROM teachers WHERE deleted_at IS NULL`, func(rows *sql.Rows) error {
+        var id int
+        var name, subject string
+        if err := rows.Scan(&id, &name, &subject); err != nil {
+            return err
+        }
+        lookup.TeacherIDs[id] = true
+        lookup.TeacherKeys[TeacherKey(name, subject)] = true
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    err = r.scan(`SELECT name, to_char(date_of_birth, 'YYYY-MM-DD') FROM students WHERE deleted_at IS NULL`, func(rows *sql.Rows) error {
+        var name, dateOfBirth string
+        if err := rows.Scan(&name, &dateOfBirth); err != nil {
+            return err
+        }
+        lookup.StudentKeys[StudentKey(name, dateOfBirth)] = true
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    err = r.scan(`SELECT name FROM classrooms WHERE deleted_at IS NULL`, func(rows *sql.Rows) error {
+        var name string
+        if err := rows.Scan(&name); err != nil {
+            return err
+        }
+        lookup.ClassroomNames[name] = true
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return lookup, nil
+}
+
+func (r *ImportRepository) scan(query string, fn func(rows *sql.Rows) error) error {
+    rows, err := r.DB.Query(query)
+    if err != nil {
+        return err
+    }
+    defer rows.Close()
+    for rows.Next() {
+        if err := fn(rows); err != nil {
+            return err
+        }
+    }
+    return rows.Err()
+}
+
+// importInTx выполняет вставку всех записей в одной транзакции: либо сохраняются все, либо ни одной
+func (r *ImportRepository) importInTx(query string, count int, args func(i int) []interface{}, after func(tx *sql.Tx, i int, id int) error) ([]int, error) {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    stmt, err := tx.Prepare(query)
+    if err != nil {
+        return nil, err
+    }
+    defer stmt.Close()
+
+    ids := make([]int, count)
+    for i := 0; i < count; i++ {
+        if err := stmt.QueryRow(args(i)...).Scan(&ids[i]); err != nil {
+            // Запись с тем же названием могли создать после проверки файла
+            var pqErr *pq.Error
+            if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+                return nil, models.Conflict("record %d already exists, it was created after the file was checked", i+1)
+            }
+            return nil, fmt.Errorf("failed to import record %d: %w", i+1, err)
+        }
+        if after != nil {
+            if err := after(tx.Tx, i, ids[i]); err != nil {
+                return nil, err
+            }
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return ids, nil
+}
+
+// ImportStudents сохраняет студентов одной транзакцией
+func (r *ImportRepository) ImportStudents(students []models.Student) error {
+    ids, err := r.importInTx(`
+        INSERT INTO students (name, date_of_birth, group_name)
+        VALUES ($1, $2, $3)
+        RETURNING id
+    `, len(students), func(i int) []interface{} {
+        return []interface{}{students[i].Name, students[i].DateOfBirth, students[i].GroupName}
+    }, nil)
+    if err != nil {
+        return err
+    }
+    for i := range students {
+        students[i].ID = ids[i]
+        students[i].Status = models.StudentActive
+    }
+    return nil
+}
+
+// ImportTeachers сохраняет преподавателей одной транзакцией
+func (r *ImportRepository) ImportTeachers(teachers []models.Teacher) error {
+    ids, err := r.importInTx(`
+        INSERT INTO teachers (name, subject, courses, working_hours, hours_budget)
+        VALUES ($1, $2, $3, $4, $4)
+        RETURNING id
+    `, len(teachers), func(i int) []interface{} {
+        return []interface{}{teachers[i].Name, teachers[i].Subject, pq.Array(teachers[i].Courses), teachers[i].WorkingHours}
+    }, nil)
+    if err != nil {
+        return err
+    }
+    for i := range teachers {
+        teachers[i].ID = ids[i]
+    }
+    return nil
+}
+
+// ImportCourses сохраняет курсы одной транзакцией и добавляет их в список курсов преподавателей
+func (r *ImportRepository) ImportCourses(courses []models.Course) error {
+    ids, err := r.importInTx(`
+        INSERT INTO courses (name, description, teacher_id)
+        VALUES ($1, $2, $3)
+        RETURNING id
+    `, len(courses), func(i int) []interface{} {
+        return []interface{}{courses[i].Name, courses[i].Description, courses[i].TeacherID}
+    }, func(tx *sql.Tx, i int, id int) error {
+        if courses[i].TeacherID == nil {
+            return nil
+        }
+        _, err := tx.Exec(`
+            UPDATE teachers SET courses = array_append(courses, $1::text)
+            WHERE id = $2 AND NOT ($1 = ANY(courses))
+        `, courses[i].Name, *courses[i].TeacherID)
+        if err != nil {
+            return fmt.Errorf("failed to update teacher's courses: %w", err)
+        }
+        return nil
+    })
+    if err != nil {
+        return err
+    }
+    for i := range courses {
+        courses[i].ID = ids[i]
+    }
+    return nil
+}
+
+// ImportClassrooms сохраняет аудитории одной транзакцией
+func (r *ImportRepository) ImportClassrooms(classrooms []models.Classroom) error {
+    ids, err := r.importInTx(`
+        INSERT INTO classrooms (name, capacity, description)
+        VALUES ($1, $2, $3)
+        RETURNING id
+    `, len(classrooms), func(i int) []interface{} {
+        return []interface{}{classrooms[i].Name, classrooms[i].Capacity, classrooms[i].Description}
+    }, nil)
+    if err != nil {
+        return err
+    }
+    for i := range classrooms {
+        classrooms[i].ID = ids[i]
+    }
+    return nil
+}
\ No newline at end of file
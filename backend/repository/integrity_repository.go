@@ -0,0 +1,153 @@
+package repositories
+
+import (
+    "backend/models"
+    "database/sql"
+    "fmt"
+)
+
+// integrityCheck запрос, возвращающий (id, details) для каждой проблемной записи
+type integrityCheck struct {
+    Name       string
+    EntityType string
+    Query      string
+}
+
+var integrityChecks = []integrityCheck{
+    {
+        Name:       "student_group_missing",
+        EntityType: "students",
+        Query: `
+            SELECT s.id, 'group ''' || s.group_name || ''' does not exist or is deleted'
+            FROM students s
+            WHERE s.deleted_at IS NULL AND s.status = 'active'
+              AND NOT EXISTS (SELECT 1 FROM courses c WHERE c.name = s.group_name AND c.deleted_at IS NULL)
+        `,
+    },
+    {
+        Name:       "teacher_course_missing",
+        EntityType: "teachers",
+        Query: `
+            SELECT t.id, 'course ''' || tc.name || ''' in teacher courses does not exist or is deleted'
+            FROM teachers t, unnest(t.courses) AS tc(name)
+            WHERE t.deleted_at IS NULL
+              AND NOT EXISTS (SELECT 1 FROM courses c WHERE c.name = tc.name AND c.deleted_at IS NULL)
+        `,
+    },
+    {
+        Name:       "course_not_in_teacher_courses",
+        EntityType: "courses",
+        Query: `
+            SELECT c.id, 'course is assigned to teacher ' || t.id || ' but missing from teacher courses'
+            FROM courses c
+            JOIN teachers t ON c.teacher_id = t.id
+            WHERE c.deleted_at IS NULL AND t.deleted_at IS NULL AND NOT (c.name = ANY(t.courses))
+        `,
+    },
+    {
+        Name:       "schedule_deleted_reference",
+        EntityType: "schedules",
+        Query: `
+            SELECT s.id, 'references deleted ' ||
+                CASE WHEN t.deleted_at IS NOT NULL THEN 'teacher'
+                     WHEN cl.deleted_at IS NOT NULL THEN 'classroom'
+                     ELSE 'course' END
+            FROM schedules s
+            JOIN teachers t ON s.teacher_id = t.id
+            JOIN classrooms cl ON s.classroom_id = cl.id
+            JOIN courses c ON s.group_name = c.name
+            WHERE s.deleted_at IS NULL
+              AND (t.deleted_at IS NOT NULL OR cl.deleted_at IS NOT NULL OR c.deleted_at IS NOT NULL)
+        `,
+    },
+    {
+        Name:       "teacher_double_booked",
+        EntityType: "schedules",
+        Query: `
+            SELECT a.id, 'overlaps schedule ' || b.id || ' of the same teacher on ' || a.day_of_week
+            FROM schedules a
+            JOIN schedules b ON a.teacher_id = b.teacher_id AND a.id < b.id
+            WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+              AND ` + scheduleOverlap("a", "b") + `
+        `,
+    },
+    {
+        Name:       "classroom_double_booked",
+        EntityType: "schedules",
+        Query: `
+            SELECT a.id, 'overlaps schedule ' || b.id || ' in the same classroom on ' || a.day_of_week
+            FROM schedules a
+            JOIN schedules b ON a.classroom_id = b.classroom_id AND a.id < b.id
+            WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+              AND ` + scheduleOverlap("a", "b") + `
+        `,
+    },
+    {
+        Name:       "working_hours_drift",
+        EntityType: "teachers",
+        Query: `
+            SELECT t.id, 'working_hours ' || round(t.working_hours::numeric, 2) || ' does not match schedule (expected ' ||
+                round((t.hours_budget - COALESCE(sch.hours, 0))::numeric, 2) || '), run recalc-hours'
+            FROM teachers t
+            LEFT JOIN (
+                SELECT teacher_id, SUM(EXTRACT(EPOCH FROM (end_time - start_time)) / 3600) AS hours
+                FROM schedules WHERE deleted_at IS NULL GROUP BY teacher_id
+            ) sch ON sch.teacher_id = t.id
+            WHERE t.deleted_at IS NULL AND abs(t.working_hours - (t.hours_budget - COALESCE(sch.hours, 0))) > 1.0 / 60
+        `,
+    },
+    {
+        Name:       "user_linked_to_deleted",
+        EntityType: "users",
+        Query: `
+            SELECT u.id, 'linked to deleted ' || CASE WHEN t.id IS NOT NULL THEN 'teacher ' || t.id ELSE 'student ' || s.id END
+            FROM users u
+            LEFT JOIN teachers t ON u.teacher_id = t.id AND t.deleted_at IS NOT NULL
+            LEFT JOIN students s ON u.student_id = s.id AND s.deleted_at IS NOT NULL
+            WHERE t.id IS NOT NULL OR s.id IS NOT NULL
+        `,
+    },
+    {
+        Name:       "closed_sheet_without_marks",
+        EntityType: "grade_sheets",
+        Query: `
+            SELECT gs.id, COUNT(*) || ' entries without mark in a closed sheet'
+            FROM grade_sheets gs
+            JOIN grade_sheet_entries e ON e.grade_sheet_id = gs.id
+            WHERE gs.status = 'closed' AND e.mark IS NULL
+            GROUP BY gs.id
+        `,
+    },
+}
+
+type IntegrityRepository struct {
+    DB *sql.DB
+}
+
+func NewIntegrityRepository(db *sql.DB) *IntegrityRepository {
+    return &IntegrityRepository{DB: db}
+}
+
+// CheckIntegrity выполняет все проверки и возвращает найденные проблемы
+func (r *IntegrityRepository) CheckIntegrity() ([]models.IntegrityIssue, error) {
+    issues := []models.IntegrityIssue{}
+    for _, check := range integrityChecks {
+        rows, err := r.DB.Query(check.Query)
+        if err != nil {
+            return nil, fmt.Errorf("integrity check %s failed: %w", check.Name, err)
+        }
+        for rows.Next() {
+            issue := models.IntegrityIssue{Check: check.Name, EntityType: check.EntityType}
+            if err := rows.Scan(&issue.EntityID, &issue.Details); err != nil {
+                rows.Close()
+                return nil, err
+            }
+            issues = append(issues, issue)
+        }
+        rows.Close()
+        if err := rows.Err(); err != nil {
+            return nil, err
+        }
+    }
+    return issues, nil
+}
\ No newline at end of file
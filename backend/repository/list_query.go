@@ -0,0 +1,154 @@
+package repositories
+
+import (
+    "backend/models"
+    "database/sql"
+    "fmt"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// listFilter условие фильтра: SQL с одним "?" вместо значения и разбор значения из строки запроса
+type listFilter struct {
+    expr  string
+    parse func(value string) (interface{}, error)
+}
+
+// listSpec описывает, по каким полям список можно сортировать и фильтровать
+type listSpec struct {
+    sorts   map[string]string     // поле API -> выражение SQL
+    filters map[string]listFilter // параметр запроса -> условие
+    id      string                // Ключ для стабильного порядка страниц
+}
+
+func textFilter(expr string) listFilter {
+    return listFilter{expr: expr, parse: func(value string) (interface{}, error) { return value, nil }}
+}
+
+// prefixFilter ищет по началу строки без учёта регистра
+func prefixFilter(column string) listFilter {
+    return listFilter{expr: column + " ILIKE ?", parse: func(value string) (interface{}, error) {
+        escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+        return escaped + "%", nil
+    }}
+}
+
+func intFilter(expr string) listFilter {
+    return listFilter{expr: expr, parse: func(value string) (interface{}, error) {
+        return strconv.Atoi(value)
+    }}
+}
+
+func floatFilter(expr string) listFilter {
+    return listFilter{expr: expr, parse: func(value string) (interface{}, error) {
+        return strconv.ParseFloat(value, 64)
+    }}
+}
+
+func dateFilter(expr string) listFilter {
+    return listFilter{expr: expr, parse: func(value string) (interface{}, error) {
+        if _, err := time.Parse("2006-01-02", value); err != nil {
+            return nil, err
+        }
+        return value, nil
+    }}
+}
+
+// build дописывает к базовому запросу (он должен заканчиваться условием WHERE) фильтры,
+// сортировку и страницу. Возвращает запрос страницы и запрос общего количества
+func (spec listSpec) build(base string, query models.ListQuery) (string, string, []interface{}, error) {
+    var conditions []string
+    var args []interface{}
+
+    // Порядок фильтров фиксируем, чтобы номера параметров не зависели от обхода map
+    names := make([]string, 0, len(query.Filters))
+    for name := range query.Filters {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+
+    for _, name := range names {
+        value := query.Filters[name]
+        filter, ok := spec.filters[name]
+        if !ok {
+            return "", "", nil, models.Invalid("invalid filter '%s'", name)
+        }
+        if value == "" {
+            continue
+        }
+        parsed, err := filter.parse(value)
+        if err != nil {
+            return "", "", nil, models.Invalid("invalid value for filter '%s': %s", name, value)
+        }
+        args = append(args, parsed)
+        conditions = append(conditions, strings.Replace(filter.expr, "?", fmt.Sprintf("$%d", len(args)), -1))
+    }
+
+    filtered := base
+    for _, condition := range conditions {
+        filtered += "\n AND " + condition
+    }
+    countQuery := "SELECT COUNT(*) FROM (" + filtered + ") AS list"
+
+    var order []string
+    for _, field := range query.Sort {
+        expr, ok := spec.sorts[field.Field]
+        if !ok {
+            return "", "", nil, models.Invalid("invalid sort field '%s'", field.Field)
+        }
+        if field.Desc {
+            expr += " DESC"
+        }
+        order = append(order, expr)
+    }
+    order = append(order, spec.id)
+
+    pageQuery := filtered + "\n ORDER BY " + strings.Join(order, ", ")
+    if query.Limit > 0 {
+        pageQuery += fmt.Sprintf(" LIMIT %d", query.Limit)
+    }
+    if query.Offset > 0 {
+        pageQuery += fmt.Sprintf(" OFFSET %d", query.Offset)
+    }
+    return pageQuery, countQuery, args, nil
+}
+
+// queryList выполняет запрос страницы и запрос количества; scan вызывается для каждой строки
+func queryList(db DBTX, spec listSpec, base string, query models.ListQuery, scan func(rows *sql.Rows) error) (int, error) {
+    _, countQuery, args, err := spec.build(base, query)
+    if err != nil {
+        return 0, err
+    }
+
+    var total int
+    if err := db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+        return 0, err
+    }
+    if err := eachList(db, spec, base, query, scan); err != nil {
+        return 0, err
+    }
+    return total, nil
+}
+
+// eachList выполняет только запрос строк, без подсчёта: scan вызывается по мере чтения,
+// поэтому выгрузка всего списка не собирает его в памяти. Ошибка scan прерывает чтение
+func eachList(db DBTX, spec listSpec, base string, query models.ListQuery, scan func(rows *sql.Rows) error) error {
+    pageQuery, _, args, err := spec.build(base, query)
+    if err != nil {
+        return err
+    }
+
+    rows, err := db.Query(pageQuery, args...)
+    if err != nil {
+        return err
+    }
+    defer rows.Close()
+    for rows.Next() {
+        if err := scan(rows); err != nil {
+            return err
+        }
+    }
+    return rows.Err()
+}
\ No newline at end of file
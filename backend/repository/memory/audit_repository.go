@@ -0,0 +1,49 @@
+package memory
+
+import (
+    "backend/models"
+    "slices"
+    "time"
+)
+
+// AuditRepository журнал изменений; записи откатываются вместе с UnitOfWork, как в Postgres
+type AuditRepository struct {
+    DB *DB
+}
+
+func NewAuditRepository(db *DB) *AuditRepository {
+    return &AuditRepository{DB: db}
+}
+
+func (r *AuditRepository) CreateEntry(entry *models.AuditEntry) error {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    entry.ID = t.nextID("audit_log")
+    entry.CreatedAt = time.Now()
+    t.audit = append(t.audit, *entry)
+    return nil
+}
+
+// GetEntries фильтрует журнал так же, как запрос в Postgres; новые записи сначала
+func (r *AuditRepository) GetEntries(filter models.AuditFilter) ([]models.AuditEntry, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    entries := []models.AuditEntry{}
+    for _, entry := range slices.Backward(t.audit) {
+        switch {
+        case filter.UserID != 0 && (entry.UserID == nil || *entry.UserID != filter.UserID),
+            filter.EntityType != "" && entry.EntityType != filter.EntityType,
+            filter.EntityID != 0 && entry.EntityID != filter.EntityID,
+            !filter.From.IsZero() && entry.CreatedAt.Before(filter.From),
+            !filter.To.IsZero() && !entry.CreatedAt.Before(filter.To):
+            continue
+        }
+        entries = append(entries, entry)
+        if filter.Limit > 0 && len(entries) == filter.Limit {
+            break
+        }
+    }
+    return entries, nil
+}
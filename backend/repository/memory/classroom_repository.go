@@ -0,0 +1,156 @@
+package memory
+
+import (
+    "backend/models"
+    "cmp"
+)
+
+type ClassroomRepository struct {
+    DB *DB
+}
+
+func NewClassroomRepository(db *DB) *ClassroomRepository {
+    return &ClassroomRepository{DB: db}
+}
+
+func (t *tables) activeClassroom(id int) (classroomRow, bool) {
+    row, ok := t.classrooms[id]
+    return row, ok && row.deletedAt == nil
+}
+
+// checkClassroom повторяет UNIQUE (name) и CHECK (capacity > 0) таблицы classrooms
+func (t *tables) checkClassroom(classroom models.Classroom) error {
+    for id, row := range t.classrooms {
+        if id != classroom.ID && row.Name == classroom.Name {
+            return errUnique()
+        }
+    }
+    if classroom.Capacity <= 0 {
+        return errCheck("classrooms", "classrooms_capacity_check")
+    }
+    return nil
+}
+
+func (r *ClassroomRepository) CreateClassroom(classroom *models.Classroom) error {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    if err := t.checkClassroom(*classroom); err != nil {
+        return err
+    }
+    classroom.ID = t.nextID("classrooms")
+    t.classrooms[classroom.ID] = classroomRow{Classroom: *classroom}
+    return nil
+}
+
+// classroomList поля сортировки и фильтры списка аудиторий
+var classroomList = listSpec[models.Classroom]{
+    sorts: map[string]func(a, b models.Classroom) int{
+        "id":       func(a, b models.Classroom) int { return cmp.Compare(a.ID, b.ID) },
+        "name":     func(a, b models.Classroom) int { return cmp.Compare(a.Name, b.Name) },
+        "capacity": func(a, b models.Classroom) int { return cmp.Compare(a.Capacity, b.Capacity) },
+    },
+    filters: map[string]listFilter[models.Classroom]{
+        "name":         prefixFilter(func(classroom models.Classroom) string { return classroom.Name }),
+        "capacity_min": intFilter(func(classroom models.Classroom, value int) bool { return classroom.Capacity >= value }),
+        "capacity_max": intFilter(func(classroom models.Classroom, value int) bool { return classroom.Capacity <= value }),
+    },
+    id: func(classroom models.Classroom) int { return classroom.ID },
+}
+
+func (r *ClassroomRepository) ListClassrooms(query models.ListQuery) ([]models.Classroom, int, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    var classrooms []models.Classroom
+    for _, row := range t.classrooms {
+        if row.deletedAt == nil {
+            classrooms = append(classrooms, row.Classroom)
+        }
+    }
+    return classroomList.apply(classrooms, query)
+}
+
+func (r *ClassroomRepository) EachClassroom(query models.ListQuery, fn func(models.Classroom) error) error {
+    classrooms, _, err := r.ListClassrooms(query)
+    return each(classrooms, err, fn)
+}
+
+func (r *ClassroomRepository) GetClassrooms() ([]models.Classroom, error) {
+    classrooms, _, err := r.ListClassrooms(models.ListQuery{})
+    return classrooms, err
+}
+
+func (r *ClassroomRepository) GetClassroomByID(id int) (*models.Classroom, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeClassroom(id)
+    if !ok {
+        return nil, models.NotFound("classroom not found")
+    }
+    classroom := row.Classroom
+    return &classroom, nil
+}
+
+func (r *ClassroomRepository) UpdateClassroom(id int, update models.ClassroomUpdate) (*models.Classroom, error) {
+    if update.Name == nil && update.Capacity == nil && update.Description == nil {
+        return nil, errNoFields()
+    }
+
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeClassroom(id)
+    if !ok {
+        return nil, models.NotFound("classroom not found")
+    }
+    if update.Name != nil {
+        row.Name = *update.Name
+    }
+    if update.Capacity != nil {
+        row.Capacity = *update.Capacity
+    }
+    if update.Description != nil {
+        row.Description = *update.Description
+    }
+    if err := t.checkClassroom(row.Classroom); err != nil {
+        return nil, err
+    }
+    t.classrooms[id] = row
+
+    classroom := row.Classroom
+    return &classroom, nil
+}
+
+func (t *tables) classroomSchedules(id int) []int {
+    return t.dependentSchedules(func(row scheduleRow) bool { return row.classroomID == id })
+}
+
+func (r *ClassroomRepository) GetClassroomDeletionImpact(id int) (models.Cascade, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    if _, ok := t.activeClassroom(id); !ok {
+        return nil, models.NotFound("classroom not found")
+    }
+    return cascade(t.classroomSchedules(id)), nil
+}
+
+// DeleteClassroom помещает аудиторию и занятия в ней в корзину; название получает суффикс корзины
+func (r *ClassroomRepository) DeleteClassroom(id int) (models.Cascade, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeClassroom(id)
+    if !ok {
+        return nil, models.NotFound("classroom not found")
+    }
+    row.deletedAt = now()
+    row.Name = trashedName(row.Name, id)
+    t.classrooms[id] = row
+
+    ids := t.classroomSchedules(id)
+    t.deleteSchedules(ids, row.deletedAt)
+    return cascade(ids), nil
+}
@@ -0,0 +1,218 @@
+package memory
+
+import (
+    "backend/models"
+    "cmp"
+    "slices"
+    "sort"
+)
+
+type CourseRepository struct {
+    DB *DB
+}
+
+func NewCourseRepository(db *DB) *CourseRepository {
+    return &CourseRepository{DB: db}
+}
+
+func (t *tables) activeCourse(id int) (courseRow, bool) {
+    row, ok := t.courses[id]
+    return row, ok && row.deletedAt == nil
+}
+
+// CreateCourse создаёт курс и добавляет его в список курсов преподавателя.
+// Название уникально, как UNIQUE в схеме; у курсов в корзине оно с суффиксом корзины
+func (r *CourseRepository) CreateCourse(course *models.Course) error {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    if _, exists := t.courseNamed(course.Name, false); exists {
+        return errUnique()
+    }
+
+    var teacher teacherRow
+    if course.TeacherID != nil {
+        row, exists := t.teachers[*course.TeacherID]
+        if !exists {
+            return errForeignKey()
+        }
+        if row.deletedAt != nil {
+            return models.NotFound("teacher with id %d not found", *course.TeacherID)
+        }
+        teacher = row
+    }
+
+    course.ID = t.nextID("courses")
+    t.courses[course.ID] = courseRow{Course: *course}
+
+    if course.TeacherID != nil && !slices.Contains(teacher.Courses, course.Name) {
+        teacher.Courses = append(slices.Clone(teacher.Courses), course.Name)
+        t.teachers[teacher.ID] = teacher
+    }
+    return nil
+}
+
+// courseList поля сортировки и фильтры списка курсов
+var courseList = listSpec[models.Course]{
+    sorts: map[string]func(a, b models.Course) int{
+        "id":         func(a, b models.Course) int { return cmp.Compare(a.ID, b.ID) },
+        "name":       func(a, b models.Course) int { return cmp.Compare(a.Name, b.Name) },
+        "teacher_id": func(a, b models.Course) int { return compareNullable(a.TeacherID, b.TeacherID) },
+    },
+    filters: map[string]listFilter[models.Course]{
+        "name": prefixFilter(func(course models.Course) string { return course.Name }),
+        "teacher_id": intFilter(func(course models.Course, value int) bool {
+            return course.TeacherID != nil && *course.TeacherID == value
+        }),
+    },
+    id: func(course models.Course) int { return course.ID },
+}
+
+func (r *CourseRepository) ListCourses(query models.ListQuery) ([]models.Course, int, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    var courses []models.Course
+    for _, row := range t.courses {
+        if row.deletedAt == nil {
+            courses = append(courses, row.Course)
+        }
+    }
+    return courseList.apply(courses, query)
+}
+
+func (r *CourseRepository) EachCourse(query models.ListQuery, fn func(models.Course) error) error {
+    courses, _, err := r.ListCourses(query)
+    return each(courses, err, fn)
+}
+
+func (r *CourseRepository) GetCourses() ([]models.Course, error) {
+    courses, _, err := r.ListCourses(models.ListQuery{})
+    return courses, err
+}
+
+func (r *CourseRepository) GetCourseByID(id int) (*models.Course, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeCourse(id)
+    if !ok {
+        return nil, models.NotFound("course with id %d not found", id)
+    }
+    course := row.Course
+    return &course, nil
+}
+
+// UpdateCourse меняет переданные поля. При переименовании занятия (ON UPDATE CASCADE), студенты группы
+// и список курсов преподавателя переходят на новое название, как и в CourseRepository
+func (r *CourseRepository) UpdateCourse(id int, update models.CourseUpdate) (*models.Course, error) {
+    if update.Name == nil && update.Description == nil && update.TeacherID == nil {
+        return nil, errNoFields()
+    }
+
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeCourse(id)
+    if !ok {
+        return nil, models.NotFound("course with id %d not found", id)
+    }
+
+    if update.Name != nil && *update.Name != row.Name {
+        if _, exists := t.courseNamed(*update.Name, false); exists {
+            return nil, errUnique()
+        }
+        t.renameGroup(row.Name, *update.Name)
+        for id, student := range t.students {
+            if student.GroupName == row.Name {
+                student.GroupName = *update.Name
+                t.students[id] = student
+            }
+        }
+        for id, teacher := range t.teachers {
+            if i := slices.Index(teacher.Courses, row.Name); i >= 0 {
+                teacher.Courses = slices.Clone(teacher.Courses)
+                teacher.Courses[i] = *update.Name
+                t.teachers[id] = teacher
+            }
+        }
+        row.Name = *update.Name
+    }
+    if update.Description != nil {
+        row.Description = *update.Description
+    }
+    if update.TeacherID != nil {
+        if _, exists := t.teachers[*update.TeacherID]; !exists {
+            return nil, errForeignKey()
+        }
+        teacherID := *update.TeacherID
+        row.TeacherID = &teacherID
+    }
+    t.courses[id] = row
+
+    course := row.Course
+    return &course, nil
+}
+
+func (t *tables) courseSchedules(name string) []int {
+    return t.dependentSchedules(func(row scheduleRow) bool { return row.GroupName == name })
+}
+
+func (r *CourseRepository) GetCourseDeletionImpact(id int) (models.Cascade, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeCourse(id)
+    if !ok {
+        return nil, models.NotFound("course with id %d not found", id)
+    }
+    return cascade(t.courseSchedules(row.Name)), nil
+}
+
+// DeleteCourse помещает курс и занятия группы в корзину и убирает курс из списка курсов преподавателя.
+// Название получает суффикс корзины, занятия переходят на него вместе с курсом
+func (r *CourseRepository) DeleteCourse(id int) (models.Cascade, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeCourse(id)
+    if !ok {
+        return nil, models.NotFound("course with id %d not found", id)
+    }
+    if row.TeacherID != nil {
+        if teacher, exists := t.teachers[*row.TeacherID]; exists {
+            teacher.Courses = slices.DeleteFunc(slices.Clone(teacher.Courses), func(name string) bool { return name == row.Name })
+            t.teachers[teacher.ID] = teacher
+        }
+    }
+
+    ids := t.courseSchedules(row.Name)
+    row.deletedAt = now()
+    t.deleteSchedules(ids, row.deletedAt)
+
+    name := trashedName(row.Name, id)
+    t.renameGroup(row.Name, name)
+    row.Name = name
+    t.courses[id] = row
+    return cascade(ids), nil
+}
+
+// GetStudentCourses возвращает курс группы студента. Ведомостей в памяти нет,
+// поэтому курсы, по которым студент аттестовывался в других группах, не попадают
+func (r *CourseRepository) GetStudentCourses(studentID int) ([]models.Course, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    courses := []models.Course{}
+    student, ok := t.students[studentID]
+    if !ok {
+        return courses, nil
+    }
+    for _, row := range t.courses {
+        if row.deletedAt == nil && row.Name == student.GroupName {
+            courses = append(courses, row.Course)
+        }
+    }
+    sort.Slice(courses, func(i, j int) bool { return courses[i].Name < courses[j].Name })
+    return courses, nil
+}
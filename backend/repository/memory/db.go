@@ -0,0 +1,238 @@
+// Package memory реализует интерфейсы репозиториев в памяти для тестов сервисов без Postgres.
+// Семантика та же, что у таблиц: мягкое удаление с каскадом, уникальные названия курсов
+// и аудиторий, внешние ключи и ограничения CHECK; ошибки - те же models.Error,
+// что отдаёт API для соответствующих ошибок Postgres
+package memory
+
+import (
+    "backend/models"
+    "backend/repository"
+    "fmt"
+    "maps"
+    "slices"
+    "sort"
+    "sync"
+    "time"
+)
+
+// DB общее хранилище репозиториев в памяти.
+// Каждый метод репозитория атомарен. UnitOfWork выполняются по очереди и откатываются целиком,
+// но запись вне UnitOfWork параллельно с ней может потеряться при откате - в тестах так не делаем
+type DB struct {
+    mu   sync.Mutex // Таблицы
+    tx   sync.Mutex // Транзакции UnitOfWork
+    data *tables
+}
+
+type tables struct {
+    seq          map[string]int // Последний ID по таблицам, как SERIAL
+    teachers     map[int]teacherRow
+    courses      map[int]courseRow
+    classrooms   map[int]classroomRow
+    schedules    map[int]scheduleRow
+    students     map[int]studentRow
+    overrides    map[int]models.ScheduleOverride
+    orders       []models.StudentOrder
+    groupChanges []models.StudentGroupChange
+    audit        []models.AuditEntry
+}
+
+type teacherRow struct {
+    models.Teacher
+    budget    float64 // hours_budget
+    deletedAt *time.Time
+}
+
+type courseRow struct {
+    models.Course
+    deletedAt *time.Time
+}
+
+type classroomRow struct {
+    models.Classroom
+    deletedAt *time.Time
+}
+
+// scheduleRow занятие; имена преподавателя и аудитории подставляются при чтении
+type scheduleRow struct {
+    models.Schedule
+    teacherID   int
+    classroomID int
+    deletedAt   *time.Time
+}
+
+// studentRow студент; возраст и преподаватель группы вычисляются при чтении
+type studentRow struct {
+    models.Student
+    deletedAt *time.Time
+}
+
+func NewDB() *DB {
+    return &DB{data: &tables{
+        seq:        map[string]int{},
+        teachers:   map[int]teacherRow{},
+        courses:    map[int]courseRow{},
+        classrooms: map[int]classroomRow{},
+        schedules:  map[int]scheduleRow{},
+        students:   map[int]studentRow{},
+        overrides:  map[int]models.ScheduleOverride{},
+    }}
+}
+
+// lock блокирует таблицы; снимать через db.mu.Unlock()
+func (db *DB) lock() *tables {
+    db.mu.Lock()
+    return db.data
+}
+
+func (t *tables) nextID(table string) int {
+    t.seq[table]++
+    return t.seq[table]
+}
+
+// clone копия таблиц для отката UnitOfWork. Указатели в строках не меняются на месте,
+// поэтому копировать нужно только срезы
+func (t *tables) clone() *tables {
+    c := &tables{
+        seq:          maps.Clone(t.seq),
+        teachers:     make(map[int]teacherRow, len(t.teachers)),
+        courses:      maps.Clone(t.courses),
+        classrooms:   maps.Clone(t.classrooms),
+        schedules:    maps.Clone(t.schedules),
+        students:     maps.Clone(t.students),
+        overrides:    maps.Clone(t.overrides),
+        orders:       slices.Clone(t.orders),
+        groupChanges: slices.Clone(t.groupChanges),
+        audit:        slices.Clone(t.audit),
+    }
+    for id, row := range t.teachers {
+        row.Courses = slices.Clone(row.Courses)
+        c.teachers[id] = row
+    }
+    return c
+}
+
+// UnitOfWork выполняет fn над репозиториями в памяти; если fn вернула ошибку, таблицы
+// возвращаются к состоянию до вызова
+type UnitOfWork struct {
+    DB *DB
+}
+
+func NewUnitOfWork(db *DB) *UnitOfWork {
+    return &UnitOfWork{DB: db}
+}
+
+func (u *UnitOfWork) Do(fn func(tx *repositories.Tx) error) error {
+    u.DB.tx.Lock()
+    defer u.DB.tx.Unlock()
+
+    snapshot := u.DB.lock().clone()
+    u.DB.mu.Unlock()
+
+    if err := fn(&repositories.Tx{
+        Teachers:   NewTeacherRepository(u.DB),
+        Courses:    NewCourseRepository(u.DB),
+        Classrooms: NewClassroomRepository(u.DB),
+        Schedules:  NewScheduleRepository(u.DB),
+        Students:   NewStudentRepository(u.DB),
+        Audit:      NewAuditRepository(u.DB),
+    }); err != nil {
+        u.DB.lock()
+        u.DB.data = snapshot
+        u.DB.mu.Unlock()
+        return err
+    }
+    return nil
+}
+
+var (
+    _ repositories.Transactor     = (*UnitOfWork)(nil)
+    _ repositories.TeacherStore   = (*TeacherRepository)(nil)
+    _ repositories.CourseStore    = (*CourseRepository)(nil)
+    _ repositories.ClassroomStore = (*ClassroomRepository)(nil)
+    _ repositories.ScheduleStore  = (*ScheduleRepository)(nil)
+    _ repositories.StudentStore   = (*StudentRepository)(nil)
+    _ repositories.AuditStore     = (*AuditRepository)(nil)
+)
+
+// Ошибки, которые API отдаёт для нарушений ограничений Postgres (см. middleware.ErrorMiddleware)
+func errUnique() error {
+    return models.Conflict("record already exists")
+}
+
+func errForeignKey() error {
+    return models.Conflict("record is referenced by or references a missing record")
+}
+
+func errCheck(table, constraint string) error {
+    return models.Invalid("invalid value: new row for relation \"%s\" violates check constraint \"%s\"", table, constraint)
+}
+
+func errNoFields() error {
+    return models.Invalid("no fields to update")
+}
+
+// dependentSchedules занятия, которые попадут в корзину вместе с записью (ON DELETE CASCADE)
+func (t *tables) dependentSchedules(match func(row scheduleRow) bool) []int {
+    ids := []int{}
+    for id, row := range t.schedules {
+        if row.deletedAt == nil && match(row) {
+            ids = append(ids, id)
+        }
+    }
+    sort.Ints(ids)
+    return ids
+}
+
+// cascade зависимые занятия в виде models.Cascade
+func cascade(ids []int) models.Cascade {
+    result := models.Cascade{}
+    if len(ids) > 0 {
+        result["schedules"] = ids
+    }
+    return result
+}
+
+// deleteSchedules помещает занятия в корзину с отметкой времени удалённого родителя
+// и возвращает их часы преподавателям, как softDelete
+func (t *tables) deleteSchedules(ids []int, deletedAt *time.Time) {
+    for _, id := range ids {
+        row := t.schedules[id]
+        row.deletedAt = deletedAt
+        t.schedules[id] = row
+
+        teacher := t.teachers[row.teacherID]
+        teacher.WorkingHours += row.EndTime.Sub(row.StartTime).Hours()
+        t.teachers[row.teacherID] = teacher
+    }
+}
+
+// trashedName название записи id в корзине, как суффикс корзины в TrashRepository
+func trashedName(name string, id int) string {
+    return fmt.Sprintf("%s [deleted #%d]", name, id)
+}
+
+// renameGroup переводит занятия группы на новое название курса, как ON UPDATE CASCADE
+func (t *tables) renameGroup(oldName, newName string) {
+    for id, row := range t.schedules {
+        if row.GroupName == oldName {
+            row.GroupName = newName
+            t.schedules[id] = row
+        }
+    }
+}
+
+// courseNamed курс с названием name; active - только не удалённые
+func (t *tables) courseNamed(name string, active bool) (courseRow, bool) {
+    for _, row := range t.courses {
+        if row.Name == name && (!active || row.deletedAt == nil) {
+            return row, true
+        }
+    }
+    return courseRow{}, false
+}
+
+func now() *time.Time {
+    value := time.Now()
+    return &value
+}
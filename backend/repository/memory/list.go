@@ -0,0 +1,160 @@
+package memory
+
+import (
+    "backend/models"
+    "cmp"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// listFilter разбирает значение параметра запроса в условие отбора
+type listFilter[T any] func(value string) (func(item T) bool, error)
+
+// listSpec поля сортировки и фильтры списка; имена и ошибки те же, что у списков на Postgres
+type listSpec[T any] struct {
+    sorts   map[string]func(a, b T) int
+    filters map[string]listFilter[T]
+    id      func(item T) int // Ключ для стабильного порядка страниц
+}
+
+func textFilter[T any](get func(item T) string) listFilter[T] {
+    return func(value string) (func(item T) bool, error) {
+        return func(item T) bool { return get(item) == value }, nil
+    }
+}
+
+// prefixFilter ищет по началу строки без учёта регистра
+func prefixFilter[T any](get func(item T) string) listFilter[T] {
+    return func(value string) (func(item T) bool, error) {
+        prefix := strings.ToLower(value)
+        return func(item T) bool { return strings.HasPrefix(strings.ToLower(get(item)), prefix) }, nil
+    }
+}
+
+func intFilter[T any](match func(item T, value int) bool) listFilter[T] {
+    return func(value string) (func(item T) bool, error) {
+        parsed, err := strconv.Atoi(value)
+        if err != nil {
+            return nil, err
+        }
+        return func(item T) bool { return match(item, parsed) }, nil
+    }
+}
+
+func floatFilter[T any](match func(item T, value float64) bool) listFilter[T] {
+    return func(value string) (func(item T) bool, error) {
+        parsed, err := strconv.ParseFloat(value, 64)
+        if err != nil {
+            return nil, err
+        }
+        return func(item T) bool { return match(item, parsed) }, nil
+    }
+}
+
+func dateFilter[T any](match func(item T, value time.Time) bool) listFilter[T] {
+    return func(value string) (func(item T) bool, error) {
+        parsed, err := time.Parse("2006-01-02", value)
+        if err != nil {
+            return nil, err
+        }
+        return func(item T) bool { return match(item, parsed) }, nil
+    }
+}
+
+// compareNullable сравнивает необязательные значения; NULL - в конце, как в Postgres
+func compareNullable(a, b *int) int {
+    switch {
+    case a == nil && b == nil:
+        return 0
+    case a == nil:
+        return 1
+    case b == nil:
+        return -1
+    }
+    return cmp.Compare(*a, *b)
+}
+
+// each передаёт fn элементы уже отобранного списка; ошибка fn прерывает обход
+func each[T any](items []T, err error, fn func(item T) error) error {
+    if err != nil {
+        return err
+    }
+    for _, item := range items {
+        if err := fn(item); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// apply отбирает, сортирует и режет на страницу; возвращает страницу и общее количество
+func (spec listSpec[T]) apply(items []T, query models.ListQuery) ([]T, int, error) {
+    names := make([]string, 0, len(query.Filters))
+    for name := range query.Filters {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+
+    var matches []func(item T) bool
+    for _, name := range names {
+        value := query.Filters[name]
+        filter, ok := spec.filters[name]
+        if !ok {
+            return nil, 0, models.Invalid("invalid filter '%s'", name)
+        }
+        if value == "" {
+            continue
+        }
+        match, err := filter(value)
+        if err != nil {
+            return nil, 0, models.Invalid("invalid value for filter '%s': %s", name, value)
+        }
+        matches = append(matches, match)
+    }
+
+    var order []func(a, b T) int
+    for _, field := range query.Sort {
+        compare, ok := spec.sorts[field.Field]
+        if !ok {
+            return nil, 0, models.Invalid("invalid sort field '%s'", field.Field)
+        }
+        if field.Desc {
+            asc := compare
+            compare = func(a, b T) int { return asc(b, a) }
+        }
+        order = append(order, compare)
+    }
+
+    filtered := []T{}
+    for _, item := range items {
+        matched := true
+        for _, match := range matches {
+            if !match(item) {
+                matched = false
+                break
+            }
+        }
+        if matched {
+            filtered = append(filtered, item)
+        }
+    }
+
+    sort.SliceStable(filtered, func(i, j int) bool {
+        for _, compare := range order {
+            if result := compare(filtered[i], filtered[j]); result != 0 {
+                return result < 0
+            }
+        }
+        return spec.id(filtered[i]) < spec.id(filtered[j])
+    })
+
+    total := len(filtered)
+    start := min(max(query.Offset, 0), total)
+    end := total
+    if query.Limit > 0 {
+        end = min(start+query.Limit, total)
+    }
+    return filtered[start:end], total, nil
+}
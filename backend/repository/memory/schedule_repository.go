@@ -0,0 +1,343 @@
+package memory
+
+import (
+    "backend/models"
+    "cmp"
+    "slices"
+    "sort"
+    "time"
+)
+
+type ScheduleRepository struct {
+    DB *DB
+}
+
+func NewScheduleRepository(db *DB) *ScheduleRepository {
+    return &ScheduleRepository{DB: db}
+}
+
+// weekdays порядок дней для сортировки по дню недели
+var weekdays = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+func (t *tables) activeSchedule(id int) (scheduleRow, bool) {
+    row, ok := t.schedules[id]
+    return row, ok && row.deletedAt == nil
+}
+
+// activeSchedules занятия не в корзине по возрастанию ID
+func (t *tables) activeSchedules() []scheduleRow {
+    rows := make([]scheduleRow, 0, len(t.schedules))
+    for _, row := range t.schedules {
+        if row.deletedAt == nil {
+            rows = append(rows, row)
+        }
+    }
+    sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+    return rows
+}
+
+// schedule занятие с именами преподавателя и аудитории (LEFT JOIN без учёта корзины)
+func (t *tables) schedule(row scheduleRow) models.Schedule {
+    schedule := row.Schedule
+    schedule.TeacherID = row.teacherID
+    schedule.TeacherName = t.teachers[row.teacherID].Name
+    schedule.ClassroomID = row.classroomID
+    schedule.ClassroomName = t.classrooms[row.classroomID].Name
+    return schedule
+}
+
+// checkSchedule повторяет внешние ключи и CHECK таблицы schedules. Внешние ключи не знают
+// о корзине: достаточно, чтобы строка существовала
+func (t *tables) checkSchedule(row scheduleRow) error {
+    if _, ok := t.teachers[row.teacherID]; !ok {
+        return errForeignKey()
+    }
+    if _, ok := t.classrooms[row.classroomID]; !ok {
+        return errForeignKey()
+    }
+    if _, ok := t.courseNamed(row.GroupName, false); !ok {
+        return errForeignKey()
+    }
+    if !slices.Contains(weekdays, row.DayOfWeek) {
+        return errCheck("schedules", "schedules_day_of_week_check")
+    }
+    if !models.IsValidWeekType(row.WeekType) {
+        return errCheck("schedules", "schedules_week_type_check")
+    }
+    return nil
+}
+
+// CreateSchedule создает занятие; аудитория и группа должны быть не в корзине
+func (r *ScheduleRepository) CreateSchedule(teacherID, classroomID int, schedule *models.Schedule) error {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    if _, ok := t.activeClassroom(classroomID); !ok {
+        return models.NotFound("classroom not found")
+    }
+    if _, ok := t.courseNamed(schedule.GroupName, true); !ok {
+        return models.Invalid("course with name '%s' does not exist", schedule.GroupName)
+    }
+
+    row := scheduleRow{Schedule: *schedule, teacherID: teacherID, classroomID: classroomID}
+    if err := t.checkSchedule(row); err != nil {
+        return err
+    }
+    row.ID = t.nextID("schedules")
+    t.schedules[row.ID] = row
+
+    *schedule = t.schedule(row)
+    return nil
+}
+
+// CheckScheduleConflict проверяет пересечение с занятиями преподавателя по тому же условию, что scheduleOverlap
+// в Postgres; занятия по чётным и нечётным неделям не пересекаются.
+// excludeID - изменяемое занятие (0 при создании)
+func (r *ScheduleRepository) CheckScheduleConflict(teacherID, excludeID int, dayOfWeek, weekType string, startTime, endTime time.Time) (bool, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    for _, row := range t.activeSchedules() {
+        if row.teacherID != teacherID || row.DayOfWeek != dayOfWeek || row.ID == excludeID {
+            continue
+        }
+        if clock(startTime) >= clock(row.EndTime) || clock(endTime) <= clock(row.StartTime) {
+            continue
+        }
+        if row.WeekType == models.WeekAll || weekType == models.WeekAll || row.WeekType == weekType {
+            return true, nil
+        }
+    }
+    return false, nil
+}
+
+// clock время суток занятия для сортировки по началу (start_time::time)
+func clock(value time.Time) time.Duration {
+    return value.Sub(time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, value.Location()))
+}
+
+// scheduleItem занятие в списке вместе с ключами фильтров, которых нет в models.Schedule
+type scheduleItem struct {
+    models.Schedule
+    teacherID   int
+    classroomID int
+}
+
+// scheduleList поля сортировки и фильтры списка занятий
+var scheduleList = listSpec[scheduleItem]{
+    sorts: map[string]func(a, b scheduleItem) int{
+        "id": func(a, b scheduleItem) int { return cmp.Compare(a.ID, b.ID) },
+        "day": func(a, b scheduleItem) int {
+            return cmp.Compare(slices.Index(weekdays, a.DayOfWeek), slices.Index(weekdays, b.DayOfWeek))
+        },
+        "start_time":     func(a, b scheduleItem) int { return cmp.Compare(clock(a.StartTime), clock(b.StartTime)) },
+        "group_name":     func(a, b scheduleItem) int { return cmp.Compare(a.GroupName, b.GroupName) },
+        "teacher_name":   func(a, b scheduleItem) int { return cmp.Compare(a.TeacherName, b.TeacherName) },
+        "classroom_name": func(a, b scheduleItem) int { return cmp.Compare(a.ClassroomName, b.ClassroomName) },
+    },
+    filters: map[string]listFilter[scheduleItem]{
+        "teacher_id":   intFilter(func(item scheduleItem, value int) bool { return item.teacherID == value }),
+        "classroom_id": intFilter(func(item scheduleItem, value int) bool { return item.classroomID == value }),
+        "group":        textFilter(func(item scheduleItem) string { return item.GroupName }),
+        "day":          textFilter(func(item scheduleItem) string { return item.DayOfWeek }),
+        "week_type":    textFilter(func(item scheduleItem) string { return item.WeekType }),
+        "date_from":    dateFilter(func(item scheduleItem, value time.Time) bool { return !item.StartTime.Before(value) }),
+        "date_to":      dateFilter(func(item scheduleItem, value time.Time) bool { return item.StartTime.Before(value.AddDate(0, 0, 1)) }),
+    },
+    id: func(item scheduleItem) int { return item.ID },
+}
+
+func (r *ScheduleRepository) ListSchedules(query models.ListQuery) ([]models.Schedule, int, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    var items []scheduleItem
+    for _, row := range t.activeSchedules() {
+        items = append(items, scheduleItem{Schedule: t.schedule(row), teacherID: row.teacherID, classroomID: row.classroomID})
+    }
+    page, total, err := scheduleList.apply(items, query)
+    if err != nil {
+        return nil, 0, err
+    }
+
+    schedules := make([]models.Schedule, 0, len(page))
+    for _, item := range page {
+        schedules = append(schedules, item.Schedule)
+    }
+    return schedules, total, nil
+}
+
+func (r *ScheduleRepository) EachSchedule(query models.ListQuery, fn func(models.Schedule) error) error {
+    schedules, _, err := r.ListSchedules(query)
+    return each(schedules, err, fn)
+}
+
+func (r *ScheduleRepository) GetSchedules() ([]models.Schedule, error) {
+    schedules, _, err := r.ListSchedules(models.ListQuery{})
+    return schedules, err
+}
+
+func (r *ScheduleRepository) GetScheduleByID(id int) (*models.Schedule, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeSchedule(id)
+    if !ok {
+        return nil, models.NotFound("schedule not found")
+    }
+    schedule := t.schedule(row)
+    return &schedule, nil
+}
+
+func (r *ScheduleRepository) UpdateSchedule(id int, update models.ScheduleUpdate) (*models.Schedule, error) {
+    if update == (models.ScheduleUpdate{}) {
+        return nil, errNoFields()
+    }
+
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeSchedule(id)
+    if !ok {
+        return nil, models.NotFound("schedule not found")
+    }
+    if update.TeacherID != nil {
+        row.teacherID = *update.TeacherID
+    }
+    if update.ClassroomID != nil {
+        row.classroomID = *update.ClassroomID
+    }
+    if update.GroupName != nil {
+        row.GroupName = *update.GroupName
+    }
+    if update.StartTime != nil {
+        row.StartTime = *update.StartTime
+    }
+    if update.EndTime != nil {
+        row.EndTime = *update.EndTime
+    }
+    if update.DayOfWeek != nil {
+        row.DayOfWeek = *update.DayOfWeek
+    }
+    if update.WeekType != nil {
+        row.WeekType = *update.WeekType
+    }
+    if err := t.checkSchedule(row); err != nil {
+        return nil, err
+    }
+    t.schedules[id] = row
+
+    schedule := t.schedule(row)
+    return &schedule, nil
+}
+
+// DeleteSchedule помещает занятие в корзину и возвращает его часы преподавателю
+func (r *ScheduleRepository) DeleteSchedule(id int) (models.Cascade, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    if _, ok := t.activeSchedule(id); !ok {
+        return nil, models.NotFound("schedule not found")
+    }
+    t.deleteSchedules([]int{id}, now())
+    return models.Cascade{}, nil
+}
+
+func (r *ScheduleRepository) GetFilteredSchedules(dayOfWeek, groupName string) ([]models.Schedule, error) {
+    schedules, _, err := r.ListSchedules(models.ListQuery{Filters: map[string]string{"day": dayOfWeek, "group": groupName}})
+    return schedules, err
+}
+
+func (r *ScheduleRepository) GetScheduleTeacherID(id int) (int, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeSchedule(id)
+    if !ok {
+        return 0, models.NotFound("schedule not found")
+    }
+    return row.teacherID, nil
+}
+
+// override изменение занятия с названием аудитории
+func (t *tables) override(override models.ScheduleOverride) models.ScheduleOverride {
+    override.ClassroomName = nil
+    if override.ClassroomID != nil {
+        if classroom, ok := t.classrooms[*override.ClassroomID]; ok {
+            name := classroom.Name
+            override.ClassroomName = &name
+        }
+    }
+    return override
+}
+
+// SaveOverride создает или заменяет изменение занятия на дату (одно на занятие и дату)
+func (r *ScheduleRepository) SaveOverride(override *models.ScheduleOverride) error {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    if _, ok := t.activeSchedule(override.ScheduleID); !ok {
+        return models.NotFound("schedule not found")
+    }
+    if override.ClassroomID != nil {
+        if _, ok := t.activeClassroom(*override.ClassroomID); !ok {
+            return models.NotFound("classroom not found")
+        }
+    }
+    if _, err := time.Parse("2006-01-02", override.Date); err != nil {
+        return models.Invalid("invalid value: %v", err)
+    }
+
+    saved := *override
+    saved.ID, saved.CreatedAt = 0, time.Now()
+    for id, existing := range t.overrides {
+        if existing.ScheduleID == override.ScheduleID && existing.Date == override.Date {
+            saved.ID, saved.CreatedAt = id, existing.CreatedAt
+        }
+    }
+    if saved.ID == 0 {
+        saved.ID = t.nextID("schedule_overrides")
+    }
+    t.overrides[saved.ID] = saved
+
+    *override = t.override(saved)
+    return nil
+}
+
+// GetOverrides возвращает изменения расписания группы за период (даты включительно); пустая группа - все группы
+func (r *ScheduleRepository) GetOverrides(groupName, from, to string) ([]models.ScheduleOverride, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    overrides := []models.ScheduleOverride{}
+    for _, override := range t.overrides {
+        schedule, ok := t.activeSchedule(override.ScheduleID)
+        if !ok || (groupName != "" && schedule.GroupName != groupName) {
+            continue
+        }
+        if override.Date < from || override.Date > to {
+            continue
+        }
+        overrides = append(overrides, t.override(override))
+    }
+    sort.Slice(overrides, func(i, j int) bool {
+        if overrides[i].Date != overrides[j].Date {
+            return overrides[i].Date < overrides[j].Date
+        }
+        return overrides[i].ScheduleID < overrides[j].ScheduleID
+    })
+    return overrides, nil
+}
+
+func (r *ScheduleRepository) DeleteOverride(scheduleID int, date string) error {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    for id, override := range t.overrides {
+        if override.ScheduleID == scheduleID && override.Date == date {
+            delete(t.overrides, id)
+            return nil
+        }
+    }
+    return models.NotFound("schedule override not found")
+}
@@ -0,0 +1,295 @@
+package memory
+
+import (
+	"backend/models"
+	"backend/utils"
+	"cmp"
+	"sort"
+	"time"
+)
+
+type StudentRepository struct {
+	DB *DB
+}
+
+func NewStudentRepository(db *DB) *StudentRepository {
+	return &StudentRepository{DB: db}
+}
+
+func (t *tables) activeStudent(id int) (studentRow, bool) {
+	row, ok := t.students[id]
+	return row, ok && row.deletedAt == nil
+}
+
+// student студент с возрастом и преподавателем группы
+func (t *tables) student(row studentRow) models.Student {
+	student := row.Student
+	dateOfBirth, _ := time.Parse("2006-01-02", student.DateOfBirth)
+	student.Age = utils.CalculateAge(dateOfBirth)
+	student.TeacherID = nil
+	if course, ok := t.courseNamed(student.GroupName, true); ok {
+		student.TeacherID = course.TeacherID
+	}
+	return student
+}
+
+func (r *StudentRepository) CreateStudent(student *models.Student) error {
+	t := r.DB.lock()
+	defer r.DB.mu.Unlock()
+
+	if _, ok := t.courseNamed(student.GroupName, true); !ok {
+		return models.Invalid("course with name '%s' does not exist", student.GroupName)
+	}
+	dateOfBirth, err := time.Parse("2006-01-02", student.DateOfBirth)
+	if err != nil {
+		return models.Invalid("invalid date_of_birth format: %v", err)
+	}
+
+	student.ID = t.nextID("students")
+	student.Status = models.StudentActive
+	row := studentRow{Student: *student}
+	row.DateOfBirth = dateOfBirth.Format("2006-01-02")
+	t.students[student.ID] = row
+	return nil
+}
+
+// yearsAgo дата n лет назад от сегодняшнего дня
+func yearsAgo(n int) time.Time {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return today.AddDate(-n, 0, 0)
+}
+
+func birthDate(student models.Student) time.Time {
+	date, _ := time.Parse("2006-01-02", student.DateOfBirth)
+	return date
+}
+
+// studentList поля сортировки и фильтры списка студентов
+var studentList = listSpec[models.Student]{
+	sorts: map[string]func(a, b models.Student) int{
+		"id":            func(a, b models.Student) int { return cmp.Compare(a.ID, b.ID) },
+		"name":          func(a, b models.Student) int { return cmp.Compare(a.Name, b.Name) },
+		"date_of_birth": func(a, b models.Student) int { return birthDate(a).Compare(birthDate(b)) },
+		"age":           func(a, b models.Student) int { return birthDate(b).Compare(birthDate(a)) },
+		"group_name":    func(a, b models.Student) int { return cmp.Compare(a.GroupName, b.GroupName) },
+		"status":        func(a, b models.Student) int { return cmp.Compare(a.Status, b.Status) },
+	},
+	filters: map[string]listFilter[models.Student]{
+		"group":  textFilter(func(student models.Student) string { return student.GroupName }),
+		"status": textFilter(func(student models.Student) string { return student.Status }),
+		"name":   prefixFilter(func(student models.Student) string { return student.Name }),
+		"teacher_id": intFilter(func(student models.Student, value int) bool {
+			return student.TeacherID != nil && *student.TeacherID == value
+		}),
+		"age_min": intFilter(func(student models.Student, value int) bool { return !birthDate(student).After(yearsAgo(value)) }),
+		"age_max": intFilter(func(student models.Student, value int) bool { return birthDate(student).After(yearsAgo(value + 1)) }),
+	},
+	id: func(student models.Student) int { return student.ID },
+}
+
+func (r *StudentRepository) ListStudents(query models.ListQuery) ([]models.Student, int, error) {
+	t := r.DB.lock()
+	defer r.DB.mu.Unlock()
+
+	var students []models.Student
+	for _, row := range t.students {
+		if row.deletedAt == nil {
+			students = append(students, t.student(row))
+		}
+	}
+	return studentList.apply(students, query)
+}
+
+func (r *StudentRepository) EachStudent(query models.ListQuery, fn func(models.Student) error) error {
+	students, _, err := r.ListStudents(query)
+	return each(students, err, fn)
+}
+
+func (r *StudentRepository) GetStudents(status string) ([]models.Student, error) {
+	students, _, err := r.ListStudents(models.ListQuery{Filters: map[string]string{"status": status}})
+	return students, err
+}
+
+func (r *StudentRepository) GetStudentByID(id int) (*models.Student, error) {
+	t := r.DB.lock()
+	defer r.DB.mu.Unlock()
+
+	row, ok := t.activeStudent(id)
+	if !ok {
+		return nil, models.NotFound("student with id %d not found", id)
+	}
+	student := t.student(row)
+	return &student, nil
+}
+
+// UpdateStudent меняет переданные поля студента; смена группы попадает в историю переводов
+func (r *StudentRepository) UpdateStudent(id int, update models.StudentUpdate) (*models.Student, error) {
+	t := r.DB.lock()
+	defer r.DB.mu.Unlock()
+
+	if update.GroupName != nil {
+		if _, ok := t.courseNamed(*update.GroupName, true); !ok {
+			return nil, models.Invalid("course with name '%s' does not exist", *update.GroupName)
+		}
+	}
+	if update.Name == nil && update.DateOfBirth == nil && update.GroupName == nil {
+		return nil, errNoFields()
+	}
+
+	row, ok := t.activeStudent(id)
+	if !ok {
+		return nil, models.NotFound("student with id %d not found", id)
+	}
+	oldGroup := row.GroupName
+
+	if update.Name != nil {
+		row.Name = *update.Name
+	}
+	if update.DateOfBirth != nil {
+		dateOfBirth, err := time.Parse("2006-01-02", *update.DateOfBirth)
+		if err != nil {
+			return nil, models.Invalid("invalid value: %v", err)
+		}
+		row.DateOfBirth = dateOfBirth.Format("2006-01-02")
+	}
+	if update.GroupName != nil {
+		row.GroupName = *update.GroupName
+	}
+	t.students[id] = row
+
+	if row.GroupName != oldGroup {
+		t.insertGroupChange(id, oldGroup, row.GroupName, nil)
+	}
+
+	student := t.student(row)
+	return &student, nil
+}
+
+// DeleteStudent помещает студента в корзину
+func (r *StudentRepository) DeleteStudent(id int) (models.Cascade, error) {
+	t := r.DB.lock()
+	defer r.DB.mu.Unlock()
+
+	row, ok := t.activeStudent(id)
+	if !ok {
+		return nil, models.NotFound("student with id %d not found", id)
+	}
+	row.deletedAt = now()
+	t.students[id] = row
+	return models.Cascade{}, nil
+}
+
+func (r *StudentRepository) CourseExists(courseName string) (bool, error) {
+	t := r.DB.lock()
+	defer r.DB.mu.Unlock()
+
+	_, ok := t.courseNamed(courseName, true)
+	return ok, nil
+}
+
+// ChangeStudentStatus меняет статус студента и регистрирует приказ
+func (r *StudentRepository) ChangeStudentStatus(id int, order *models.StudentOrder) error {
+	t := r.DB.lock()
+	defer r.DB.mu.Unlock()
+
+	row, ok := t.activeStudent(id)
+	if !ok {
+		return models.NotFound("student with id %d not found", id)
+	}
+	order.OldStatus = row.Status
+	if order.OldStatus == order.NewStatus {
+		return models.Conflict("student already has status '%s'", order.NewStatus)
+	}
+	if !models.IsValidStudentStatus(order.NewStatus) {
+		return errCheck("students", "students_status_check")
+	}
+
+	order.StudentID = id
+	if err := t.insertOrder(order); err != nil {
+		return err
+	}
+	row.Status = order.NewStatus
+	t.students[id] = row
+	return nil
+}
+
+// TransferStudent переводит студента в другую группу по приказу
+func (r *StudentRepository) TransferStudent(id int, newGroup string, order *models.StudentOrder) error {
+	t := r.DB.lock()
+	defer r.DB.mu.Unlock()
+
+	row, ok := t.activeStudent(id)
+	if !ok {
+		return models.NotFound("student with id %d not found", id)
+	}
+	order.OldStatus = row.Status
+	if row.GroupName == newGroup {
+		return models.Conflict("student is already in group '%s'", newGroup)
+	}
+	if _, ok := t.courseNamed(newGroup, true); !ok {
+		return models.Invalid("course with name '%s' does not exist", newGroup)
+	}
+
+	// Перевод между группами не меняет статус студента
+	order.StudentID = id
+	order.NewStatus = order.OldStatus
+	if err := t.insertOrder(order); err != nil {
+		return err
+	}
+
+	oldGroup := row.GroupName
+	row.GroupName = newGroup
+	t.students[id] = row
+	t.insertGroupChange(id, oldGroup, newGroup, &order.ID)
+	return nil
+}
+
+func (t *tables) insertOrder(order *models.StudentOrder) error {
+	if _, err := time.Parse("2006-01-02", order.OrderDate); err != nil {
+		return models.Invalid("invalid order_date format: %v", err)
+	}
+	order.ID = t.nextID("student_orders")
+	order.CreatedAt = time.Now()
+	t.orders = append(t.orders, *order)
+	return nil
+}
+
+func (t *tables) insertGroupChange(studentID int, oldGroup, newGroup string, orderID *int) {
+	t.groupChanges = append(t.groupChanges, models.StudentGroupChange{
+		ID:        t.nextID("student_group_history"),
+		StudentID: studentID,
+		OldGroup:  oldGroup,
+		NewGroup:  newGroup,
+		OrderID:   orderID,
+		ChangedAt: time.Now(),
+	})
+}
+
+// GetStudentOrders возвращает журнал приказов по студенту
+func (r *StudentRepository) GetStudentOrders(studentID int) ([]models.StudentOrder, error) {
+	t := r.DB.lock()
+	defer r.DB.mu.Unlock()
+
+	orders := []models.StudentOrder{}
+	for _, order := range t.orders {
+		if order.StudentID == studentID {
+			orders = append(orders, order)
+		}
+	}
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].OrderDate < orders[j].OrderDate })
+	return orders, nil
+}
+
+// GetStudentGroupHistory возвращает историю переводов студента между группами
+func (r *StudentRepository) GetStudentGroupHistory(studentID int) ([]models.StudentGroupChange, error) {
+	t := r.DB.lock()
+	defer r.DB.mu.Unlock()
+
+	history := []models.StudentGroupChange{}
+	for _, change := range t.groupChanges {
+		if change.StudentID == studentID {
+			history = append(history, change)
+		}
+	}
+	return history, nil
+}
@@ -0,0 +1,307 @@
+package memory
+
+import (
+    "backend/models"
+    "cmp"
+    "math"
+    "slices"
+    "sort"
+)
+
+type TeacherRepository struct {
+    DB *DB
+}
+
+func NewTeacherRepository(db *DB) *TeacherRepository {
+    return &TeacherRepository{DB: db}
+}
+
+// activeTeacher преподаватель не в корзине
+func (t *tables) activeTeacher(id int) (teacherRow, bool) {
+    row, ok := t.teachers[id]
+    return row, ok && row.deletedAt == nil
+}
+
+func (t *tables) coursesExist(courseNames []string) bool {
+    count := 0
+    for _, row := range t.courses {
+        if row.deletedAt == nil && slices.Contains(courseNames, row.Name) {
+            count++
+        }
+    }
+    return count == len(courseNames)
+}
+
+func (r *TeacherRepository) CreateTeacher(teacher *models.Teacher) error {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    if len(teacher.Courses) > 0 && !t.coursesExist(teacher.Courses) {
+        return models.Invalid("some courses do not exist")
+    }
+
+    teacher.ID = t.nextID("teachers")
+    row := teacherRow{Teacher: *teacher, budget: teacher.WorkingHours}
+    row.Courses = slices.Clone(teacher.Courses)
+    t.teachers[teacher.ID] = row
+    return nil
+}
+
+func (r *TeacherRepository) LockTeacher(id int) error {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    if _, ok := t.activeTeacher(id); !ok {
+        return models.NotFound("teacher with id %d not found", id)
+    }
+    return nil
+}
+
+// UpdateTeacherWorkingHours списывает часы, если их хватает
+func (r *TeacherRepository) UpdateTeacherWorkingHours(teacherID int, hours float64) error {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeTeacher(teacherID)
+    if !ok || row.WorkingHours < hours {
+        return models.Conflict("teacher with id %d does not have enough working hours", teacherID)
+    }
+    row.WorkingHours -= hours
+    t.teachers[teacherID] = row
+    return nil
+}
+
+// ReturnTeacherWorkingHours возвращает часы, в том числе преподавателю в корзине
+func (r *TeacherRepository) ReturnTeacherWorkingHours(teacherID int, hours float64) error {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.teachers[teacherID]
+    if !ok {
+        return models.NotFound("teacher with id %d not found", teacherID)
+    }
+    row.WorkingHours += hours
+    t.teachers[teacherID] = row
+    return nil
+}
+
+func (r *TeacherRepository) CheckTeacherExists(name, subject string) (bool, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    for _, row := range t.teachers {
+        if row.deletedAt == nil && row.Name == name && row.Subject == subject {
+            return true, nil
+        }
+    }
+    return false, nil
+}
+
+func (r *TeacherRepository) CheckCoursesExist(courseNames []string) (bool, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    return t.coursesExist(courseNames), nil
+}
+
+// GetAllTeachersWithCourses возвращает преподавателей с курсами, где они указаны преподавателем
+func (r *TeacherRepository) GetAllTeachersWithCourses() ([]models.Teacher, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    var teachers []models.Teacher
+    for _, row := range t.teachers {
+        if row.deletedAt != nil {
+            continue
+        }
+        teacher := models.Teacher{ID: row.ID, Name: row.Name, Subject: row.Subject, Courses: []string{}}
+        for _, course := range t.courses {
+            if course.deletedAt == nil && course.TeacherID != nil && *course.TeacherID == row.ID {
+                teacher.Courses = append(teacher.Courses, course.Name)
+            }
+        }
+        sort.Strings(teacher.Courses)
+        teachers = append(teachers, teacher)
+    }
+    sort.Slice(teachers, func(i, j int) bool { return teachers[i].ID < teachers[j].ID })
+    return teachers, nil
+}
+
+// teacherList поля сортировки и фильтры списка преподавателей
+var teacherList = listSpec[models.Teacher]{
+    sorts: map[string]func(a, b models.Teacher) int{
+        "id":            func(a, b models.Teacher) int { return cmp.Compare(a.ID, b.ID) },
+        "name":          func(a, b models.Teacher) int { return cmp.Compare(a.Name, b.Name) },
+        "subject":       func(a, b models.Teacher) int { return cmp.Compare(a.Subject, b.Subject) },
+        "working_hours": func(a, b models.Teacher) int { return cmp.Compare(a.WorkingHours, b.WorkingHours) },
+    },
+    filters: map[string]listFilter[models.Teacher]{
+        "name":    prefixFilter(func(teacher models.Teacher) string { return teacher.Name }),
+        "subject": textFilter(func(teacher models.Teacher) string { return teacher.Subject }),
+        "course": func(value string) (func(models.Teacher) bool, error) {
+            return func(teacher models.Teacher) bool { return slices.Contains(teacher.Courses, value) }, nil
+        },
+        "hours_min": floatFilter(func(teacher models.Teacher, value float64) bool { return teacher.WorkingHours >= value }),
+        "hours_max": floatFilter(func(teacher models.Teacher, value float64) bool { return teacher.WorkingHours <= value }),
+    },
+    id: func(teacher models.Teacher) int { return teacher.ID },
+}
+
+func (row teacherRow) model() models.Teacher {
+    teacher := row.Teacher
+    teacher.Courses = slices.Clone(row.Courses)
+    return teacher
+}
+
+func (r *TeacherRepository) ListTeachers(query models.ListQuery) ([]models.Teacher, int, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    var teachers []models.Teacher
+    for _, row := range t.teachers {
+        if row.deletedAt == nil {
+            teachers = append(teachers, row.model())
+        }
+    }
+    return teacherList.apply(teachers, query)
+}
+
+func (r *TeacherRepository) EachTeacher(query models.ListQuery, fn func(models.Teacher) error) error {
+    teachers, _, err := r.ListTeachers(query)
+    return each(teachers, err, fn)
+}
+
+func (r *TeacherRepository) GetAllTeachers() ([]models.Teacher, error) {
+    teachers, _, err := r.ListTeachers(models.ListQuery{})
+    return teachers, err
+}
+
+func (r *TeacherRepository) GetTeacherByID(teacherID int) (*models.Teacher, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeTeacher(teacherID)
+    if !ok {
+        return nil, models.NotFound("teacher with id %d not found", teacherID)
+    }
+    teacher := row.model()
+    return &teacher, nil
+}
+
+// UpdateTeacherPartial меняет переданные поля; бюджет часов меняется на ту же величину, что и остаток
+func (r *TeacherRepository) UpdateTeacherPartial(id int, update models.TeacherUpdate) (*models.Teacher, error) {
+    if update.Name == nil && update.Subject == nil && update.WorkingHours == nil {
+        return nil, errNoFields()
+    }
+
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeTeacher(id)
+    if !ok {
+        return nil, models.NotFound("teacher with id %d not found", id)
+    }
+
+    if update.Name != nil {
+        row.Name = *update.Name
+    }
+    if update.Subject != nil {
+        row.Subject = *update.Subject
+    }
+    if update.WorkingHours != nil {
+        row.budget += *update.WorkingHours - row.WorkingHours
+        row.WorkingHours = *update.WorkingHours
+    }
+    t.teachers[id] = row
+    teacher := row.model()
+    return &teacher, nil
+}
+
+func (t *tables) teacherSchedules(id int) []int {
+    return t.dependentSchedules(func(row scheduleRow) bool { return row.teacherID == id })
+}
+
+func (r *TeacherRepository) GetTeacherDeletionImpact(id int) (models.Cascade, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    if _, ok := t.activeTeacher(id); !ok {
+        return nil, models.NotFound("teacher with id %d not found", id)
+    }
+    return cascade(t.teacherSchedules(id)), nil
+}
+
+// DeleteTeacher помещает преподавателя и его занятия в корзину
+func (r *TeacherRepository) DeleteTeacher(id int) (models.Cascade, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    row, ok := t.activeTeacher(id)
+    if !ok {
+        return nil, models.NotFound("teacher with id %d not found", id)
+    }
+    row.deletedAt = now()
+    t.teachers[id] = row
+
+    ids := t.teacherSchedules(id)
+    t.deleteSchedules(ids, row.deletedAt)
+    return cascade(ids), nil
+}
+
+func (r *TeacherRepository) GetTeacherSchedule(teacherName string) ([]models.Schedule, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    var schedules []models.Schedule
+    for _, row := range t.activeSchedules() {
+        teacher, ok := t.activeTeacher(row.teacherID)
+        if !ok || teacher.Name != teacherName {
+            continue
+        }
+        schedules = append(schedules, t.schedule(row))
+    }
+
+    if len(schedules) == 0 {
+        return nil, models.NotFound("teacher not found")
+    }
+    return schedules, nil
+}
+
+// RecalculateWorkingHours пересчитывает остаток часов как бюджет минус длительность действующих занятий.
+// При apply = false только возвращает расхождения
+func (r *TeacherRepository) RecalculateWorkingHours(apply bool) ([]models.HoursChange, error) {
+    t := r.DB.lock()
+    defer r.DB.mu.Unlock()
+
+    ids := make([]int, 0, len(t.teachers))
+    for id, row := range t.teachers {
+        if row.deletedAt == nil {
+            ids = append(ids, id)
+        }
+    }
+    sort.Ints(ids)
+
+    changes := []models.HoursChange{}
+    for _, id := range ids {
+        row := t.teachers[id]
+        expected := row.budget
+        for _, schedule := range t.activeSchedules() {
+            if schedule.teacherID == id {
+                expected -= schedule.EndTime.Sub(schedule.StartTime).Hours()
+            }
+        }
+        // Сравниваем с точностью до минуты, чтобы не ловить ошибки округления
+        if math.Abs(row.WorkingHours-expected) > 1.0/60 {
+            changes = append(changes, models.HoursChange{TeacherID: id, TeacherName: row.Name, OldHours: row.WorkingHours, NewHours: expected})
+        }
+    }
+
+    if apply {
+        for _, change := range changes {
+            row := t.teachers[change.TeacherID]
+            row.WorkingHours = change.NewHours
+            t.teachers[change.TeacherID] = row
+        }
+    }
+    return changes, nil
+}
@@ -0,0 +1,179 @@
+package repositories
+
+import (
+    "backend/models"
+    "database/sql"
+    "fmt"
+
+    "github.com/lib/pq"
+)
+
+type PermissionRepository struct {
+    DB DBTX
+}
+
+func NewPermissionRepository(db DBTX) *PermissionRepository {
+    return &PermissionRepository{DB: db}
+}
+
+// GetRolePermissions возвращает права роли
+func (r *PermissionRepository) GetRolePermissions(role string) ([]string, error) {
+    rows, err := r.DB.Query(`SELECT permission FROM role_permissions WHERE role = $1 ORDER BY permission`, role)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    permissions := []string{}
+    for rows.Next() {
+        var permission string
+        if err := rows.Scan(&permission); err != nil {
+            return nil, err
+        }
+        permissions = append(permissions, permission)
+    }
+    return permissions, rows.Err()
+}
+
+const roleQuery = `
+    SELECT r.id, r.name, r.description, COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+    FROM roles r
+    LEFT JOIN role_permissions rp ON rp.role = r.name
+`
+
+// GetRoles возвращает все роли вместе с правами
+func (r *PermissionRepository) GetRoles() ([]models.Role, error) {
+    rows, err := r.DB.Query(roleQuery + ` GROUP BY r.id, r.name, r.description ORDER BY r.name`)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    roles := []models.Role{}
+    for rows.Next() {
+        var role models.Role
+        if err := rows.Scan(&role.ID, &role.Name, &role.Description, pq.Array(&role.Permissions)); err != nil {
+            return nil, err
+        }
+        roles = append(roles, role)
+    }
+    return roles, rows.Err()
+}
+
+// GetRole возвращает роль с правами
+func (r *PermissionRepository) GetRole(name string) (*models.Role, error) {
+    var role models.Role
+    err := r.DB.QueryRow(roleQuery+` WHERE r.name = $1 GROUP BY r.id, r.name, r.description`, name).
+        Scan(&role.ID, &role.Name, &role.Description, pq.Array(&role.Permissions))
+    if err == sql.ErrNoRows {
+        return nil, models.NotFound("role '%s' not found", name)
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &role, nil
+}
+
+// GetPermissions возвращает справочник прав
+func (r *PermissionRepository) GetPermissions() ([]models.Permission, error) {
+    rows, err := r.DB.Query(`SELECT name, description FROM permissions ORDER BY name`)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    permissions := []models.Permission{}
+    for rows.Next() {
+        var permission models.Permission
+        if err := rows.Scan(&permission.Name, &permission.Description); err != nil {
+            return nil, err
+        }
+        permissions = append(permissions, permission)
+    }
+    return permissions, rows.Err()
+}
+
+// CreateRole создает роль с набором прав
+func (r *PermissionRepository) CreateRole(role *models.Role) error {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    err = tx.QueryRow(`INSERT INTO roles (name, description) VALUES ($1, $2) RETURNING id`, role.Name, role.Description).Scan(&role.ID)
+    if err != nil {
+        return fmt.Errorf("failed to create role: %w", err)
+    }
+
+    if err := setRolePermissions(tx.Tx, role.Name, role.Permissions); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+// SetRolePermissions заменяет набор прав роли
+func (r *PermissionRepository) SetRolePermissions(role string, permissions []string) error {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var exists bool
+    if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM roles WHERE name = $1)`, role).Scan(&exists); err != nil {
+        return err
+    }
+    if !exists {
+        return models.NotFound("role '%s' not found", role)
+    }
+
+    if _, err := tx.Exec(`DELETE FROM role_permissions WHERE role = $1`, role); err != nil {
+        return err
+    }
+    if err := setRolePermissions(tx.Tx, role, permissions); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+func setRolePermissions(tx *sql.Tx, role string, permissions []string) error {
+    if len(permissions) == 0 {
+        return nil
+    }
+
+    var known int
+    if err := tx.QueryRow(`SELECT COUNT(*) FROM permissions WHERE name = ANY($1)`, pq.Array(permissions)).Scan(&known); err != nil {
+        return err
+    }
+    if known != len(permissions) {
+        return models.Invalid("invalid permissions: some permissions do not exist")
+    }
+
+    _, err := tx.Exec(`
+        INSERT INTO role_permissions (role, permission)
+        SELECT $1, unnest($2::text[])
+    `, role, pq.Array(permissions))
+    return err
+}
+
+// DeleteRole удаляет роль, если она не назначена пользователям
+func (r *PermissionRepository) DeleteRole(name string) error {
+    var users int
+    if err := r.DB.QueryRow(`SELECT COUNT(*) FROM users WHERE role = $1`, name).Scan(&users); err != nil {
+        return err
+    }
+    if users > 0 {
+        return models.Conflict("role '%s' cannot be deleted: assigned to %d users", name, users)
+    }
+
+    result, err := r.DB.Exec(`DELETE FROM roles WHERE name = $1`, name)
+    if err != nil {
+        return err
+    }
+    rowsAffected, _ := result.RowsAffected()
+    if rowsAffected == 0 {
+        return models.NotFound("role '%s' not found", name)
+    }
+    return nil
+}
\ No newline at end of file
@@ -4,31 +4,50 @@ import (
     "backend/models"
     "database/sql"
     "errors"
-	"fmt"
-	"strings"
+    "fmt"
     "time"
 )
 
 type ScheduleRepository struct {
-    DB *sql.DB
+    DB DBTX
 }
 
-func NewScheduleRepository(db *sql.DB) *ScheduleRepository {
+func NewScheduleRepository(db DBTX) *ScheduleRepository {
     return &ScheduleRepository{DB: db}
 }
 
 // CreateSchedule создает новую запись в расписании
 func (r *ScheduleRepository) CreateSchedule(teacherID, classroomID int, schedule *models.Schedule) error {
+    // Внешние ключи не знают о корзине, поэтому проверяем аудиторию и группу явно
+    var classroomExists, courseExists bool
+    err := r.DB.QueryRow(`
+        SELECT
+            EXISTS(SELECT 1 FROM classrooms WHERE id = $1 AND deleted_at IS NULL),
+            EXISTS(SELECT 1 FROM courses WHERE name = $2 AND deleted_at IS NULL)
+    `, classroomID, schedule.GroupName).Scan(&classroomExists, &courseExists)
+    if err != nil {
+        return err
+    }
+    if !classroomExists {
+        return models.NotFound("classroom not found")
+    }
+    if !courseExists {
+        return models.Invalid("course with name '%s' does not exist", schedule.GroupName)
+    }
+
     query := `
-        INSERT INTO schedules (teacher_id, classroom_id, group_name, start_time, end_time, day_of_week)
-        VALUES ($1, $2, $3, $4, $5, $6)
+        INSERT INTO schedules (teacher_id, classroom_id, group_name, start_time, end_time, day_of_week, week_type)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
         RETURNING id
     `
-    err := r.DB.QueryRow(query, teacherID, classroomID, schedule.GroupName, schedule.StartTime, schedule.EndTime, schedule.DayOfWeek).Scan(&schedule.ID)
+    err = r.DB.QueryRow(query, teacherID, classroomID, schedule.GroupName, schedule.StartTime, schedule.EndTime, schedule.DayOfWeek, schedule.WeekType).Scan(&schedule.ID)
     if err != nil {
         return err
     }
 
+    schedule.TeacherID = teacherID
+    schedule.ClassroomID = classroomID
+
     // Подтягиваем teacher_name и classroom_name для ответа
     query = `
         SELECT t.name AS teacher_name, c.name AS classroom_name
@@ -45,127 +64,170 @@ func (r *ScheduleRepository) CreateSchedule(teacherID, classroomID int, schedule
     return nil
 }
 
-func (r *ScheduleRepository) CheckScheduleConflict(teacherID int, dayOfWeek string, startTime, endTime time.Time) (bool, error) {
+// scheduleOverlap условие пересечения занятий a и b (псевдонимы строк с колонками schedules):
+// тот же день недели, пересекающееся время суток (занятия повторяются каждую неделю, дата в start_time
+// не важна, как в Schedule.OccursOn), а занятия по чётным и нечётным неделям не пересекаются.
+// Одно условие и для проверки при записи, и для проверки целостности
+func scheduleOverlap(a, b string) string {
+    return fmt.Sprintf(`%[1]s.day_of_week = %[2]s.day_of_week
+              AND %[1]s.start_time::time < %[2]s.end_time::time AND %[2]s.start_time::time < %[1]s.end_time::time
+              AND (%[1]s.week_type = 'all' OR %[2]s.week_type = 'all' OR %[1]s.week_type = %[2]s.week_type)`, a, b)
+}
+
+// CheckScheduleConflict проверяет пересечение с занятиями преподавателя (scheduleOverlap).
+// excludeID - изменяемое занятие (0 при создании). Чтобы параллельная запись не прошла ту же проверку,
+// вызывать внутри UnitOfWork после TeacherRepository.LockTeacher
+func (r *ScheduleRepository) CheckScheduleConflict(teacherID, excludeID int, dayOfWeek, weekType string, startTime, endTime time.Time) (bool, error) {
     query := `
         SELECT EXISTS (
             SELECT 1
-            FROM schedules
-            WHERE teacher_id = $1
-              AND day_of_week = $2
-              AND (
-                  ($3 < end_time AND $4 > start_time)
-              )
+            FROM schedules s,
+                (SELECT $2::text AS day_of_week, $3::timestamp AS start_time, $4::timestamp AS end_time, $5::text AS week_type) n
+            WHERE s.teacher_id = $1
+              AND s.id <> $6
+              AND s.deleted_at IS NULL
+              AND ` + scheduleOverlap("s", "n") + `
         )
     `
     var exists bool
-    err := r.DB.QueryRow(query, teacherID, dayOfWeek, startTime, endTime).Scan(&exists)
+    err := r.DB.QueryRow(query, teacherID, dayOfWeek, startTime, endTime, weekType, excludeID).Scan(&exists)
     if err != nil {
         return false, err
     }
     return exists, nil
 }
 
-func (r *ScheduleRepository) GetSchedules() ([]models.Schedule, error) {
-    query := `
-        SELECT s.id, t.name AS teacher_name, c.name AS classroom_name, s.group_name, s.start_time, s.end_time, s.day_of_week
+// scheduleList поля сортировки и фильтры списка занятий
+var scheduleList = listSpec{
+    sorts: map[string]string{
+        "id":             "s.id",
+        "day":            "array_position(ARRAY['Monday','Tuesday','Wednesday','Thursday','Friday','Saturday','Sunday']::varchar[], s.day_of_week)",
+        "start_time":     "s.start_time::time",
+        "group_name":     "s.group_name",
+        "teacher_name":   "t.name",
+        "classroom_name": "c.name",
+    },
+    filters: map[string]listFilter{
+        "teacher_id":   intFilter("s.teacher_id = ?"),
+        "classroom_id": intFilter("s.classroom_id = ?"),
+        "group":        textFilter("s.group_name = ?"),
+        "day":          textFilter("s.day_of_week = ?"),
+        "week_type":    textFilter("s.week_type = ?"),
+        "date_from":    dateFilter("s.start_time >= ?::date"),
+        "date_to":      dateFilter("s.start_time < ?::date + 1"),
+    },
+    id: "s.id",
+}
+
+const scheduleListQuery = `
+        SELECT s.id, s.teacher_id, t.name AS teacher_name, s.classroom_id, c.name AS classroom_name, s.group_name, s.start_time, s.end_time, s.day_of_week, s.week_type
         FROM schedules s
         LEFT JOIN teachers t ON s.teacher_id = t.id
         LEFT JOIN classrooms c ON s.classroom_id = c.id
-    `
-    rows, err := r.DB.Query(query)
-    if err != nil {
+        WHERE s.deleted_at IS NULL`
+
+func scanSchedule(row rowScanner) (*models.Schedule, error) {
+    var schedule models.Schedule
+    if err := row.Scan(&schedule.ID, &schedule.TeacherID, &schedule.TeacherName, &schedule.ClassroomID, &schedule.ClassroomName, &schedule.GroupName, &schedule.StartTime, &schedule.EndTime, &schedule.DayOfWeek, &schedule.WeekType); err != nil {
         return nil, err
     }
-    defer rows.Close()
+    return &schedule, nil
+}
 
-    var schedules []models.Schedule
-    for rows.Next() {
-        var schedule models.Schedule
-        if err := rows.Scan(&schedule.ID, &schedule.TeacherName, &schedule.ClassroomName, &schedule.GroupName, &schedule.StartTime, &schedule.EndTime, &schedule.DayOfWeek); err != nil {
-            return nil, err
+// ListSchedules возвращает страницу занятий и их общее количество с учётом фильтров
+func (r *ScheduleRepository) ListSchedules(query models.ListQuery) ([]models.Schedule, int, error) {
+    schedules := []models.Schedule{}
+    total, err := queryList(r.DB, scheduleList, scheduleListQuery, query, func(rows *sql.Rows) error {
+        schedule, err := scanSchedule(rows)
+        if err != nil {
+            return err
         }
-        schedules = append(schedules, schedule)
+        schedules = append(schedules, *schedule)
+        return nil
+    })
+    if err != nil {
+        return nil, 0, err
     }
-    return schedules, nil
+    return schedules, total, nil
+}
+
+// EachSchedule передаёт fn занятия с учётом фильтров и сортировки по мере чтения из базы
+func (r *ScheduleRepository) EachSchedule(query models.ListQuery, fn func(models.Schedule) error) error {
+    return eachList(r.DB, scheduleList, scheduleListQuery, query, func(rows *sql.Rows) error {
+        schedule, err := scanSchedule(rows)
+        if err != nil {
+            return err
+        }
+        return fn(*schedule)
+    })
+}
+
+func (r *ScheduleRepository) GetSchedules() ([]models.Schedule, error) {
+    schedules, _, err := r.ListSchedules(models.ListQuery{})
+    return schedules, err
 }
 
 func (r *ScheduleRepository) GetScheduleByID(id int) (*models.Schedule, error) {
     query := `
-        SELECT s.id, t.name AS teacher_name, c.name AS classroom_name, s.group_name, s.start_time, s.end_time, s.day_of_week
+        SELECT s.id, s.teacher_id, t.name AS teacher_name, s.classroom_id, c.name AS classroom_name, s.group_name, s.start_time, s.end_time, s.day_of_week, s.week_type
         FROM schedules s
         LEFT JOIN teachers t ON s.teacher_id = t.id
         LEFT JOIN classrooms c ON s.classroom_id = c.id
-        WHERE s.id = $1
+        WHERE s.id = $1 AND s.deleted_at IS NULL
     `
     row := r.DB.QueryRow(query, id)
 
     var schedule models.Schedule
-    if err := row.Scan(&schedule.ID, &schedule.TeacherName, &schedule.ClassroomName, &schedule.GroupName, &schedule.StartTime, &schedule.EndTime, &schedule.DayOfWeek); err != nil {
+    if err := row.Scan(&schedule.ID, &schedule.TeacherID, &schedule.TeacherName, &schedule.ClassroomID, &schedule.ClassroomName, &schedule.GroupName, &schedule.StartTime, &schedule.EndTime, &schedule.DayOfWeek, &schedule.WeekType); err != nil {
         if errors.Is(err, sql.ErrNoRows) {
-            return nil, errors.New("schedule not found")
+            return nil, models.NotFound("schedule not found")
         }
         return nil, err
     }
     return &schedule, nil
 }
 
-func (r *ScheduleRepository) UpdateSchedule(id int, updates map[string]interface{}) (*models.Schedule, error) {
-    setClauses := []string{}
-    args := []interface{}{}
-    paramIndex := 1
-
-    // Собираем условия для обновления
-    for key, value := range updates {
-        switch key {
-        case "teacher_id":
-            setClauses = append(setClauses, fmt.Sprintf("teacher_id = $%d", paramIndex))
-            args = append(args, value)
-            paramIndex++
-        case "classroom_id":
-            setClauses = append(setClauses, fmt.Sprintf("classroom_id = $%d", paramIndex))
-            args = append(args, value)
-            paramIndex++
-        case "group_name":
-            setClauses = append(setClauses, fmt.Sprintf("group_name = $%d", paramIndex))
-            args = append(args, value)
-            paramIndex++
-        case "start_time":
-            setClauses = append(setClauses, fmt.Sprintf("start_time = $%d", paramIndex))
-            args = append(args, value)
-            paramIndex++
-        case "end_time":
-            setClauses = append(setClauses, fmt.Sprintf("end_time = $%d", paramIndex))
-            args = append(args, value)
-            paramIndex++
-        case "day_of_week":
-            setClauses = append(setClauses, fmt.Sprintf("day_of_week = $%d", paramIndex))
-            args = append(args, value)
-            paramIndex++
-        default:
-            return nil, errors.New("invalid field: " + key)
-        }
+func (r *ScheduleRepository) UpdateSchedule(id int, update models.ScheduleUpdate) (*models.Schedule, error) {
+    var set updateSet
+    if update.TeacherID != nil {
+        set.set("teacher_id = ?", *update.TeacherID)
     }
-
-    if len(setClauses) == 0 {
-        return nil, errors.New("no fields to update")
+    if update.ClassroomID != nil {
+        set.set("classroom_id = ?", *update.ClassroomID)
+    }
+    if update.GroupName != nil {
+        set.set("group_name = ?", *update.GroupName)
+    }
+    if update.StartTime != nil {
+        set.set("start_time = ?", *update.StartTime)
+    }
+    if update.EndTime != nil {
+        set.set("end_time = ?", *update.EndTime)
+    }
+    if update.DayOfWeek != nil {
+        set.set("day_of_week = ?", *update.DayOfWeek)
+    }
+    if update.WeekType != nil {
+        set.set("week_type = ?", *update.WeekType)
     }
 
-    // Формируем SQL-запрос для обновления
-    query := fmt.Sprintf(`UPDATE schedules SET %s WHERE id = $%d RETURNING id`, strings.Join(setClauses, ", "), paramIndex)
-    args = append(args, id)
+    query, args, err := set.query(`UPDATE schedules SET %s WHERE id = $%d AND deleted_at IS NULL RETURNING id`, id)
+    if err != nil {
+        return nil, err
+    }
 
     var scheduleID int
-    err := r.DB.QueryRow(query, args...).Scan(&scheduleID)
+    err = r.DB.QueryRow(query, args...).Scan(&scheduleID)
     if err != nil {
         if errors.Is(err, sql.ErrNoRows) {
-            return nil, errors.New("schedule not found")
+            return nil, models.NotFound("schedule not found")
         }
         return nil, err
     }
 
     // Подтягиваем обновленные данные с именами
     query = `
-        SELECT s.id, t.name AS teacher_name, c.name AS classroom_name, s.group_name, s.start_time, s.end_time, s.day_of_week
+        SELECT s.id, s.teacher_id, t.name AS teacher_name, s.classroom_id, c.name AS classroom_name, s.group_name, s.start_time, s.end_time, s.day_of_week, s.week_type
         FROM schedules s
         LEFT JOIN teachers t ON s.teacher_id = t.id
         LEFT JOIN classrooms c ON s.classroom_id = c.id
@@ -174,66 +236,146 @@ func (r *ScheduleRepository) UpdateSchedule(id int, updates map[string]interface
     row := r.DB.QueryRow(query, scheduleID)
 
     var schedule models.Schedule
-    if err := row.Scan(&schedule.ID, &schedule.TeacherName, &schedule.ClassroomName, &schedule.GroupName, &schedule.StartTime, &schedule.EndTime, &schedule.DayOfWeek); err != nil {
+    if err := row.Scan(&schedule.ID, &schedule.TeacherID, &schedule.TeacherName, &schedule.ClassroomID, &schedule.ClassroomName, &schedule.GroupName, &schedule.StartTime, &schedule.EndTime, &schedule.DayOfWeek, &schedule.WeekType); err != nil {
         return nil, err
     }
 
     return &schedule, nil
 }
 
-// DeleteSchedule удаляет запись расписания по ID
-func (r *ScheduleRepository) DeleteSchedule(id int) error {
-    query := `DELETE FROM schedules WHERE id = $1`
-    result, err := r.DB.Exec(query, id)
+// DeleteSchedule помещает запись расписания в корзину
+func (r *ScheduleRepository) DeleteSchedule(id int) (models.Cascade, error) {
+    cascade, err := softDelete(r.DB, "schedules", id)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, models.NotFound("schedule not found")
+    }
+    return cascade, err
+}
+
+func (r *ScheduleRepository) GetFilteredSchedules(dayOfWeek, groupName string) ([]models.Schedule, error) {
+    schedules, _, err := r.ListSchedules(models.ListQuery{Filters: map[string]string{"day": dayOfWeek, "group": groupName}})
+    return schedules, err
+}
+
+func (r *ScheduleRepository) GetScheduleTeacherID(id int) (int, error) {
+    var teacherID int
+    err := r.DB.QueryRow(`SELECT teacher_id FROM schedules WHERE id = $1 AND deleted_at IS NULL`, id).Scan(&teacherID)
     if err != nil {
-        return err
+        if errors.Is(err, sql.ErrNoRows) {
+            return 0, models.NotFound("schedule not found")
+        }
+        return 0, err
     }
+    return teacherID, nil
+}
 
-    rowsAffected, _ := result.RowsAffected()
-    if rowsAffected == 0 {
-        return errors.New("schedule not found")
+const overrideSelect = `
+    SELECT o.id, o.schedule_id, to_char(o.date, 'YYYY-MM-DD'), o.cancelled, o.classroom_id, c.name,
+           o.start_time, o.end_time, o.reason, o.created_at
+    FROM schedule_overrides o
+    JOIN schedules s ON o.schedule_id = s.id
+    LEFT JOIN classrooms c ON o.classroom_id = c.id
+`
+
+func scanOverride(row rowScanner) (*models.ScheduleOverride, error) {
+    var override models.ScheduleOverride
+    var classroomID sql.NullInt64
+    var classroomName sql.NullString
+    var startTime, endTime sql.NullTime
+    err := row.Scan(&override.ID, &override.ScheduleID, &override.Date, &override.Cancelled, &classroomID, &classroomName,
+        &startTime, &endTime, &override.Reason, &override.CreatedAt)
+    if err != nil {
+        return nil, err
     }
-    return nil
+    if classroomID.Valid {
+        value := int(classroomID.Int64)
+        override.ClassroomID = &value
+    }
+    if classroomName.Valid {
+        override.ClassroomName = &classroomName.String
+    }
+    if startTime.Valid {
+        override.StartTime = &startTime.Time
+    }
+    if endTime.Valid {
+        override.EndTime = &endTime.Time
+    }
+    return &override, nil
 }
 
-func (r *ScheduleRepository) GetFilteredSchedules(dayOfWeek, groupName string) ([]models.Schedule, error) {
+// SaveOverride создает или заменяет изменение занятия на дату
+func (r *ScheduleRepository) SaveOverride(override *models.ScheduleOverride) error {
+    var exists bool
+    if err := r.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM schedules WHERE id = $1 AND deleted_at IS NULL)`, override.ScheduleID).Scan(&exists); err != nil {
+        return err
+    }
+    if !exists {
+        return models.NotFound("schedule not found")
+    }
+
+    if override.ClassroomID != nil {
+        if err := r.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM classrooms WHERE id = $1 AND deleted_at IS NULL)`, *override.ClassroomID).Scan(&exists); err != nil {
+            return err
+        }
+        if !exists {
+            return models.NotFound("classroom not found")
+        }
+    }
+
     query := `
-        SELECT s.id, t.name AS teacher_name, c.name AS classroom_name, s.group_name, s.start_time, s.end_time, s.day_of_week
-        FROM schedules s
-        LEFT JOIN teachers t ON s.teacher_id = t.id
-        LEFT JOIN classrooms c ON s.classroom_id = c.id
-        WHERE 1=1
+        INSERT INTO schedule_overrides (schedule_id, date, cancelled, classroom_id, start_time, end_time, reason)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (schedule_id, date) DO UPDATE
+        SET cancelled = EXCLUDED.cancelled, classroom_id = EXCLUDED.classroom_id,
+            start_time = EXCLUDED.start_time, end_time = EXCLUDED.end_time, reason = EXCLUDED.reason
+        RETURNING id
     `
-    args := []interface{}{}
-    paramIndex := 1
-
-    if dayOfWeek != "" {
-        query += fmt.Sprintf(" AND s.day_of_week = $%d", paramIndex)
-        args = append(args, dayOfWeek)
-        paramIndex++
+    var id int
+    err := r.DB.QueryRow(query, override.ScheduleID, override.Date, override.Cancelled, override.ClassroomID,
+        override.StartTime, override.EndTime, override.Reason).Scan(&id)
+    if err != nil {
+        return err
     }
 
-    if groupName != "" {
-        query += fmt.Sprintf(" AND s.group_name = $%d", paramIndex)
-        args = append(args, groupName)
-        paramIndex++
+    saved, err := scanOverride(r.DB.QueryRow(overrideSelect+` WHERE o.id = $1`, id))
+    if err != nil {
+        return err
     }
+    *override = *saved
+    return nil
+}
 
-    rows, err := r.DB.Query(query, args...)
+// GetOverrides возвращает изменения расписания группы за период (даты включительно); пустая группа - все группы
+func (r *ScheduleRepository) GetOverrides(groupName, from, to string) ([]models.ScheduleOverride, error) {
+    query := overrideSelect + `
+        WHERE ($1 = '' OR s.group_name = $1) AND s.deleted_at IS NULL AND o.date BETWEEN $2 AND $3
+        ORDER BY o.date, o.schedule_id
+    `
+    rows, err := r.DB.Query(query, groupName, from, to)
     if err != nil {
         return nil, err
     }
     defer rows.Close()
 
-    var schedules []models.Schedule
+    overrides := []models.ScheduleOverride{}
     for rows.Next() {
-        var schedule models.Schedule
-        if err := rows.Scan(&schedule.ID, &schedule.TeacherName, &schedule.ClassroomName, &schedule.GroupName, &schedule.StartTime, &schedule.EndTime, &schedule.DayOfWeek); err != nil {
+        override, err := scanOverride(rows)
+        if err != nil {
             return nil, err
         }
-        schedules = append(schedules, schedule)
+        overrides = append(overrides, *override)
     }
-    return schedules, nil
+    return overrides, rows.Err()
 }
 
-
+func (r *ScheduleRepository) DeleteOverride(scheduleID int, date string) error {
+    result, err := r.DB.Exec(`DELETE FROM schedule_overrides WHERE schedule_id = $1 AND date = $2`, scheduleID, date)
+    if err != nil {
+        return err
+    }
+    rowsAffected, _ := result.RowsAffected()
+    if rowsAffected == 0 {
+        return models.NotFound("schedule override not found")
+    }
+    return nil
+}
\ No newline at end of file
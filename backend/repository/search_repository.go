@@ -0,0 +1,125 @@
+package repositories
+
+import (
+    "backend/models"
+    "database/sql"
+    "fmt"
+    "strings"
+
+    "github.com/lib/pq"
+)
+
+// SearchThreshold минимальная похожесть по триграммам; ниже неё совпадение считается случайным.
+// Значение по умолчанию в pg_trgm (0.6) отсекает слова с опечатками
+const SearchThreshold = 0.3
+
+type SearchRepository struct {
+    DB *sql.DB
+}
+
+func NewSearchRepository(db *sql.DB) *SearchRepository {
+    return &SearchRepository{DB: db}
+}
+
+// searchSource таблица, по которой ищем: main - основное поле (ФИО, название), secondary - поля с меньшим весом
+type searchSource struct {
+    columns   string // id, title, subtitle
+    from      string
+    main      string
+    secondary []string
+}
+
+var searchSources = map[string]searchSource{
+    models.SearchStudent:   {columns: "s.id, s.name, s.group_name", from: "students s", main: "s.name"},
+    models.SearchTeacher:   {columns: "t.id, t.name, t.subject", from: "teachers t", main: "t.name", secondary: []string{"t.subject"}},
+    models.SearchCourse:    {columns: "c.id, c.name, COALESCE(c.description, '')", from: "courses c", main: "c.name", secondary: []string{"c.description"}},
+    models.SearchClassroom: {columns: "r.id, r.name, r.capacity::text", from: "classrooms r", main: "r.name", secondary: []string{"r.description"}},
+}
+
+// searchScore релевантность поля для варианта строки поиска v: начало строки - 1,
+// вхождение - 0.9, иначе похожесть по триграммам (допускает опечатки)
+func searchScore(column string) string {
+    return fmt.Sprintf(`GREATEST(
+            CASE WHEN strpos(lower(%[1]s), v) = 1 THEN 1.0 WHEN strpos(lower(%[1]s), v) > 0 THEN 0.9 ELSE 0 END,
+            word_similarity(v, lower(%[1]s)))`, column)
+}
+
+// query собирает запрос по таблице. Параметры: $1 - массив вариантов строки поиска, с $4 - те же варианты по одному.
+// Условие <% по каждому варианту использует триграммные индексы, релевантность считается по всем вариантам
+func (source searchSource) query(searchType string, variants int) string {
+    columns := append([]string{source.main}, source.secondary...)
+
+    scores := []string{searchScore(source.main)}
+    for _, column := range source.secondary {
+        scores = append(scores, "0.8 * "+searchScore("COALESCE("+column+", '')"))
+    }
+
+    var conditions []string
+    for _, column := range columns {
+        for i := 0; i < variants; i++ {
+            conditions = append(conditions, fmt.Sprintf("$%d <%% lower(%s)", i+4, column))
+        }
+    }
+
+    alias := strings.Fields(source.from)[1]
+    return fmt.Sprintf(`
+        SELECT '%s', %s, (SELECT MAX(GREATEST(%s)) FROM unnest($1::text[]) v)
+        FROM %s
+        WHERE %s.deleted_at IS NULL AND (%s)`,
+        searchType, source.columns, strings.Join(scores, ", "), source.from, alias, strings.Join(conditions, " OR "))
+}
+
+// Search ищет по всем вариантам строки (исходная и транслитерации) среди записей указанных типов
+func (r *SearchRepository) Search(variants []string, types []string, limit int) ([]models.SearchResult, error) {
+    var parts []string
+    for _, searchType := range types {
+        source, ok := searchSources[searchType]
+        if !ok {
+            return nil, models.Invalid("invalid search type: %s", searchType)
+        }
+        parts = append(parts, source.query(searchType, len(variants)))
+    }
+    results := []models.SearchResult{}
+    if len(parts) == 0 || len(variants) == 0 {
+        return results, nil
+    }
+
+    query := `
+        SELECT type, id, title, subtitle, rank
+        FROM (` + strings.Join(parts, "\n UNION ALL") + `) AS found (type, id, title, subtitle, rank)
+        WHERE rank >= $2
+        ORDER BY rank DESC, title, id
+        LIMIT $3`
+    args := []interface{}{pq.Array(variants), SearchThreshold, limit}
+    for _, variant := range variants {
+        args = append(args, variant)
+    }
+
+    // Порог похожести для <% задаётся настройкой, поэтому поиск идёт в отдельной транзакции
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+    if _, err := tx.Exec(fmt.Sprintf("SET LOCAL pg_trgm.word_similarity_threshold = %v", SearchThreshold)); err != nil {
+        return nil, err
+    }
+
+    rows, err := tx.Query(query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var result models.SearchResult
+        if err := rows.Scan(&result.Type, &result.ID, &result.Title, &result.Subtitle, &result.Rank); err != nil {
+            return nil, err
+        }
+        results = append(results, result)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+    return results, tx.Commit()
+}
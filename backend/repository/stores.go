@@ -0,0 +1,214 @@
+package repositories
+
+import (
+    "backend/models"
+    "time"
+)
+
+// Интерфейсы репозиториев: сервисы зависят от них, а не от реализаций на Postgres.
+// Реализация в памяти для тестов сервисов - пакет repository/memory
+
+// TeacherStore преподаватели и их часы
+type TeacherStore interface {
+    CreateTeacher(teacher *models.Teacher) error
+    LockTeacher(id int) error
+    UpdateTeacherWorkingHours(teacherID int, hours float64) error
+    ReturnTeacherWorkingHours(teacherID int, hours float64) error
+    CheckTeacherExists(name, subject string) (bool, error)
+    CheckCoursesExist(courseNames []string) (bool, error)
+    GetAllTeachersWithCourses() ([]models.Teacher, error)
+    ListTeachers(query models.ListQuery) ([]models.Teacher, int, error)
+    EachTeacher(query models.ListQuery, fn func(models.Teacher) error) error // Для выгрузки в файл, без страницы в памяти
+    GetAllTeachers() ([]models.Teacher, error)
+    GetTeacherByID(teacherID int) (*models.Teacher, error)
+    UpdateTeacherPartial(id int, update models.TeacherUpdate) (*models.Teacher, error)
+    GetTeacherDeletionImpact(id int) (models.Cascade, error)
+    DeleteTeacher(id int) (models.Cascade, error)
+    GetTeacherSchedule(teacherName string) ([]models.Schedule, error)
+    RecalculateWorkingHours(apply bool) ([]models.HoursChange, error)
+}
+
+// StudentStore студенты, приказы и история переводов
+type StudentStore interface {
+    CreateStudent(student *models.Student) error
+    ListStudents(query models.ListQuery) ([]models.Student, int, error)
+    EachStudent(query models.ListQuery, fn func(models.Student) error) error // Для выгрузки в файл, без страницы в памяти
+    GetStudents(status string) ([]models.Student, error)
+    GetStudentByID(id int) (*models.Student, error)
+    UpdateStudent(id int, update models.StudentUpdate) (*models.Student, error)
+    DeleteStudent(id int) (models.Cascade, error)
+    CourseExists(courseName string) (bool, error)
+    ChangeStudentStatus(id int, order *models.StudentOrder) error
+    TransferStudent(id int, newGroup string, order *models.StudentOrder) error
+    GetStudentOrders(studentID int) ([]models.StudentOrder, error)
+    GetStudentGroupHistory(studentID int) ([]models.StudentGroupChange, error)
+}
+
+// CourseStore курсы (группы)
+type CourseStore interface {
+    CreateCourse(course *models.Course) error
+    ListCourses(query models.ListQuery) ([]models.Course, int, error)
+    EachCourse(query models.ListQuery, fn func(models.Course) error) error // Для выгрузки в файл, без страницы в памяти
+    GetCourses() ([]models.Course, error)
+    GetCourseByID(id int) (*models.Course, error)
+    UpdateCourse(id int, update models.CourseUpdate) (*models.Course, error)
+    GetCourseDeletionImpact(id int) (models.Cascade, error)
+    DeleteCourse(id int) (models.Cascade, error)
+    GetStudentCourses(studentID int) ([]models.Course, error)
+}
+
+// ClassroomStore аудитории
+type ClassroomStore interface {
+    CreateClassroom(classroom *models.Classroom) error
+    ListClassrooms(query models.ListQuery) ([]models.Classroom, int, error)
+    EachClassroom(query models.ListQuery, fn func(models.Classroom) error) error // Для выгрузки в файл, без страницы в памяти
+    GetClassrooms() ([]models.Classroom, error)
+    GetClassroomByID(id int) (*models.Classroom, error)
+    UpdateClassroom(id int, update models.ClassroomUpdate) (*models.Classroom, error)
+    GetClassroomDeletionImpact(id int) (models.Cascade, error)
+    DeleteClassroom(id int) (models.Cascade, error)
+}
+
+// ScheduleStore занятия и их изменения на даты
+type ScheduleStore interface {
+    CreateSchedule(teacherID, classroomID int, schedule *models.Schedule) error
+    CheckScheduleConflict(teacherID, excludeID int, dayOfWeek, weekType string, startTime, endTime time.Time) (bool, error)
+    ListSchedules(query models.ListQuery) ([]models.Schedule, int, error)
+    EachSchedule(query models.ListQuery, fn func(models.Schedule) error) error // Для выгрузки в файл, без страницы в памяти
+    GetSchedules() ([]models.Schedule, error)
+    GetScheduleByID(id int) (*models.Schedule, error)
+    UpdateSchedule(id int, update models.ScheduleUpdate) (*models.Schedule, error)
+    DeleteSchedule(id int) (models.Cascade, error)
+    GetFilteredSchedules(dayOfWeek, groupName string) ([]models.Schedule, error)
+    GetScheduleTeacherID(id int) (int, error)
+    SaveOverride(override *models.ScheduleOverride) error
+    GetOverrides(groupName, from, to string) ([]models.ScheduleOverride, error)
+    DeleteOverride(scheduleID int, date string) error
+}
+
+// UserStore учётные записи
+type UserStore interface {
+    CreateUser(user *models.User) error
+    GetUserByUsername(username string) (*models.User, error)
+    GetUserByID(id int) (*models.User, error)
+    UpdatePassword(id int, passwordHash string) error
+    RoleExists(role string) (bool, error)
+    UpdateUserRole(id int, role string, teacherID *int) (*models.User, error)
+    UpdateProfile(id int, username, passwordHash *string) error
+}
+
+// PermissionStore роли и права
+type PermissionStore interface {
+    GetRolePermissions(role string) ([]string, error)
+    GetRoles() ([]models.Role, error)
+    GetRole(name string) (*models.Role, error)
+    GetPermissions() ([]models.Permission, error)
+    CreateRole(role *models.Role) error
+    SetRolePermissions(role string, permissions []string) error
+    DeleteRole(name string) error
+}
+
+// GuardianStore родители и законные представители
+type GuardianStore interface {
+    CreateGuardian(guardian *models.Guardian) error
+    GetGuardianByID(id int) (*models.Guardian, error)
+    GetStudentGuardians(studentID int) ([]models.Guardian, error)
+    UpdateGuardian(guardian *models.Guardian) error
+    DeleteGuardian(id int) error
+    CreateAccount(guardianID int, user *models.User) error
+    LinkAccount(guardianID, userID int) (*models.Guardian, error)
+    GetLinkedStudents(userID int) ([]models.Student, error)
+    IsLinked(userID, studentID int) (bool, error)
+    GetAbsenceContacts(studentIDs []int) ([]models.AbsenceContact, error)
+}
+
+// GradeSheetStore ведомости и оценки
+type GradeSheetStore interface {
+    CreateGradeSheet(sheet *models.GradeSheet) error
+    GetGradeSheets(courseID int, status string) ([]models.GradeSheet, error)
+    GetGradeSheetByID(id int) (*models.GradeSheet, error)
+    GetOpenRetakeID(parentID int) (int, error)
+    UpdateStatus(id int, from, to string) error
+    SetMarks(id int, marks map[int]string) error
+    CloseGradeSheet(id, userID int) error
+    DeleteGradeSheet(id int) error
+    GetStudentGrades(studentID int) ([]models.Grade, error)
+}
+
+// AttendanceStore посещаемость
+type AttendanceStore interface {
+    MarkAttendance(scheduleID int, date string, marks map[int]string, markedBy int) ([]models.Attendance, error)
+    GetScheduleAttendance(scheduleID int, date string) ([]models.Attendance, error)
+    GetStudentAttendance(studentID int, from, to string) ([]models.Attendance, error)
+}
+
+// AnnouncementStore объявления
+type AnnouncementStore interface {
+    CreateAnnouncement(announcement *models.Announcement) error
+    GetAnnouncements(groupName string) ([]models.Announcement, error)
+    DeleteAnnouncement(id int) error
+}
+
+// ActivationStore коды активации учётных записей студентов
+type ActivationStore interface {
+    GetStudentsWithoutAccount(groupName string) ([]models.Student, error)
+    ReplaceCodes(hashes map[int]string, expiresAt time.Time) error
+    Activate(codeHash string, user *models.User) error
+}
+
+// AuditStore журнал изменений
+type AuditStore interface {
+    CreateEntry(entry *models.AuditEntry) error
+    GetEntries(filter models.AuditFilter) ([]models.AuditEntry, error)
+}
+
+// ImportStore массовый импорт
+type ImportStore interface {
+    LoadImportLookup() (*ImportLookup, error)
+    ImportStudents(students []models.Student) error
+    ImportTeachers(teachers []models.Teacher) error
+    ImportCourses(courses []models.Course) error
+    ImportClassrooms(classrooms []models.Classroom) error
+}
+
+// IntegrityStore проверка целостности данных
+type IntegrityStore interface {
+    CheckIntegrity() ([]models.IntegrityIssue, error)
+}
+
+// SearchStore поиск по людям, курсам и аудиториям
+type SearchStore interface {
+    Search(variants []string, types []string, limit int) ([]models.SearchResult, error)
+}
+
+// TrashStore корзина
+type TrashStore interface {
+    GetTrash(entityType string) ([]models.TrashItem, error)
+    Restore(table string, id int) (models.Cascade, error)
+}
+
+// Transactor выполняет fn в одной транзакции (UnitOfWork)
+type Transactor interface {
+    Do(fn func(tx *Tx) error) error
+}
+
+var (
+    _ TeacherStore = (*TeacherRepository)(nil)
+    _ StudentStore = (*StudentRepository)(nil)
+    _ CourseStore = (*CourseRepository)(nil)
+    _ ClassroomStore = (*ClassroomRepository)(nil)
+    _ ScheduleStore = (*ScheduleRepository)(nil)
+    _ UserStore = (*UserRepository)(nil)
+    _ PermissionStore = (*PermissionRepository)(nil)
+    _ GuardianStore = (*GuardianRepository)(nil)
+    _ GradeSheetStore = (*GradeSheetRepository)(nil)
+    _ AttendanceStore = (*AttendanceRepository)(nil)
+    _ AnnouncementStore = (*AnnouncementRepository)(nil)
+    _ ActivationStore = (*ActivationRepository)(nil)
+    _ AuditStore = (*AuditRepository)(nil)
+    _ ImportStore = (*ImportRepository)(nil)
+    _ IntegrityStore = (*IntegrityRepository)(nil)
+    _ SearchStore = (*SearchRepository)(nil)
+    _ TrashStore = (*TrashRepository)(nil)
+    _ Transactor = (*UnitOfWork)(nil)
+)
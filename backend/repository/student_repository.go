@@ -6,15 +6,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 )
 
 type StudentRepository struct {
-	DB *sql.DB
+	DB DBTX
 }
 
-func NewStudentRepository(db *sql.DB) *StudentRepository {
+func NewStudentRepository(db DBTX) *StudentRepository {
 	return &StudentRepository{DB: db}
 }
 
@@ -23,13 +22,13 @@ func (r *StudentRepository) CreateStudent(student *models.Student) error {
 	query := `
         SELECT id 
         FROM courses 
-        WHERE name = $1
+        WHERE name = $1 AND deleted_at IS NULL
     `
 	var courseID int
 	err := r.DB.QueryRow(query, student.GroupName).Scan(&courseID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("course with name '%s' does not exist", student.GroupName)
+			return models.Invalid("course with name '%s' does not exist", student.GroupName)
 		}
 		return err
 	}
@@ -37,74 +36,118 @@ func (r *StudentRepository) CreateStudent(student *models.Student) error {
 	// Преобразуем дату рождения в time.Time
 	dateOfBirth, err := time.Parse("2006-01-02", student.DateOfBirth)
 	if err != nil {
-		return fmt.Errorf("invalid date_of_birth format: %v", err)
+		return models.Invalid("invalid date_of_birth format: %v", err)
 	}
 
 	// Вставляем данные студента в базу данных
 	insertQuery := `
         INSERT INTO students (name, date_of_birth, group_name)
         VALUES ($1, $2, $3)
-        RETURNING id
+        RETURNING id, status
     `
-	err = r.DB.QueryRow(insertQuery, student.Name, dateOfBirth, student.GroupName).Scan(&student.ID)
+	err = r.DB.QueryRow(insertQuery, student.Name, dateOfBirth, student.GroupName).Scan(&student.ID, &student.Status)
 	return err
 }
 
-func (r *StudentRepository) GetStudents() ([]models.Student, error) {
-    query := `
-        SELECT s.id, s.name, s.date_of_birth, s.group_name, c.teacher_id
+// studentList поля сортировки и фильтры списка студентов
+var studentList = listSpec{
+    sorts: map[string]string{
+        "id":            "s.id",
+        "name":          "s.name",
+        "date_of_birth": "s.date_of_birth",
+        "age":           "age(s.date_of_birth)",
+        "group_name":    "s.group_name",
+        "status":        "s.status",
+    },
+    filters: map[string]listFilter{
+        "group":      textFilter("s.group_name = ?"),
+        "status":     textFilter("s.status = ?"),
+        "name":       prefixFilter("s.name"),
+        "teacher_id": intFilter("c.teacher_id = ?"),
+        "age_min":    intFilter("s.date_of_birth <= CURRENT_DATE - make_interval(years => ?::int)"),
+        "age_max":    intFilter("s.date_of_birth > CURRENT_DATE - make_interval(years => ?::int + 1)"),
+    },
+    id: "s.id",
+}
+
+const studentListQuery = `
+        SELECT s.id, s.name, s.date_of_birth, s.group_name, c.teacher_id, s.status
         FROM students s
-        LEFT JOIN courses c ON s.group_name = c.name
-    `
-    rows, err := r.DB.Query(query)
-    if err != nil {
+        LEFT JOIN courses c ON s.group_name = c.name AND c.deleted_at IS NULL
+        WHERE s.deleted_at IS NULL`
+
+// scanStudentListRow читает строку studentListQuery
+func scanStudentListRow(row rowScanner) (*models.Student, error) {
+    var student models.Student
+    var dateOfBirth time.Time
+    var teacherID sql.NullInt64
+    if err := row.Scan(&student.ID, &student.Name, &dateOfBirth, &student.GroupName, &teacherID, &student.Status); err != nil {
         return nil, err
     }
-    defer rows.Close()
-
-    var students []models.Student
-    for rows.Next() {
-        var student models.Student
-        var dateOfBirth time.Time
-        var teacherID sql.NullInt64
-        if err := rows.Scan(&student.ID, &student.Name, &dateOfBirth, &student.GroupName, &teacherID); err != nil {
-            return nil, err
-        }
 
-        // Преобразуем date_of_birth в строку
-        student.DateOfBirth = dateOfBirth.Format("2006-01-02")
+    // Преобразуем date_of_birth в строку
+    student.DateOfBirth = dateOfBirth.Format("2006-01-02")
 
-        // Вычисляем возраст
-        student.Age = utils.CalculateAge(dateOfBirth)
+    // Вычисляем возраст
+    student.Age = utils.CalculateAge(dateOfBirth)
 
-        // Если teacher_id существует, добавляем его в ответ
-        if teacherID.Valid {
-            teacherIDValue := int(teacherID.Int64)
-            student.TeacherID = &teacherIDValue
-        } else {
-            student.TeacherID = nil
-        }
+    // Если teacher_id существует, добавляем его в ответ
+    if teacherID.Valid {
+        teacherIDValue := int(teacherID.Int64)
+        student.TeacherID = &teacherIDValue
+    }
+    return &student, nil
+}
 
-        students = append(students, student)
+// ListStudents возвращает страницу студентов и их общее количество с учётом фильтров
+func (r *StudentRepository) ListStudents(query models.ListQuery) ([]models.Student, int, error) {
+    students := []models.Student{}
+    total, err := queryList(r.DB, studentList, studentListQuery, query, func(rows *sql.Rows) error {
+        student, err := scanStudentListRow(rows)
+        if err != nil {
+            return err
+        }
+        students = append(students, *student)
+        return nil
+    })
+    if err != nil {
+        return nil, 0, err
     }
-    return students, nil
+    return students, total, nil
+}
+
+// EachStudent передаёт fn студентов с учётом фильтров и сортировки по мере чтения из базы
+func (r *StudentRepository) EachStudent(query models.ListQuery, fn func(models.Student) error) error {
+    return eachList(r.DB, studentList, studentListQuery, query, func(rows *sql.Rows) error {
+        student, err := scanStudentListRow(rows)
+        if err != nil {
+            return err
+        }
+        return fn(*student)
+    })
+}
+
+// GetStudents возвращает студентов; пустой статус означает всех студентов
+func (r *StudentRepository) GetStudents(status string) ([]models.Student, error) {
+    students, _, err := r.ListStudents(models.ListQuery{Filters: map[string]string{"status": status}})
+    return students, err
 }
 
 func (r *StudentRepository) GetStudentByID(id int) (*models.Student, error) {
     query := `
-        SELECT s.id, s.name, s.date_of_birth, s.group_name, c.teacher_id
+        SELECT s.id, s.name, s.date_of_birth, s.group_name, c.teacher_id, s.status
         FROM students s
-        LEFT JOIN courses c ON s.group_name = c.name
-        WHERE s.id = $1
+        LEFT JOIN courses c ON s.group_name = c.name AND c.deleted_at IS NULL
+        WHERE s.id = $1 AND s.deleted_at IS NULL
     `
     row := r.DB.QueryRow(query, id)
 
     var student models.Student
     var dateOfBirth time.Time
     var teacherID sql.NullInt64
-    if err := row.Scan(&student.ID, &student.Name, &dateOfBirth, &student.GroupName, &teacherID); err != nil {
+    if err := row.Scan(&student.ID, &student.Name, &dateOfBirth, &student.GroupName, &teacherID, &student.Status); err != nil {
         if errors.Is(err, sql.ErrNoRows) {
-            return nil, fmt.Errorf("student with id %d not found", id)
+            return nil, models.NotFound("student with id %d not found", id)
         }
         return nil, err
     }
@@ -126,91 +169,85 @@ func (r *StudentRepository) GetStudentByID(id int) (*models.Student, error) {
     return &student, nil
 }
 
-func (r *StudentRepository) UpdateStudent(id int, updates map[string]interface{}) (*models.Student, error) {
-	setClauses := []string{}
-	args := []interface{}{}
-	paramIndex := 1
-
-	for key, value := range updates {
-		switch key {
-		case "name":
-			setClauses = append(setClauses, fmt.Sprintf("name = $%d", paramIndex))
-			args = append(args, value)
-			paramIndex++
-		case "date_of_birth":
-			dateOfBirth, ok := value.(string)
-			if !ok {
-				return nil, fmt.Errorf("invalid type for date_of_birth")
-			}
-			parsedDate, err := time.Parse("2006-01-02", dateOfBirth)
-			if err != nil {
-				return nil, fmt.Errorf("invalid date_of_birth format: %v", err)
-			}
-			setClauses = append(setClauses, fmt.Sprintf("date_of_birth = $%d", paramIndex))
-			args = append(args, parsedDate)
-			paramIndex++
-		case "group_name":
-			groupName, ok := value.(string)
-			if !ok {
-				return nil, fmt.Errorf("invalid type for group_name")
-			}
-
-			// Проверяем существование курса
-			exists, err := r.CourseExists(groupName)
-			if err != nil {
-				return nil, err
-			}
-			if !exists {
-				return nil, fmt.Errorf("course with name '%s' does not exist", groupName)
-			}
-
-			setClauses = append(setClauses, fmt.Sprintf("group_name = $%d", paramIndex))
-			args = append(args, groupName)
-			paramIndex++
-		default:
-			return nil, fmt.Errorf("invalid field: %s", key)
+// UpdateStudent меняет переданные поля студента; смена группы попадает в историю переводов
+func (r *StudentRepository) UpdateStudent(id int, update models.StudentUpdate) (*models.Student, error) {
+	var set updateSet
+	if update.Name != nil {
+		set.set("name = ?", *update.Name)
+	}
+	if update.DateOfBirth != nil {
+		set.set("date_of_birth = ?", *update.DateOfBirth)
+	}
+	if update.GroupName != nil {
+		// Проверяем существование курса
+		exists, err := r.CourseExists(*update.GroupName)
+		if err != nil {
+			return nil, err
 		}
+		if !exists {
+			return nil, models.Invalid("course with name '%s' does not exist", *update.GroupName)
+		}
+		set.set("group_name = ?", *update.GroupName)
 	}
 
-	if len(setClauses) == 0 {
-		return nil, fmt.Errorf("no fields to update")
+	query, args, err := set.query(`UPDATE students SET %s WHERE id = $%d RETURNING id, name, date_of_birth, group_name, status`, id)
+	if err != nil {
+		return nil, err
 	}
 
-	query := fmt.Sprintf(`UPDATE students SET %s WHERE id = $%d RETURNING id, name, date_of_birth, group_name`, strings.Join(setClauses, ", "), paramIndex)
-	args = append(args, id)
+	tx, err := beginTx(r.DB)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Запоминаем текущую группу, чтобы записать перевод в историю
+	var oldGroup string
+	err = tx.QueryRow(`SELECT group_name FROM students WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&oldGroup)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.NotFound("student with id %d not found", id)
+		}
+		return nil, err
+	}
 
 	var student models.Student
 	var dateOfBirth time.Time
-	err := r.DB.QueryRow(query, args...).Scan(&student.ID, &student.Name, &dateOfBirth, &student.GroupName)
+	err = tx.QueryRow(query, args...).Scan(&student.ID, &student.Name, &dateOfBirth, &student.GroupName, &student.Status)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("student with id %d not found", id)
+			return nil, models.NotFound("student with id %d not found", id)
 		}
 		return nil, err
 	}
 
+	if student.GroupName != oldGroup {
+		if err := insertGroupChange(tx, id, oldGroup, student.GroupName, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	student.DateOfBirth = dateOfBirth.Format("2006-01-02")
 	student.Age = utils.CalculateAge(dateOfBirth)
 
 	return &student, nil
 }
 
-func (r *StudentRepository) DeleteStudent(id int) error {
-	query := `DELETE FROM students WHERE id = $1`
-	result, err := r.DB.Exec(query, id)
-	if err != nil {
-		return err
-	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("student with id %d not found", id)
+// DeleteStudent помещает студента в корзину
+func (r *StudentRepository) DeleteStudent(id int) (models.Cascade, error) {
+	cascade, err := softDelete(r.DB, "students", id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.NotFound("student with id %d not found", id)
 	}
-	return nil
+	return cascade, err
 }
 
 func (r *StudentRepository) CourseExists(courseName string) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM courses WHERE name = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM courses WHERE name = $1 AND deleted_at IS NULL)`
 	var exists bool
 	err := r.DB.QueryRow(query, courseName).Scan(&exists)
 	if err != nil {
@@ -218,3 +255,173 @@ func (r *StudentRepository) CourseExists(courseName string) (bool, error) {
 	}
 	return exists, nil
 }
+
+// ChangeStudentStatus меняет статус студента и регистрирует приказ
+func (r *StudentRepository) ChangeStudentStatus(id int, order *models.StudentOrder) error {
+	tx, err := beginTx(r.DB)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`SELECT status FROM students WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&order.OldStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.NotFound("student with id %d not found", id)
+		}
+		return err
+	}
+	if order.OldStatus == order.NewStatus {
+		return models.Conflict("student already has status '%s'", order.NewStatus)
+	}
+
+	if _, err := tx.Exec(`UPDATE students SET status = $1 WHERE id = $2`, order.NewStatus, id); err != nil {
+		return err
+	}
+
+	order.StudentID = id
+	if err := insertOrder(tx, order); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// TransferStudent переводит студента в другую группу по приказу
+func (r *StudentRepository) TransferStudent(id int, newGroup string, order *models.StudentOrder) error {
+	tx, err := beginTx(r.DB)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var oldGroup string
+	err = tx.QueryRow(`SELECT group_name, status FROM students WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&oldGroup, &order.OldStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.NotFound("student with id %d not found", id)
+		}
+		return err
+	}
+	if oldGroup == newGroup {
+		return models.Conflict("student is already in group '%s'", newGroup)
+	}
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM courses WHERE name = $1 AND deleted_at IS NULL)`, newGroup).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return models.Invalid("course with name '%s' does not exist", newGroup)
+	}
+
+	if _, err := tx.Exec(`UPDATE students SET group_name = $1 WHERE id = $2`, newGroup, id); err != nil {
+		return err
+	}
+
+	// Перевод между группами не меняет статус студента
+	order.StudentID = id
+	order.NewStatus = order.OldStatus
+	if err := insertOrder(tx, order); err != nil {
+		return err
+	}
+
+	if err := insertGroupChange(tx, id, oldGroup, newGroup, &order.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertOrder(tx DBTX, order *models.StudentOrder) error {
+	orderDate, err := time.Parse("2006-01-02", order.OrderDate)
+	if err != nil {
+		return models.Invalid("invalid order_date format: %v", err)
+	}
+
+	query := `
+        INSERT INTO student_orders (student_id, order_number, order_date, reason, old_status, new_status, created_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id, created_at
+    `
+	err = tx.QueryRow(query, order.StudentID, order.OrderNumber, orderDate, order.Reason, order.OldStatus, order.NewStatus, order.CreatedBy).
+		Scan(&order.ID, &order.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to register order: %w", err)
+	}
+	return nil
+}
+
+func insertGroupChange(tx DBTX, studentID int, oldGroup, newGroup string, orderID *int) error {
+	query := `
+        INSERT INTO student_group_history (student_id, old_group, new_group, order_id)
+        VALUES ($1, $2, $3, $4)
+    `
+	if _, err := tx.Exec(query, studentID, oldGroup, newGroup, orderID); err != nil {
+		return fmt.Errorf("failed to record group change: %w", err)
+	}
+	return nil
+}
+
+// GetStudentOrders возвращает журнал приказов по студенту
+func (r *StudentRepository) GetStudentOrders(studentID int) ([]models.StudentOrder, error) {
+	query := `
+        SELECT id, student_id, order_number, order_date, reason, old_status, new_status, created_by, created_at
+        FROM student_orders
+        WHERE student_id = $1
+        ORDER BY order_date, id
+    `
+	rows, err := r.DB.Query(query, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []models.StudentOrder{}
+	for rows.Next() {
+		var order models.StudentOrder
+		var orderDate time.Time
+		var createdBy sql.NullInt64
+		if err := rows.Scan(&order.ID, &order.StudentID, &order.OrderNumber, &orderDate, &order.Reason,
+			&order.OldStatus, &order.NewStatus, &createdBy, &order.CreatedAt); err != nil {
+			return nil, err
+		}
+		order.OrderDate = orderDate.Format("2006-01-02")
+		if createdBy.Valid {
+			value := int(createdBy.Int64)
+			order.CreatedBy = &value
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+// GetStudentGroupHistory возвращает историю переводов студента между группами
+func (r *StudentRepository) GetStudentGroupHistory(studentID int) ([]models.StudentGroupChange, error) {
+	query := `
+        SELECT id, student_id, old_group, new_group, order_id, changed_at
+        FROM student_group_history
+        WHERE student_id = $1
+        ORDER BY changed_at, id
+    `
+	rows, err := r.DB.Query(query, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.StudentGroupChange{}
+	for rows.Next() {
+		var change models.StudentGroupChange
+		var orderID sql.NullInt64
+		if err := rows.Scan(&change.ID, &change.StudentID, &change.OldGroup, &change.NewGroup, &orderID, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		if orderID.Valid {
+			value := int(orderID.Int64)
+			change.OrderID = &value
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
\ No newline at end of file
@@ -5,17 +5,17 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strings"
+	"math"
 
 	"github.com/lib/pq"
 )
 
 type TeacherRepository struct {
-    DB *sql.DB
+    DB DBTX
     CourseRepo *CourseRepository
 }
 
-func NewTeacherRepository(db *sql.DB) *TeacherRepository {
+func NewTeacherRepository(db DBTX) *TeacherRepository {
     return &TeacherRepository{
         DB:         db,
         CourseRepo: NewCourseRepository(db), // Инициализируем CourseRepository
@@ -28,42 +28,67 @@ func (r *TeacherRepository) CreateTeacher(teacher *models.Teacher) error {
     if len(teacher.Courses) > 0 {
         validCourses, err := r.CheckCoursesExist(teacher.Courses)
         if err != nil {
-            return fmt.Errorf("failed to validate courses: %v", err)
+            return fmt.Errorf("failed to validate courses: %w", err)
         }
         if !validCourses {
-            return fmt.Errorf("some courses do not exist")
+            return models.Invalid("some courses do not exist")
         }
     }
 
     query := `
-        INSERT INTO teachers (name, subject, courses, working_hours)
-        VALUES ($1, $2, $3, $4)
+        INSERT INTO teachers (name, subject, courses, working_hours, hours_budget)
+        VALUES ($1, $2, $3, $4, $4)
         RETURNING id
     `
     err := r.DB.QueryRow(query, teacher.Name, teacher.Subject, pq.Array(teacher.Courses), teacher.WorkingHours).Scan(&teacher.ID)
     if err != nil {
-        return fmt.Errorf("failed to create teacher: %v", err)
+        return fmt.Errorf("failed to create teacher: %w", err)
     }
     return nil
 }
 
+// LockTeacher блокирует строку преподавателя до конца транзакции UnitOfWork: параллельные
+// изменения его расписания и часов ждут её завершения
+func (r *TeacherRepository) LockTeacher(id int) error {
+    var locked int
+    err := r.DB.QueryRow(`SELECT id FROM teachers WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&locked)
+    if errors.Is(err, sql.ErrNoRows) {
+        return models.NotFound("teacher with id %d not found", id)
+    }
+    return err
+}
+
 // UpdateTeacherWorkingHours обновляет количество рабочих часов преподавателя
 func (r *TeacherRepository) UpdateTeacherWorkingHours(teacherID int, hours float64) error {
     query := `
         UPDATE teachers
         SET working_hours = working_hours - $1
-        WHERE id = $2 AND working_hours >= $1
+        WHERE id = $2 AND working_hours >= $1 AND deleted_at IS NULL
     `
     result, err := r.DB.Exec(query, hours, teacherID)
     if err != nil {
-        return fmt.Errorf("failed to update teacher's working hours: %v", err)
+        return fmt.Errorf("failed to update teacher's working hours: %w", err)
     }
 
     rowsAffected, _ := result.RowsAffected()
     if rowsAffected == 0 {
-        return fmt.Errorf("teacher with id %d does not have enough working hours", teacherID)
+        return models.Conflict("teacher with id %d does not have enough working hours", teacherID)
+    }
+
+    return nil
+}
+
+// ReturnTeacherWorkingHours возвращает преподавателю часы занятия, которое у него больше не числится
+func (r *TeacherRepository) ReturnTeacherWorkingHours(teacherID int, hours float64) error {
+    result, err := r.DB.Exec(`UPDATE teachers SET working_hours = working_hours + $1 WHERE id = $2`, hours, teacherID)
+    if err != nil {
+        return fmt.Errorf("failed to update teacher's working hours: %w", err)
     }
 
+    rowsAffected, _ := result.RowsAffected()
+    if rowsAffected == 0 {
+        return models.NotFound("teacher with id %d not found", teacherID)
+    }
     return nil
 }
 
@@ -72,7 +97,7 @@ func (r *TeacherRepository) CheckTeacherExists(name, subject string) (bool, erro
         SELECT EXISTS (
             SELECT 1
             FROM teachers
-            WHERE name = $1 AND subject = $2
+            WHERE name = $1 AND subject = $2 AND deleted_at IS NULL
         )
     `
     var exists bool
@@ -87,7 +112,7 @@ func (r *TeacherRepository) CheckCoursesExist(courseNames []string) (bool, error
     query := `
         SELECT COUNT(*)
         FROM courses
-        WHERE name = ANY($1)
+        WHERE name = ANY($1) AND deleted_at IS NULL
     `
     var count int
     err := r.DB.QueryRow(query, pq.Array(courseNames)).Scan(&count)
@@ -102,7 +127,8 @@ func (r *TeacherRepository) GetAllTeachersWithCourses() ([]models.Teacher, error
     query := `
         SELECT t.id, t.name, t.subject, c.name AS course_name
         FROM teachers t
-        LEFT JOIN courses c ON t.id = c.teacher_id
+        LEFT JOIN courses c ON t.id = c.teacher_id AND c.deleted_at IS NULL
+        WHERE t.deleted_at IS NULL
     `
     rows, err := r.DB.Query(query)
     if err != nil {
@@ -143,30 +169,71 @@ func (r *TeacherRepository) GetAllTeachersWithCourses() ([]models.Teacher, error
 
     return teachers, nil
 }
-// Получение всех преподавателей
-func (r *TeacherRepository) GetAllTeachers() ([]models.Teacher, error) {
-    query := `
+// teacherList поля сортировки и фильтры списка преподавателей
+var teacherList = listSpec{
+    sorts: map[string]string{
+        "id":            "id",
+        "name":          "name",
+        "subject":       "subject",
+        "working_hours": "working_hours",
+    },
+    filters: map[string]listFilter{
+        "name":      prefixFilter("name"),
+        "subject":   textFilter("subject = ?"),
+        "course":    textFilter("? = ANY(courses)"),
+        "hours_min": floatFilter("working_hours >= ?"),
+        "hours_max": floatFilter("working_hours <= ?"),
+    },
+    id: "id",
+}
+
+const teacherListQuery = `
         SELECT id, name, subject, courses, working_hours
         FROM teachers
-    `
-    rows, err := r.DB.Query(query)
-    if err != nil {
+        WHERE deleted_at IS NULL`
+
+func scanTeacher(row rowScanner) (*models.Teacher, error) {
+    var teacher models.Teacher
+    courses := []string{}
+    if err := row.Scan(&teacher.ID, &teacher.Name, &teacher.Subject, pq.Array(&courses), &teacher.WorkingHours); err != nil {
         return nil, err
     }
-    defer rows.Close()
+    teacher.Courses = courses
+    return &teacher, nil
+}
 
-    var teachers []models.Teacher
-    for rows.Next() {
-        var teacher models.Teacher
-        var courses []string
-        if err := rows.Scan(&teacher.ID, &teacher.Name, &teacher.Subject, pq.Array(&courses), &teacher.WorkingHours); err != nil {
-            return nil, err
+// ListTeachers возвращает страницу преподавателей и их общее количество с учётом фильтров
+func (r *TeacherRepository) ListTeachers(query models.ListQuery) ([]models.Teacher, int, error) {
+    teachers := []models.Teacher{}
+    total, err := queryList(r.DB, teacherList, teacherListQuery, query, func(rows *sql.Rows) error {
+        teacher, err := scanTeacher(rows)
+        if err != nil {
+            return err
         }
-        teacher.Courses = courses
-        teachers = append(teachers, teacher)
+        teachers = append(teachers, *teacher)
+        return nil
+    })
+    if err != nil {
+        return nil, 0, err
     }
+    return teachers, total, nil
+}
 
-    return teachers, nil
+// EachTeacher передаёт fn преподавателей с учётом фильтров и сортировки по мере чтения из базы
+func (r *TeacherRepository) EachTeacher(query models.ListQuery, fn func(models.Teacher) error) error {
+    return eachList(r.DB, teacherList, teacherListQuery, query, func(rows *sql.Rows) error {
+        teacher, err := scanTeacher(rows)
+        if err != nil {
+            return err
+        }
+        return fn(*teacher)
+    })
+}
+
+// Получение всех преподавателей
+func (r *TeacherRepository) GetAllTeachers() ([]models.Teacher, error) {
+    teachers, _, err := r.ListTeachers(models.ListQuery{})
+    return teachers, err
 }
 
 // Получение преподавателя по ID
@@ -174,7 +241,7 @@ func (r *TeacherRepository) GetTeacherByID(teacherID int) (*models.Teacher, erro
     query := `
         SELECT id, name, subject, courses, working_hours
         FROM teachers
-        WHERE id = $1
+        WHERE id = $1 AND deleted_at IS NULL
     `
 
     var teacher models.Teacher
@@ -189,134 +256,90 @@ func (r *TeacherRepository) GetTeacherByID(teacherID int) (*models.Teacher, erro
     )
     if err != nil {
         if errors.Is(err, sql.ErrNoRows) {
-            return nil, fmt.Errorf("teacher with id %d not found", teacherID)
+            return nil, models.NotFound("teacher with id %d not found", teacherID)
         }
-        return nil, fmt.Errorf("failed to fetch teacher data: %v", err)
+        return nil, fmt.Errorf("failed to fetch teacher data: %w", err)
     }
 
     teacher.Courses = courses
     return &teacher, nil
 }
-func (r *TeacherRepository) UpdateTeacherPartial(id int, updates map[string]interface{}) (map[string]interface{}, error) {
-    setClauses := []string{}
-    args := []interface{}{}
-    paramIndex := 1
-
-    // Подготавливаем запрос и аргументы
-    for key, value := range updates {
-        switch key {
-        case "name":
-            setClauses = append(setClauses, fmt.Sprintf("name = $%d", paramIndex))
-            args = append(args, value)
-            paramIndex++
-        case "subject":
-            setClauses = append(setClauses, fmt.Sprintf("subject = $%d", paramIndex))
-            args = append(args, value)
-            paramIndex++
-        case "working_hours":
-            workingHours, ok := value.(float64) // JSON передает числа как float64
-            if !ok {
-                return nil, fmt.Errorf("invalid type for working_hours: expected number")
-            }
-            if workingHours < 0 {
-                return nil, fmt.Errorf("working_hours cannot be negative")
-            }
-            setClauses = append(setClauses, fmt.Sprintf("working_hours = $%d", paramIndex))
-            args = append(args, workingHours)
-            paramIndex++
-        default:
-            return nil, fmt.Errorf("invalid field: %s", key)
-        }
+// UpdateTeacherPartial меняет переданные поля и возвращает преподавателя целиком
+func (r *TeacherRepository) UpdateTeacherPartial(id int, update models.TeacherUpdate) (*models.Teacher, error) {
+    var set updateSet
+    if update.Name != nil {
+        set.set("name = ?", *update.Name)
     }
-
-    if len(setClauses) == 0 {
-        return nil, fmt.Errorf("no fields to update")
+    if update.Subject != nil {
+        set.set("subject = ?", *update.Subject)
+    }
+    if update.WorkingHours != nil {
+        // Бюджет меняется на ту же величину, что и остаток
+        set.set("working_hours = ?, hours_budget = hours_budget + (? - working_hours)", *update.WorkingHours)
     }
 
-    query := fmt.Sprintf(`
+    query, args, err := set.query(`
         UPDATE teachers
         SET %s
-        WHERE id = $%d
-        RETURNING id, name, subject, working_hours
-    `, strings.Join(setClauses, ", "), paramIndex)
-    args = append(args, id)
-
-    // Промежуточные переменные для Scan
-    var teacherID int
-    var name sql.NullString       // Используем sql.NullString для обработки NULL
-    var subject sql.NullString    // Используем sql.NullString для обработки NULL
-    var workingHours sql.NullFloat64 // Используем sql.NullFloat64 для обработки NULL
-
-    err := r.DB.QueryRow(query, args...).Scan(
-        &teacherID,
-        &name,
-        &subject,
-        &workingHours,
-    )
+        WHERE id = $%d AND deleted_at IS NULL
+        RETURNING id, name, subject, courses, working_hours
+    `, id)
     if err != nil {
-        if errors.Is(err, sql.ErrNoRows) {
-            return nil, fmt.Errorf("teacher with id %d not found", id)
-        }
-        return nil, fmt.Errorf("failed to update teacher data: %v", err)
-    }
-
-    // Формируем словарь с обновленными данными
-    updatedData := make(map[string]interface{})
-    if name.Valid {
-        updatedData["name"] = name.String
-    }
-    if subject.Valid {
-        updatedData["subject"] = subject.String
-    }
-    if workingHours.Valid {
-        updatedData["working_hours"] = workingHours.Float64
+        return nil, err
     }
 
-    // Удаляем из updatedData поля, которые не были обновлены
-    for key := range updatedData {
-        if _, exists := updates[key]; !exists {
-            delete(updatedData, key)
+    var teacher models.Teacher
+    var courses []string
+    err = r.DB.QueryRow(query, args...).Scan(
+        &teacher.ID,
+        &teacher.Name,
+        &teacher.Subject,
+        pq.Array(&courses),
+        &teacher.WorkingHours,
+    )
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, models.NotFound("teacher with id %d not found", id)
         }
+        return nil, fmt.Errorf("failed to update teacher data: %w", err)
     }
 
-    // Добавляем ID в ответ
-    updatedData["id"] = teacherID
-    return updatedData, nil
+    teacher.Courses = courses
+    return &teacher, nil
 }
 
 func (r *TeacherRepository) TeacherExists(id int) (bool, error) {
     var exists bool
-    query := `SELECT EXISTS(SELECT 1 FROM teachers WHERE id = $1)`
+    query := `SELECT EXISTS(SELECT 1 FROM teachers WHERE id = $1 AND deleted_at IS NULL)`
     err := r.DB.QueryRow(query, id).Scan(&exists)
     return exists, err
 }
 
-func (r *TeacherRepository) DeleteTeacher(id int) error {
-    // Проверяем, существует ли запись
-    var exists bool
-    query := `SELECT EXISTS(SELECT 1 FROM teachers WHERE id = $1)`
-    err := r.DB.QueryRow(query, id).Scan(&exists)
-    if err != nil {
-        return err
+// GetTeacherDeletionImpact возвращает записи, которые будут удалены вместе с преподавателем
+func (r *TeacherRepository) GetTeacherDeletionImpact(id int) (models.Cascade, error) {
+    impact, err := deletionImpact(r.DB, "teachers", id)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, models.NotFound("teacher with id %d not found", id)
     }
+    return impact, err
+}
 
-    if !exists {
-        return fmt.Errorf("teacher with id %d not found", id)
+// DeleteTeacher помещает преподавателя и его занятия в корзину
+func (r *TeacherRepository) DeleteTeacher(id int) (models.Cascade, error) {
+    cascade, err := softDelete(r.DB, "teachers", id)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, models.NotFound("teacher with id %d not found", id)
     }
-
-    // Удаляем запись
-    query = `DELETE FROM teachers WHERE id = $1`
-    _, err = r.DB.Exec(query, id)
-    return err
+    return cascade, err
 }
 
-func (r *TeacherRepository) GetTeacherSchedule(teacherName string) ([]models.ScheduleResponse, error) {
+func (r *TeacherRepository) GetTeacherSchedule(teacherName string) ([]models.Schedule, error) {
     query := `
-        SELECT s.id, t.name AS teacher_name, c.name AS classroom_name, s.group_name, s.start_time, s.end_time, s.day_of_week
+        SELECT s.id, s.teacher_id, t.name AS teacher_name, s.classroom_id, c.name AS classroom_name, s.group_name, s.start_time, s.end_time, s.day_of_week, s.week_type
         FROM schedules s
         LEFT JOIN teachers t ON s.teacher_id = t.id
         LEFT JOIN classrooms c ON s.classroom_id = c.id
-        WHERE t.name = $1
+        WHERE t.name = $1 AND t.deleted_at IS NULL AND s.deleted_at IS NULL
     `
     rows, err := r.DB.Query(query, teacherName)
     if err != nil {
@@ -324,46 +347,71 @@ func (r *TeacherRepository) GetTeacherSchedule(teacherName string) ([]models.Sch
     }
     defer rows.Close()
 
-    var schedules []models.ScheduleResponse
+    var schedules []models.Schedule
     for rows.Next() {
-        var schedule models.ScheduleResponse
-        if err := rows.Scan(&schedule.ID, &schedule.TeacherName, &schedule.ClassroomName, &schedule.GroupName, &schedule.StartTime, &schedule.EndTime, &schedule.DayOfWeek); err != nil {
+        var schedule models.Schedule
+        if err := rows.Scan(&schedule.ID, &schedule.TeacherID, &schedule.TeacherName, &schedule.ClassroomID, &schedule.ClassroomName, &schedule.GroupName, &schedule.StartTime, &schedule.EndTime, &schedule.DayOfWeek, &schedule.WeekType); err != nil {
             return nil, err
         }
         schedules = append(schedules, schedule)
     }
 
     if len(schedules) == 0 {
-        return nil, errors.New("teacher not found")
+        return nil, models.NotFound("teacher not found")
     }
     return schedules, nil
 }
 
-func (r *TeacherRepository) UpdateTeacherProfile(teacherID int, updates map[string]interface{}) error {
-    if len(updates) == 0 {
-        return fmt.Errorf("no fields to update")
+// RecalculateWorkingHours пересчитывает остаток часов как бюджет минус длительность действующих занятий.
+// При apply = false только возвращает расхождения
+func (r *TeacherRepository) RecalculateWorkingHours(apply bool) ([]models.HoursChange, error) {
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return nil, err
     }
+    defer tx.Rollback()
 
-    query := "UPDATE teachers SET "
-    var args []interface{}
-    var setClauses []string
-
-    for key, value := range updates {
-        setClauses = append(setClauses, fmt.Sprintf("%s = ?", key))
-        args = append(args, value)
+    query := `
+        SELECT t.id, t.name, t.working_hours,
+               t.hours_budget - COALESCE((
+                   SELECT SUM(EXTRACT(EPOCH FROM (s.end_time - s.start_time)) / 3600)
+                   FROM schedules s
+                   WHERE s.teacher_id = t.id AND s.deleted_at IS NULL
+               ), 0) AS expected
+        FROM teachers t
+        WHERE t.deleted_at IS NULL
+        ORDER BY t.id
+        FOR UPDATE OF t
+    `
+    rows, err := tx.Query(query)
+    if err != nil {
+        return nil, err
     }
 
-    // Добавляем все поля через запятую
-    query += strings.Join(setClauses, ", ")
-
-    // Добавляем условие WHERE
-    query += " WHERE id = ?"
-    args = append(args, teacherID)
-
-    _, err := r.DB.Exec(query, args...)
-    if err != nil {
-        return err
+    changes := []models.HoursChange{}
+    for rows.Next() {
+        var change models.HoursChange
+        if err := rows.Scan(&change.TeacherID, &change.TeacherName, &change.OldHours, &change.NewHours); err != nil {
+            rows.Close()
+            return nil, err
+        }
+        // Сравниваем с точностью до минуты, чтобы не ловить ошибки округления
+        if math.Abs(change.OldHours-change.NewHours) > 1.0/60 {
+            changes = append(changes, change)
+        }
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return nil, err
     }
 
-    return nil
+    if !apply {
+        return changes, nil
+    }
+    for _, change := range changes {
+        if _, err := tx.Exec(`UPDATE teachers SET working_hours = $1 WHERE id = $2`, change.NewHours, change.TeacherID); err != nil {
+            return nil, fmt.Errorf("failed to update teacher %d: %w", change.TeacherID, err)
+        }
+    }
+    return changes, tx.Commit()
 }
\ No newline at end of file
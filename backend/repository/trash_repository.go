@@ -0,0 +1,371 @@
+package repositories
+
+import (
+    "backend/models"
+    "database/sql"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/lib/pq"
+)
+
+// reference описывает внешний ключ между таблицами с мягким удалением
+type reference struct {
+    Child        string // Зависимая таблица
+    ChildColumn  string // Колонка зависимой таблицы
+    Parent       string // Родительская таблица
+    ParentColumn string // Колонка родителя, на которую ссылается ChildColumn
+}
+
+// references повторяет ON DELETE CASCADE из схемы: при удалении родителя
+// зависимые записи тоже попадают в корзину
+var references = []reference{
+    {Child: "schedules", ChildColumn: "teacher_id", Parent: "teachers", ParentColumn: "id"},
+    {Child: "schedules", ChildColumn: "classroom_id", Parent: "classrooms", ParentColumn: "id"},
+    {Child: "schedules", ChildColumn: "group_name", Parent: "courses", ParentColumn: "name"},
+}
+
+// trashNames выражение для отображения записи в корзине
+var trashNames = map[string]string{
+    "teachers":   "name",
+    "students":   "name",
+    "courses":    untrashedName("courses", "name"),
+    "classrooms": untrashedName("classrooms", "name"),
+    "schedules":  "group_name || ' ' || day_of_week || ' ' || to_char(start_time, 'HH24:MI')",
+}
+
+// trashedNames уникальные названия. В корзине к названию добавляется суффикс trashSuffix, чтобы его
+// можно было занять снова; ссылки по названию (ON UPDATE CASCADE) переходят вместе с записью.
+// При восстановлении суффикс снимается
+var trashedNames = map[string]string{
+    "courses":    "name",
+    "classrooms": "name",
+}
+
+// trashSuffix суффикс названия записи table в корзине
+func trashSuffix(table string) string {
+    return fmt.Sprintf(`' [deleted #' || %s.id || ']'`, table)
+}
+
+// untrashedName название записи без суффикса корзины
+func untrashedName(table, column string) string {
+    return fmt.Sprintf(`left(%[1]s.%[2]s, -length(%[3]s))`, table, column, trashSuffix(table))
+}
+
+// TrashTables таблицы, поддерживающие мягкое удаление
+var TrashTables = []string{"teachers", "students", "courses", "classrooms", "schedules"}
+
+func isTrashTable(table string) bool {
+    _, ok := trashNames[table]
+    return ok
+}
+
+type queryer interface {
+    Query(query string, args ...interface{}) (*sql.Rows, error)
+    QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func collectIDs(q queryer, query string, args ...interface{}) ([]int, error) {
+    rows, err := q.Query(query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    ids := []int{}
+    for rows.Next() {
+        var id int
+        if err := rows.Scan(&id); err != nil {
+            return nil, err
+        }
+        ids = append(ids, id)
+    }
+    return ids, rows.Err()
+}
+
+// deletionImpact возвращает зависимые записи, которые будут удалены вместе с записью.
+// Если записи нет или она уже в корзине, возвращает sql.ErrNoRows. Внутри транзакции строка
+// блокируется до удаления, чтобы список не устарел между проверкой и удалением
+func deletionImpact(db DBTX, table string, id int) (models.Cascade, error) {
+    var locked int
+    query := fmt.Sprintf(`SELECT id FROM %s WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, table)
+    if err := db.QueryRow(query, id).Scan(&locked); err != nil {
+        return nil, err
+    }
+
+    impact := models.Cascade{}
+    for _, ref := range references {
+        if ref.Parent != table {
+            continue
+        }
+        query := fmt.Sprintf(`
+            SELECT c.id FROM %s c
+            JOIN %s p ON c.%s = p.%s
+            WHERE p.id = $1 AND c.deleted_at IS NULL
+            ORDER BY c.id
+        `, ref.Child, ref.Parent, ref.ChildColumn, ref.ParentColumn)
+        ids, err := collectIDs(db, query, id)
+        if err != nil {
+            return nil, err
+        }
+        if len(ids) > 0 {
+            impact[ref.Child] = append(impact[ref.Child], ids...)
+        }
+    }
+    return impact, nil
+}
+
+// shiftTeacherHours возвращает преподавателям часы занятий ids (sign = 1) или списывает их (sign = -1),
+// чтобы остаток оставался бюджетом минус часы действующих занятий. Возвращает ID преподавателей,
+// у которых остатка не хватило
+func shiftTeacherHours(db DBTX, ids []int, sign float64) ([]int, error) {
+    if len(ids) == 0 {
+        return nil, nil
+    }
+    query := `
+        WITH shifted AS (
+            UPDATE teachers t SET working_hours = t.working_hours + $2 * s.hours
+            FROM (
+                SELECT teacher_id, SUM(EXTRACT(EPOCH FROM (end_time - start_time)) / 3600) AS hours
+                FROM schedules WHERE id = ANY($1) GROUP BY teacher_id
+            ) s
+            WHERE t.id = s.teacher_id
+            RETURNING t.id, t.working_hours
+        )
+        SELECT id FROM shifted WHERE working_hours < 0 ORDER BY id
+    `
+    short, err := collectIDs(db, query, pq.Array(ids), sign)
+    if err != nil {
+        return nil, fmt.Errorf("failed to update teacher's working hours: %w", err)
+    }
+    return short, nil
+}
+
+// softDelete помещает запись и зависимые записи в корзину с одинаковой отметкой времени,
+// чтобы при восстановлении вернуть ровно то, что было удалено вместе с ней.
+// Часы занятий, попавших в корзину, возвращаются преподавателям в той же транзакции
+func softDelete(db DBTX, table string, id int) (models.Cascade, error) {
+    tx, err := beginTx(db)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    // Удалённый курс не должен числиться за преподавателем; название ещё без суффикса корзины
+    if table == "courses" {
+        _, err := tx.Exec(`
+            UPDATE teachers t SET courses = array_remove(t.courses, c.name)
+            FROM courses c
+            WHERE c.id = $1 AND c.deleted_at IS NULL AND t.id = c.teacher_id
+        `, id)
+        if err != nil {
+            return nil, fmt.Errorf("failed to update teacher's courses: %w", err)
+        }
+    }
+
+    set := "deleted_at = CURRENT_TIMESTAMP"
+    if column, ok := trashedNames[table]; ok {
+        set += fmt.Sprintf(", %[1]s = %[1]s || %[2]s", column, trashSuffix(table))
+    }
+    var deletedAt time.Time
+    query := fmt.Sprintf(`UPDATE %s SET %s WHERE id = $1 AND deleted_at IS NULL RETURNING deleted_at`, table, set)
+    if err := tx.QueryRow(query, id).Scan(&deletedAt); err != nil {
+        return nil, err
+    }
+
+    cascade := models.Cascade{}
+    for _, ref := range references {
+        if ref.Parent != table {
+            continue
+        }
+        query := fmt.Sprintf(`
+            UPDATE %s c SET deleted_at = $2
+            FROM %s p
+            WHERE c.%s = p.%s AND p.id = $1 AND c.deleted_at IS NULL
+            RETURNING c.id
+        `, ref.Child, ref.Parent, ref.ChildColumn, ref.ParentColumn)
+        ids, err := collectIDs(tx, query, id, deletedAt)
+        if err != nil {
+            return nil, fmt.Errorf("failed to delete related %s: %w", ref.Child, err)
+        }
+        if len(ids) > 0 {
+            cascade[ref.Child] = append(cascade[ref.Child], ids...)
+        }
+    }
+
+    schedules := cascade["schedules"]
+    if table == "schedules" {
+        schedules = []int{id}
+    }
+    if _, err := shiftTeacherHours(tx, schedules, 1); err != nil {
+        return nil, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return cascade, nil
+}
+
+type TrashRepository struct {
+    DB DBTX
+}
+
+func NewTrashRepository(db DBTX) *TrashRepository {
+    return &TrashRepository{DB: db}
+}
+
+// GetTrash возвращает записи из корзины; пустой entityType означает все таблицы
+func (r *TrashRepository) GetTrash(entityType string) ([]models.TrashItem, error) {
+    tables := TrashTables
+    if entityType != "" {
+        if !isTrashTable(entityType) {
+            return nil, models.Invalid("invalid entity type: %s", entityType)
+        }
+        tables = []string{entityType}
+    }
+
+    items := []models.TrashItem{}
+    for _, table := range tables {
+        query := fmt.Sprintf(`SELECT id, %s, deleted_at FROM %s WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC`, trashNames[table], table)
+        rows, err := r.DB.Query(query)
+        if err != nil {
+            return nil, err
+        }
+
+        for rows.Next() {
+            item := models.TrashItem{EntityType: table}
+            if err := rows.Scan(&item.ID, &item.Name, &item.DeletedAt); err != nil {
+                rows.Close()
+                return nil, err
+            }
+            items = append(items, item)
+        }
+        rows.Close()
+        if err := rows.Err(); err != nil {
+            return nil, err
+        }
+    }
+    return items, nil
+}
+
+// Restore восстанавливает запись из корзины вместе с записями, удалёнными каскадом.
+// Часы восстановленных занятий списываются у преподавателей; если их не хватает, восстановление отменяется
+func (r *TrashRepository) Restore(table string, id int) (models.Cascade, error) {
+    if !isTrashTable(table) {
+        return nil, models.Invalid("invalid entity type: %s", table)
+    }
+
+    tx, err := beginTx(r.DB)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var deletedAt time.Time
+    query := fmt.Sprintf(`SELECT deleted_at FROM %s WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`, table)
+    if err := tx.QueryRow(query, id).Scan(&deletedAt); err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, models.NotFound("%s record with id %d not found in trash", table, id)
+        }
+        return nil, err
+    }
+
+    // Название удалённой записи могли занять, пока она лежала в корзине
+    if column, ok := trashedNames[table]; ok {
+        var taken bool
+        query := fmt.Sprintf(`
+            SELECT EXISTS (
+                SELECT 1 FROM %[1]s a, %[1]s
+                WHERE %[1]s.id = $1 AND a.%[2]s = %[3]s
+            )
+        `, table, column, untrashedName(table, column))
+        if err := tx.QueryRow(query, id).Scan(&taken); err != nil {
+            return nil, err
+        }
+        if taken {
+            return nil, models.Conflict("cannot restore: active %s record with the same %s exists, rename it first", table, column)
+        }
+    }
+
+    // Нельзя восстановить запись, если её родитель остаётся в корзине
+    for _, ref := range references {
+        if ref.Child != table {
+            continue
+        }
+        var parentDeleted bool
+        query := fmt.Sprintf(`
+            SELECT EXISTS (
+                SELECT 1 FROM %s c
+                JOIN %s p ON c.%s = p.%s
+                WHERE c.id = $1 AND p.deleted_at IS NOT NULL
+            )
+        `, ref.Child, ref.Parent, ref.ChildColumn, ref.ParentColumn)
+        if err := tx.QueryRow(query, id).Scan(&parentDeleted); err != nil {
+            return nil, err
+        }
+        if parentDeleted {
+            return nil, models.Conflict("cannot restore: related %s record is deleted, restore it first", ref.Parent)
+        }
+    }
+
+    set := "deleted_at = NULL"
+    if column, ok := trashedNames[table]; ok {
+        set += fmt.Sprintf(", %s = %s", column, untrashedName(table, column))
+    }
+    query = fmt.Sprintf(`UPDATE %s SET %s WHERE id = $1`, table, set)
+    if _, err := tx.Exec(query, id); err != nil {
+        return nil, err
+    }
+
+    cascade := models.Cascade{}
+    for _, ref := range references {
+        if ref.Parent != table {
+            continue
+        }
+        query := fmt.Sprintf(`
+            UPDATE %s c SET deleted_at = NULL
+            FROM %s p
+            WHERE c.%s = p.%s AND p.id = $1 AND c.deleted_at = $2
+            RETURNING c.id
+        `, ref.Child, ref.Parent, ref.ChildColumn, ref.ParentColumn)
+        ids, err := collectIDs(tx, query, id, deletedAt)
+        if err != nil {
+            return nil, fmt.Errorf("failed to restore related %s: %w", ref.Child, err)
+        }
+        if len(ids) > 0 {
+            cascade[ref.Child] = append(cascade[ref.Child], ids...)
+        }
+    }
+
+    // Восстановленные занятия снова списывают часы преподавателей
+    schedules := cascade["schedules"]
+    if table == "schedules" {
+        schedules = []int{id}
+    }
+    short, err := shiftTeacherHours(tx, schedules, -1)
+    if err != nil {
+        return nil, err
+    }
+    if len(short) > 0 {
+        return nil, models.Conflict("cannot restore: teacher with id %d does not have enough working hours", short[0])
+    }
+
+    // Возвращаем курс в список курсов преподавателя
+    if table == "courses" {
+        _, err := tx.Exec(`
+            UPDATE teachers t SET courses = array_append(t.courses, c.name)
+            FROM courses c
+            WHERE c.id = $1 AND t.id = c.teacher_id AND NOT (c.name = ANY(t.courses))
+        `, id)
+        if err != nil {
+            return nil, fmt.Errorf("failed to update teacher's courses: %w", err)
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return cascade, nil
+}
\ No newline at end of file
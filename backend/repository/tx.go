@@ -0,0 +1,110 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+)
+
+// DBTX то, что репозиториям нужно от соединения: *sql.DB или *sql.Tx.
+// Один и тот же репозиторий работает и сам по себе, и внутри UnitOfWork
+type DBTX interface {
+    Exec(query string, args ...interface{}) (sql.Result, error)
+    Query(query string, args ...interface{}) (*sql.Rows, error)
+    QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// UnitOfWork выполняет несколько вызовов репозиториев в одной транзакции
+type UnitOfWork struct {
+    DB *sql.DB
+}
+
+func NewUnitOfWork(db *sql.DB) *UnitOfWork {
+    return &UnitOfWork{DB: db}
+}
+
+// Tx репозитории, работающие в транзакции UnitOfWork. Audit пишет журнал в той же транзакции:
+// запись об изменении фиксируется и откатывается вместе с ним
+type Tx struct {
+    Teachers      TeacherStore
+    Courses       CourseStore
+    Classrooms    ClassroomStore
+    Schedules     ScheduleStore
+    Students      StudentStore
+    Users         UserStore
+    Permissions   PermissionStore
+    Guardians     GuardianStore
+    GradeSheets   GradeSheetStore
+    Attendance    AttendanceStore
+    Announcements AnnouncementStore
+    Activations   ActivationStore
+    Imports       ImportStore
+    Trash         TrashStore
+    Audit         AuditStore
+}
+
+// Do выполняет fn в транзакции: фиксирует её, если fn вернула nil, иначе откатывает.
+// Проверки перед записью (пересечения занятий, остаток часов) должны блокировать проверяемые строки
+// (SELECT ... FOR UPDATE), иначе параллельная транзакция пройдёт ту же проверку
+func (u *UnitOfWork) Do(fn func(tx *Tx) error) error {
+    tx, err := u.DB.Begin()
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    if err := fn(&Tx{
+        Teachers:      NewTeacherRepository(tx),
+        Courses:       NewCourseRepository(tx),
+        Classrooms:    NewClassroomRepository(tx),
+        Schedules:     NewScheduleRepository(tx),
+        Students:      NewStudentRepository(tx),
+        Users:         NewUserRepository(tx),
+        Permissions:   NewPermissionRepository(tx),
+        Guardians:     NewGuardianRepository(tx),
+        GradeSheets:   NewGradeSheetRepository(tx),
+        Attendance:    NewAttendanceRepository(tx),
+        Announcements: NewAnnouncementRepository(tx),
+        Activations:   NewActivationRepository(tx),
+        Imports:       NewImportRepository(tx),
+        Trash:         NewTrashRepository(tx),
+        Audit:         NewAuditRepository(tx),
+    }); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+// txScope транзакция метода репозитория. Если репозиторий уже работает внутри UnitOfWork,
+// метод выполняется в её транзакции, а фиксирует и откатывает её UnitOfWork
+type txScope struct {
+    *sql.Tx
+    owned bool
+}
+
+func beginTx(db DBTX) (*txScope, error) {
+    switch conn := db.(type) {
+    case *sql.Tx:
+        return &txScope{Tx: conn}, nil
+    case *sql.DB:
+        tx, err := conn.Begin()
+        if err != nil {
+            return nil, err
+        }
+        return &txScope{Tx: tx, owned: true}, nil
+    }
+    return nil, fmt.Errorf("unsupported connection type %T", db)
+}
+
+func (t *txScope) Commit() error {
+    if !t.owned {
+        return nil
+    }
+    return t.Tx.Commit()
+}
+
+func (t *txScope) Rollback() error {
+    if !t.owned {
+        return nil
+    }
+    return t.Tx.Rollback()
+}
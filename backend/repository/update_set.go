@@ -0,0 +1,28 @@
+package repositories
+
+import (
+    "backend/models"
+    "fmt"
+    "strings"
+)
+
+// updateSet собирает SET частичного обновления из переданных полей DTO
+type updateSet struct {
+    clauses []string
+    args    []interface{}
+}
+
+// set добавляет присваивание; expr - SQL с "?" вместо значения ("name = ?")
+func (u *updateSet) set(expr string, value interface{}) {
+    u.args = append(u.args, value)
+    u.clauses = append(u.clauses, strings.Replace(expr, "?", fmt.Sprintf("$%d", len(u.args)), -1))
+}
+
+// query подставляет SET в шаблон UPDATE; %s - список присваиваний, %d - номер параметра для id
+func (u *updateSet) query(template string, id int) (string, []interface{}, error) {
+    if len(u.clauses) == 0 {
+        return "", nil, models.Invalid("no fields to update")
+    }
+    args := append(u.args, id)
+    return fmt.Sprintf(template, strings.Join(u.clauses, ", "), len(args)), args, nil
+}
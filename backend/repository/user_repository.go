@@ -4,91 +4,125 @@ import (
     "backend/models"
     "database/sql"
     "errors"
-    "strings"
-    "fmt"
 )
 
 type UserRepository struct {
-    DB *sql.DB
+    DB DBTX
 }
 
-func NewUserRepository(db *sql.DB) *UserRepository {
+func NewUserRepository(db DBTX) *UserRepository {
     return &UserRepository{DB: db}
 }
 
 // CreateUser создает нового пользователя
 func (r *UserRepository) CreateUser(user *models.User) error {
     query := `
-        INSERT INTO users (username, password_hash, role)
-        VALUES ($1, $2, $3)
+        INSERT INTO users (username, password_hash, role, teacher_id, student_id)
+        VALUES ($1, $2, $3, $4, $5)
         RETURNING id
     `
-    return r.DB.QueryRow(query, user.Username, user.PasswordHash, user.Role).Scan(&user.ID)
+    return r.DB.QueryRow(query, user.Username, user.PasswordHash, user.Role, user.TeacherID, user.StudentID).Scan(&user.ID)
 }
 
 // GetUserByUsername находит пользователя по имени
 func (r *UserRepository) GetUserByUsername(username string) (*models.User, error) {
     query := `
-        SELECT id, username, password_hash, role
+        SELECT id, username, password_hash, role, teacher_id, student_id
         FROM users
         WHERE username = $1
     `
-    row := r.DB.QueryRow(query, username)
+    return scanUser(r.DB.QueryRow(query, username))
+}
+
+// GetUserByID находит пользователя по ID
+func (r *UserRepository) GetUserByID(id int) (*models.User, error) {
+    query := `
+        SELECT id, username, password_hash, role, teacher_id, student_id
+        FROM users
+        WHERE id = $1
+    `
+    return scanUser(r.DB.QueryRow(query, id))
+}
 
+func scanUser(row *sql.Row) (*models.User, error) {
     var user models.User
-    err := row.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role)
+    var teacherID, studentID sql.NullInt64
+    err := row.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role, &teacherID, &studentID)
     if err != nil {
         if errors.Is(err, sql.ErrNoRows) {
             return nil, nil
         }
         return nil, err
     }
+    if teacherID.Valid {
+        value := int(teacherID.Int64)
+        user.TeacherID = &value
+    }
+    if studentID.Valid {
+        value := int(studentID.Int64)
+        user.StudentID = &value
+    }
     return &user, nil
 }
 
-func (r *UserRepository) UpdateTeacherProfile(userID int, updates map[string]interface{}) error {
-    if len(updates) == 0 {
-        fmt.Println("Error: no fields to update")
-        return fmt.Errorf("no fields to update")
+// UpdatePassword заменяет хэш пароля пользователя
+func (r *UserRepository) UpdatePassword(id int, passwordHash string) error {
+    result, err := r.DB.Exec(`UPDATE users SET password_hash = $1 WHERE id = $2`, passwordHash, id)
+    if err != nil {
+        return err
     }
-
-    allowedFields := map[string]bool{
-        "username": true,
-        "password": true,
+    rowsAffected, _ := result.RowsAffected()
+    if rowsAffected == 0 {
+        return models.NotFound("user with id %d not found", id)
     }
+    return nil
+}
 
-    query := "UPDATE users SET "
-    var args []interface{}
-    var setClauses []string
+// RoleExists проверяет, что роль есть в таблице ролей
+func (r *UserRepository) RoleExists(role string) (bool, error) {
+    var exists bool
+    err := r.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM roles WHERE name = $1)`, role).Scan(&exists)
+    return exists, err
+}
 
-    for key, value := range updates {
-        if !allowedFields[key] {
-            fmt.Printf("Error: invalid field %s\n", key)
-            return fmt.Errorf("invalid field: %s", key)
-        }
-        setClauses = append(setClauses, fmt.Sprintf("%s = ?", key))
-        args = append(args, value)
+// UpdateUserRole назначает пользователю роль и связанного преподавателя
+func (r *UserRepository) UpdateUserRole(id int, role string, teacherID *int) (*models.User, error) {
+    query := `
+        UPDATE users SET role = $1, teacher_id = $2
+        WHERE id = $3
+        RETURNING id, username, password_hash, role, teacher_id, student_id
+    `
+    user, err := scanUser(r.DB.QueryRow(query, role, teacherID, id))
+    if err != nil {
+        return nil, err
     }
+    if user == nil {
+        return nil, models.NotFound("user with id %d not found", id)
+    }
+    return user, nil
+}
 
-    // Логируем поля для обновления
-    fmt.Println("Fields to update:", setClauses)
-
-    // Добавляем все поля через запятую
-    query += strings.Join(setClauses, ", ")
-
-    // Добавляем условие WHERE
-    query += " WHERE id = ?"
-    args = append(args, userID)
-
-    // Логируем сформированный SQL-запрос и аргументы
-    fmt.Println("Generated query:", query)
-    fmt.Println("Query arguments:", args)
+// UpdateProfile меняет логин и/или хэш пароля пользователя; nil - поле не меняется
+func (r *UserRepository) UpdateProfile(id int, username, passwordHash *string) error {
+    var set updateSet
+    if username != nil {
+        set.set("username = ?", *username)
+    }
+    if passwordHash != nil {
+        set.set("password_hash = ?", *passwordHash)
+    }
 
-    _, err := r.DB.Exec(query, args...)
+    query, args, err := set.query(`UPDATE users SET %s WHERE id = $%d`, id)
     if err != nil {
-        fmt.Println("Database error:", err) // Логируем ошибку базы данных
         return err
     }
-
+    result, err := r.DB.Exec(query, args...)
+    if err != nil {
+        return err
+    }
+    rowsAffected, _ := result.RowsAffected()
+    if rowsAffected == 0 {
+        return models.NotFound("user with id %d not found", id)
+    }
     return nil
 }
\ No newline at end of file
@@ -0,0 +1,50 @@
+package main
+
+import (
+    "backend/config"
+    "backend/logging"
+    "bytes"
+    "encoding/json"
+    "log/slog"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+// Номер запроса возвращается в X-Request-ID и попадает в журнал вместе с маршрутом и статусом
+func TestRequestIDAndAccessLog(t *testing.T) {
+    var buf bytes.Buffer
+    defaultLogger := slog.Default()
+    slog.SetDefault(logging.New(&buf, "info", logging.FormatJSON))
+    defer slog.SetDefault(defaultLogger)
+    router := setupRouter(config.Default(), nil, nil, nil)
+
+    w := httptest.NewRecorder()
+    req := httptest.NewRequest(http.MethodGet, "/api/v1/teachers", nil)
+    req.Header.Set("X-Request-ID", "trace-42")
+    req.Header.Set("Authorization", "Bearer secret-token")
+    router.ServeHTTP(w, req)
+    if got := w.Header().Get("X-Request-ID"); got != "trace-42" {
+        t.Errorf("X-Request-ID = %q, want the client's id", got)
+    }
+
+    var entry map[string]any
+    if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+        t.Fatalf("access log is not a single JSON record: %v\n%s", err, buf.String())
+    }
+    if entry["request_id"] != "trace-42" || entry["route"] != "/api/v1/teachers" || entry["status"] != float64(http.StatusUnauthorized) || entry["level"] != "WARN" {
+        t.Errorf("unexpected access log record: %v", entry)
+    }
+    if bytes.Contains(buf.Bytes(), []byte("secret-token")) {
+        t.Errorf("token leaked into the log: %s", buf.String())
+    }
+
+    // Без заголовка номер создаётся сервером, недопустимый заменяется
+    w = httptest.NewRecorder()
+    req = httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+    req.Header.Set("X-Request-ID", "bad id\nwith newline")
+    router.ServeHTTP(w, req)
+    if got := w.Header().Get("X-Request-ID"); len(got) != 32 {
+        t.Errorf("generated X-Request-ID = %q", got)
+    }
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+    "backend/config"
+    "backend/handlers"
+    "backend/metrics"
+    "backend/migrate"
+    "backend/repository"
+    "backend/services"
+    "backend/middleware"
+    "backend/models"
+    "backend/openapi"
+    "database/sql"
+    "log/slog"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+// legacyAPIDeprecated дата выхода /api/v1, с которой маршруты /api без версии считаются устаревшими
+var legacyAPIDeprecated = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// setupRouter собирает репозитории, сервисы, обработчики и маршруты API.
+// runner - миграции для пробы готовности, tasks - фоновые задачи сервисов, которые сервер
+// дожидается при остановке (nil в тестах)
+func setupRouter(cfg *config.Config, db *sql.DB, runner *migrate.Runner, tasks *services.Background) *gin.Engine {
+    // Инициализация репозитория
+    teacherRepo := repositories.NewTeacherRepository(db)
+    studentRepo := repositories.NewStudentRepository(db)
+    courseRepo := repositories.NewCourseRepository(db)
+    classroomRepo := repositories.NewClassroomRepository(db)
+    scheduleRepo := repositories.NewScheduleRepository(db)
+    userRepo := repositories.NewUserRepository(db) // Добавляем репозиторий для пользователей
+    gradeSheetRepo := repositories.NewGradeSheetRepository(db)
+    trashRepo := repositories.NewTrashRepository(db)
+    auditRepo := repositories.NewAuditRepository(db)
+    permissionRepo := repositories.NewPermissionRepository(db)
+    attendanceRepo := repositories.NewAttendanceRepository(db)
+    announcementRepo := repositories.NewAnnouncementRepository(db)
+    activationRepo := repositories.NewActivationRepository(db)
+    guardianRepo := repositories.NewGuardianRepository(db)
+    importRepo := repositories.NewImportRepository(db)
+    searchRepo := repositories.NewSearchRepository(db)
+
+    // Инициализация сервиса
+    uow := repositories.NewUnitOfWork(db) // Транзакции из нескольких вызовов репозиториев
+    teacherService := services.NewTeacherService(teacherRepo, uow)
+    studentService := services.NewStudentService(studentRepo, uow)
+    courseService := services.NewCourseService(courseRepo, uow)
+    classroomService := services.NewClassroomService(classroomRepo, uow)
+    scheduleService := services.NewScheduleService(scheduleRepo, teacherRepo, uow) // Передаем teacherRepo
+    authService := services.NewAuthService(userRepo, uow, cfg.JWT.Secret, time.Duration(cfg.JWT.TokenTTL))       // Добавляем сервис для авторизации
+    emailService := services.NewEmailService(cfg.SMTP)
+    gradeSheetService := services.NewGradeSheetService(gradeSheetRepo, uow)
+    trashService := services.NewTrashService(trashRepo, uow)
+    auditService := services.NewAuditService(auditRepo)
+    permissionService := services.NewPermissionService(permissionRepo, userRepo, uow)
+    attendanceService := services.NewAttendanceService(attendanceRepo, uow, scheduleRepo, guardianRepo, emailService, tasks) // Сообщает представителям о пропусках
+    guardianService := services.NewGuardianService(guardianRepo, uow)
+    announcementService := services.NewAnnouncementService(announcementRepo, uow)
+    studentAccountService := services.NewStudentAccountService(activationRepo, uow)
+    importService := services.NewImportService(importRepo, uow)
+    timetableService := services.NewTimetableService(scheduleRepo)
+    searchService := services.NewSearchService(searchRepo)
+    portalService := services.NewPortalService(studentRepo, scheduleRepo, courseRepo, gradeSheetRepo, attendanceRepo, announcementRepo)
+    // Инициализация обработчика
+    // Журнал аудита пишут сервисы в транзакции изменения, обработчики передают им автора (user_id)
+    teacherHandler := handlers.NewTeacherHandler(teacherService, emailService)
+
+    studentHandler := handlers.NewStudentHandler(studentService)
+    courseHandler := handlers.NewCourseHandler(courseService)
+    classroomHandler := handlers.NewClassroomHandler(classroomService)
+    scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+    authHandler := handlers.NewAuthHandler(authService) // Добавляем обработчик для авторизации
+    gradeSheetHandler := handlers.NewGradeSheetHandler(gradeSheetService)
+    trashHandler := handlers.NewTrashHandler(trashService)
+    auditHandler := handlers.NewAuditHandler(auditService)
+    roleHandler := handlers.NewRoleHandler(permissionService)
+    attendanceHandler := handlers.NewAttendanceHandler(attendanceService)
+    announcementHandler := handlers.NewAnnouncementHandler(announcementService)
+    studentAccountHandler := handlers.NewStudentAccountHandler(studentAccountService)
+    portalHandler := handlers.NewPortalHandler(portalService)
+    configHandler := handlers.NewConfigHandler(cfg)
+    guardianHandler := handlers.NewGuardianHandler(guardianService, portalService)
+    importHandler := handlers.NewImportHandler(importService)
+    timetableHandler := handlers.NewTimetableHandler(timetableService)
+    searchHandler := handlers.NewSearchHandler(searchService)
+    // Готовность: база доступна и схема совпадает со встроенными миграциями. Runner создаётся один раз
+    // при запуске, проба только читает таблицу версий
+    var schema func() error
+    if runner != nil {
+        schema = runner.Current
+    }
+    healthHandler := handlers.NewHealthHandler(db, schema)
+
+    // Роутер
+    r := gin.New()
+
+    // Мониторинг: пробы и метрики регистрируются до middleware, чтобы частые опросы не попадали в журнал и метрики запросов
+    r.GET("/healthz", healthHandler.Live)
+    r.GET("/readyz", healthHandler.Ready)
+    r.GET("/metrics", gin.WrapH(metrics.Handler(metrics.Default, metrics.DBStats(db))))
+
+    r.Use(middleware.RequestID())                                // X-Request-ID и поля запроса для журнала
+    r.Use(middleware.AccessLog(slog.Default()))                  // Запись о каждом запросе
+    r.Use(middleware.Metrics())                                  // Счётчики и длительность запросов для /metrics
+    r.Use(middleware.SecurityHeaders(cfg.Server.TLS()))          // nosniff, запрет фреймов, HSTS при HTTPS
+    r.Use(middleware.CORS(cfg.Server.CORSOrigins))               // Запросы фронтенда с других источников
+    r.Use(middleware.BodyLimit(int64(cfg.Server.MaxBodyBytes)))  // Предел тела запроса, 413 при превышении
+    r.Use(middleware.ErrorMiddleware())                          // Ошибки из c.Error - в единый JSON-ответ
+    r.Use(middleware.Recovery())                                 // Паника обработчика - ответ 500
+
+    spec := apiSpec().Handler()
+
+    // routes регистрирует маршруты API в группе: /api/v1 и устаревшая /api без версии
+    routes := func(api *gin.RouterGroup) {
+        // Маршруты для авторизации
+        api.POST("/register", authHandler.Register) // Регистрация нового пользователя
+        api.POST("/login", authHandler.Login)       // Авторизация пользователя
+        api.POST("/activate", studentAccountHandler.Activate) // Активация учётной записи студента по коду
+
+        // Описание API (OpenAPI 3) и страница документации к нему
+        api.GET("/openapi.json", spec)
+        api.GET("/docs", openapi.UI(api.BasePath()+"/openapi.json"))
+
+        // Защищенные маршруты
+        authorized := api.Group("/")
+        authorized.Use(middleware.AuthMiddleware(cfg.JWT.Secret, userRepo)) // Middleware для проверки JWT-токена
+
+        // can пропускает запрос, если у роли пользователя есть хотя бы одно из прав
+        can := func(permissions ...string) gin.HandlerFunc {
+            return middleware.PermissionMiddleware(permissionService, permissions...)
+        }
+
+        authorized.GET("/admin", can(models.PermUsersManage), func(c *gin.Context) {
+            c.JSON(200, gin.H{"message": "welcome, admin!"})
+        })
+        authorized.GET("/admin/trash", can(models.PermTrashManage), trashHandler.GetTrash) // Корзина удалённых записей
+        authorized.GET("/admin/config", can(models.PermUsersManage), configHandler.GetConfig) // Настройки без секретов
+
+        // Журнал аудита
+        authorized.GET("/audit", can(models.PermAuditRead), auditHandler.GetAuditLog)
+        authorized.GET("/audit/:entity_type/:entity_id", can(models.PermAuditRead), auditHandler.GetEntityHistory)
+
+        // Роли и права
+        authorized.GET("/roles", can(models.PermUsersManage), roleHandler.GetRoles)
+        authorized.POST("/roles", can(models.PermUsersManage), roleHandler.CreateRole)
+        authorized.PUT("/roles/:name/permissions", can(models.PermUsersManage), roleHandler.SetRolePermissions)
+        authorized.DELETE("/roles/:name", can(models.PermUsersManage), roleHandler.DeleteRole)
+        authorized.GET("/permissions", can(models.PermUsersManage), roleHandler.GetPermissions)
+        authorized.PATCH("/users/:id/role", can(models.PermUsersManage), roleHandler.AssignUserRole)
+
+        // Поиск по людям, курсам и аудиториям; в выдаче только типы, доступные роли
+        authorized.GET("/search", can(models.PermStudentsRead, models.PermTeachersRead, models.PermCoursesRead, models.PermClassroomsRead), searchHandler.Search)
+
+        authorized.GET("/teachers", can(models.PermTeachersRead), teacherHandler.GetAllTeachers)
+        authorized.POST("/teachers", can(models.PermTeachersWrite), teacherHandler.CreateTeacher)
+        authorized.PATCH("/teachers/:id", can(models.PermTeachersWrite), teacherHandler.UpdateTeacherPartial)
+        authorized.DELETE("/teachers/:id", can(models.PermTeachersWrite), teacherHandler.DeleteTeacher) // ?confirm=true, если удаляются и занятия
+        authorized.POST("/teachers/:id/restore", can(models.PermTrashManage), trashHandler.Restore("teachers"))
+        authorized.POST("/teachers/import", can(models.PermTeachersWrite), importHandler.Import("teachers")) // CSV/XLSX, ?dry_run=true
+
+        authorized.GET("/students", can(models.PermStudentsRead), studentHandler.GetStudents)
+        authorized.POST("/students", can(models.PermStudentsWrite), studentHandler.CreateStudent)
+        authorized.GET("/students/:id", can(models.PermStudentsRead), studentHandler.GetStudentByID)
+        authorized.PATCH("/students/:id", can(models.PermStudentsWrite), studentHandler.UpdateStudent)
+        authorized.DELETE("/students/:id", can(models.PermStudentsWrite), studentHandler.DeleteStudent)
+        authorized.POST("/students/:id/restore", can(models.PermTrashManage), trashHandler.Restore("students"))
+        authorized.POST("/students/import", can(models.PermStudentsWrite), importHandler.Import("students"))
+        authorized.POST("/students/:id/status", can(models.PermStudentsWrite), studentHandler.ChangeStudentStatus) // Смена статуса по приказу
+        authorized.POST("/students/:id/transfer", can(models.PermStudentsWrite), studentHandler.TransferStudent)   // Перевод в другую группу по приказу
+        authorized.GET("/students/:id/orders", can(models.PermStudentsRead), studentHandler.GetStudentOrders)     // Журнал приказов
+        authorized.GET("/students/:id/group-history", can(models.PermStudentsRead), studentHandler.GetStudentGroupHistory)
+        authorized.POST("/students/accounts", can(models.PermUsersManage), studentAccountHandler.ProvisionAccounts) // Коды активации для студентов
+
+        // Законные представители
+        authorized.GET("/students/:id/guardians", can(models.PermStudentsRead), guardianHandler.GetStudentGuardians)
+        authorized.POST("/students/:id/guardians", can(models.PermGuardiansWrite), guardianHandler.CreateGuardian)
+        authorized.PATCH("/guardians/:id", can(models.PermGuardiansWrite), guardianHandler.UpdateGuardian)
+        authorized.DELETE("/guardians/:id", can(models.PermGuardiansWrite), guardianHandler.DeleteGuardian)
+        authorized.POST("/guardians/:id/account", can(models.PermUsersManage), guardianHandler.CreateGuardianAccount)
+        authorized.PUT("/guardians/:id/account", can(models.PermUsersManage), guardianHandler.LinkGuardianAccount)
+
+        authorized.GET("/courses", can(models.PermCoursesRead), courseHandler.GetCourses)
+        authorized.POST("/courses", can(models.PermCoursesWrite), courseHandler.CreateCourse)
+        authorized.GET("/courses/:id", can(models.PermCoursesRead), courseHandler.GetCourseByID)
+        authorized.PATCH("/courses/:id", can(models.PermCoursesWrite), courseHandler.UpdateCourse)
+        authorized.DELETE("/courses/:id", can(models.PermCoursesWrite), courseHandler.DeleteCourse)
+        authorized.POST("/courses/:id/restore", can(models.PermTrashManage), trashHandler.Restore("courses"))
+        authorized.POST("/courses/import", can(models.PermCoursesWrite), importHandler.Import("courses"))
+
+        authorized.POST("/classrooms", can(models.PermClassroomsWrite), classroomHandler.CreateClassroom)
+        authorized.GET("/classrooms", can(models.PermClassroomsRead), classroomHandler.GetClassrooms)
+        authorized.GET("/classrooms/:id", can(models.PermClassroomsRead), classroomHandler.GetClassroomByID)
+        authorized.PATCH("/classrooms/:id", can(models.PermClassroomsWrite), classroomHandler.UpdateClassroom)
+        authorized.DELETE("/classrooms/:id", can(models.PermClassroomsWrite), classroomHandler.DeleteClassroom)
+        authorized.POST("/classrooms/:id/restore", can(models.PermTrashManage), trashHandler.Restore("classrooms"))
+        authorized.POST("/classrooms/import", can(models.PermClassroomsWrite), importHandler.Import("classrooms"))
+
+        authorized.POST("/schedules", can(models.PermScheduleWrite), scheduleHandler.CreateSchedule)
+        authorized.GET("/schedules", can(models.PermScheduleRead), scheduleHandler.GetSchedules)
+        authorized.GET("/schedules/:id", can(models.PermScheduleRead), scheduleHandler.GetScheduleByID)
+        authorized.PATCH("/schedules/:id", can(models.PermScheduleWrite), scheduleHandler.UpdateSchedule)
+        authorized.DELETE("/schedules/:id", can(models.PermScheduleWrite), scheduleHandler.DeleteSchedule)
+        authorized.POST("/schedules/:id/restore", can(models.PermTrashManage), trashHandler.Restore("schedules"))
+        authorized.GET("/schedules/day/:day", can(models.PermScheduleRead), scheduleHandler.GetSchedulesByDay) // Просмотр расписания по дню недели
+        authorized.GET("/schedules/group/:group_name", can(models.PermScheduleRead), scheduleHandler.GetSchedulesByGroup)
+        authorized.GET("/teachers/:teacher_name/schedule", can(models.PermScheduleRead), teacherHandler.GetTeacherSchedule)
+        authorized.PUT("/schedules/:id/overrides/:date", can(models.PermScheduleWrite), scheduleHandler.SaveScheduleOverride) // Отмена или перенос на дату
+        authorized.DELETE("/schedules/:id/overrides/:date", can(models.PermScheduleWrite), scheduleHandler.DeleteScheduleOverride)
+
+        // Сетки расписания для печати: ?format=json|html|pdf, ?date= - конкретная неделя
+        authorized.GET("/timetables/groups/:name", can(models.PermScheduleRead), timetableHandler.GetGrid("group"))
+        authorized.GET("/timetables/teachers/:name", can(models.PermScheduleRead), timetableHandler.GetGrid("teacher"))
+        authorized.GET("/timetables/classrooms/:name", can(models.PermScheduleRead), timetableHandler.GetGrid("classroom"))
+        authorized.GET("/timetables/poster", can(models.PermScheduleRead), timetableHandler.GetPoster) // Все группы рядом
+
+        // Посещаемость; с правом :own-courses - только на своих занятиях
+        authorized.POST("/schedules/:id/attendance", can(models.PermAttendanceWrite, models.PermAttendanceWriteOwn), attendanceHandler.MarkAttendance)
+        authorized.GET("/schedules/:id/attendance", can(models.PermAttendanceWrite, models.PermAttendanceWriteOwn), attendanceHandler.GetScheduleAttendance)
+
+        // Объявления
+        authorized.GET("/announcements", can(models.PermAnnouncementsWrite), announcementHandler.GetAnnouncements)
+        authorized.POST("/announcements", can(models.PermAnnouncementsWrite), announcementHandler.CreateAnnouncement)
+        authorized.DELETE("/announcements/:id", can(models.PermAnnouncementsWrite), announcementHandler.DeleteAnnouncement)
+
+        // Личный кабинет студента: только данные студента из токена
+        me := authorized.Group("/me")
+        me.Use(can(models.PermPortalRead))
+        {
+            me.GET("", portalHandler.GetProfile)
+            me.GET("/timetable", portalHandler.GetTimetable)
+            me.GET("/grades", portalHandler.GetGrades)
+            me.GET("/attendance", portalHandler.GetAttendance)
+            me.GET("/courses", portalHandler.GetCourses)
+            me.GET("/announcements", portalHandler.GetAnnouncements)
+        }
+
+        // Кабинет представителя: только связанные студенты, только чтение
+        guardian := authorized.Group("/guardian")
+        guardian.Use(can(models.PermGuardianRead))
+        {
+            guardian.GET("/students", guardianHandler.GetLinkedStudents)
+            guardian.GET("/students/:id/timetable", guardianHandler.GetStudentTimetable)
+            guardian.GET("/students/:id/attendance", guardianHandler.GetStudentAttendance)
+            guardian.GET("/students/:id/grades", guardianHandler.GetStudentGrades)
+        }
+
+        authorized.PUT("/teacher/profile", can(models.PermProfileWrite), authHandler.UpdateProfile)
+
+        // Ведомости: черновик -> выдана -> заполнена -> закрыта
+        authorized.POST("/grade-sheets", can(models.PermGradeSheetsManage), gradeSheetHandler.CreateGradeSheet)
+        authorized.GET("/grade-sheets", can(models.PermGradeSheetsManage, models.PermGradesRead), gradeSheetHandler.GetGradeSheets)
+        authorized.POST("/grade-sheets/:id/issue", can(models.PermGradeSheetsManage), gradeSheetHandler.IssueGradeSheet)
+        authorized.POST("/grade-sheets/:id/close", can(models.PermGradeSheetsManage), gradeSheetHandler.CloseGradeSheet)
+        authorized.POST("/grade-sheets/:id/retake", can(models.PermGradeSheetsManage), gradeSheetHandler.CreateRetakeSheet)
+        authorized.DELETE("/grade-sheets/:id", can(models.PermGradeSheetsManage), gradeSheetHandler.DeleteGradeSheet)
+        authorized.GET("/students/:id/grades", can(models.PermGradesRead), gradeSheetHandler.GetStudentGrades) // Зачётная книжка
+
+        // Экзаменатор заполняет выданную ведомость; с правом :own-courses - только свою
+        authorized.GET("/grade-sheets/:id", can(models.PermGradesRead, models.PermGradesReadOwn), gradeSheetHandler.GetGradeSheetByID)
+        authorized.PATCH("/grade-sheets/:id/marks", can(models.PermGradesWrite, models.PermGradesWriteOwn), gradeSheetHandler.FillGradeSheet)
+        authorized.GET("/grade-sheets/:id/export", can(models.PermGradesRead, models.PermGradesReadOwn), gradeSheetHandler.ExportGradeSheet)
+
+        // Новый маршрут для отправки email-уведомлений
+        authorized.POST("/notify", can(models.PermNotifySend), teacherHandler.NotifyTeacher)
+    }
+
+    routes(r.Group("/api/v1", middleware.APIVersion(middleware.APIv1)))
+    // Старые маршруты работают как раньше, но помечены устаревшими до даты отключения
+    routes(r.Group("/api", middleware.APIVersion(middleware.APILegacy),
+        middleware.Deprecated(legacyAPIDeprecated, cfg.Server.LegacySunset(), "/api", "/api/v1", "/api/v1/docs")))
+    return r
+}
\ No newline at end of file
@@ -0,0 +1,79 @@
+package main
+
+import (
+    "backend/config"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+// Предварительный запрос браузера с разрешённого источника получает 204 и заголовки CORS, с чужого - нет
+func TestCORS(t *testing.T) {
+    cfg := config.Default()
+    cfg.Server.CORSOrigins = []string{"https://college.example.com/"}
+    router := setupRouter(cfg, nil, nil, nil)
+
+    preflight := func(origin string) *httptest.ResponseRecorder {
+        w := httptest.NewRecorder()
+        req := httptest.NewRequest(http.MethodOptions, "/api/v1/students", nil)
+        req.Header.Set("Origin", origin)
+        req.Header.Set("Access-Control-Request-Method", "POST")
+        router.ServeHTTP(w, req)
+        return w
+    }
+
+    w := preflight("https://college.example.com")
+    if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://college.example.com" ||
+        !strings.Contains(w.Header().Get("Access-Control-Allow-Headers"), "Authorization") {
+        t.Errorf("allowed origin: status %d, headers %v", w.Code, w.Header())
+    }
+    if w := preflight("https://evil.example.com"); w.Header().Get("Access-Control-Allow-Origin") != "" {
+        t.Errorf("foreign origin allowed: %v", w.Header())
+    }
+
+    w = httptest.NewRecorder()
+    req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+    req.Header.Set("Origin", "https://college.example.com")
+    router.ServeHTTP(w, req)
+    if w.Header().Get("Access-Control-Allow-Origin") != "https://college.example.com" || !strings.Contains(w.Header().Get("Access-Control-Expose-Headers"), "X-Request-ID") {
+        t.Errorf("simple request: headers %v", w.Header())
+    }
+}
+
+// Заголовки безопасности есть в каждом ответе API, тело больше предела отклоняется с 413
+func TestSecurityHeadersAndBodyLimit(t *testing.T) {
+    cfg := config.Default()
+    cfg.Server.MaxBodyBytes = 1024
+    router := setupRouter(cfg, nil, nil, nil)
+
+    w := httptest.NewRecorder()
+    body := `{"username": "admin", "password": "` + strings.Repeat("x", 2048) + `"}`
+    req := httptest.NewRequest(http.MethodPost, "/api/v1/login", strings.NewReader(body))
+    req.Header.Set("Content-Type", "application/json")
+    router.ServeHTTP(w, req)
+    if w.Code != http.StatusRequestEntityTooLarge || !strings.Contains(w.Body.String(), `"too_large"`) {
+        t.Errorf("oversized body: %d %s, want 413 too_large", w.Code, w.Body)
+    }
+    for header, want := range map[string]string{
+        "X-Content-Type-Options": "nosniff",
+        "X-Frame-Options":        "DENY",
+        "Referrer-Policy":        "no-referrer",
+    } {
+        if got := w.Header().Get(header); got != want {
+            t.Errorf("%s = %q, want %q", header, got, want)
+        }
+    }
+    if w.Header().Get("Strict-Transport-Security") != "" {
+        t.Error("HSTS must be sent only over HTTPS")
+    }
+}
+
+func TestHTTPServerTimeouts(t *testing.T) {
+    cfg := config.Default().Server
+    server := newHTTPServer(cfg, http.NotFoundHandler())
+    if server.Addr != ":8080" || server.ReadHeaderTimeout != 5*time.Second || server.WriteTimeout != 2*time.Minute || server.IdleTimeout != 2*time.Minute {
+        t.Errorf("server = addr %s, read header %v, write %v, idle %v", server.Addr, server.ReadHeaderTimeout, server.WriteTimeout, server.IdleTimeout)
+    }
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+    "backend/models"
+    "backend/repository"
+)
+
+type AnnouncementService struct {
+    Repo repositories.AnnouncementStore
+    UoW  repositories.Transactor
+}
+
+func NewAnnouncementService(repo repositories.AnnouncementStore, uow repositories.Transactor) *AnnouncementService {
+    return &AnnouncementService{Repo: repo, UoW: uow}
+}
+
+func (s *AnnouncementService) CreateAnnouncement(announcement *models.Announcement, userID int) error {
+    if err := models.Validate.Struct(announcement); err != nil {
+        return models.FromValidation(err)
+    }
+    // Пустая группа означает объявление для всех
+    if announcement.GroupName != nil && *announcement.GroupName == "" {
+        announcement.GroupName = nil
+    }
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Announcements.CreateAnnouncement(announcement); err != nil {
+            return err
+        }
+        return record(tx, userID, "create", "announcements", announcement.ID, nil, announcement)
+    })
+}
+
+func (s *AnnouncementService) GetAnnouncements(groupName string) ([]models.Announcement, error) {
+    return s.Repo.GetAnnouncements(groupName)
+}
+
+func (s *AnnouncementService) DeleteAnnouncement(id, userID int) error {
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Announcements.DeleteAnnouncement(id); err != nil {
+            return err
+        }
+        return record(tx, userID, "delete", "announcements", id, nil, nil)
+    })
+}
\ No newline at end of file
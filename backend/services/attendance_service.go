@@ -0,0 +1,135 @@
+package services
+
+import (
+    "backend/models"
+    "backend/repository"
+    "backend/utils"
+    "fmt"
+    "html"
+    "log/slog"
+    "time"
+)
+
+type AttendanceService struct {
+    Repo         repositories.AttendanceStore
+    UoW          repositories.Transactor
+    ScheduleRepo repositories.ScheduleStore
+    GuardianRepo repositories.GuardianStore
+    Email        *EmailService
+    Tasks        *Background // Уведомления дожидаются при остановке сервера
+}
+
+func NewAttendanceService(
+    repo repositories.AttendanceStore,
+    uow repositories.Transactor,
+    scheduleRepo repositories.ScheduleStore,
+    guardianRepo repositories.GuardianStore,
+    email *EmailService,
+    tasks *Background,
+) *AttendanceService {
+    return &AttendanceService{Repo: repo, UoW: uow, ScheduleRepo: scheduleRepo, GuardianRepo: guardianRepo, Email: email, Tasks: tasks}
+}
+
+// ScheduleTeacherID возвращает преподавателя занятия (для правила "только свои занятия")
+func (s *AttendanceService) ScheduleTeacherID(scheduleID int) (int, error) {
+    return s.ScheduleRepo.GetScheduleTeacherID(scheduleID)
+}
+
+// MarkAttendance отмечает посещаемость занятия на дату; markedBy - автор отметок и записи в журнале аудита
+func (s *AttendanceService) MarkAttendance(scheduleID int, date string, marks map[int]string, markedBy int) ([]models.Attendance, error) {
+    schedule, err := s.ScheduleRepo.GetScheduleByID(scheduleID)
+    if err != nil {
+        return nil, err
+    }
+
+    day, err := time.Parse("2006-01-02", date)
+    if err != nil {
+        return nil, models.Invalid("invalid date format. Use YYYY-MM-DD")
+    }
+    if day.Weekday().String() != schedule.DayOfWeek {
+        return nil, models.Invalid("invalid date: lesson takes place on %s", schedule.DayOfWeek)
+    }
+    if !schedule.OccursOn(day) {
+        return nil, models.Invalid("invalid date: lesson takes place on %s weeks only", schedule.WeekType)
+    }
+    if day.After(today()) {
+        return nil, models.Invalid("invalid date: cannot mark attendance in the future")
+    }
+
+    if len(marks) == 0 {
+        return nil, models.Invalid("no marks to save")
+    }
+    for studentID, status := range marks {
+        if !models.IsValidAttendanceStatus(status) {
+            return nil, models.Invalid("invalid status '%s' for student %d", status, studentID)
+        }
+    }
+
+    // Запоминаем прежние отметки, чтобы не уведомлять о пропуске повторно
+    var previous, attendance []models.Attendance
+    err = s.UoW.Do(func(tx *repositories.Tx) error {
+        var err error
+        if previous, err = tx.Attendance.GetScheduleAttendance(scheduleID, date); err != nil {
+            return err
+        }
+        if attendance, err = tx.Attendance.MarkAttendance(scheduleID, date, marks, markedBy); err != nil {
+            return err
+        }
+        return record(tx, markedBy, "mark_attendance", "schedules", scheduleID, previous, attendance)
+    })
+    if err != nil {
+        return nil, err
+    }
+    wasAbsent := make(map[int]bool, len(previous))
+    for _, item := range previous {
+        wasAbsent[item.StudentID] = item.Status == models.AttendanceAbsent
+    }
+
+    absent := []int{}
+    for _, item := range attendance {
+        if item.Status == models.AttendanceAbsent && !wasAbsent[item.StudentID] {
+            absent = append(absent, item.StudentID)
+        }
+    }
+    if len(absent) > 0 && s.GuardianRepo != nil && s.Email != nil {
+        // Почта отправляется в фоне, чтобы не задерживать ответ преподавателю
+        s.Tasks.Go(func() { s.notifyAbsences(schedule, date, absent) })
+    }
+
+    return attendance, nil
+}
+
+// notifyAbsences сообщает представителям несовершеннолетних студентов о пропуске занятия
+func (s *AttendanceService) notifyAbsences(schedule *models.Schedule, date string, studentIDs []int) {
+    contacts, err := s.GuardianRepo.GetAbsenceContacts(studentIDs)
+    if err != nil {
+        slog.Error("failed to load guardian contacts", "schedule_id", schedule.ID, "error", err)
+        return
+    }
+
+    for _, contact := range contacts {
+        if utils.CalculateAge(contact.DateOfBirth) >= 18 {
+            continue
+        }
+
+        subject := fmt.Sprintf("Пропуск занятия: %s", contact.StudentName)
+        body := fmt.Sprintf(
+            "<p>Здравствуйте, %s!</p><p>%s отсутствовал(а) на занятии группы %s %s в %s.</p>",
+            html.EscapeString(contact.GuardianName),
+            html.EscapeString(contact.StudentName),
+            html.EscapeString(schedule.GroupName),
+            date,
+            schedule.StartTime.Format("15:04"),
+        )
+        if err := s.Email.SendEmail(contact.Email, subject, body); err != nil {
+            slog.Error("failed to send absence notification", "schedule_id", schedule.ID, "student_id", contact.StudentID, "error", err)
+        }
+    }
+}
+
+func (s *AttendanceService) GetScheduleAttendance(scheduleID int, date string) ([]models.Attendance, error) {
+    if _, err := time.Parse("2006-01-02", date); err != nil {
+        return nil, models.Invalid("invalid date format. Use YYYY-MM-DD")
+    }
+    return s.Repo.GetScheduleAttendance(scheduleID, date)
+}
\ No newline at end of file
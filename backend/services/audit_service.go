@@ -0,0 +1,69 @@
+package services
+
+import (
+    "backend/models"
+    "backend/repository"
+    "encoding/json"
+    "maps"
+    "slices"
+)
+
+type AuditService struct {
+    Repo repositories.AuditStore
+}
+
+func NewAuditService(repo repositories.AuditStore) *AuditService {
+    return &AuditService{Repo: repo}
+}
+
+// record записывает изменение сущности в журнал в транзакции самого изменения: при ошибке журнала
+// изменение откатывается, при откате изменения пропадает и запись. userID - автор изменения,
+// 0 - без пользователя (командная строка, открытая регистрация). before и after сериализуются в JSON (nil - пусто)
+func record(tx *repositories.Tx, userID int, action, entityType string, entityID int, before, after interface{}) error {
+    entry := &models.AuditEntry{
+        Action:     action,
+        EntityType: entityType,
+        EntityID:   entityID,
+    }
+    if userID != 0 {
+        entry.UserID = &userID
+    }
+
+    var err error
+    if entry.Before, err = marshalState(before); err != nil {
+        return err
+    }
+    if entry.After, err = marshalState(after); err != nil {
+        return err
+    }
+
+    return tx.Audit.CreateEntry(entry)
+}
+
+// recordCascade записывает записи, затронутые каскадным удалением или восстановлением
+func recordCascade(tx *repositories.Tx, userID int, action string, cascade models.Cascade) error {
+    for _, entityType := range slices.Sorted(maps.Keys(cascade)) {
+        for _, id := range cascade[entityType] {
+            if err := record(tx, userID, action, entityType, id, nil, nil); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
+
+func marshalState(state interface{}) (json.RawMessage, error) {
+    if state == nil {
+        return nil, nil
+    }
+    return json.Marshal(state)
+}
+
+func (s *AuditService) GetEntries(filter models.AuditFilter) ([]models.AuditEntry, error) {
+    return s.Repo.GetEntries(filter)
+}
+
+// GetEntityHistory возвращает все изменения одной сущности
+func (s *AuditService) GetEntityHistory(entityType string, entityID int) ([]models.AuditEntry, error) {
+    return s.Repo.GetEntries(models.AuditFilter{EntityType: entityType, EntityID: entityID})
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+package services
+
+import (
+    "backend/models"
+    "backend/repository/memory"
+    "testing"
+)
+
+// Журнал пишется в транзакции изменения: у записей настоящие id сущностей и автор, а отменённое
+// изменение не оставляет записей
+func TestAuditRecordedWithChange(t *testing.T) {
+    f := newFixture(t)
+    audit := memory.NewAuditRepository(f.db)
+    teacherID := f.teacher(t, "Иванов", 10)
+    f.course(t, "ИВТ-21", nil)
+    classroomID := f.classroom(t, "101")
+
+    schedule := lesson("Monday", "09:00", "10:30", "")
+    if err := f.schedules.CreateSchedule(teacherID, classroomID, schedule, 7); err != nil {
+        t.Fatalf("create schedule: %v", err)
+    }
+    if err := f.schedules.CreateSchedule(teacherID, 999, lesson("Tuesday", "09:00", "10:30", ""), 7); errorCode(err) != models.CodeNotFound {
+        t.Fatalf("create schedule in missing classroom: %v", err)
+    }
+    if _, err := f.teachers.DeleteTeacher(teacherID, true, 7); err != nil {
+        t.Fatalf("delete teacher: %v", err)
+    }
+
+    entries, err := audit.GetEntries(models.AuditFilter{UserID: 7})
+    if err != nil {
+        t.Fatalf("get entries: %v", err)
+    }
+    want := []struct {
+        action, entityType string
+        entityID           int
+    }{
+        {"cascade_delete", "schedules", schedule.ID},
+        {"delete", "teachers", teacherID},
+        {"create", "schedules", schedule.ID},
+    }
+    if len(entries) != len(want) {
+        t.Fatalf("entries = %+v, want %d", entries, len(want))
+    }
+    for i, w := range want {
+        if entries[i].Action != w.action || entries[i].EntityType != w.entityType || entries[i].EntityID != w.entityID {
+            t.Errorf("entry %d = %s %s %d, want %s %s %d", i, entries[i].Action, entries[i].EntityType, entries[i].EntityID, w.action, w.entityType, w.entityID)
+        }
+    }
+    if entries[1].Before == nil || entries[2].After == nil {
+        t.Errorf("entries without state: %+v", entries)
+    }
+}
+
+// Без пользователя (командная строка) запись пишется с пустым user_id
+func TestAuditWithoutUser(t *testing.T) {
+    f := newFixture(t)
+    teacherID := f.teacher(t, "Иванов", 10)
+
+    entries, err := memory.NewAuditRepository(f.db).GetEntries(models.AuditFilter{EntityType: "teachers", EntityID: teacherID})
+    if err != nil {
+        t.Fatalf("get entries: %v", err)
+    }
+    if len(entries) != 1 || entries[0].Action != "create" || entries[0].UserID != nil {
+        t.Errorf("entries = %+v, want one create without user", entries)
+    }
+}
@@ -1,42 +1,80 @@
 package services
 
 import (
+	"backend/metrics"
 	"backend/models"
 	"backend/repository"
-	"errors"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService struct {
-    Repo *repositories.UserRepository
+    Repo repositories.UserStore
+    UoW  repositories.Transactor
     SecretKey string
+    TokenTTL  time.Duration
 }
 
-func NewAuthService(repo *repositories.UserRepository, secretKey string) *AuthService {
-    return &AuthService{Repo: repo, SecretKey: secretKey}
+func NewAuthService(repo repositories.UserStore, uow repositories.Transactor, secretKey string, tokenTTL time.Duration) *AuthService {
+    return &AuthService{Repo: repo, UoW: uow, SecretKey: secretKey, TokenTTL: tokenTTL}
 }
 
-// Register регистрирует нового пользователя
-func (s *AuthService) Register(username, password, role string) error {
-    // Проверяем роль
-    if role != "admin" && role != "teacher" {
-        return errors.New("invalid role")
+// SelfRegisterRole единственная роль, которую можно получить открытой регистрацией. Прав у неё нет:
+// доступ появляется, когда администратор назначит роль; студенты получают учётную запись по коду активации
+const SelfRegisterRole = "pending"
+
+// SignUp открытая регистрация (POST /register): роль не указывается или равна SelfRegisterRole
+func (s *AuthService) SignUp(username, password, role string) (*models.User, error) {
+    if role == "" {
+        role = SelfRegisterRole
+    }
+    if role != SelfRegisterRole {
+        return nil, models.Forbidden("registration is only available for role '%s', other roles are assigned by an administrator", SelfRegisterRole)
     }
+    return s.Register(username, password, role, 0)
+}
 
+// Register создаёт пользователя с любой существующей ролью; для командной строки и доверенного кода,
+// открытая регистрация идёт через SignUp. userID - автор записи в журнале аудита
+func (s *AuthService) Register(username, password, role string, userID int) (*models.User, error) {
     // Хэшируем пароль
     user := &models.User{
         Username: username,
         Role:     role,
     }
     if err := user.HashPassword(password); err != nil {
-        return err
+        return nil, err
     }
 
-    // Создаем пользователя
-    return s.Repo.CreateUser(user)
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        // Проверяем, что роль существует
+        exists, err := tx.Users.RoleExists(role)
+        if err != nil {
+            return err
+        }
+        if !exists {
+            return models.Invalid("invalid role")
+        }
+
+        existing, err := tx.Users.GetUserByUsername(username)
+        if err != nil {
+            return err
+        }
+        if existing != nil {
+            return models.Conflict("username already taken")
+        }
+
+        // Создаем пользователя
+        if err := tx.Users.CreateUser(user); err != nil {
+            return err
+        }
+        return record(tx, userID, "create", "users", user.ID, nil, user)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return user, nil
 }
 
 // Login авторизует пользователя и возвращает JWT-токен
@@ -46,15 +84,23 @@ func (s *AuthService) Login(username, password string) (string, error) {
         return "", err
     }
     if user == nil || !user.CheckPassword(password) {
-        return "", errors.New("invalid credentials")
+        metrics.LoginFailures.Inc()
+        return "", models.Unauthorized("invalid credentials")
     }
 
     // Генерируем JWT-токен
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+    claims := jwt.MapClaims{
         "user_id": user.ID,
         "role":    user.Role,
-        "exp":     time.Now().Add(time.Hour * 24).Unix(), // Токен действителен 24 часа
-    })
+        "exp":     time.Now().Add(s.TokenTTL).Unix(), // Срок действия из настроек (jwt.token_ttl)
+    }
+    if user.TeacherID != nil {
+        claims["teacher_id"] = *user.TeacherID // Нужен для правил "только свои курсы"
+    }
+    if user.StudentID != nil {
+        claims["student_id"] = *user.StudentID // Личный кабинет студента видит только свои данные
+    }
+    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
     tokenString, err := token.SignedString([]byte(s.SecretKey))
     if err != nil {
@@ -64,20 +110,59 @@ func (s *AuthService) Login(username, password string) (string, error) {
     return tokenString, nil
 }
 
-func (s *AuthService) UpdateTeacherProfile(userID int, updates map[string]interface{}) error {
-    // Если передан новый пароль, хэшируем его
-    if newPassword, ok := updates["password"].(string); ok {
-        hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+// ResetPassword задаёт пользователю новый пароль (используется из командной строки)
+func (s *AuthService) ResetPassword(username, password string, userID int) error {
+    if password == "" {
+        return models.Invalid("password is required")
+    }
+
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        user, err := tx.Users.GetUserByUsername(username)
         if err != nil {
             return err
         }
-        updates["password"] = string(hashedPassword)
+        if user == nil {
+            return models.NotFound("user '%s' not found", username)
+        }
+
+        if err := user.HashPassword(password); err != nil {
+            return err
+        }
+        if err := tx.Users.UpdatePassword(user.ID, user.PasswordHash); err != nil {
+            return err
+        }
+        // Пароль, даже хэш, в журнал не попадает
+        return record(tx, userID, "reset_password", "users", user.ID, nil, nil)
+    })
+}
+
+// UpdateProfile меняет логин и/или пароль пользователя; пароль сохраняется только хэшем
+func (s *AuthService) UpdateProfile(userID int, update models.ProfileUpdate) error {
+    if err := models.Validate.Struct(update); err != nil {
+        return models.FromValidation(err)
     }
 
-    // Обновляем данные в базе данных
-    if err := s.Repo.UpdateTeacherProfile(userID, updates); err != nil {
-        return err
+    var passwordHash *string
+    if update.Password != nil {
+        var user models.User
+        if err := user.HashPassword(*update.Password); err != nil {
+            return err
+        }
+        passwordHash = &user.PasswordHash
     }
 
-    return nil
+    // Новый пароль в журнал не попадает
+    changes := map[string]interface{}{}
+    if update.Username != nil {
+        changes["username"] = *update.Username
+    }
+    if update.Password != nil {
+        changes["password"] = "[changed]"
+    }
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Users.UpdateProfile(userID, update.Username, passwordHash); err != nil {
+            return err
+        }
+        return record(tx, userID, "update", "users", userID, nil, changes)
+    })
 }
\ No newline at end of file
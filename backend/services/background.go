@@ -0,0 +1,43 @@
+package services
+
+import (
+    "context"
+    "sync"
+)
+
+// Background фоновые задачи (отправка писем), которые сервер дожидается при остановке
+type Background struct {
+    wg sync.WaitGroup
+}
+
+func NewBackground() *Background {
+    return &Background{}
+}
+
+// Go запускает задачу в отдельной горутине. Без Background задача просто запускается в фоне
+func (b *Background) Go(task func()) {
+    if b == nil {
+        go task()
+        return
+    }
+    b.wg.Add(1)
+    go func() {
+        defer b.wg.Done()
+        task()
+    }()
+}
+
+// Wait ждёт завершения запущенных задач или отмены ctx
+func (b *Background) Wait(ctx context.Context) error {
+    done := make(chan struct{})
+    go func() {
+        b.wg.Wait()
+        close(done)
+    }()
+    select {
+    case <-done:
+        return nil
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
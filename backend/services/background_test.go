@@ -0,0 +1,31 @@
+package services
+
+import (
+    "context"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestBackgroundWait(t *testing.T) {
+    tasks := NewBackground()
+    var done atomic.Int32
+    release := make(chan struct{})
+    for i := 0; i < 3; i++ {
+        tasks.Go(func() {
+            <-release
+            done.Add(1)
+        })
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+    defer cancel()
+    if err := tasks.Wait(ctx); err == nil {
+        t.Fatal("Wait returned before the tasks finished")
+    }
+
+    close(release)
+    if err := tasks.Wait(context.Background()); err != nil || done.Load() != 3 {
+        t.Fatalf("Wait = %v, %d tasks done, want all 3", err, done.Load())
+    }
+}
@@ -6,15 +6,38 @@ import (
 )
 
 type ClassroomService struct {
-    Repo *repositories.ClassroomRepository
+    Repo repositories.ClassroomStore
+    UoW  repositories.Transactor
 }
 
-func NewClassroomService(repo *repositories.ClassroomRepository) *ClassroomService {
-    return &ClassroomService{Repo: repo}
+func NewClassroomService(repo repositories.ClassroomStore, uow repositories.Transactor) *ClassroomService {
+    return &ClassroomService{Repo: repo, UoW: uow}
 }
 
-func (s *ClassroomService) CreateClassroom(classroom *models.Classroom) error {
-    return s.Repo.CreateClassroom(classroom)
+func (s *ClassroomService) CreateClassroom(classroom *models.Classroom, userID int) error {
+    if err := models.Validate.Struct(classroom); err != nil {
+        return models.FromValidation(err)
+    }
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Classrooms.CreateClassroom(classroom); err != nil {
+            return err
+        }
+        return record(tx, userID, "create", "classrooms", classroom.ID, nil, classroom)
+    })
+}
+
+// ListClassrooms возвращает страницу аудиторий с фильтрами и сортировкой
+func (s *ClassroomService) ListClassrooms(query models.ListQuery) (*models.Page[models.Classroom], error) {
+    classrooms, total, err := s.Repo.ListClassrooms(query)
+    if err != nil {
+        return nil, err
+    }
+    return models.NewPage(classrooms, total, query), nil
+}
+
+// ExportClassrooms передаёт fn все аудитории с фильтрами и сортировкой для выгрузки в файл
+func (s *ClassroomService) ExportClassrooms(query models.ListQuery, fn func(models.Classroom) error) error {
+    return s.Repo.EachClassroom(query, fn)
 }
 
 func (s *ClassroomService) GetClassrooms() ([]models.Classroom, error) {
@@ -25,10 +48,44 @@ func (s *ClassroomService) GetClassroomByID(id int) (*models.Classroom, error) {
     return s.Repo.GetClassroomByID(id)
 }
 
-func (s *ClassroomService) UpdateClassroom(id int, updates map[string]interface{}) (*models.Classroom, error) {
-    return s.Repo.UpdateClassroom(id, updates)
+func (s *ClassroomService) UpdateClassroom(id int, update models.ClassroomUpdate, userID int) (*models.Classroom, error) {
+    if err := models.Validate.Struct(update); err != nil {
+        return nil, models.FromValidation(err)
+    }
+
+    var classroom *models.Classroom
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Classrooms.GetClassroomByID(id)
+        if err != nil {
+            return err
+        }
+        if classroom, err = tx.Classrooms.UpdateClassroom(id, update); err != nil {
+            return err
+        }
+        return record(tx, userID, "update", "classrooms", id, before, classroom)
+    })
+    return classroom, err
 }
 
-func (s *ClassroomService) DeleteClassroom(id int) error {
-    return s.Repo.DeleteClassroom(id)
-}
\ No newline at end of file
+// DeleteClassroom помещает аудиторию в корзину; занятия в ней удаляются только после подтверждения
+func (s *ClassroomService) DeleteClassroom(id int, confirm bool, userID int) (models.Cascade, error) {
+    var cascade models.Cascade
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Classrooms.GetClassroomByID(id)
+        if err != nil {
+            return err
+        }
+        cascade, err = confirmDelete(confirm,
+            func() (models.Cascade, error) { return tx.Classrooms.GetClassroomDeletionImpact(id) },
+            func() (models.Cascade, error) { return tx.Classrooms.DeleteClassroom(id) },
+        )
+        if err != nil {
+            return err
+        }
+        if err := record(tx, userID, "delete", "classrooms", id, before, nil); err != nil {
+            return err
+        }
+        return recordCascade(tx, userID, "cascade_delete", cascade)
+    })
+    return cascade, err
+}
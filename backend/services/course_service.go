@@ -6,15 +6,38 @@ import (
 )
 
 type CourseService struct {
-    Repo *repositories.CourseRepository
+    Repo repositories.CourseStore
+    UoW  repositories.Transactor
 }
 
-func NewCourseService(repo *repositories.CourseRepository) *CourseService {
-    return &CourseService{Repo: repo}
+func NewCourseService(repo repositories.CourseStore, uow repositories.Transactor) *CourseService {
+    return &CourseService{Repo: repo, UoW: uow}
 }
 
-func (s *CourseService) CreateCourse(course *models.Course) error {
-    return s.Repo.CreateCourse(course)
+func (s *CourseService) CreateCourse(course *models.Course, userID int) error {
+    if err := models.Validate.Struct(course); err != nil {
+        return models.FromValidation(err)
+    }
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Courses.CreateCourse(course); err != nil {
+            return err
+        }
+        return record(tx, userID, "create", "courses", course.ID, nil, course)
+    })
+}
+
+// ListCourses возвращает страницу курсов с фильтрами и сортировкой
+func (s *CourseService) ListCourses(query models.ListQuery) (*models.Page[models.Course], error) {
+    courses, total, err := s.Repo.ListCourses(query)
+    if err != nil {
+        return nil, err
+    }
+    return models.NewPage(courses, total, query), nil
+}
+
+// ExportCourses передаёт fn все курсы с фильтрами и сортировкой для выгрузки в файл
+func (s *CourseService) ExportCourses(query models.ListQuery, fn func(models.Course) error) error {
+    return s.Repo.EachCourse(query, fn)
 }
 
 func (s *CourseService) GetCourses() ([]models.Course, error) {
@@ -25,10 +48,44 @@ func (s *CourseService) GetCourseByID(id int) (*models.Course, error) {
     return s.Repo.GetCourseByID(id)
 }
 
-func (s *CourseService) UpdateCourse(id int, updates map[string]interface{}) (*models.Course, error) {
-    return s.Repo.UpdateCourse(id, updates)
+func (s *CourseService) UpdateCourse(id int, update models.CourseUpdate, userID int) (*models.Course, error) {
+    if err := models.Validate.Struct(update); err != nil {
+        return nil, models.FromValidation(err)
+    }
+
+    var course *models.Course
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Courses.GetCourseByID(id)
+        if err != nil {
+            return err
+        }
+        if course, err = tx.Courses.UpdateCourse(id, update); err != nil {
+            return err
+        }
+        return record(tx, userID, "update", "courses", id, before, course)
+    })
+    return course, err
 }
 
-func (s *CourseService) DeleteCourse(id int) error {
-    return s.Repo.DeleteCourse(id)
-}
\ No newline at end of file
+// DeleteCourse помещает курс в корзину; занятия группы удаляются только после подтверждения
+func (s *CourseService) DeleteCourse(id int, confirm bool, userID int) (models.Cascade, error) {
+    var cascade models.Cascade
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Courses.GetCourseByID(id)
+        if err != nil {
+            return err
+        }
+        cascade, err = confirmDelete(confirm,
+            func() (models.Cascade, error) { return tx.Courses.GetCourseDeletionImpact(id) },
+            func() (models.Cascade, error) { return tx.Courses.DeleteCourse(id) },
+        )
+        if err != nil {
+            return err
+        }
+        if err := record(tx, userID, "delete", "courses", id, before, nil); err != nil {
+            return err
+        }
+        return recordCascade(tx, userID, "cascade_delete", cascade)
+    })
+    return cascade, err
+}
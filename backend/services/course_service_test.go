@@ -0,0 +1,148 @@
+package services
+
+import (
+    "backend/models"
+    "slices"
+    "testing"
+)
+
+func TestCreateCourse(t *testing.T) {
+    tests := []struct {
+        name     string
+        course   func(teacherID, deletedID int) *models.Course
+        wantCode string
+    }{
+        {
+            name:   "without teacher",
+            course: func(int, int) *models.Course { return &models.Course{Name: "ПМ-22"} },
+        },
+        {
+            name:   "with teacher",
+            course: func(teacherID, _ int) *models.Course { return &models.Course{Name: "ПМ-22", TeacherID: &teacherID} },
+        },
+        {
+            name:     "empty name",
+            course:   func(int, int) *models.Course { return &models.Course{} },
+            wantCode: models.CodeValidation,
+        },
+        {
+            name:     "duplicate name",
+            course:   func(int, int) *models.Course { return &models.Course{Name: "ИВТ-21"} },
+            wantCode: models.CodeConflict,
+        },
+        {
+            name:   "name of a deleted course",
+            course: func(int, int) *models.Course { return &models.Course{Name: "УДЛ-20"} },
+        },
+        {
+            name:     "missing teacher",
+            course:   func(int, int) *models.Course { return &models.Course{Name: "ПМ-22", TeacherID: ptr(999)} },
+            wantCode: models.CodeConflict,
+        },
+        {
+            name:     "deleted teacher",
+            course:   func(_, deletedID int) *models.Course { return &models.Course{Name: "ПМ-22", TeacherID: &deletedID} },
+            wantCode: models.CodeNotFound,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            f := newFixture(t)
+            teacherID := f.teacher(t, "Иванов", 10)
+            deletedID := f.teacher(t, "Петров", 10)
+            if _, err := f.teachers.DeleteTeacher(deletedID, false, 0); err != nil {
+                t.Fatalf("delete teacher: %v", err)
+            }
+            f.course(t, "ИВТ-21", nil)
+            removed := f.course(t, "УДЛ-20", nil)
+            if _, err := f.courses.DeleteCourse(removed, false, 0); err != nil {
+                t.Fatalf("delete course: %v", err)
+            }
+
+            course := tt.course(teacherID, deletedID)
+            err := f.courses.CreateCourse(course, 0)
+            if code := errorCode(err); code != tt.wantCode {
+                t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantCode)
+            }
+
+            teacher, _ := f.teachers.GetTeacherByID(teacherID)
+            assigned := err == nil && course.TeacherID != nil
+            if slices.Contains(teacher.Courses, "ПМ-22") != assigned {
+                t.Errorf("teacher courses = %v", teacher.Courses)
+            }
+        })
+    }
+}
+
+func TestUpdateCourse(t *testing.T) {
+    tests := []struct {
+        name     string
+        update   models.CourseUpdate
+        wantCode string
+    }{
+        {name: "description", update: models.CourseUpdate{Description: ptr("Вечерняя группа")}},
+        {name: "rename without lessons", update: models.CourseUpdate{Name: ptr("ПМ-23")}, wantCode: ""},
+        {name: "duplicate name", update: models.CourseUpdate{Name: ptr("ИВТ-21")}, wantCode: models.CodeConflict},
+        {name: "empty name", update: models.CourseUpdate{Name: ptr("")}, wantCode: models.CodeValidation},
+        {name: "missing teacher", update: models.CourseUpdate{TeacherID: ptr(999)}, wantCode: models.CodeConflict},
+        {name: "no fields", update: models.CourseUpdate{}, wantCode: models.CodeValidation},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            f := newFixture(t)
+            f.course(t, "ИВТ-21", nil)
+            id := f.course(t, "ПМ-22", nil)
+
+            _, err := f.courses.UpdateCourse(id, tt.update, 0)
+            if code := errorCode(err); code != tt.wantCode {
+                t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantCode)
+            }
+        })
+    }
+}
+
+// При переименовании курса занятия, студенты группы и список курсов преподавателя переходят на новое название
+func TestRenameCourseWithLessons(t *testing.T) {
+    f := newFixture(t)
+    teacherID := f.teacher(t, "Иванов", 10)
+    id := f.course(t, "ИВТ-21", &teacherID)
+    lessonID := f.lesson(t, teacherID, f.classroom(t, "101"), lesson("Monday", "09:00", "10:30", ""))
+    student := &models.Student{Name: "Сидоров", DateOfBirth: "2005-03-14", GroupName: "ИВТ-21"}
+    if err := f.students.CreateStudent(student, 0); err != nil {
+        t.Fatalf("create student: %v", err)
+    }
+
+    if _, err := f.courses.UpdateCourse(id, models.CourseUpdate{Name: ptr("ИВТ-22")}, 0); err != nil {
+        t.Fatalf("rename course: %v", err)
+    }
+
+    schedule, _ := f.schedules.GetScheduleByID(lessonID)
+    saved, _ := f.students.GetStudentByID(student.ID)
+    teacher, _ := f.teachers.GetTeacherByID(teacherID)
+    if schedule.GroupName != "ИВТ-22" || saved.GroupName != "ИВТ-22" || !slices.Equal(teacher.Courses, []string{"ИВТ-22"}) {
+        t.Errorf("schedule group = %q, student group = %q, teacher courses = %v", schedule.GroupName, saved.GroupName, teacher.Courses)
+    }
+}
+
+// Курс в корзине освобождает название; занятия остаются за ним и не переходят к новому курсу
+func TestDeletedCourseFreesName(t *testing.T) {
+    f := newFixture(t)
+    teacherID := f.teacher(t, "Иванов", 10)
+    id := f.course(t, "ИВТ-21", nil)
+    f.lesson(t, teacherID, f.classroom(t, "101"), lesson("Monday", "09:00", "10:30", ""))
+
+    if _, err := f.courses.DeleteCourse(id, true, 0); err != nil {
+        t.Fatalf("delete course: %v", err)
+    }
+    twin := f.course(t, "ИВТ-21", nil)
+
+    impact, err := f.courses.DeleteCourse(twin, true, 0)
+    if err != nil {
+        t.Fatalf("delete new course: %v", err)
+    }
+    if len(impact) != 0 {
+        t.Errorf("new course took over lessons: %v", impact)
+    }
+}
@@ -3,16 +3,19 @@ package services
 import (
     "gopkg.in/gomail.v2"
     "backend/config"
+    "backend/metrics"
 )
 
-type EmailService struct{}
+type EmailService struct {
+    Config config.EmailConfig
+}
 
-func NewEmailService() *EmailService {
-    return &EmailService{}
+func NewEmailService(cfg config.EmailConfig) *EmailService {
+    return &EmailService{Config: cfg}
 }
 
 func (s *EmailService) SendEmail(to, subject, body string) error {
-    cfg := config.GetEmailConfig()
+    cfg := s.Config
 
     m := gomail.NewMessage()
     m.SetHeader("From", cfg.From)
@@ -23,8 +26,10 @@ func (s *EmailService) SendEmail(to, subject, body string) error {
     d := gomail.NewDialer(cfg.Host, cfg.Port, cfg.Username, cfg.Password)
 
     if err := d.DialAndSend(m); err != nil {
+        metrics.Emails.Inc("failed")
         return err
     }
 
+    metrics.Emails.Inc("sent")
     return nil
 }
\ No newline at end of file
@@ -0,0 +1,250 @@
+package services
+
+import (
+    "backend/export"
+    "backend/models"
+    "backend/repository"
+    "fmt"
+    "io"
+)
+
+type GradeSheetService struct {
+    Repo repositories.GradeSheetStore
+    UoW  repositories.Transactor
+}
+
+func NewGradeSheetService(repo repositories.GradeSheetStore, uow repositories.Transactor) *GradeSheetService {
+    return &GradeSheetService{Repo: repo, UoW: uow}
+}
+
+// CreateGradeSheet создает черновик ведомости для группы курса
+func (s *GradeSheetService) CreateGradeSheet(sheet *models.GradeSheet, userID int) error {
+    if sheet.ControlType != models.ControlExam && sheet.ControlType != models.ControlCredit {
+        return models.Invalid("control_type must be 'exam' or 'credit'")
+    }
+    if sheet.CourseID == 0 {
+        return models.Invalid("course_id is required")
+    }
+    sheet.ParentID = nil
+
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        return createGradeSheet(tx, sheet, userID)
+    })
+}
+
+// CreateRetakeSheet создает ведомость пересдачи для студентов с неудовлетворительными оценками.
+// Пока по ведомости есть выданная или заполненная пересдача, новая не создаётся
+func (s *GradeSheetService) CreateRetakeSheet(parentID int, retake *models.GradeSheet, userID int) error {
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        parent, err := tx.GradeSheets.GetGradeSheetByID(parentID)
+        if err != nil {
+            return err
+        }
+        if parent.Status != models.GradeSheetClosed {
+            return models.Conflict("retake sheet can only be created for a closed grade sheet")
+        }
+
+        failed := 0
+        for _, entry := range parent.Entries {
+            if entry.Mark != nil && models.IsFailingMark(*entry.Mark) {
+                failed++
+            }
+        }
+        if failed == 0 {
+            return models.Invalid("grade sheet has no students to retake")
+        }
+
+        openID, err := tx.GradeSheets.GetOpenRetakeID(parent.ID)
+        if err != nil {
+            return err
+        }
+        if openID != 0 {
+            return models.Conflict("grade sheet %d already has an open retake sheet %d, close it first", parent.ID, openID)
+        }
+
+        retake.CourseID = parent.CourseID
+        retake.ControlType = parent.ControlType
+        retake.ParentID = &parent.ID
+        if retake.TeacherID == nil {
+            retake.TeacherID = parent.TeacherID
+        }
+        return createGradeSheet(tx, retake, userID)
+    })
+}
+
+// createGradeSheet сохраняет ведомость, перечитывает её со списком студентов и пишет в журнал
+func createGradeSheet(tx *repositories.Tx, sheet *models.GradeSheet, userID int) error {
+    if err := tx.GradeSheets.CreateGradeSheet(sheet); err != nil {
+        return err
+    }
+    created, err := tx.GradeSheets.GetGradeSheetByID(sheet.ID)
+    if err != nil {
+        return err
+    }
+    *sheet = *created
+    return record(tx, userID, "create", "grade_sheets", sheet.ID, nil, sheet)
+}
+
+// changeGradeSheet меняет ведомость в транзакции: check проверяет текущее состояние, apply
+// применяет изменение. В журнал пишется ведомость до и после него
+func (s *GradeSheetService) changeGradeSheet(id, userID int, action string, check func(sheet *models.GradeSheet) error, apply func(tx *repositories.Tx) error) (*models.GradeSheet, error) {
+    var sheet *models.GradeSheet
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.GradeSheets.GetGradeSheetByID(id)
+        if err != nil {
+            return err
+        }
+        if err := check(before); err != nil {
+            return err
+        }
+        if err := apply(tx); err != nil {
+            return err
+        }
+        if sheet, err = tx.GradeSheets.GetGradeSheetByID(id); err != nil {
+            return err
+        }
+        return record(tx, userID, action, "grade_sheets", id, before, sheet)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return sheet, nil
+}
+
+func (s *GradeSheetService) GetGradeSheets(courseID int, status string) ([]models.GradeSheet, error) {
+    return s.Repo.GetGradeSheets(courseID, status)
+}
+
+func (s *GradeSheetService) GetGradeSheetByID(id int) (*models.GradeSheet, error) {
+    return s.Repo.GetGradeSheetByID(id)
+}
+
+// IssueGradeSheet выдает черновик ведомости экзаменатору
+func (s *GradeSheetService) IssueGradeSheet(id, userID int) (*models.GradeSheet, error) {
+    return s.changeGradeSheet(id, userID, "issue",
+        func(sheet *models.GradeSheet) error {
+            if sheet.Status != models.GradeSheetDraft {
+                return models.Conflict("grade sheet in status '%s' cannot be issued", sheet.Status)
+            }
+            if len(sheet.Entries) == 0 {
+                return models.Invalid("grade sheet has no students")
+            }
+            return nil
+        },
+        func(tx *repositories.Tx) error {
+            return tx.GradeSheets.UpdateStatus(id, models.GradeSheetDraft, models.GradeSheetIssued)
+        },
+    )
+}
+
+// FillGradeSheet выставляет оценки по студентам
+func (s *GradeSheetService) FillGradeSheet(id int, marks map[int]string, userID int) (*models.GradeSheet, error) {
+    if len(marks) == 0 {
+        return nil, models.Invalid("no marks to set")
+    }
+
+    return s.changeGradeSheet(id, userID, "fill",
+        func(sheet *models.GradeSheet) error {
+            if sheet.Status == models.GradeSheetClosed {
+                return models.Conflict("grade sheet is closed and cannot be changed")
+            }
+            for studentID, mark := range marks {
+                if !models.IsValidMark(sheet.ControlType, mark) {
+                    return models.Invalid("invalid mark '%s' for student %d", mark, studentID)
+                }
+            }
+            return nil
+        },
+        func(tx *repositories.Tx) error { return tx.GradeSheets.SetMarks(id, marks) },
+    )
+}
+
+// CloseGradeSheet закрывает ведомость и переносит оценки в зачётную книжку
+func (s *GradeSheetService) CloseGradeSheet(id, userID int) (*models.GradeSheet, error) {
+    return s.changeGradeSheet(id, userID, "close",
+        func(*models.GradeSheet) error { return nil },
+        func(tx *repositories.Tx) error { return tx.GradeSheets.CloseGradeSheet(id, userID) },
+    )
+}
+
+func (s *GradeSheetService) DeleteGradeSheet(id, userID int) error {
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.GradeSheets.GetGradeSheetByID(id)
+        if err != nil {
+            return err
+        }
+        if err := tx.GradeSheets.DeleteGradeSheet(id); err != nil {
+            return err
+        }
+        return record(tx, userID, "delete", "grade_sheets", id, before, nil)
+    })
+}
+
+func (s *GradeSheetService) GetStudentGrades(studentID int) ([]models.Grade, error) {
+    return s.Repo.GetStudentGrades(studentID)
+}
+
+// ExportGradeSheet выгружает ведомость в PDF или XLSX
+func (s *GradeSheetService) ExportGradeSheet(w io.Writer, id int, format string) error {
+    sheet, err := s.Repo.GetGradeSheetByID(id)
+    if err != nil {
+        return err
+    }
+
+    table := gradeSheetTable(sheet)
+    switch format {
+    case "pdf":
+        return export.WritePDF(w, table, false)
+    case "xlsx":
+        return export.WriteXLSX(w, table)
+    }
+    return models.Invalid("unsupported export format: %s", format)
+}
+
+var controlTypeTitles = map[string]string{
+    models.ControlExam:   "Экзаменационная ведомость",
+    models.ControlCredit: "Зачётная ведомость",
+}
+
+var markTitles = map[string]string{
+    models.MarkExcellent: "отлично",
+    models.MarkGood:      "хорошо",
+    models.MarkSatisfy:   "удовлетворительно",
+    models.MarkFail:      "неудовлетворительно",
+    models.MarkPass:      "зачтено",
+    models.MarkNoPass:    "не зачтено",
+    models.MarkAbsent:    "не явился",
+}
+
+func gradeSheetTable(sheet *models.GradeSheet) export.Table {
+    title := controlTypeTitles[sheet.ControlType]
+    if sheet.Number != "" {
+        title += " № " + sheet.Number
+    }
+    if sheet.ParentID != nil {
+        title += " (пересдача)"
+    }
+
+    table := export.Table{
+        Title: title,
+        Meta: []string{
+            "Дисциплина / группа: " + sheet.CourseName,
+            "Экзаменатор: " + sheet.TeacherName,
+            "Дата: " + sheet.ExamDate,
+        },
+        Headers: []string{"№", "ФИО студента", "Оценка", "Подпись экзаменатора"},
+        Footer: []string{
+            "Экзаменатор: ____________________ " + sheet.TeacherName,
+            "Заведующий учебной частью: ____________________",
+        },
+    }
+
+    for i, entry := range sheet.Entries {
+        mark := ""
+        if entry.Mark != nil {
+            mark = markTitles[*entry.Mark]
+        }
+        table.Rows = append(table.Rows, []string{fmt.Sprint(i + 1), entry.StudentName, mark, ""})
+    }
+    return table
+}
\ No newline at end of file
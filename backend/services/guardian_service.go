@@ -0,0 +1,169 @@
+package services
+
+import (
+    "backend/models"
+    "backend/repository"
+)
+
+type GuardianService struct {
+    Repo repositories.GuardianStore
+    UoW  repositories.Transactor
+}
+
+func NewGuardianService(repo repositories.GuardianStore, uow repositories.Transactor) *GuardianService {
+    return &GuardianService{Repo: repo, UoW: uow}
+}
+
+// validateGuardian проверяет контакт: нужен телефон или email
+func (s *GuardianService) validateGuardian(guardian *models.Guardian) error {
+    if err := models.Validate.Struct(guardian); err != nil {
+        return models.FromValidation(err)
+    }
+    if guardian.Phone == "" && guardian.Email == "" {
+        return models.Invalid("phone or email is required")
+    }
+    return nil
+}
+
+// CreateGuardian сохраняет контакт без учётной записи: её привязывает только CreateAccount
+// (право users:manage), иначе редактор контактов мог бы открыть любой учётной записи данные студента
+func (s *GuardianService) CreateGuardian(guardian *models.Guardian, userID int) error {
+    guardian.UserID = nil
+    if err := s.validateGuardian(guardian); err != nil {
+        return err
+    }
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Guardians.CreateGuardian(guardian); err != nil {
+            return err
+        }
+        return record(tx, userID, "create", "guardians", guardian.ID, nil, guardian)
+    })
+}
+
+func (s *GuardianService) GetGuardianByID(id int) (*models.Guardian, error) {
+    return s.Repo.GetGuardianByID(id)
+}
+
+func (s *GuardianService) GetStudentGuardians(studentID int) ([]models.Guardian, error) {
+    return s.Repo.GetStudentGuardians(studentID)
+}
+
+// UpdateGuardian меняет переданные поля контакта; привязка к учётной записи не меняется
+func (s *GuardianService) UpdateGuardian(id int, update models.GuardianUpdate, userID int) (*models.Guardian, error) {
+    if err := models.Validate.Struct(update); err != nil {
+        return nil, models.FromValidation(err)
+    }
+
+    var guardian *models.Guardian
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Guardians.GetGuardianByID(id)
+        if err != nil {
+            return err
+        }
+        changed := *before
+        guardian = &changed
+        applyGuardianUpdate(guardian, update)
+
+        if err := s.validateGuardian(guardian); err != nil {
+            return err
+        }
+        if err := tx.Guardians.UpdateGuardian(guardian); err != nil {
+            return err
+        }
+        return record(tx, userID, "update", "guardians", id, before, guardian)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return guardian, nil
+}
+
+// applyGuardianUpdate заменяет поля контакта переданными
+func applyGuardianUpdate(guardian *models.Guardian, update models.GuardianUpdate) {
+    if update.Name != nil {
+        guardian.Name = *update.Name
+    }
+    if update.Phone != nil {
+        guardian.Phone = *update.Phone
+    }
+    if update.Email != nil {
+        guardian.Email = *update.Email
+    }
+    if update.Relation != nil {
+        guardian.Relation = *update.Relation
+    }
+    if update.NotifyAbsence != nil {
+        guardian.NotifyAbsence = *update.NotifyAbsence
+    }
+}
+
+func (s *GuardianService) DeleteGuardian(id, userID int) error {
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Guardians.GetGuardianByID(id)
+        if err != nil {
+            return err
+        }
+        if err := tx.Guardians.DeleteGuardian(id); err != nil {
+            return err
+        }
+        return record(tx, userID, "delete", "guardians", id, before, nil)
+    })
+}
+
+// CreateAccount создает учётную запись представителя с ролью guardian
+func (s *GuardianService) CreateAccount(guardianID int, username, password string, userID int) (*models.User, error) {
+    if len(password) < 8 {
+        return nil, models.Invalid("password must be at least 8 characters")
+    }
+
+    user := &models.User{Username: username, Role: "guardian"}
+    if err := models.Validate.Struct(user); err != nil {
+        return nil, models.FromValidation(err)
+    }
+    if err := user.HashPassword(password); err != nil {
+        return nil, err
+    }
+
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Guardians.CreateAccount(guardianID, user); err != nil {
+            return err
+        }
+        return record(tx, userID, "create", "users", user.ID, nil, user)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return user, nil
+}
+
+// LinkAccount связывает контакт с существующей учётной записью представителя,
+// чтобы одна учётная запись видела нескольких студентов
+func (s *GuardianService) LinkAccount(guardianID int, link models.GuardianAccountLink, userID int) (*models.Guardian, error) {
+    if err := models.Validate.Struct(link); err != nil {
+        return nil, models.FromValidation(err)
+    }
+
+    var guardian *models.Guardian
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Guardians.GetGuardianByID(guardianID)
+        if err != nil {
+            return err
+        }
+        if guardian, err = tx.Guardians.LinkAccount(guardianID, link.UserID); err != nil {
+            return err
+        }
+        return record(tx, userID, "update", "guardians", guardianID, before, guardian)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return guardian, nil
+}
+
+func (s *GuardianService) GetLinkedStudents(userID int) ([]models.Student, error) {
+    return s.Repo.GetLinkedStudents(userID)
+}
+
+func (s *GuardianService) IsLinked(userID, studentID int) (bool, error) {
+    return s.Repo.IsLinked(userID, studentID)
+}
\ No newline at end of file
@@ -0,0 +1,362 @@
+package services
+
+import (
+    "backend/export"
+    "backend/models"
+    "backend/repository"
+    "fmt"
+    "io"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Максимальное число строк в одном файле импорта
+const maxImportRows = 5000
+
+// Форматы даты рождения в таблицах: ISO и привычный "дд.мм.гггг"
+var importDateLayouts = []string{"2006-01-02", "02.01.2006", "2.1.2006"}
+
+type ImportService struct {
+    Repo repositories.ImportStore
+    UoW  repositories.Transactor
+}
+
+func NewImportService(repo repositories.ImportStore, uow repositories.Transactor) *ImportService {
+    return &ImportService{Repo: repo, UoW: uow}
+}
+
+// importRow значения строки файла по именам полей
+type importRow struct {
+    Number int
+    Values map[string]string
+}
+
+// Import проверяет строки файла и, если ошибок нет и это не dry-run, сохраняет их одной транзакцией.
+// mapping сопоставляет колонку файла полю сущности; без него колонки ищутся по имени поля и русским названиям.
+// Каждая созданная запись попадает в журнал аудита от имени userID в той же транзакции
+func (s *ImportService) Import(entity string, table export.Table, mapping map[string]string, dryRun bool, userID int) (*models.ImportResult, error) {
+    fields, ok := models.ImportFields[entity]
+    if !ok {
+        return nil, models.Invalid("import of '%s' is not supported", entity)
+    }
+
+    columns, err := mapColumns(fields, table.Headers, mapping)
+    if err != nil {
+        return nil, err
+    }
+
+    result := &models.ImportResult{Entity: entity, DryRun: dryRun, Errors: []models.ImportError{}}
+    var rows []importRow
+    for i, record := range table.Rows {
+        if export.IsEmptyRow(record) {
+            continue
+        }
+        row := importRow{Number: i + 2, Values: map[string]string{}} // +1 за шапку, +1 за нумерацию с единицы
+        for index, field := range columns {
+            if index < len(record) {
+                row.Values[field] = strings.TrimSpace(record[index])
+            }
+        }
+        rows = append(rows, row)
+    }
+    if len(rows) == 0 {
+        return nil, models.Invalid("file has no data rows")
+    }
+    if len(rows) > maxImportRows {
+        return nil, models.Invalid("file has %d rows, must be at most %d", len(rows), maxImportRows)
+    }
+    result.Total = len(rows)
+
+    lookup, err := s.Repo.LoadImportLookup()
+    if err != nil {
+        return nil, err
+    }
+
+    // Обязательные поля проверяем одинаково для всех сущностей
+    rowValid := func(row importRow) bool {
+        valid := true
+        for _, field := range fields {
+            if field.Required && row.Values[field.Name] == "" {
+                result.Errors = append(result.Errors, models.ImportError{Row: row.Number, Column: field.Name, Message: "value is required"})
+                valid = false
+            }
+        }
+        return valid
+    }
+    fail := func(row importRow, column, message string) {
+        result.Errors = append(result.Errors, models.ImportError{
+            Row: row.Number, Column: column, Value: row.Values[column], Message: message,
+        })
+    }
+
+    var save func(tx *repositories.Tx) error
+    switch entity {
+    case "students":
+        var students []models.Student
+        seen := map[string]int{}
+        for _, row := range rows {
+            if !rowValid(row) {
+                continue
+            }
+            valid := true
+            dateOfBirth, err := parseImportDate(row.Values["date_of_birth"])
+            if err != nil {
+                fail(row, "date_of_birth", "invalid date, expected YYYY-MM-DD or DD.MM.YYYY")
+                valid = false
+            }
+            if !lookup.CourseNames[row.Values["group_name"]] {
+                fail(row, "group_name", "group does not exist")
+                valid = false
+            }
+            if !valid {
+                continue
+            }
+            key := repositories.StudentKey(row.Values["name"], dateOfBirth)
+            if !duplicateCheck(fail, row, "name", key, lookup.StudentKeys, seen, "student") {
+                continue
+            }
+            students = append(students, models.Student{
+                Name:        row.Values["name"],
+                DateOfBirth: dateOfBirth,
+                GroupName:   row.Values["group_name"],
+            })
+        }
+        result.Valid = len(students)
+        save = func(tx *repositories.Tx) error {
+            if err := tx.Imports.ImportStudents(students); err != nil {
+                return err
+            }
+            return recordImported(tx, userID, entity, students, func(student models.Student) int { return student.ID })
+        }
+
+    case "teachers":
+        var teachers []models.Teacher
+        seen := map[string]int{}
+        for _, row := range rows {
+            if !rowValid(row) {
+                continue
+            }
+            valid := true
+            hours := 0.0
+            if value := row.Values["working_hours"]; value != "" {
+                hours, err = strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+                if err != nil || hours < 0 {
+                    fail(row, "working_hours", "must be a non-negative number")
+                    valid = false
+                }
+            }
+            courses := splitList(row.Values["courses"])
+            for _, course := range courses {
+                if !lookup.CourseNames[course] {
+                    fail(row, "courses", fmt.Sprintf("course '%s' does not exist", course))
+                    valid = false
+                }
+            }
+            if !valid {
+                continue
+            }
+            key := repositories.TeacherKey(row.Values["name"], row.Values["subject"])
+            if !duplicateCheck(fail, row, "name", key, lookup.TeacherKeys, seen, "teacher with this name and subject") {
+                continue
+            }
+            teachers = append(teachers, models.Teacher{
+                Name:         row.Values["name"],
+                Subject:      row.Values["subject"],
+                Courses:      courses,
+                WorkingHours: hours,
+            })
+        }
+        result.Valid = len(teachers)
+        save = func(tx *repositories.Tx) error {
+            if err := tx.Imports.ImportTeachers(teachers); err != nil {
+                return err
+            }
+            return recordImported(tx, userID, entity, teachers, func(teacher models.Teacher) int { return teacher.ID })
+        }
+
+    case "courses":
+        var courses []models.Course
+        seen := map[string]int{}
+        for _, row := range rows {
+            if !rowValid(row) {
+                continue
+            }
+            var teacherID *int
+            if value := row.Values["teacher_id"]; value != "" {
+                id, err := strconv.Atoi(value)
+                if err != nil || !lookup.TeacherIDs[id] {
+                    fail(row, "teacher_id", "teacher not found")
+                    continue
+                }
+                teacherID = &id
+            }
+            if !duplicateCheck(fail, row, "name", row.Values["name"], lookup.CourseNames, seen, "course") {
+                continue
+            }
+            courses = append(courses, models.Course{
+                Name:        row.Values["name"],
+                Description: row.Values["description"],
+                TeacherID:   teacherID,
+            })
+        }
+        result.Valid = len(courses)
+        save = func(tx *repositories.Tx) error {
+            if err := tx.Imports.ImportCourses(courses); err != nil {
+                return err
+            }
+            return recordImported(tx, userID, entity, courses, func(course models.Course) int { return course.ID })
+        }
+
+    case "classrooms":
+        var classrooms []models.Classroom
+        seen := map[string]int{}
+        for _, row := range rows {
+            if !rowValid(row) {
+                continue
+            }
+            capacity, err := strconv.Atoi(row.Values["capacity"])
+            if err != nil || capacity <= 0 {
+                fail(row, "capacity", "must be a positive integer")
+                continue
+            }
+            if !duplicateCheck(fail, row, "name", row.Values["name"], lookup.ClassroomNames, seen, "classroom") {
+                continue
+            }
+            classrooms = append(classrooms, models.Classroom{
+                Name:        row.Values["name"],
+                Capacity:    capacity,
+                Description: row.Values["description"],
+            })
+        }
+        result.Valid = len(classrooms)
+        save = func(tx *repositories.Tx) error {
+            if err := tx.Imports.ImportClassrooms(classrooms); err != nil {
+                return err
+            }
+            return recordImported(tx, userID, entity, classrooms, func(classroom models.Classroom) int { return classroom.ID })
+        }
+    }
+
+    // Всё или ничего: при любой ошибке в файле ничего не сохраняем
+    if dryRun || len(result.Errors) > 0 {
+        return result, nil
+    }
+    if err := s.UoW.Do(save); err != nil {
+        return nil, err
+    }
+    result.Created = result.Valid
+    return result, nil
+}
+
+// recordImported записывает в журнал аудита каждую созданную импортом запись с её ID
+func recordImported[T any](tx *repositories.Tx, userID int, entity string, items []T, id func(T) int) error {
+    for _, item := range items {
+        if err := record(tx, userID, "import", entity, id(item), nil, item); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// WriteErrorReport записывает ошибки импорта в CSV или XLSX для скачивания
+func (s *ImportService) WriteErrorReport(w io.Writer, result *models.ImportResult, format string) error {
+    table := export.Table{
+        Title:   "Ошибки импорта: " + result.Entity,
+        Headers: []string{"Строка", "Колонка", "Значение", "Ошибка"},
+    }
+    for _, item := range result.Errors {
+        table.Rows = append(table.Rows, []string{strconv.Itoa(item.Row), item.Column, item.Value, item.Message})
+    }
+
+    switch format {
+    case "csv":
+        return export.WriteCSV(w, table)
+    case "xlsx":
+        return export.WriteXLSX(w, table)
+    }
+    return models.Invalid("invalid report format: %s", format)
+}
+
+// mapColumns возвращает поле сущности для каждого номера колонки файла
+func mapColumns(fields []models.ImportField, headers []string, mapping map[string]string) (map[int]string, error) {
+    known := map[string]bool{}
+    byAlias := map[string]string{}
+    for _, field := range fields {
+        known[field.Name] = true
+        byAlias[strings.ToLower(field.Name)] = field.Name
+        for _, alias := range field.Aliases {
+            byAlias[strings.ToLower(alias)] = field.Name
+        }
+    }
+
+    for header, field := range mapping {
+        if !known[field] {
+            return nil, models.Invalid("invalid mapping for column '%s': unknown field '%s'", header, field)
+        }
+    }
+
+    columns := map[int]string{}
+    used := map[string]string{}
+    for index, header := range headers {
+        field, ok := mapping[header]
+        if !ok {
+            if len(mapping) > 0 {
+                continue // При явном сопоставлении остальные колонки игнорируются
+            }
+            field, ok = byAlias[strings.ToLower(header)]
+            if !ok {
+                continue
+            }
+        }
+        if previous, ok := used[field]; ok {
+            return nil, models.Invalid("invalid mapping: columns '%s' and '%s' both map to '%s'", previous, header, field)
+        }
+        used[field] = header
+        columns[index] = field
+    }
+
+    for _, field := range fields {
+        if field.Required {
+            if _, ok := used[field.Name]; !ok {
+                return nil, models.Invalid("no column for required field '%s'", field.Name)
+            }
+        }
+    }
+    return columns, nil
+}
+
+// duplicateCheck отмечает строку, если запись уже есть в базе или встречалась в файле выше
+func duplicateCheck(fail func(importRow, string, string), row importRow, column, key string, existing map[string]bool, seen map[string]int, what string) bool {
+    if existing[key] {
+        fail(row, column, what+" already exists")
+        return false
+    }
+    if first, ok := seen[key]; ok {
+        fail(row, column, fmt.Sprintf("duplicate of row %d", first))
+        return false
+    }
+    seen[key] = row.Number
+    return true
+}
+
+// parseImportDate приводит дату к формату YYYY-MM-DD
+func parseImportDate(value string) (string, error) {
+    for _, layout := range importDateLayouts {
+        if date, err := time.Parse(layout, value); err == nil {
+            return date.Format("2006-01-02"), nil
+        }
+    }
+    return "", models.Invalid("invalid date: %s", value)
+}
+
+// splitList разбирает список через запятую или точку с запятой
+func splitList(value string) []string {
+    var items []string
+    for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+        if item = strings.TrimSpace(item); item != "" {
+            items = append(items, item)
+        }
+    }
+    return items
+}
\ No newline at end of file
@@ -0,0 +1,19 @@
+package services
+
+import (
+    "backend/models"
+    "backend/repository"
+)
+
+type IntegrityService struct {
+    Repo repositories.IntegrityStore
+}
+
+func NewIntegrityService(repo repositories.IntegrityStore) *IntegrityService {
+    return &IntegrityService{Repo: repo}
+}
+
+// CheckIntegrity ищет несогласованные данные: висячие ссылки, пересечения в расписании, расхождения часов
+func (s *IntegrityService) CheckIntegrity() ([]models.IntegrityIssue, error) {
+    return s.Repo.CheckIntegrity()
+}
\ No newline at end of file
@@ -0,0 +1,187 @@
+package services
+
+import (
+    "backend/models"
+    "backend/repository"
+    "sync"
+    "time"
+)
+
+// Права ролей кэшируются, чтобы не читать их из БД на каждый запрос
+const permissionCacheTTL = time.Minute
+
+type cachedPermissions struct {
+    permissions map[string]bool
+    loadedAt    time.Time
+}
+
+type PermissionService struct {
+    Repo     repositories.PermissionStore
+    UserRepo repositories.UserStore
+    UoW      repositories.Transactor
+
+    mu    sync.RWMutex
+    cache map[string]cachedPermissions
+}
+
+func NewPermissionService(repo repositories.PermissionStore, userRepo repositories.UserStore, uow repositories.Transactor) *PermissionService {
+    return &PermissionService{
+        Repo:     repo,
+        UserRepo: userRepo,
+        UoW:      uow,
+        cache:    make(map[string]cachedPermissions),
+    }
+}
+
+// RolePermissions возвращает права роли (с кэшированием)
+func (s *PermissionService) RolePermissions(role string) (map[string]bool, error) {
+    s.mu.RLock()
+    cached, ok := s.cache[role]
+    s.mu.RUnlock()
+    if ok && time.Since(cached.loadedAt) < permissionCacheTTL {
+        return cached.permissions, nil
+    }
+
+    list, err := s.Repo.GetRolePermissions(role)
+    if err != nil {
+        return nil, err
+    }
+    permissions := make(map[string]bool, len(list))
+    for _, permission := range list {
+        permissions[permission] = true
+    }
+
+    s.mu.Lock()
+    s.cache[role] = cachedPermissions{permissions: permissions, loadedAt: time.Now()}
+    s.mu.Unlock()
+    return permissions, nil
+}
+
+func (s *PermissionService) invalidate() {
+    s.mu.Lock()
+    s.cache = make(map[string]cachedPermissions)
+    s.mu.Unlock()
+}
+
+func (s *PermissionService) GetRoles() ([]models.Role, error) {
+    return s.Repo.GetRoles()
+}
+
+func (s *PermissionService) GetPermissions() ([]models.Permission, error) {
+    return s.Repo.GetPermissions()
+}
+
+// CreateRole создает новую роль с набором прав
+func (s *PermissionService) CreateRole(role *models.Role, userID int) error {
+    if err := models.Validate.Struct(role); err != nil {
+        return models.FromValidation(err)
+    }
+    role.Permissions = uniqueStrings(role.Permissions)
+
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Permissions.CreateRole(role); err != nil {
+            return err
+        }
+        return record(tx, userID, "create", "roles", role.ID, nil, role)
+    })
+    if err != nil {
+        return err
+    }
+    s.invalidate()
+    return nil
+}
+
+// SetRolePermissions заменяет набор прав роли и возвращает роль с новыми правами
+func (s *PermissionService) SetRolePermissions(role string, permissions []string, userID int) (*models.Role, error) {
+    if role == "admin" {
+        return nil, models.Conflict("permissions of role 'admin' cannot be changed")
+    }
+    if role == SelfRegisterRole && len(permissions) > 0 {
+        return nil, models.Conflict("role '%s' is given by open registration and cannot have permissions", SelfRegisterRole)
+    }
+
+    var updated *models.Role
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Permissions.GetRole(role)
+        if err != nil {
+            return err
+        }
+        if err := tx.Permissions.SetRolePermissions(role, uniqueStrings(permissions)); err != nil {
+            return err
+        }
+        if updated, err = tx.Permissions.GetRole(role); err != nil {
+            return err
+        }
+        return record(tx, userID, "update", "roles", before.ID, before, updated)
+    })
+    if err != nil {
+        return nil, err
+    }
+    s.invalidate()
+    return updated, nil
+}
+
+func (s *PermissionService) DeleteRole(role string, userID int) error {
+    if role == "admin" || role == SelfRegisterRole {
+        return models.Conflict("role '%s' cannot be deleted", role)
+    }
+
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Permissions.GetRole(role)
+        if err != nil {
+            return err
+        }
+        if err := tx.Permissions.DeleteRole(role); err != nil {
+            return err
+        }
+        return record(tx, userID, "delete", "roles", before.ID, before, nil)
+    })
+    if err != nil {
+        return err
+    }
+    s.invalidate()
+    return nil
+}
+
+// AssignUserRole назначает пользователю id роль и (необязательно) связанного преподавателя;
+// userID - администратор, назначивший роль
+func (s *PermissionService) AssignUserRole(id int, role string, teacherID *int, userID int) (*models.User, error) {
+    var user *models.User
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        exists, err := tx.Users.RoleExists(role)
+        if err != nil {
+            return err
+        }
+        if !exists {
+            return models.Invalid("invalid role")
+        }
+
+        before, err := tx.Users.GetUserByID(id)
+        if err != nil {
+            return err
+        }
+        if before == nil {
+            return models.NotFound("user with id %d not found", id)
+        }
+        if user, err = tx.Users.UpdateUserRole(id, role, teacherID); err != nil {
+            return err
+        }
+        return record(tx, userID, "role_change", "users", id, before, user)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return user, nil
+}
+
+func uniqueStrings(values []string) []string {
+    seen := make(map[string]bool, len(values))
+    result := []string{}
+    for _, value := range values {
+        if !seen[value] {
+            seen[value] = true
+            result = append(result, value)
+        }
+    }
+    return result
+}
\ No newline at end of file
@@ -0,0 +1,144 @@
+package services
+
+import (
+    "backend/models"
+    "backend/repository"
+    "time"
+)
+
+// Период по умолчанию: расписание на две недели вперёд, посещаемость за месяц назад
+const (
+    portalTimetableDays  = 14
+    portalAttendanceDays = 30
+    portalMaxPeriodDays  = 184
+)
+
+// PortalService данные личного кабинета студента.
+// Все методы принимают ID студента из токена и возвращают только его данные
+type PortalService struct {
+    StudentRepo      repositories.StudentStore
+    ScheduleRepo     repositories.ScheduleStore
+    CourseRepo       repositories.CourseStore
+    GradeSheetRepo   repositories.GradeSheetStore
+    AttendanceRepo   repositories.AttendanceStore
+    AnnouncementRepo repositories.AnnouncementStore
+}
+
+func NewPortalService(
+    studentRepo repositories.StudentStore,
+    scheduleRepo repositories.ScheduleStore,
+    courseRepo repositories.CourseStore,
+    gradeSheetRepo repositories.GradeSheetStore,
+    attendanceRepo repositories.AttendanceStore,
+    announcementRepo repositories.AnnouncementStore,
+) *PortalService {
+    return &PortalService{
+        StudentRepo:      studentRepo,
+        ScheduleRepo:     scheduleRepo,
+        CourseRepo:       courseRepo,
+        GradeSheetRepo:   gradeSheetRepo,
+        AttendanceRepo:   attendanceRepo,
+        AnnouncementRepo: announcementRepo,
+    }
+}
+
+// parsePeriod разбирает границы периода (YYYY-MM-DD); пустые значения заменяются значениями по умолчанию
+func parsePeriod(from, to string, defaultFrom, defaultTo time.Time) (string, string, error) {
+    start, end := defaultFrom, defaultTo
+    var err error
+    if from != "" {
+        if start, err = time.Parse("2006-01-02", from); err != nil {
+            return "", "", models.Invalid("invalid from date format. Use YYYY-MM-DD")
+        }
+    }
+    if to != "" {
+        if end, err = time.Parse("2006-01-02", to); err != nil {
+            return "", "", models.Invalid("invalid to date format. Use YYYY-MM-DD")
+        }
+    }
+    if end.Before(start) {
+        return "", "", models.Invalid("invalid period: to is before from")
+    }
+    if end.Sub(start) > portalMaxPeriodDays*24*time.Hour {
+        return "", "", models.Invalid("invalid period: must be at most 184 days")
+    }
+    return start.Format("2006-01-02"), end.Format("2006-01-02"), nil
+}
+
+func today() time.Time {
+    now := time.Now()
+    return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (s *PortalService) GetProfile(studentID int) (*models.Student, error) {
+    return s.StudentRepo.GetStudentByID(studentID)
+}
+
+// GetTimetable возвращает расписание группы студента и изменения в нём за период
+func (s *PortalService) GetTimetable(studentID int, from, to string) (*models.Timetable, error) {
+    student, err := s.StudentRepo.GetStudentByID(studentID)
+    if err != nil {
+        return nil, err
+    }
+
+    from, to, err = parsePeriod(from, to, today(), today().AddDate(0, 0, portalTimetableDays-1))
+    if err != nil {
+        return nil, err
+    }
+
+    schedule, err := s.ScheduleRepo.GetFilteredSchedules("", student.GroupName)
+    if err != nil {
+        return nil, err
+    }
+    if schedule == nil {
+        schedule = []models.Schedule{}
+    }
+
+    overrides, err := s.ScheduleRepo.GetOverrides(student.GroupName, from, to)
+    if err != nil {
+        return nil, err
+    }
+
+    return &models.Timetable{
+        GroupName: student.GroupName,
+        From:      from,
+        To:        to,
+        Schedule:  schedule,
+        Overrides: overrides,
+    }, nil
+}
+
+func (s *PortalService) GetGrades(studentID int) ([]models.Grade, error) {
+    if _, err := s.StudentRepo.GetStudentByID(studentID); err != nil {
+        return nil, err
+    }
+    return s.GradeSheetRepo.GetStudentGrades(studentID)
+}
+
+func (s *PortalService) GetAttendance(studentID int, from, to string) ([]models.Attendance, error) {
+    if _, err := s.StudentRepo.GetStudentByID(studentID); err != nil {
+        return nil, err
+    }
+
+    from, to, err := parsePeriod(from, to, today().AddDate(0, 0, -portalAttendanceDays), today())
+    if err != nil {
+        return nil, err
+    }
+    return s.AttendanceRepo.GetStudentAttendance(studentID, from, to)
+}
+
+func (s *PortalService) GetCourses(studentID int) ([]models.Course, error) {
+    if _, err := s.StudentRepo.GetStudentByID(studentID); err != nil {
+        return nil, err
+    }
+    return s.CourseRepo.GetStudentCourses(studentID)
+}
+
+// GetAnnouncements возвращает объявления группы студента и общие объявления
+func (s *PortalService) GetAnnouncements(studentID int) ([]models.Announcement, error) {
+    student, err := s.StudentRepo.GetStudentByID(studentID)
+    if err != nil {
+        return nil, err
+    }
+    return s.AnnouncementRepo.GetAnnouncements(student.GroupName)
+}
\ No newline at end of file
@@ -1,63 +1,108 @@
 package services
 
 import (
+    "backend/metrics"
     "backend/models"
     "backend/repository"
-    "fmt"
-    "errors"
+    "time"
 )
 
 type ScheduleService struct {
-    Repo *repositories.ScheduleRepository
-    TeacherRepo *repositories.TeacherRepository
+    Repo repositories.ScheduleStore
+    TeacherRepo repositories.TeacherStore
+    UoW         repositories.Transactor
 }
 
 func NewScheduleService(
-    scheduleRepo *repositories.ScheduleRepository,
-    teacherRepo *repositories.TeacherRepository, // Добавляем параметр для TeacherRepository
+    scheduleRepo repositories.ScheduleStore,
+    teacherRepo repositories.TeacherStore, // Добавляем параметр для TeacherRepository
+    uow repositories.Transactor,
 ) *ScheduleService {
     return &ScheduleService{
         Repo:       scheduleRepo,
         TeacherRepo: teacherRepo,
+        UoW:         uow,
     }
 }
 
-func (s *ScheduleService) CreateSchedule(teacherID, classroomID int, schedule *models.Schedule) error {
-    // Проверка, что start_time < end_time
-    if !schedule.StartTime.Before(schedule.EndTime) {
-        return errors.New("start_time must be before end_time")
+func (s *ScheduleService) CreateSchedule(teacherID, classroomID int, schedule *models.Schedule, userID int) error {
+    // Поля занятия, в том числе start_time < end_time
+    if err := models.Validate.Struct(schedule); err != nil {
+        return models.FromValidation(err)
     }
-
-    // Проверка, что продолжительность занятия равна 90 минутам (1.5 часа)
-    duration := schedule.EndTime.Sub(schedule.StartTime)
-    if duration.Minutes() != 90 {
-        return errors.New("lesson duration must be exactly 1.5 hours (90 minutes)")
+    if teacherID <= 0 || classroomID <= 0 {
+        return models.Invalid("teacher_id and classroom_id are required")
     }
 
-    // Проверяем пересечение времени
-    conflict, err := s.Repo.CheckScheduleConflict(teacherID, schedule.DayOfWeek, schedule.StartTime, schedule.EndTime)
-    if err != nil {
+    // Проверка, что продолжительность занятия равна 90 минутам (1.5 часа)
+    if err := checkLessonDuration(schedule.StartTime, schedule.EndTime); err != nil {
         return err
     }
-    if conflict {
-        return errors.New("teacher already has a class at this time")
+    duration := schedule.EndTime.Sub(schedule.StartTime)
+
+    if schedule.WeekType == "" {
+        schedule.WeekType = models.WeekAll
     }
 
-    // Получаем продолжительность занятия в часах
-    durationInHours := duration.Hours()
-    fmt.Printf("Duration of the lesson: %.2f hours\n", durationInHours)
+    // Проверка пересечений, списание часов и запись - одной транзакцией. Строка преподавателя
+    // заблокирована до конца, поэтому два параллельных занятия не пройдут проверку оба
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Teachers.LockTeacher(teacherID); err != nil {
+            return err
+        }
 
-    // Проверяем и списываем рабочие часы у преподавателя
-    err = s.TeacherRepo.UpdateTeacherWorkingHours(teacherID, durationInHours)
-    if err != nil {
-        fmt.Println("Error updating teacher working hours:", err)
-        return err
+        // Проверяем пересечение времени
+        conflict, err := tx.Schedules.CheckScheduleConflict(teacherID, 0, schedule.DayOfWeek, schedule.WeekType, schedule.StartTime, schedule.EndTime)
+        if err != nil {
+            return err
+        }
+        if conflict {
+            metrics.ScheduleConflicts.Inc()
+            return models.Conflict("teacher already has a class at this time")
+        }
+
+        // Получаем продолжительность занятия в часах
+        durationInHours := duration.Hours()
+
+        // Проверяем и списываем рабочие часы у преподавателя
+        if err := tx.Teachers.UpdateTeacherWorkingHours(teacherID, durationInHours); err != nil {
+            return err
+        }
+
+        // Создаем запись в расписании
+        if err := tx.Schedules.CreateSchedule(teacherID, classroomID, schedule); err != nil {
+            return err
+        }
+        return record(tx, userID, "create", "schedules", schedule.ID, nil, schedule)
+    })
+}
+
+// checkScheduleFilters проверяет значения фильтров, которых нет в схеме списка
+func checkScheduleFilters(query models.ListQuery) error {
+    if weekType := query.Filters["week_type"]; weekType != "" && !models.IsValidWeekType(weekType) {
+        return models.Invalid("invalid week_type: %s", weekType)
     }
+    return nil
+}
 
-    fmt.Println("Teacher working hours updated successfully")
+// ListSchedules возвращает страницу занятий с фильтрами и сортировкой
+func (s *ScheduleService) ListSchedules(query models.ListQuery) (*models.Page[models.Schedule], error) {
+    if err := checkScheduleFilters(query); err != nil {
+        return nil, err
+    }
+    schedules, total, err := s.Repo.ListSchedules(query)
+    if err != nil {
+        return nil, err
+    }
+    return models.NewPage(schedules, total, query), nil
+}
 
-    // Создаем запись в расписании
-    return s.Repo.CreateSchedule(teacherID, classroomID, schedule)
+// ExportSchedules передаёт fn все занятия с фильтрами и сортировкой для выгрузки в файл
+func (s *ScheduleService) ExportSchedules(query models.ListQuery, fn func(models.Schedule) error) error {
+    if err := checkScheduleFilters(query); err != nil {
+        return err
+    }
+    return s.Repo.EachSchedule(query, fn)
 }
 
 func (s *ScheduleService) GetSchedules() ([]models.Schedule, error) {
@@ -67,12 +112,105 @@ func (s *ScheduleService) GetSchedules() ([]models.Schedule, error) {
 func (s *ScheduleService) GetScheduleByID(id int) (*models.Schedule, error) {
     return s.Repo.GetScheduleByID(id)
 }
-func (s *ScheduleService) UpdateSchedule(id int, updates map[string]interface{}) (*models.Schedule, error) {
-    return s.Repo.UpdateSchedule(id, updates)
+// UpdateSchedule меняет переданные поля занятия. Если меняется только начало или только окончание,
+// новое время сравнивается с сохранённым. При смене времени, дня или преподавателя
+// пересечения проверяются так же, как при создании. При смене преподавателя или длительности
+// часы возвращаются прежнему преподавателю и списываются у нового в той же транзакции
+func (s *ScheduleService) UpdateSchedule(id int, update models.ScheduleUpdate, userID int) (*models.Schedule, error) {
+    if err := models.Validate.Struct(update); err != nil {
+        return nil, models.FromValidation(err)
+    }
+
+    var updated *models.Schedule
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        current, err := tx.Schedules.GetScheduleByID(id)
+        if err != nil {
+            return err
+        }
+        teacherID, err := tx.Schedules.GetScheduleTeacherID(id)
+        if err != nil {
+            return err
+        }
+        oldTeacherID, oldHours := teacherID, current.EndTime.Sub(current.StartTime).Hours()
+
+        // Итоговое занятие: сохранённые поля с учётом переданных
+        next := *current
+        if update.TeacherID != nil {
+            teacherID = *update.TeacherID
+        }
+        if update.StartTime != nil {
+            next.StartTime = *update.StartTime
+        }
+        if update.EndTime != nil {
+            next.EndTime = *update.EndTime
+        }
+        if update.DayOfWeek != nil {
+            next.DayOfWeek = *update.DayOfWeek
+        }
+        if update.WeekType != nil {
+            next.WeekType = *update.WeekType
+        }
+
+        if update.StartTime != nil || update.EndTime != nil {
+            if !next.EndTime.After(next.StartTime) {
+                return models.InvalidFields(models.ValidationFailed, map[string]string{"end_time": "after=start_time"})
+            }
+            if err := checkLessonDuration(next.StartTime, next.EndTime); err != nil {
+                return err
+            }
+        }
+
+        if update.TeacherID != nil || update.StartTime != nil || update.EndTime != nil || update.DayOfWeek != nil || update.WeekType != nil {
+            if err := tx.Teachers.LockTeacher(teacherID); err != nil {
+                return err
+            }
+            conflict, err := tx.Schedules.CheckScheduleConflict(teacherID, id, next.DayOfWeek, next.WeekType, next.StartTime, next.EndTime)
+            if err != nil {
+                return err
+            }
+            if conflict {
+                metrics.ScheduleConflicts.Inc()
+                return models.Conflict("teacher already has a class at this time")
+            }
+        }
+
+        if hours := next.EndTime.Sub(next.StartTime).Hours(); teacherID != oldTeacherID || hours != oldHours {
+            if err := tx.Teachers.ReturnTeacherWorkingHours(oldTeacherID, oldHours); err != nil {
+                return err
+            }
+            if err := tx.Teachers.UpdateTeacherWorkingHours(teacherID, hours); err != nil {
+                return err
+            }
+        }
+
+        if updated, err = tx.Schedules.UpdateSchedule(id, update); err != nil {
+            return err
+        }
+        return record(tx, userID, "update", "schedules", id, current, updated)
+    })
+    return updated, err
+}
+
+// checkLessonDuration занятие длится ровно 90 минут (1.5 часа)
+func checkLessonDuration(start, end time.Time) error {
+    if end.Sub(start).Minutes() != 90 {
+        return models.Invalid("lesson duration must be exactly 1.5 hours (90 minutes)")
+    }
+    return nil
 }
 
-func (s *ScheduleService) DeleteSchedule(id int) error {
-    return s.Repo.DeleteSchedule(id)
+// DeleteSchedule помещает занятие в корзину; часы возвращаются преподавателю в той же транзакции
+func (s *ScheduleService) DeleteSchedule(id, userID int) error {
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Schedules.GetScheduleByID(id)
+        if err != nil {
+            return err
+        }
+        if _, err := tx.Schedules.DeleteSchedule(id); err != nil {
+            return err
+        }
+        return record(tx, userID, "delete", "schedules", id, before, nil)
+    })
 }
 
 func (s *ScheduleService) GetFilteredSchedules(dayOfWeek, groupName string) ([]models.Schedule, error) {
@@ -85,7 +223,7 @@ func (s *ScheduleService) GetSchedulesByDay(dayOfWeek string) ([]models.Schedule
 
 func (s *ScheduleService) GetSchedulesByGroup(groupName string) ([]models.Schedule, error) {
     if groupName == "" {
-        return nil, errors.New("group name cannot be empty")
+        return nil, models.Invalid("group name cannot be empty")
     }
     schedules, err := s.Repo.GetFilteredSchedules("", groupName)
     if err != nil {
@@ -94,3 +232,51 @@ func (s *ScheduleService) GetSchedulesByGroup(groupName string) ([]models.Schedu
     return schedules, nil
 }
 
+
+// SaveOverride отменяет или переносит занятие на конкретную дату
+func (s *ScheduleService) SaveOverride(override *models.ScheduleOverride, userID int) error {
+    schedule, err := s.Repo.GetScheduleByID(override.ScheduleID)
+    if err != nil {
+        return err
+    }
+
+    date, err := time.Parse("2006-01-02", override.Date)
+    if err != nil {
+        return models.Invalid("invalid date format. Use YYYY-MM-DD")
+    }
+    if date.Weekday().String() != schedule.DayOfWeek {
+        return models.Invalid("invalid date: lesson takes place on %s", schedule.DayOfWeek)
+    }
+    if !schedule.OccursOn(date) {
+        return models.Invalid("invalid date: lesson takes place on %s weeks only", schedule.WeekType)
+    }
+
+    if (override.StartTime == nil) != (override.EndTime == nil) {
+        return models.Invalid("start_time and end_time must be set together")
+    }
+    if override.StartTime != nil && !override.StartTime.Before(*override.EndTime) {
+        return models.Invalid("start_time must be before end_time")
+    }
+    if !override.Cancelled && override.StartTime == nil && override.ClassroomID == nil {
+        return models.Invalid("no changes: set cancelled, classroom_id or start_time/end_time")
+    }
+
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Schedules.SaveOverride(override); err != nil {
+            return err
+        }
+        return record(tx, userID, "override", "schedules", override.ScheduleID, nil, override)
+    })
+}
+
+func (s *ScheduleService) DeleteOverride(scheduleID int, date string, userID int) error {
+    if _, err := time.Parse("2006-01-02", date); err != nil {
+        return models.Invalid("invalid date format. Use YYYY-MM-DD")
+    }
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Schedules.DeleteOverride(scheduleID, date); err != nil {
+            return err
+        }
+        return record(tx, userID, "delete_override", "schedules", scheduleID, map[string]string{"date": date}, nil)
+    })
+}
\ No newline at end of file
@@ -0,0 +1,320 @@
+package services
+
+import (
+    "backend/models"
+    "testing"
+)
+
+func TestCreateSchedule(t *testing.T) {
+    tests := []struct {
+        name      string
+        hours     float64          // Остаток часов преподавателя
+        existing  *models.Schedule // Занятие преподавателя, созданное заранее
+        schedule  *models.Schedule
+        classroom int // 0 - аудитория из фикстуры
+        wantCode  string
+        wantHours float64 // Остаток после вызова
+    }{
+        {
+            name:      "90 minutes debits 1.5 hours",
+            hours:     10,
+            schedule:  lesson("Monday", "09:00", "10:30", ""),
+            wantHours: 8.5,
+        },
+        {
+            name:      "60 minutes",
+            hours:     10,
+            schedule:  lesson("Monday", "09:00", "10:00", ""),
+            wantCode:  models.CodeValidation,
+            wantHours: 10,
+        },
+        {
+            name:      "end before start",
+            hours:     10,
+            schedule:  lesson("Monday", "10:30", "09:00", ""),
+            wantCode:  models.CodeValidation,
+            wantHours: 10,
+        },
+        {
+            name:      "unknown weekday",
+            hours:     10,
+            schedule:  lesson("Funday", "09:00", "10:30", ""),
+            wantCode:  models.CodeValidation,
+            wantHours: 10,
+        },
+        {
+            name:      "overlapping lesson",
+            hours:     10,
+            existing:  lesson("Monday", "09:00", "10:30", ""),
+            schedule:  lesson("Monday", "10:00", "11:30", ""),
+            wantCode:  models.CodeConflict,
+            wantHours: 8.5,
+        },
+        {
+            name:      "adjacent lesson",
+            hours:     10,
+            existing:  lesson("Monday", "09:00", "10:30", ""),
+            schedule:  lesson("Monday", "10:30", "12:00", ""),
+            wantHours: 7,
+        },
+        {
+            name:      "same time on another day",
+            hours:     10,
+            existing:  lesson("Monday", "09:00", "10:30", ""),
+            schedule:  lesson("Tuesday", "09:00", "10:30", ""),
+            wantHours: 7,
+        },
+        {
+            name:      "same time a week later overlaps",
+            hours:     10,
+            existing:  lesson("Monday", "09:00", "10:30", ""),
+            schedule:  &models.Schedule{GroupName: "ИВТ-21", DayOfWeek: "Monday", StartTime: at("09:00").AddDate(0, 0, 7), EndTime: at("10:30").AddDate(0, 0, 7)},
+            wantCode:  models.CodeConflict,
+            wantHours: 8.5,
+        },
+        {
+            name:      "odd and even weeks do not overlap",
+            hours:     10,
+            existing:  lesson("Monday", "09:00", "10:30", models.WeekOdd),
+            schedule:  lesson("Monday", "09:00", "10:30", models.WeekEven),
+            wantHours: 7,
+        },
+        {
+            name:      "every week overlaps odd weeks",
+            hours:     10,
+            existing:  lesson("Monday", "09:00", "10:30", models.WeekOdd),
+            schedule:  lesson("Monday", "09:00", "10:30", models.WeekAll),
+            wantCode:  models.CodeConflict,
+            wantHours: 8.5,
+        },
+        {
+            name:      "not enough hours",
+            hours:     1,
+            schedule:  lesson("Monday", "09:00", "10:30", ""),
+            wantCode:  models.CodeConflict,
+            wantHours: 1,
+        },
+        {
+            name:      "missing classroom rolls back hours",
+            hours:     10,
+            schedule:  lesson("Monday", "09:00", "10:30", ""),
+            classroom: 999,
+            wantCode:  models.CodeNotFound,
+            wantHours: 10,
+        },
+        {
+            name:      "missing group rolls back hours",
+            hours:     10,
+            schedule:  &models.Schedule{GroupName: "НЕТ-00", DayOfWeek: "Monday", StartTime: at("09:00"), EndTime: at("10:30")},
+            wantCode:  models.CodeValidation,
+            wantHours: 10,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            f := newFixture(t)
+            teacherID := f.teacher(t, "Иванов", tt.hours)
+            f.course(t, "ИВТ-21", nil)
+            classroomID := f.classroom(t, "101")
+            if tt.existing != nil {
+                f.lesson(t, teacherID, classroomID, tt.existing)
+            }
+            if tt.classroom != 0 {
+                classroomID = tt.classroom
+            }
+
+            err := f.schedules.CreateSchedule(teacherID, classroomID, tt.schedule, 0)
+            if code := errorCode(err); code != tt.wantCode {
+                t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantCode)
+            }
+            if hours := f.hours(t, teacherID); hours != tt.wantHours {
+                t.Errorf("working hours = %v, want %v", hours, tt.wantHours)
+            }
+            if err == nil {
+                saved, err := f.schedules.GetScheduleByID(tt.schedule.ID)
+                if err != nil {
+                    t.Fatalf("get schedule: %v", err)
+                }
+                if saved.TeacherName != "Иванов" || saved.ClassroomName != "101" || saved.WeekType == "" {
+                    t.Errorf("saved schedule = %+v", saved)
+                }
+            }
+        })
+    }
+}
+
+func TestCreateScheduleUnknownTeacher(t *testing.T) {
+    f := newFixture(t)
+    f.course(t, "ИВТ-21", nil)
+    classroomID := f.classroom(t, "101")
+
+    err := f.schedules.CreateSchedule(42, classroomID, lesson("Monday", "09:00", "10:30", ""), 0)
+    if code := errorCode(err); code != models.CodeNotFound {
+        t.Fatalf("error code = %q (%v), want %q", code, err, models.CodeNotFound)
+    }
+}
+
+func TestUpdateSchedule(t *testing.T) {
+    tests := []struct {
+        name     string
+        update   func(other int) models.ScheduleUpdate // other - второй преподаватель
+        wantCode string
+    }{
+        {
+            name:   "week type of itself does not conflict",
+            update: func(int) models.ScheduleUpdate { return models.ScheduleUpdate{WeekType: ptr(models.WeekOdd)} },
+        },
+        {
+            name: "shift both times",
+            update: func(int) models.ScheduleUpdate {
+                return models.ScheduleUpdate{StartTime: ptr(at("08:45")), EndTime: ptr(at("10:15"))}
+            },
+        },
+        {
+            name: "overlap with own lesson",
+            update: func(int) models.ScheduleUpdate {
+                return models.ScheduleUpdate{StartTime: ptr(at("10:00")), EndTime: ptr(at("11:30"))}
+            },
+            wantCode: models.CodeConflict,
+        },
+        {
+            name:     "only end time breaks duration",
+            update:   func(int) models.ScheduleUpdate { return models.ScheduleUpdate{EndTime: ptr(at("10:00"))} },
+            wantCode: models.CodeValidation,
+        },
+        {
+            name:     "only start time after stored end",
+            update:   func(int) models.ScheduleUpdate { return models.ScheduleUpdate{StartTime: ptr(at("11:00"))} },
+            wantCode: models.CodeValidation,
+        },
+        {
+            name:     "busy teacher",
+            update:   func(other int) models.ScheduleUpdate { return models.ScheduleUpdate{TeacherID: ptr(other)} },
+            wantCode: models.CodeConflict,
+        },
+        {
+            name:     "unknown weekday",
+            update:   func(int) models.ScheduleUpdate { return models.ScheduleUpdate{DayOfWeek: ptr("Funday")} },
+            wantCode: models.CodeValidation,
+        },
+        {
+            name:     "no fields",
+            update:   func(int) models.ScheduleUpdate { return models.ScheduleUpdate{} },
+            wantCode: models.CodeValidation,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            f := newFixture(t)
+            teacherID := f.teacher(t, "Иванов", 10)
+            otherID := f.teacher(t, "Петров", 10)
+            f.course(t, "ИВТ-21", nil)
+            classroomID := f.classroom(t, "101")
+            id := f.lesson(t, teacherID, classroomID, lesson("Monday", "09:00", "10:30", ""))
+            f.lesson(t, teacherID, classroomID, lesson("Monday", "10:30", "12:00", ""))
+            f.lesson(t, otherID, classroomID, lesson("Monday", "09:00", "10:30", ""))
+
+            before, _ := f.schedules.GetScheduleByID(id)
+            updated, err := f.schedules.UpdateSchedule(id, tt.update(otherID), 0)
+            if code := errorCode(err); code != tt.wantCode {
+                t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantCode)
+            }
+
+            after, _ := f.schedules.GetScheduleByID(id)
+            if err != nil && *after != *before {
+                t.Errorf("failed update changed schedule: %+v -> %+v", before, after)
+            }
+            if err == nil && *after != *updated {
+                t.Errorf("returned %+v, stored %+v", updated, after)
+            }
+        })
+    }
+}
+
+func TestUpdateScheduleNotFound(t *testing.T) {
+    f := newFixture(t)
+    _, err := f.schedules.UpdateSchedule(1, models.ScheduleUpdate{WeekType: ptr(models.WeekOdd)}, 0)
+    if code := errorCode(err); code != models.CodeNotFound {
+        t.Fatalf("error code = %q (%v), want %q", code, err, models.CodeNotFound)
+    }
+}
+
+// Остаток часов - бюджет минус действующие занятия: при смене преподавателя часы переходят к новому,
+// занятие в корзине возвращает часы, в том числе при каскадном удалении
+func TestScheduleHoursFollowLesson(t *testing.T) {
+    tests := []struct {
+        name      string
+        change    func(f *fixture, id, otherID, classroomID int) error
+        wantCode  string
+        wantHours float64 // Иванов, у которого занятие
+        wantOther float64 // Петров
+    }{
+        {
+            name: "move to another teacher",
+            change: func(f *fixture, id, otherID, _ int) error {
+                _, err := f.schedules.UpdateSchedule(id, models.ScheduleUpdate{TeacherID: ptr(otherID)}, 0)
+                return err
+            },
+            wantHours: 10,
+            wantOther: 0.5,
+        },
+        {
+            name: "move to a teacher without hours",
+            change: func(f *fixture, id, otherID, _ int) error {
+                if _, err := f.teachers.UpdateTeacherPartial(otherID, models.TeacherUpdate{WorkingHours: ptr(1.0)}, 0); err != nil {
+                    return err
+                }
+                _, err := f.schedules.UpdateSchedule(id, models.ScheduleUpdate{TeacherID: ptr(otherID)}, 0)
+                return err
+            },
+            wantCode:  models.CodeConflict,
+            wantHours: 8.5,
+            wantOther: 1,
+        },
+        {
+            name: "shift time keeps hours",
+            change: func(f *fixture, id, _, _ int) error {
+                _, err := f.schedules.UpdateSchedule(id, models.ScheduleUpdate{StartTime: ptr(at("11:00")), EndTime: ptr(at("12:30"))}, 0)
+                return err
+            },
+            wantHours: 8.5,
+            wantOther: 2,
+        },
+        {
+            name:      "delete lesson",
+            change:    func(f *fixture, id, _, _ int) error { return f.schedules.DeleteSchedule(id, 0) },
+            wantHours: 10,
+            wantOther: 2,
+        },
+        {
+            name: "delete classroom with lesson",
+            change: func(f *fixture, _, _, classroomID int) error {
+                _, err := f.classrooms.DeleteClassroom(classroomID, true, 0)
+                return err
+            },
+            wantHours: 10,
+            wantOther: 2,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            f := newFixture(t)
+            teacherID := f.teacher(t, "Иванов", 10)
+            otherID := f.teacher(t, "Петров", 2)
+            f.course(t, "ИВТ-21", nil)
+            classroomID := f.classroom(t, "101")
+            id := f.lesson(t, teacherID, classroomID, lesson("Monday", "09:00", "10:30", ""))
+
+            err := tt.change(f, id, otherID, classroomID)
+            if code := errorCode(err); code != tt.wantCode {
+                t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantCode)
+            }
+            if hours, other := f.hours(t, teacherID), f.hours(t, otherID); hours != tt.wantHours || other != tt.wantOther {
+                t.Errorf("working hours = %v and %v, want %v and %v", hours, other, tt.wantHours, tt.wantOther)
+            }
+        })
+    }
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+    "backend/models"
+    "backend/repository"
+    "backend/utils"
+    "unicode/utf8"
+)
+
+// minSearchLength короче двух символов триграммы ничего осмысленного не находят
+const minSearchLength = 2
+
+type SearchService struct {
+    Repo repositories.SearchStore
+}
+
+func NewSearchService(repo repositories.SearchStore) *SearchService {
+    return &SearchService{Repo: repo}
+}
+
+// Search ищет студентов, преподавателей, курсы и аудитории.
+// allowed - типы, доступные роли; requested - типы из запроса (пусто - все доступные)
+func (s *SearchService) Search(query string, requested, allowed []string, limit int) (*models.SearchResponse, error) {
+    variants := utils.SearchVariants(query)
+    if len(variants) == 0 || utf8.RuneCountInString(variants[0]) < minSearchLength {
+        return nil, models.Invalid("query must be at least %d characters", minSearchLength)
+    }
+    if limit < 1 || limit > models.MaxSearchLimit {
+        return nil, models.Invalid("limit must be between 1 and %d", models.MaxSearchLimit)
+    }
+
+    types := allowed
+    if len(requested) > 0 {
+        permitted := map[string]bool{}
+        for _, searchType := range allowed {
+            permitted[searchType] = true
+        }
+        types = nil
+        for _, searchType := range requested {
+            known := false
+            for _, t := range models.SearchTypes {
+                known = known || t.Type == searchType
+            }
+            if !known {
+                return nil, models.Invalid("invalid search type: %s", searchType)
+            }
+            // Недоступные роли типы молча пропускаем: ответ не должен выдавать, что такие записи есть
+            if permitted[searchType] {
+                types = append(types, searchType)
+            }
+        }
+    }
+
+    results, err := s.Repo.Search(variants, types, limit)
+    if err != nil {
+        return nil, err
+    }
+    if types == nil {
+        types = []string{}
+    }
+    return &models.SearchResponse{Query: query, Types: types, Items: results}, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+    "backend/models"
+    "backend/repository/memory"
+    "errors"
+    "testing"
+    "time"
+)
+
+// fixture сервисы над репозиториями в памяти
+type fixture struct {
+    db         *memory.DB
+    teachers   *TeacherService
+    courses    *CourseService
+    classrooms *ClassroomService
+    schedules  *ScheduleService
+    students   *StudentService
+}
+
+func newFixture(t *testing.T) *fixture {
+    t.Helper()
+    db := memory.NewDB()
+    uow := memory.NewUnitOfWork(db)
+    teacherRepo := memory.NewTeacherRepository(db)
+    return &fixture{
+        db:         db,
+        teachers:   NewTeacherService(teacherRepo, uow),
+        courses:    NewCourseService(memory.NewCourseRepository(db), uow),
+        classrooms: NewClassroomService(memory.NewClassroomRepository(db), uow),
+        schedules:  NewScheduleService(memory.NewScheduleRepository(db), teacherRepo, uow),
+        students:   NewStudentService(memory.NewStudentRepository(db), uow),
+    }
+}
+
+func (f *fixture) teacher(t *testing.T, name string, hours float64) int {
+    t.Helper()
+    teacher := &models.Teacher{Name: name, Subject: "Математика", WorkingHours: hours}
+    if err := f.teachers.CreateTeacher(teacher, 0); err != nil {
+        t.Fatalf("create teacher: %v", err)
+    }
+    return teacher.ID
+}
+
+func (f *fixture) course(t *testing.T, name string, teacherID *int) int {
+    t.Helper()
+    course := &models.Course{Name: name, TeacherID: teacherID}
+    if err := f.courses.CreateCourse(course, 0); err != nil {
+        t.Fatalf("create course: %v", err)
+    }
+    return course.ID
+}
+
+func (f *fixture) classroom(t *testing.T, name string) int {
+    t.Helper()
+    classroom := &models.Classroom{Name: name, Capacity: 30}
+    if err := f.classrooms.CreateClassroom(classroom, 0); err != nil {
+        t.Fatalf("create classroom: %v", err)
+    }
+    return classroom.ID
+}
+
+func (f *fixture) lesson(t *testing.T, teacherID, classroomID int, schedule *models.Schedule) int {
+    t.Helper()
+    if err := f.schedules.CreateSchedule(teacherID, classroomID, schedule, 0); err != nil {
+        t.Fatalf("create schedule: %v", err)
+    }
+    return schedule.ID
+}
+
+func (f *fixture) hours(t *testing.T, teacherID int) float64 {
+    t.Helper()
+    teacher, err := f.teachers.GetTeacherByID(teacherID)
+    if err != nil {
+        t.Fatalf("get teacher: %v", err)
+    }
+    return teacher.WorkingHours
+}
+
+// at время занятия в первую учебную неделю: 1 сентября 2025 - понедельник
+func at(clock string) time.Time {
+    value, err := time.Parse("2006-01-02 15:04", "2025-09-01 "+clock)
+    if err != nil {
+        panic(err)
+    }
+    return value
+}
+
+// lesson занятие группы ИВТ-21 с началом и окончанием в формате HH:MM
+func lesson(day, start, end, weekType string) *models.Schedule {
+    return &models.Schedule{GroupName: "ИВТ-21", DayOfWeek: day, StartTime: at(start), EndTime: at(end), WeekType: weekType}
+}
+
+// errorCode код models.Error; "" - ошибки нет
+func errorCode(err error) string {
+    if err == nil {
+        return ""
+    }
+    var modelErr *models.Error
+    if errors.As(err, &modelErr) {
+        return modelErr.Code
+    }
+    return "untyped: " + err.Error()
+}
+
+func ptr[T any](value T) *T {
+    return &value
+}
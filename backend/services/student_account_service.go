@@ -0,0 +1,121 @@
+package services
+
+import (
+    "backend/models"
+    "backend/repository"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/hex"
+    "math/big"
+    "strings"
+    "time"
+)
+
+// Коды активации: без похожих символов (0/O, 1/I/L), действуют две недели
+const (
+    activationAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+    activationLength   = 10
+    activationTTL      = 14 * 24 * time.Hour
+)
+
+// StudentAccountService выдаёт студентам коды активации и создаёт их учётные записи
+type StudentAccountService struct {
+    Repo repositories.ActivationStore
+    UoW  repositories.Transactor
+}
+
+func NewStudentAccountService(repo repositories.ActivationStore, uow repositories.Transactor) *StudentAccountService {
+    return &StudentAccountService{Repo: repo, UoW: uow}
+}
+
+func generateActivationCode() (string, error) {
+    max := big.NewInt(int64(len(activationAlphabet)))
+    code := make([]byte, activationLength)
+    for i := range code {
+        n, err := rand.Int(rand.Reader, max)
+        if err != nil {
+            return "", err
+        }
+        code[i] = activationAlphabet[n.Int64()]
+    }
+    return string(code), nil
+}
+
+func hashActivationCode(code string) string {
+    sum := sha256.Sum256([]byte(strings.ToUpper(strings.TrimSpace(code))))
+    return hex.EncodeToString(sum[:])
+}
+
+// ProvisionAccounts выдаёт коды активации всем обучающимся студентам без учётной записи;
+// пустой groupName - по всем группам. Коды возвращаются только один раз и в журнал аудита не попадают
+func (s *StudentAccountService) ProvisionAccounts(groupName string, userID int) ([]models.ActivationCode, error) {
+    students, err := s.Repo.GetStudentsWithoutAccount(groupName)
+    if err != nil {
+        return nil, err
+    }
+
+    expiresAt := time.Now().Add(activationTTL)
+    codes := make([]models.ActivationCode, 0, len(students))
+    hashes := make(map[int]string, len(students))
+    for _, student := range students {
+        code, err := generateActivationCode()
+        if err != nil {
+            return nil, err
+        }
+        hashes[student.ID] = hashActivationCode(code)
+        codes = append(codes, models.ActivationCode{
+            StudentID:   student.ID,
+            StudentName: student.Name,
+            GroupName:   student.GroupName,
+            Code:        code,
+            ExpiresAt:   expiresAt,
+        })
+    }
+
+    if len(hashes) > 0 {
+        err := s.UoW.Do(func(tx *repositories.Tx) error {
+            if err := tx.Activations.ReplaceCodes(hashes, expiresAt); err != nil {
+                return err
+            }
+            for _, code := range codes {
+                if err := record(tx, userID, "issue_activation_code", "students", code.StudentID, nil, nil); err != nil {
+                    return err
+                }
+            }
+            return nil
+        })
+        if err != nil {
+            return nil, err
+        }
+    }
+    return codes, nil
+}
+
+// Activate создаёт учётную запись студента по коду активации; в журнале автор - сам студент
+func (s *StudentAccountService) Activate(code, username, password string) (*models.User, error) {
+    if code == "" {
+        return nil, models.Invalid("code is required")
+    }
+    if len(password) < 8 {
+        return nil, models.Invalid("password must be at least 8 characters")
+    }
+
+    user := &models.User{Username: username, Role: "student"}
+    if err := models.Validate.Struct(user); err != nil {
+        return nil, models.FromValidation(err)
+    }
+    if err := user.HashPassword(password); err != nil {
+        return nil, err
+    }
+
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Activations.Activate(hashActivationCode(code), user); err != nil {
+            return err
+        }
+        return record(tx, user.ID, "activate", "users", user.ID, nil, user)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return user, nil
+}
\ No newline at end of file
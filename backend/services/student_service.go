@@ -3,36 +3,174 @@ package services
 import (
     "backend/models"
     "backend/repository"
+    "backend/utils"
+    "time"
 )
 
 type StudentService struct {
-    Repo *repositories.StudentRepository
+    Repo repositories.StudentStore
+    UoW  repositories.Transactor
 }
 
-func NewStudentService(repo *repositories.StudentRepository) *StudentService {
-    return &StudentService{Repo: repo}
+func NewStudentService(repo repositories.StudentStore, uow repositories.Transactor) *StudentService {
+    return &StudentService{Repo: repo, UoW: uow}
 }
 
-func (s *StudentService) CreateStudent(student *models.Student) error {
-    return s.Repo.CreateStudent(student)
+// CreateStudent проверяет данные и создаёт студента; возраст считается по дате рождения
+func (s *StudentService) CreateStudent(student *models.Student, userID int) error {
+    if err := models.Validate.Struct(student); err != nil {
+        return models.FromValidation(err)
+    }
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Students.CreateStudent(student); err != nil {
+            return err
+        }
+        dateOfBirth, _ := time.Parse("2006-01-02", student.DateOfBirth)
+        student.Age = utils.CalculateAge(dateOfBirth)
+        return record(tx, userID, "create", "students", student.ID, nil, student)
+    })
 }
 
-func (s *StudentService) GetStudents() ([]models.Student, error) {
-    return s.Repo.GetStudents()
+// checkStudentFilters проверяет значения фильтров, которых нет в схеме списка
+func checkStudentFilters(query models.ListQuery) error {
+    if status := query.Filters["status"]; status != "" && !models.IsValidStudentStatus(status) {
+        return models.Invalid("invalid status: %s", status)
+    }
+    return nil
+}
+
+// ListStudents возвращает страницу студентов с фильтрами и сортировкой
+func (s *StudentService) ListStudents(query models.ListQuery) (*models.Page[models.Student], error) {
+    if err := checkStudentFilters(query); err != nil {
+        return nil, err
+    }
+    students, total, err := s.Repo.ListStudents(query)
+    if err != nil {
+        return nil, err
+    }
+    return models.NewPage(students, total, query), nil
+}
+
+// ExportStudents передаёт fn всех студентов с фильтрами и сортировкой для выгрузки в файл
+func (s *StudentService) ExportStudents(query models.ListQuery, fn func(models.Student) error) error {
+    if err := checkStudentFilters(query); err != nil {
+        return err
+    }
+    return s.Repo.EachStudent(query, fn)
+}
+
+// GetStudents возвращает студентов с указанным статусом ("" - все)
+func (s *StudentService) GetStudents(status string) ([]models.Student, error) {
+    if status != "" && !models.IsValidStudentStatus(status) {
+        return nil, models.Invalid("invalid status: %s", status)
+    }
+    return s.Repo.GetStudents(status)
 }
 
 func (s *StudentService) GetStudentByID(id int) (*models.Student, error) {
     return s.Repo.GetStudentByID(id)
 }
 
-func (s *StudentService) UpdateStudent(id int, updates map[string]interface{}) (*models.Student, error) {
-    return s.Repo.UpdateStudent(id, updates)
+func (s *StudentService) UpdateStudent(id int, update models.StudentUpdate, userID int) (*models.Student, error) {
+    if err := models.Validate.Struct(update); err != nil {
+        return nil, models.FromValidation(err)
+    }
+
+    var student *models.Student
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Students.GetStudentByID(id)
+        if err != nil {
+            return err
+        }
+        if student, err = tx.Students.UpdateStudent(id, update); err != nil {
+            return err
+        }
+        return record(tx, userID, "update", "students", id, before, student)
+    })
+    return student, err
 }
 
-func (s *StudentService) DeleteStudent(id int) error {
-    return s.Repo.DeleteStudent(id)
+func (s *StudentService) DeleteStudent(id, userID int) error {
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Students.GetStudentByID(id)
+        if err != nil {
+            return err
+        }
+        if _, err := tx.Students.DeleteStudent(id); err != nil {
+            return err
+        }
+        return record(tx, userID, "delete", "students", id, before, nil)
+    })
+}
+
+// changeStudent применяет приказ и записывает в журнал студента до и после него
+func (s *StudentService) changeStudent(id, userID int, action string, apply func(tx *repositories.Tx) error) error {
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Students.GetStudentByID(id)
+        if err != nil {
+            return err
+        }
+        if err := apply(tx); err != nil {
+            return err
+        }
+        after, err := tx.Students.GetStudentByID(id)
+        if err != nil {
+            return err
+        }
+        return record(tx, userID, action, "students", id, before, after)
+    })
 }
 
 func (s *StudentService) CourseExists(courseName string) (bool, error) {
     return s.Repo.CourseExists(courseName)
+}
+
+// ChangeStudentStatus меняет статус студента на основании приказа
+func (s *StudentService) ChangeStudentStatus(id int, order *models.StudentOrder, userID int) error {
+    if !models.IsValidStudentStatus(order.NewStatus) {
+        return models.Invalid("invalid status: %s", order.NewStatus)
+    }
+    if err := validateOrder(order); err != nil {
+        return err
+    }
+    return s.changeStudent(id, userID, "status_change", func(tx *repositories.Tx) error {
+        return tx.Students.ChangeStudentStatus(id, order)
+    })
+}
+
+// TransferStudent переводит студента в другую группу на основании приказа
+func (s *StudentService) TransferStudent(id int, groupName string, order *models.StudentOrder, userID int) error {
+    if groupName == "" {
+        return models.Invalid("group_name is required")
+    }
+    if err := validateOrder(order); err != nil {
+        return err
+    }
+    return s.changeStudent(id, userID, "transfer", func(tx *repositories.Tx) error {
+        return tx.Students.TransferStudent(id, groupName, order)
+    })
+}
+
+func validateOrder(order *models.StudentOrder) error {
+    if order.OrderNumber == "" {
+        return models.Invalid("order_number is required")
+    }
+    if order.OrderDate == "" {
+        return models.Invalid("order_date is required")
+    }
+    return nil
+}
+
+func (s *StudentService) GetStudentOrders(id int) ([]models.StudentOrder, error) {
+    if _, err := s.Repo.GetStudentByID(id); err != nil {
+        return nil, err
+    }
+    return s.Repo.GetStudentOrders(id)
+}
+
+func (s *StudentService) GetStudentGroupHistory(id int) ([]models.StudentGroupChange, error) {
+    if _, err := s.Repo.GetStudentByID(id); err != nil {
+        return nil, err
+    }
+    return s.Repo.GetStudentGroupHistory(id)
 }
\ No newline at end of file
@@ -0,0 +1,119 @@
+package services
+
+import (
+    "backend/models"
+    "testing"
+)
+
+func TestCreateStudent(t *testing.T) {
+    tests := []struct {
+        name     string
+        student  models.Student
+        wantCode string
+    }{
+        {name: "valid", student: models.Student{Name: "Сидоров", DateOfBirth: "2005-03-14", GroupName: "ИВТ-21"}},
+        {name: "missing group", student: models.Student{Name: "Сидоров", DateOfBirth: "2005-03-14", GroupName: "НЕТ-00"}, wantCode: models.CodeValidation},
+        {name: "bad date", student: models.Student{Name: "Сидоров", DateOfBirth: "14.03.2005", GroupName: "ИВТ-21"}, wantCode: models.CodeValidation},
+        {name: "birth in the future", student: models.Student{Name: "Сидоров", DateOfBirth: "2999-01-01", GroupName: "ИВТ-21"}, wantCode: models.CodeValidation},
+        {name: "no name", student: models.Student{DateOfBirth: "2005-03-14", GroupName: "ИВТ-21"}, wantCode: models.CodeValidation},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            f := newFixture(t)
+            f.course(t, "ИВТ-21", nil)
+
+            student := tt.student
+            err := f.students.CreateStudent(&student, 0)
+            if code := errorCode(err); code != tt.wantCode {
+                t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantCode)
+            }
+            if err == nil && (student.Status != models.StudentActive || student.Age == 0) {
+                t.Errorf("created student = %+v", student)
+            }
+        })
+    }
+}
+
+func TestTransferStudent(t *testing.T) {
+    order := func() *models.StudentOrder {
+        return &models.StudentOrder{OrderNumber: "15-к", OrderDate: "2025-09-01"}
+    }
+    tests := []struct {
+        name     string
+        group    string
+        order    *models.StudentOrder
+        wantCode string
+    }{
+        {name: "to another group", group: "ПМ-22", order: order()},
+        {name: "to the same group", group: "ИВТ-21", order: order(), wantCode: models.CodeConflict},
+        {name: "to a missing group", group: "НЕТ-00", order: order(), wantCode: models.CodeValidation},
+        {name: "without order number", group: "ПМ-22", order: &models.StudentOrder{OrderDate: "2025-09-01"}, wantCode: models.CodeValidation},
+        {name: "bad order date", group: "ПМ-22", order: &models.StudentOrder{OrderNumber: "15-к", OrderDate: "01.09.2025"}, wantCode: models.CodeValidation},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            f := newFixture(t)
+            f.course(t, "ИВТ-21", nil)
+            f.course(t, "ПМ-22", nil)
+            student := &models.Student{Name: "Сидоров", DateOfBirth: "2005-03-14", GroupName: "ИВТ-21"}
+            if err := f.students.CreateStudent(student, 0); err != nil {
+                t.Fatalf("create student: %v", err)
+            }
+
+            err := f.students.TransferStudent(student.ID, tt.group, tt.order, 0)
+            if code := errorCode(err); code != tt.wantCode {
+                t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantCode)
+            }
+
+            saved, _ := f.students.GetStudentByID(student.ID)
+            history, _ := f.students.GetStudentGroupHistory(student.ID)
+            orders, _ := f.students.GetStudentOrders(student.ID)
+            if err != nil {
+                if saved.GroupName != "ИВТ-21" || len(history) != 0 || len(orders) != 0 {
+                    t.Errorf("failed transfer left changes: group %s, history %v, orders %v", saved.GroupName, history, orders)
+                }
+                return
+            }
+            if saved.GroupName != tt.group || saved.Status != models.StudentActive {
+                t.Errorf("student = %+v", saved)
+            }
+            if len(history) != 1 || history[0].OrderID == nil || *history[0].OrderID != tt.order.ID {
+                t.Errorf("history = %+v, order %d", history, tt.order.ID)
+            }
+        })
+    }
+}
+
+func TestChangeStudentStatus(t *testing.T) {
+    tests := []struct {
+        name     string
+        status   string
+        wantCode string
+    }{
+        {name: "academic leave", status: models.StudentAcademicLeave},
+        {name: "same status", status: models.StudentActive, wantCode: models.CodeConflict},
+        {name: "unknown status", status: "vacation", wantCode: models.CodeValidation},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            f := newFixture(t)
+            f.course(t, "ИВТ-21", nil)
+            student := &models.Student{Name: "Сидоров", DateOfBirth: "2005-03-14", GroupName: "ИВТ-21"}
+            if err := f.students.CreateStudent(student, 0); err != nil {
+                t.Fatalf("create student: %v", err)
+            }
+
+            order := &models.StudentOrder{OrderNumber: "16-к", OrderDate: "2025-09-02", NewStatus: tt.status}
+            err := f.students.ChangeStudentStatus(student.ID, order, 0)
+            if code := errorCode(err); code != tt.wantCode {
+                t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantCode)
+            }
+            if err == nil && order.OldStatus != models.StudentActive {
+                t.Errorf("order = %+v", order)
+            }
+        })
+    }
+}
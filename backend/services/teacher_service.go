@@ -3,26 +3,23 @@ package services
 import (
 	"backend/models"
 	"backend/repository"
-	"errors"
-	"fmt"
-	"strings"
 
-	"golang.org/x/crypto/bcrypt"
 )
 
 type TeacherService struct {
-    Repo *repositories.TeacherRepository
+    Repo repositories.TeacherStore
+    UoW  repositories.Transactor
 }
 
-func NewTeacherService(repo *repositories.TeacherRepository) *TeacherService {
-    return &TeacherService{Repo: repo}
+func NewTeacherService(repo repositories.TeacherStore, uow repositories.Transactor) *TeacherService {
+    return &TeacherService{Repo: repo, UoW: uow}
 }
 
-// Создание преподавателя
-func (s *TeacherService) CreateTeacher(teacher *models.Teacher) error {
+// Создание преподавателя; userID - автор изменения для журнала аудита
+func (s *TeacherService) CreateTeacher(teacher *models.Teacher, userID int) error {
     // Проверяем валидацию модели
     if err := models.Validate.Struct(teacher); err != nil {
-        return err
+        return models.FromValidation(err)
     }
 
     // Проверяем, существует ли уже преподаватель с таким именем и предметом
@@ -31,7 +28,7 @@ func (s *TeacherService) CreateTeacher(teacher *models.Teacher) error {
         return err
     }
     if exists {
-        return errors.New("teacher with this name and subject already exists")
+        return models.Conflict("teacher with this name and subject already exists")
     }
 
     // Проверяем, что все указанные курсы существуют
@@ -41,12 +38,57 @@ func (s *TeacherService) CreateTeacher(teacher *models.Teacher) error {
             return err
         }
         if !validCourses {
-            return errors.New("some courses do not exist")
+            return models.Invalid("some courses do not exist")
         }
     }
 
     // Создаем нового преподавателя
-    return s.Repo.CreateTeacher(teacher)
+    return s.UoW.Do(func(tx *repositories.Tx) error {
+        if err := tx.Teachers.CreateTeacher(teacher); err != nil {
+            return err
+        }
+        return record(tx, userID, "create", "teachers", teacher.ID, nil, teacher)
+    })
+}
+
+// RecalculateWorkingHours пересчитывает остаток часов по расписанию; apply = false - без сохранения.
+// Каждое исправление остатка попадает в журнал аудита
+func (s *TeacherService) RecalculateWorkingHours(apply bool, userID int) ([]models.HoursChange, error) {
+    if !apply {
+        return s.Repo.RecalculateWorkingHours(false)
+    }
+
+    var changes []models.HoursChange
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        var err error
+        changes, err = tx.Teachers.RecalculateWorkingHours(true)
+        if err != nil {
+            return err
+        }
+        for _, change := range changes {
+            before := map[string]float64{"working_hours": change.OldHours}
+            after := map[string]float64{"working_hours": change.NewHours}
+            if err := record(tx, userID, "recalc_hours", "teachers", change.TeacherID, before, after); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    return changes, err
+}
+
+// ListTeachers возвращает страницу преподавателей с фильтрами и сортировкой
+func (s *TeacherService) ListTeachers(query models.ListQuery) (*models.Page[models.Teacher], error) {
+    teachers, total, err := s.Repo.ListTeachers(query)
+    if err != nil {
+        return nil, err
+    }
+    return models.NewPage(teachers, total, query), nil
+}
+
+// ExportTeachers передаёт fn все преподавателей с фильтрами и сортировкой для выгрузки в файл
+func (s *TeacherService) ExportTeachers(query models.ListQuery, fn func(models.Teacher) error) error {
+    return s.Repo.EachTeacher(query, fn)
 }
 
 // Получение всех преподавателей
@@ -59,44 +101,52 @@ func (s *TeacherService) GetTeacherByID(id int) (*models.Teacher, error) {
     return s.Repo.GetTeacherByID(id)
 }
 
-func (s *TeacherService) UpdateTeacherPartial(teacherID int, updates map[string]interface{}) (map[string]interface{}, error) {
-    updatedData, err := s.Repo.UpdateTeacherPartial(teacherID, updates)
-    if err != nil {
-        if strings.Contains(err.Error(), "not found") {
-            return nil, fmt.Errorf("teacher with id %d not found", teacherID)
-        }
-        return nil, fmt.Errorf("failed to update teacher: %v", err)
+func (s *TeacherService) UpdateTeacherPartial(teacherID int, update models.TeacherUpdate, userID int) (*models.Teacher, error) {
+    if err := models.Validate.Struct(update); err != nil {
+        return nil, models.FromValidation(err)
     }
-    return updatedData, nil
+
+    var teacher *models.Teacher
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Teachers.GetTeacherByID(teacherID)
+        if err != nil {
+            return err
+        }
+        if teacher, err = tx.Teachers.UpdateTeacherPartial(teacherID, update); err != nil {
+            return err
+        }
+        return record(tx, userID, "update", "teachers", teacherID, before, teacher)
+    })
+    return teacher, err
 }
 
-// Удаление преподавателя
-func (s *TeacherService) DeleteTeacher(id int) error {
-    return s.Repo.DeleteTeacher(id)
+// Удаление преподавателя в корзину; его занятия удаляются только после подтверждения
+func (s *TeacherService) DeleteTeacher(id int, confirm bool, userID int) (models.Cascade, error) {
+    var cascade models.Cascade
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        before, err := tx.Teachers.GetTeacherByID(id)
+        if err != nil {
+            return err
+        }
+        cascade, err = confirmDelete(confirm,
+            func() (models.Cascade, error) { return tx.Teachers.GetTeacherDeletionImpact(id) },
+            func() (models.Cascade, error) { return tx.Teachers.DeleteTeacher(id) },
+        )
+        if err != nil {
+            return err
+        }
+        if err := record(tx, userID, "delete", "teachers", id, before, nil); err != nil {
+            return err
+        }
+        return recordCascade(tx, userID, "cascade_delete", cascade)
+    })
+    return cascade, err
 }
 
 func (s *TeacherService) GetAllTeachersWithCourses() ([]models.Teacher, error) {
     return s.Repo.GetAllTeachersWithCourses()
 }
 
-func (s *TeacherService) GetTeacherSchedule(teacherName string) ([]models.ScheduleResponse, error) {
+func (s *TeacherService) GetTeacherSchedule(teacherName string) ([]models.Schedule, error) {
     return s.Repo.GetTeacherSchedule(teacherName)
 }
-
-func (s *TeacherService) UpdateTeacherProfile(teacherID int, updates map[string]interface{}) error {
-    // Если передан новый пароль, хэшируем его
-    if newPassword, ok := updates["password"].(string); ok {
-        hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
-        if err != nil {
-            return err
-        }
-        updates["password"] = string(hashedPassword)
-    }
-
-    // Обновляем данные в базе данных
-    if err := s.Repo.UpdateTeacherProfile(teacherID, updates); err != nil {
-        return err
-    }
-
-    return nil
-}
\ No newline at end of file
@@ -0,0 +1,80 @@
+package services
+
+import (
+    "backend/models"
+    "testing"
+)
+
+func TestCreateTeacher(t *testing.T) {
+    tests := []struct {
+        name     string
+        teacher  models.Teacher
+        wantCode string
+    }{
+        {name: "valid", teacher: models.Teacher{Name: "Петров", Subject: "Физика", WorkingHours: 10, Courses: []string{"ИВТ-21"}}},
+        {name: "same name, other subject", teacher: models.Teacher{Name: "Иванов", Subject: "Физика"}},
+        {name: "same name and subject", teacher: models.Teacher{Name: "Иванов", Subject: "Математика"}, wantCode: models.CodeConflict},
+        {name: "unknown course", teacher: models.Teacher{Name: "Петров", Subject: "Физика", Courses: []string{"НЕТ-00"}}, wantCode: models.CodeValidation},
+        {name: "negative hours", teacher: models.Teacher{Name: "Петров", Subject: "Физика", WorkingHours: -1}, wantCode: models.CodeValidation},
+        {name: "no subject", teacher: models.Teacher{Name: "Петров"}, wantCode: models.CodeValidation},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            f := newFixture(t)
+            f.teacher(t, "Иванов", 10)
+            f.course(t, "ИВТ-21", nil)
+
+            teacher := tt.teacher
+            err := f.teachers.CreateTeacher(&teacher, 0)
+            if code := errorCode(err); code != tt.wantCode {
+                t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantCode)
+            }
+        })
+    }
+}
+
+// Бюджет часов меняется вместе с остатком, а удалённое занятие возвращает часы, поэтому пересчёт
+// находит только остаток, изменённый в обход учёта часов
+func TestRecalculateWorkingHours(t *testing.T) {
+    f := newFixture(t)
+    teacherID := f.teacher(t, "Иванов", 10)
+    f.course(t, "ИВТ-21", nil)
+    classroomID := f.classroom(t, "101")
+    id := f.lesson(t, teacherID, classroomID, lesson("Monday", "09:00", "10:30", ""))
+    f.lesson(t, teacherID, classroomID, lesson("Tuesday", "09:00", "10:30", ""))
+
+    if _, err := f.teachers.UpdateTeacherPartial(teacherID, models.TeacherUpdate{WorkingHours: ptr(20.0)}, 0); err != nil {
+        t.Fatalf("update hours: %v", err)
+    }
+    if err := f.schedules.DeleteSchedule(id, 0); err != nil {
+        t.Fatalf("delete schedule: %v", err)
+    }
+    changes, err := f.teachers.RecalculateWorkingHours(false, 0)
+    if err != nil || len(changes) != 0 {
+        t.Fatalf("changes = %v, %v; want none", changes, err)
+    }
+
+    // Списание без занятия, как до учёта часов в транзакциях
+    if err := f.teachers.Repo.UpdateTeacherWorkingHours(teacherID, 1.5); err != nil {
+        t.Fatalf("debit hours: %v", err)
+    }
+    changes, err = f.teachers.RecalculateWorkingHours(false, 0)
+    if err != nil {
+        t.Fatalf("recalculate: %v", err)
+    }
+    want := models.HoursChange{TeacherID: teacherID, TeacherName: "Иванов", OldHours: 20, NewHours: 21.5}
+    if len(changes) != 1 || changes[0] != want {
+        t.Fatalf("changes = %+v, want [%+v]", changes, want)
+    }
+    if hours := f.hours(t, teacherID); hours != 20 {
+        t.Errorf("dry run changed hours to %v", hours)
+    }
+
+    if _, err := f.teachers.RecalculateWorkingHours(true, 0); err != nil {
+        t.Fatalf("apply: %v", err)
+    }
+    if hours := f.hours(t, teacherID); hours != 21.5 {
+        t.Errorf("working hours = %v, want 21.5", hours)
+    }
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+    "backend/export"
+    "backend/models"
+    "backend/repository"
+    "fmt"
+    "io"
+    "sort"
+    "time"
+)
+
+// Дни недели в порядке сетки и их названия для печати
+var (
+    timetableDays = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+    dayNamesRu    = map[string]string{
+        "Monday": "Понедельник", "Tuesday": "Вторник", "Wednesday": "Среда", "Thursday": "Четверг",
+        "Friday": "Пятница", "Saturday": "Суббота", "Sunday": "Воскресенье",
+    }
+    weekTypeNamesRu = map[string]string{models.WeekOdd: "нечётная", models.WeekEven: "чётная"}
+)
+
+// TimetableService печатные сетки расписания по данным GetFilteredSchedules
+type TimetableService struct {
+    ScheduleRepo repositories.ScheduleStore
+}
+
+func NewTimetableService(scheduleRepo repositories.ScheduleStore) *TimetableService {
+    return &TimetableService{ScheduleRepo: scheduleRepo}
+}
+
+// timetableWeek выбранная неделя: отмены и переносы на её даты
+type timetableWeek struct {
+    start     time.Time
+    weekType  string
+    overrides map[int]models.ScheduleOverride // schedule_id -> изменение на дату этой недели
+}
+
+// GetGrid возвращает сетку для группы, преподавателя или аудитории.
+// date (YYYY-MM-DD) выбирает неделю: остаются занятия её чётности, показываются отмены и переносы.
+// Без date - шаблон, в котором занятия чётных и нечётных недель показаны вместе
+func (s *TimetableService) GetGrid(kind, name, date string) (*models.TimetableGrid, error) {
+    groupFilter := ""
+    switch kind {
+    case "group":
+        groupFilter = name
+    case "teacher", "classroom":
+    default:
+        return nil, models.Invalid("invalid timetable kind: %s", kind)
+    }
+
+    all, err := s.ScheduleRepo.GetFilteredSchedules("", groupFilter)
+    if err != nil {
+        return nil, err
+    }
+    var schedules []models.Schedule
+    for _, schedule := range all {
+        if (kind == "teacher" && schedule.TeacherName != name) || (kind == "classroom" && schedule.ClassroomName != name) {
+            continue
+        }
+        schedules = append(schedules, schedule)
+    }
+    if len(schedules) == 0 {
+        return nil, models.NotFound("timetable for %s '%s' not found", kind, name)
+    }
+
+    week, err := s.loadWeek(date, groupFilter)
+    if err != nil {
+        return nil, err
+    }
+
+    periods := buildPeriods(schedules)
+    return buildGrid(kind, name, schedules, periods, week), nil
+}
+
+// GetPoster возвращает общее расписание колледжа: все группы на общей сетке пар
+func (s *TimetableService) GetPoster(date string) (*models.TimetablePoster, error) {
+    schedules, err := s.ScheduleRepo.GetFilteredSchedules("", "")
+    if err != nil {
+        return nil, err
+    }
+    if len(schedules) == 0 {
+        return nil, models.NotFound("timetable not found: schedule is empty")
+    }
+
+    week, err := s.loadWeek(date, "")
+    if err != nil {
+        return nil, err
+    }
+
+    byGroup := map[string][]models.Schedule{}
+    var groups []string
+    for _, schedule := range schedules {
+        if _, ok := byGroup[schedule.GroupName]; !ok {
+            groups = append(groups, schedule.GroupName)
+        }
+        byGroup[schedule.GroupName] = append(byGroup[schedule.GroupName], schedule)
+    }
+    sort.Strings(groups)
+
+    periods := buildPeriods(schedules)
+    poster := &models.TimetablePoster{Days: gridDays(schedules), Periods: periods}
+    for _, group := range groups {
+        grid := buildGrid("group", group, byGroup[group], periods, week)
+        grid.Days = poster.Days
+        poster.Groups = append(poster.Groups, *grid)
+    }
+    if week != nil {
+        poster.WeekStart = week.start.Format("2006-01-02")
+        poster.WeekType = week.weekType
+    }
+    return poster, nil
+}
+
+// loadWeek загружает изменения расписания на неделю, в которую попадает date; пустая date - без недели
+func (s *TimetableService) loadWeek(date, groupName string) (*timetableWeek, error) {
+    if date == "" {
+        return nil, nil
+    }
+    day, err := time.Parse("2006-01-02", date)
+    if err != nil {
+        return nil, models.Invalid("invalid date format. Use YYYY-MM-DD")
+    }
+
+    week := &timetableWeek{
+        start:     models.WeekStart(day),
+        weekType:  models.AcademicWeekType(day),
+        overrides: map[int]models.ScheduleOverride{},
+    }
+    overrides, err := s.ScheduleRepo.GetOverrides(groupName, week.start.Format("2006-01-02"), week.start.AddDate(0, 0, 6).Format("2006-01-02"))
+    if err != nil {
+        return nil, err
+    }
+    for _, override := range overrides {
+        week.overrides[override.ScheduleID] = override
+    }
+    return week, nil
+}
+
+// buildPeriods нумерует различные интервалы занятий по времени начала
+func buildPeriods(schedules []models.Schedule) []models.Period {
+    seen := map[string]bool{}
+    var periods []models.Period
+    for _, schedule := range schedules {
+        start, end := schedule.StartTime.Format("15:04"), schedule.EndTime.Format("15:04")
+        if seen[start+end] {
+            continue
+        }
+        seen[start+end] = true
+        periods = append(periods, models.Period{Start: start, End: end})
+    }
+    sort.Slice(periods, func(i, j int) bool {
+        if periods[i].Start != periods[j].Start {
+            return periods[i].Start < periods[j].Start
+        }
+        return periods[i].End < periods[j].End
+    })
+    for i := range periods {
+        periods[i].Number = i + 1
+    }
+    return periods
+}
+
+// gridDays возвращает рабочие дни (понедельник - суббота) и воскресенье, если в него есть занятия
+func gridDays(schedules []models.Schedule) []string {
+    days := timetableDays[:6]
+    for _, schedule := range schedules {
+        if schedule.DayOfWeek == "Sunday" {
+            return timetableDays
+        }
+    }
+    return days
+}
+
+func buildGrid(kind, name string, schedules []models.Schedule, periods []models.Period, week *timetableWeek) *models.TimetableGrid {
+    grid := &models.TimetableGrid{Kind: kind, Name: name, Days: gridDays(schedules), Periods: periods, Lessons: []models.GridLesson{}}
+    if week != nil {
+        grid.WeekStart = week.start.Format("2006-01-02")
+        grid.WeekType = week.weekType
+    }
+
+    periodNumbers := map[string]int{}
+    for _, period := range periods {
+        periodNumbers[period.Start+period.End] = period.Number
+    }
+    dayIndex := map[string]int{}
+    for i, day := range timetableDays {
+        dayIndex[day] = i
+    }
+
+    for _, schedule := range schedules {
+        lesson := models.GridLesson{
+            Day:           schedule.DayOfWeek,
+            Period:        periodNumbers[schedule.StartTime.Format("15:04")+schedule.EndTime.Format("15:04")],
+            ScheduleID:    schedule.ID,
+            GroupName:     schedule.GroupName,
+            TeacherName:   schedule.TeacherName,
+            ClassroomName: schedule.ClassroomName,
+            WeekType:      schedule.WeekType,
+        }
+
+        if week != nil {
+            date := week.start.AddDate(0, 0, dayIndex[schedule.DayOfWeek])
+            if !schedule.OccursOn(date) {
+                continue // Занятие другой чётности на этой неделе не проходит
+            }
+            lesson.Date = date.Format("2006-01-02")
+            if override, ok := week.overrides[schedule.ID]; ok && override.Date == lesson.Date {
+                lesson.Cancelled = override.Cancelled
+                lesson.Reason = override.Reason
+                if override.StartTime != nil && override.EndTime != nil {
+                    moved := override.StartTime.Format("15:04") + "-" + override.EndTime.Format("15:04")
+                    lesson.MovedTo = &moved
+                }
+                lesson.ClassroomNote = override.ClassroomName
+            }
+        }
+        grid.Lessons = append(grid.Lessons, lesson)
+    }
+
+    sort.SliceStable(grid.Lessons, func(i, j int) bool {
+        a, b := grid.Lessons[i], grid.Lessons[j]
+        if dayIndex[a.Day] != dayIndex[b.Day] {
+            return dayIndex[a.Day] < dayIndex[b.Day]
+        }
+        if a.Period != b.Period {
+            return a.Period < b.Period
+        }
+        return a.WeekType < b.WeekType
+    })
+    return grid
+}
+
+// RenderGrid записывает сетку в HTML или PDF
+func (s *TimetableService) RenderGrid(w io.Writer, grid *models.TimetableGrid, format string) error {
+    titles := map[string]string{
+        "group":     "Расписание группы ",
+        "teacher":   "Расписание преподавателя ",
+        "classroom": "Расписание аудитории ",
+    }
+
+    columns := make([]string, len(grid.Days))
+    for i, day := range grid.Days {
+        columns[i] = dayNamesRu[day]
+    }
+
+    out := export.Grid{
+        Title:    titles[grid.Kind] + grid.Name,
+        Subtitle: weekSubtitle(grid.WeekStart, grid.WeekType),
+        Columns:  columns,
+        Legend:   timetableLegend(grid.WeekStart),
+    }
+    for _, period := range grid.Periods {
+        row := export.GridRow{
+            Label: []string{fmt.Sprintf("%d пара", period.Number), period.Start + "-" + period.End},
+            Cells: make([]export.GridCell, len(grid.Days)),
+        }
+        for i, day := range grid.Days {
+            row.Cells[i] = lessonCell(grid.Lessons, day, period.Number, grid.Kind)
+        }
+        out.Rows = append(out.Rows, row)
+    }
+    return writeGrids(w, []export.Grid{out}, format)
+}
+
+// RenderPoster записывает общее расписание: колонки - группы, строки - дни и пары
+func (s *TimetableService) RenderPoster(w io.Writer, poster *models.TimetablePoster, format string) error {
+    out := export.Grid{
+        Title:    "Расписание занятий",
+        Subtitle: weekSubtitle(poster.WeekStart, poster.WeekType),
+        Legend:   timetableLegend(poster.WeekStart),
+    }
+    for _, grid := range poster.Groups {
+        out.Columns = append(out.Columns, grid.Name)
+    }
+
+    for _, day := range poster.Days {
+        var rows []export.GridRow
+        empty := true
+        for _, period := range poster.Periods {
+            row := export.GridRow{
+                Label: []string{fmt.Sprintf("%d пара", period.Number), period.Start + "-" + period.End},
+                Cells: make([]export.GridCell, len(poster.Groups)),
+            }
+            for i, grid := range poster.Groups {
+                row.Cells[i] = lessonCell(grid.Lessons, day, period.Number, "group")
+                if len(row.Cells[i].Items) > 0 {
+                    empty = false
+                }
+            }
+            rows = append(rows, row)
+        }
+        // Дни без занятий на плакат не выводим
+        if empty {
+            continue
+        }
+        rows[0].Section = dayNamesRu[day]
+        out.Rows = append(out.Rows, rows...)
+    }
+    return writeGrids(w, []export.Grid{out}, format)
+}
+
+func writeGrids(w io.Writer, grids []export.Grid, format string) error {
+    switch format {
+    case "html":
+        return export.WriteGridHTML(w, grids)
+    case "pdf":
+        return export.WriteGridPDF(w, grids)
+    }
+    return models.Invalid("invalid format: %s", format)
+}
+
+// lessonCell собирает занятия ячейки; вторая строка зависит от вида сетки
+func lessonCell(lessons []models.GridLesson, day string, period int, kind string) export.GridCell {
+    var cell export.GridCell
+    for _, lesson := range lessons {
+        if lesson.Day != day || lesson.Period != period {
+            continue
+        }
+
+        var lines []string
+        if name, ok := weekTypeNamesRu[lesson.WeekType]; ok {
+            lines = append(lines, "("+name+")")
+        }
+        switch kind {
+        case "group":
+            lines = append(lines, lesson.TeacherName, "ауд. "+lesson.ClassroomName)
+        case "teacher":
+            lines = append(lines, lesson.GroupName, "ауд. "+lesson.ClassroomName)
+        case "classroom":
+            lines = append(lines, lesson.GroupName, lesson.TeacherName)
+        }
+        if lesson.Cancelled {
+            lines = append(lines, "ОТМЕНЕНО")
+        }
+        if lesson.MovedTo != nil {
+            lines = append(lines, "перенос на "+*lesson.MovedTo)
+        }
+        if lesson.ClassroomNote != nil {
+            lines = append(lines, "в ауд. "+*lesson.ClassroomNote)
+        }
+        if lesson.Reason != "" {
+            lines = append(lines, lesson.Reason)
+        }
+        cell.Items = append(cell.Items, export.GridItem{Lines: lines, Cancelled: lesson.Cancelled})
+    }
+    return cell
+}
+
+func weekSubtitle(weekStart, weekType string) string {
+    if weekStart == "" {
+        return "Постоянное расписание"
+    }
+    start, err := time.Parse("2006-01-02", weekStart)
+    if err != nil {
+        return ""
+    }
+    return fmt.Sprintf("Неделя %s - %s (%s)", start.Format("02.01.2006"), start.AddDate(0, 0, 6).Format("02.01.2006"), weekTypeNamesRu[weekType])
+}
+
+func timetableLegend(weekStart string) []string {
+    if weekStart == "" {
+        return []string{"(нечётная), (чётная) - занятие проходит только по нечётным или чётным неделям учебного года"}
+    }
+    return nil
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package services
+
+import (
+    "backend/models"
+    "backend/repository"
+)
+
+// ErrDeleteNotConfirmed возвращается, если удаление затронет связанные записи, а клиент его не подтвердил
+var ErrDeleteNotConfirmed = models.Conflict("deletion affects related records, repeat with ?confirm=true")
+
+// confirmDelete удаляет запись сразу, если зависимых записей нет или удаление подтверждено.
+// Иначе возвращает список зависимых записей и ErrDeleteNotConfirmed (записи - и в деталях ошибки).
+// Вызывается внутри UnitOfWork: проверка блокирует запись, и список не устаревает до удаления
+func confirmDelete(confirm bool, impact func() (models.Cascade, error), remove func() (models.Cascade, error)) (models.Cascade, error) {
+    if !confirm {
+        cascade, err := impact()
+        if err != nil {
+            return nil, err
+        }
+        if !cascade.Empty() {
+            err := *ErrDeleteNotConfirmed
+            return cascade, err.WithDetails(map[string]interface{}{"cascade": cascade})
+        }
+    }
+    return remove()
+}
+
+type TrashService struct {
+    Repo repositories.TrashStore
+    UoW  repositories.Transactor
+}
+
+func NewTrashService(repo repositories.TrashStore, uow repositories.Transactor) *TrashService {
+    return &TrashService{Repo: repo, UoW: uow}
+}
+
+func (s *TrashService) GetTrash(entityType string) ([]models.TrashItem, error) {
+    return s.Repo.GetTrash(entityType)
+}
+
+// Restore возвращает запись из корзины вместе с удалёнными каскадом
+func (s *TrashService) Restore(entityType string, id, userID int) (models.Cascade, error) {
+    var cascade models.Cascade
+    err := s.UoW.Do(func(tx *repositories.Tx) error {
+        var err error
+        if cascade, err = tx.Trash.Restore(entityType, id); err != nil {
+            return err
+        }
+        if err := record(tx, userID, "restore", entityType, id, nil, nil); err != nil {
+            return err
+        }
+        return recordCascade(tx, userID, "cascade_restore", cascade)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return cascade, nil
+}
\ No newline at end of file
@@ -0,0 +1,112 @@
+package services
+
+import (
+    "backend/models"
+    "reflect"
+    "slices"
+    "testing"
+)
+
+// Удаление с зависимыми занятиями требует подтверждения; без него ничего не удаляется
+func TestDeleteRequiresConfirmation(t *testing.T) {
+    tests := []struct {
+        name   string
+        delete func(f *fixture, ids map[string]int, confirm bool) (models.Cascade, error)
+        entity string // Ключ в ids удаляемой записи
+        lesson bool   // Есть ли у записи занятие
+    }{
+        {
+            name:   "teacher with lessons",
+            entity: "teacher",
+            lesson: true,
+            delete: func(f *fixture, ids map[string]int, confirm bool) (models.Cascade, error) {
+                return f.teachers.DeleteTeacher(ids["teacher"], confirm, 0)
+            },
+        },
+        {
+            name:   "classroom with lessons",
+            entity: "classroom",
+            lesson: true,
+            delete: func(f *fixture, ids map[string]int, confirm bool) (models.Cascade, error) {
+                return f.classrooms.DeleteClassroom(ids["classroom"], confirm, 0)
+            },
+        },
+        {
+            name:   "course with lessons",
+            entity: "course",
+            lesson: true,
+            delete: func(f *fixture, ids map[string]int, confirm bool) (models.Cascade, error) {
+                return f.courses.DeleteCourse(ids["course"], confirm, 0)
+            },
+        },
+        {
+            name:   "unused classroom",
+            entity: "spare",
+            delete: func(f *fixture, ids map[string]int, confirm bool) (models.Cascade, error) {
+                return f.classrooms.DeleteClassroom(ids["spare"], confirm, 0)
+            },
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            f := newFixture(t)
+            teacherID := f.teacher(t, "Иванов", 10)
+            ids := map[string]int{
+                "teacher":   teacherID,
+                "course":    f.course(t, "ИВТ-21", &teacherID),
+                "classroom": f.classroom(t, "101"),
+                "spare":     f.classroom(t, "102"),
+            }
+            ids["schedule"] = f.lesson(t, teacherID, ids["classroom"], lesson("Monday", "09:00", "10:30", ""))
+
+            want := models.Cascade{}
+            if tt.lesson {
+                want["schedules"] = []int{ids["schedule"]}
+
+                cascade, err := tt.delete(f, ids, false)
+                if code := errorCode(err); code != models.CodeConflict {
+                    t.Fatalf("unconfirmed: error code = %q (%v), want %q", code, err, models.CodeConflict)
+                }
+                if !reflect.DeepEqual(cascade, want) {
+                    t.Errorf("unconfirmed: cascade = %v, want %v", cascade, want)
+                }
+                if _, err := f.schedules.GetScheduleByID(ids["schedule"]); err != nil {
+                    t.Fatalf("unconfirmed delete removed the lesson: %v", err)
+                }
+            }
+
+            cascade, err := tt.delete(f, ids, true)
+            if err != nil {
+                t.Fatalf("confirmed: %v", err)
+            }
+            if !reflect.DeepEqual(cascade, want) {
+                t.Errorf("confirmed: cascade = %v, want %v", cascade, want)
+            }
+            _, err = f.schedules.GetScheduleByID(ids["schedule"])
+            if deleted := errorCode(err) == models.CodeNotFound; deleted != tt.lesson {
+                t.Errorf("lesson deleted = %v, want %v", deleted, tt.lesson)
+            }
+
+            _, err = tt.delete(f, ids, true)
+            if code := errorCode(err); code != models.CodeNotFound {
+                t.Errorf("second delete: error code = %q (%v), want %q", code, err, models.CodeNotFound)
+            }
+        })
+    }
+}
+
+func TestDeleteCourseUnassignsTeacher(t *testing.T) {
+    f := newFixture(t)
+    teacherID := f.teacher(t, "Иванов", 10)
+    id := f.course(t, "ИВТ-21", &teacherID)
+    f.course(t, "ПМ-22", &teacherID)
+
+    if _, err := f.courses.DeleteCourse(id, false, 0); err != nil {
+        t.Fatalf("delete course: %v", err)
+    }
+    teacher, _ := f.teachers.GetTeacherByID(teacherID)
+    if !slices.Equal(teacher.Courses, []string{"ПМ-22"}) {
+        t.Errorf("teacher courses = %v, want [ПМ-22]", teacher.Courses)
+    }
+}
@@ -0,0 +1,81 @@
+package utils
+
+import "strings"
+
+// Кириллица -> латиница (упрощённая транслитерация, как в загранпаспорте)
+var toLatin = map[rune]string{
+    'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+    'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+    'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+    'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+    'я': "ya",
+}
+
+// Латиница -> кириллица: сначала сочетания букв, затем одиночные буквы
+var toCyrillic = []struct{ latin, cyrillic string }{
+    {"shch", "щ"}, {"iy", "ий"}, {"yy", "ый"}, {"zh", "ж"}, {"kh", "х"}, {"ts", "ц"},
+    {"ch", "ч"}, {"sh", "ш"}, {"yu", "ю"}, {"ya", "я"}, {"yo", "ё"}, {"ye", "е"},
+    {"a", "а"}, {"b", "б"}, {"c", "к"}, {"d", "д"}, {"e", "е"}, {"f", "ф"}, {"g", "г"},
+    {"h", "х"}, {"i", "и"}, {"j", "й"}, {"k", "к"}, {"l", "л"}, {"m", "м"}, {"n", "н"},
+    {"o", "о"}, {"p", "п"}, {"q", "к"}, {"r", "р"}, {"s", "с"}, {"t", "т"}, {"u", "у"},
+    {"v", "в"}, {"w", "в"}, {"x", "кс"}, {"y", "ы"}, {"z", "з"},
+}
+
+// ToLatin транслитерирует кириллицу в латиницу; остальные символы не меняются
+func ToLatin(s string) string {
+    var b strings.Builder
+    for _, r := range strings.ToLower(s) {
+        if latin, ok := toLatin[r]; ok {
+            b.WriteString(latin)
+        } else {
+            b.WriteRune(r)
+        }
+    }
+    return b.String()
+}
+
+// ToCyrillic транслитерирует латиницу в кириллицу; остальные символы не меняются
+func ToCyrillic(s string) string {
+    s = strings.ToLower(s)
+    var b strings.Builder
+    for i := 0; i < len(s); {
+        matched := false
+        for _, pair := range toCyrillic {
+            if strings.HasPrefix(s[i:], pair.latin) {
+                b.WriteString(pair.cyrillic)
+                i += len(pair.latin)
+                matched = true
+                break
+            }
+        }
+        if !matched {
+            r := []rune(s[i:])[0]
+            b.WriteRune(r)
+            i += len(string(r))
+        }
+    }
+    return b.String()
+}
+
+// SearchVariants возвращает строку поиска в нижнем регистре и её транслитерации без повторов
+func SearchVariants(query string) []string {
+    query = strings.ToLower(strings.Join(strings.Fields(query), " "))
+    if query == "" {
+        return nil
+    }
+
+    variants := []string{query}
+    for _, variant := range []string{ToLatin(query), ToCyrillic(query)} {
+        duplicate := false
+        for _, existing := range variants {
+            if existing == variant {
+                duplicate = true
+                break
+            }
+        }
+        if !duplicate {
+            variants = append(variants, variant)
+        }
+    }
+    return variants
+}